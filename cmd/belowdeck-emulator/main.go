@@ -2,10 +2,8 @@ package main
 
 import (
 	"context"
-	"image"
 	"log"
 	"os"
-	"os/exec"
 	"os/signal"
 	"syscall"
 	"time"
@@ -13,20 +11,29 @@ import (
 	"github.com/phinze/belowdeck/internal/coordinator"
 	"github.com/phinze/belowdeck/internal/device"
 	"github.com/phinze/belowdeck/internal/device/emulator"
+	"github.com/phinze/belowdeck/internal/layout"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/modules/audiooutput"
+	"github.com/phinze/belowdeck/internal/modules/bookmarks"
+	"github.com/phinze/belowdeck/internal/modules/clock"
+	"github.com/phinze/belowdeck/internal/modules/dnd"
 	"github.com/phinze/belowdeck/internal/modules/github"
 	"github.com/phinze/belowdeck/internal/modules/homeassistant"
+	"github.com/phinze/belowdeck/internal/modules/macro"
 	"github.com/phinze/belowdeck/internal/modules/nowplaying"
+	"github.com/phinze/belowdeck/internal/modules/quicknote"
+	"github.com/phinze/belowdeck/internal/modules/ticker"
 	"github.com/phinze/belowdeck/internal/modules/weather"
+	"github.com/phinze/belowdeck/internal/platform"
 )
 
 func main() {
 	log.Println("=== Stream Deck Emulator ===")
 	log.Println("Close window or press Ctrl+C to exit")
 
-	// Check if media-control is available
-	if _, err := exec.LookPath("media-control"); err != nil {
-		log.Fatal("media-control not found. Install with: brew tap ungive/media-control && brew install media-control")
+	// Warn about missing external tools, or stop if they're required
+	if err := platform.CheckDependencies(); err != nil {
+		log.Fatal(err)
 	}
 
 	// Setup signal handling
@@ -59,38 +66,18 @@ func main() {
 // runWithDevice runs the coordinator with the given device until context cancel.
 func runWithDevice(ctx context.Context, dev device.Device) {
 	log.Printf("Connected to: %s", dev.GetModelName())
+	dev = recordInput(dev)
 
-	// Set brightness and clear keys
-	dev.SetBrightness(80)
+	// Clear keys
 	dev.ForEachKey(func(key device.KeyID) error {
 		return dev.ClearKey(key)
 	})
 
 	// Create coordinator and modules
 	coord := coordinator.New(dev)
+	coord.SetBrightness(80)
 
-	np := nowplaying.New(dev)
-	coord.RegisterModule(np, module.Resources{
-		Keys:      []module.KeyID{module.Key5, module.Key6},
-		StripRect: image.Rect(0, 0, 400, 100),
-		Dials:     []module.DialID{module.Dial1, module.Dial2},
-	})
-
-	w := weather.New(dev)
-	coord.RegisterModule(w, module.Resources{
-		StripRect: image.Rect(400, 0, 800, 100),
-	})
-
-	ha := homeassistant.New(dev)
-	coord.RegisterModule(ha, module.Resources{
-		Keys:  []module.KeyID{module.Key1, module.Key2},
-		Dials: []module.DialID{module.Dial4},
-	})
-
-	gh := github.New(dev)
-	coord.RegisterModule(gh, module.Resources{
-		Keys: []module.KeyID{module.Key3, module.Key4},
-	})
+	registerModules(coord, dev)
 
 	// Run coordinator
 	errChan := make(chan error, 1)
@@ -110,7 +97,8 @@ func runWithDevice(ctx context.Context, dev device.Device) {
 		}
 	}
 
-	// Stop coordinator with timeout
+	// Stop coordinator with timeout: the render loop, then the modules and
+	// their goroutines, so nothing touches the device once it is closed
 	done := make(chan struct{})
 	go func() {
 		coord.Stop()
@@ -125,3 +113,62 @@ func runWithDevice(ctx context.Context, dev device.Device) {
 
 	dev.Close()
 }
+
+// registerModules creates the modules and registers those that are
+// configured and fit on dev, with keys, dials and strip regions allocated
+// based on what it has.
+func registerModules(coord *coordinator.Coordinator, dev device.Device) {
+	modules := []module.Module{
+		nowplaying.New(dev),
+		weather.New(dev),
+		homeassistant.New(dev),
+		github.New(dev),
+		dnd.New(dev),
+		audiooutput.New(dev),
+		bookmarks.New(dev),
+		ticker.New(dev),
+		quicknote.New(dev),
+		clock.New(dev),
+		macro.New(dev),
+	}
+
+	// Leave unconfigured modules out so they don't hold strip space
+	var available []module.Module
+	var absent []string
+	for _, m := range modules {
+		if c, ok := m.(module.Configurable); ok && !c.Configured() {
+			log.Printf("Module %s is not configured (skipping)", m.ID())
+			absent = append(absent, m.ID())
+			continue
+		}
+		available = append(available, m)
+	}
+
+	lay := layout.ForDevice(dev, absent...)
+	for _, m := range available {
+		// The spare keys module needs no place in the layout, since it
+		// takes the keys nobody else uses
+		res, ok := lay[m.ID()]
+		if !ok && m.ID() != os.Getenv("BELOWDECK_SPARE_KEYS") {
+			log.Printf("No room for module %s on %s (skipping)", m.ID(), dev.GetModelName())
+			continue
+		}
+		coord.RegisterModule(m, res)
+	}
+}
+
+// recordInput wraps dev to log its input events when BELOWDECK_RECORD_INPUT
+// is set, for later use with "belowdeck replay".
+func recordInput(dev device.Device) device.Device {
+	path := os.Getenv("BELOWDECK_RECORD_INPUT")
+	if path == "" {
+		return dev
+	}
+	rec, err := device.NewFileRecorder(dev, path)
+	if err != nil {
+		log.Printf("Input recording disabled: %v", err)
+		return dev
+	}
+	log.Printf("Recording input to %s", path)
+	return rec
+}
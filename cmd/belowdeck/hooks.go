@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// hookTimeout bounds how long a connect or disconnect hook may run.
+const hookTimeout = 10 * time.Second
+
+// deviceHooks run shell commands when the deck connects and disconnects,
+// e.g. to log it or toggle a Home Assistant entity.
+type deviceHooks struct {
+	runner       runner.Runner
+	onConnect    string
+	onDisconnect string
+}
+
+// loadDeviceHooks reads BELOWDECK_ON_CONNECT and BELOWDECK_ON_DISCONNECT.
+// Either may be unset.
+func loadDeviceHooks() deviceHooks {
+	return deviceHooks{
+		runner:       runner.Default,
+		onConnect:    os.Getenv("BELOWDECK_ON_CONNECT"),
+		onDisconnect: os.Getenv("BELOWDECK_ON_DISCONNECT"),
+	}
+}
+
+// connected runs the connect hook in the background, so a slow command
+// doesn't hold up the deck.
+func (h deviceHooks) connected() {
+	if h.onConnect == "" {
+		return
+	}
+	go h.run("connect", h.onConnect)
+}
+
+// disconnected runs the disconnect hook and waits for it, so it finishes
+// even when the disconnect is part of shutting down.
+func (h deviceHooks) disconnected() {
+	if h.onDisconnect == "" {
+		return
+	}
+	h.run("disconnect", h.onDisconnect)
+}
+
+// run runs a hook command, logging failures. Hooks get their own context,
+// bounded by hookTimeout, since they also run after shutdown has begun.
+func (h deviceHooks) run(event, command string) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	if err := runner.Shell(ctx, h.runner, command); err != nil {
+		log.Printf("Device %s hook failed: %v", event, err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// unpluggedDevice is a fake device whose listener fails straight away, as
+// when the deck is unplugged.
+type unpluggedDevice struct {
+	*device.Fake
+}
+
+func (d unpluggedDevice) Listen(errCh chan error) error {
+	return errors.New("device unplugged")
+}
+
+func TestDisconnectHookRuns(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("BELOWDECK_KEYS", "clock:8")
+
+	fake := &runner.Fake{}
+	hooks := deviceHooks{runner: fake, onConnect: "notify connected", onDisconnect: "notify disconnected"}
+	dev := unpluggedDevice{device.NewFake()}
+	dev.Open()
+
+	done := make(chan struct{})
+	go func() {
+		runWithDevice(context.Background(), dev, make(chan platform.PowerEvent), hooks)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runWithDevice didn't return after the device disconnected")
+	}
+
+	// The disconnect hook has run by the time runWithDevice returns; the
+	// connect hook runs in the background
+	if !slices.Contains(fake.Commands(), "sh -c notify disconnected") {
+		t.Errorf("ran %q, want the disconnect hook", fake.Commands())
+	}
+	deadline := time.Now().Add(time.Second)
+	for !slices.Contains(fake.Commands(), "sh -c notify connected") {
+		if time.Now().After(deadline) {
+			t.Fatalf("ran %q, want the connect hook", fake.Commands())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHooksOptional(t *testing.T) {
+	fake := &runner.Fake{}
+	hooks := deviceHooks{runner: fake}
+	hooks.connected()
+	hooks.disconnected()
+	time.Sleep(20 * time.Millisecond)
+	if got := fake.Commands(); len(got) != 0 {
+		t.Errorf("ran %q with no hooks set", got)
+	}
+}
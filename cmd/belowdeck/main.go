@@ -2,32 +2,80 @@ package main
 
 import (
 	"context"
-	"image"
 	"log"
 	"os"
-	"os/exec"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/control"
 	"github.com/phinze/belowdeck/internal/coordinator"
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/layout"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/modules/audiooutput"
+	"github.com/phinze/belowdeck/internal/modules/bookmarks"
+	"github.com/phinze/belowdeck/internal/modules/clock"
+	"github.com/phinze/belowdeck/internal/modules/commute"
+	"github.com/phinze/belowdeck/internal/modules/dnd"
 	"github.com/phinze/belowdeck/internal/modules/github"
 	"github.com/phinze/belowdeck/internal/modules/homeassistant"
+	"github.com/phinze/belowdeck/internal/modules/macro"
 	"github.com/phinze/belowdeck/internal/modules/nowplaying"
+	"github.com/phinze/belowdeck/internal/modules/quicknote"
+	"github.com/phinze/belowdeck/internal/modules/ticker"
 	"github.com/phinze/belowdeck/internal/modules/weather"
-	"github.com/prashantgupta24/mac-sleep-notifier/notifier"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
 	"rafaelmartins.com/p/streamdeck"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if len(os.Args) != 3 {
+			log.Fatal("usage: belowdeck replay <input-log>")
+		}
+		if err := replay(os.Args[2]); err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "remote" {
+		if len(os.Args) > 3 {
+			log.Fatal("usage: belowdeck remote [addr]")
+		}
+		addr := defaultRemoteAddr
+		if len(os.Args) == 3 {
+			addr = os.Args[2]
+		}
+		if err := runRemote(addr); err != nil {
+			log.Fatalf("Remote deck failed: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "action" {
+		if len(os.Args) != 3 {
+			log.Fatal("usage: belowdeck action <module:action>")
+		}
+		path := os.Getenv("BELOWDECK_SOCKET")
+		if path == "" {
+			log.Fatal("BELOWDECK_SOCKET is not set")
+		}
+		if err := control.Send(path, os.Args[2]); err != nil {
+			log.Fatalf("Action failed: %v", err)
+		}
+		return
+	}
+
 	log.Println("=== Stream Deck Daemon ===")
 	log.Println("Press Ctrl+C to exit")
 
-	// Check if media-control is available
-	if _, err := exec.LookPath("media-control"); err != nil {
-		log.Fatal("media-control not found. Install with: brew tap ungive/media-control && brew install media-control")
+	// Warn about missing external tools, or stop if they're required
+	if err := platform.CheckDependencies(); err != nil {
+		log.Fatal(err)
 	}
 
 	// Setup signal handling
@@ -43,20 +91,14 @@ func main() {
 		cancel()
 	}()
 
-	// Start sleep/wake notifier and run device loop
-	sleepCh := notifier.GetInstance().Start()
-	wakeCh := make(chan struct{}, 1)
-	go func() {
-		for activity := range sleepCh {
-			if activity.Type == notifier.Awake {
-				log.Println("System wake detected")
-				select {
-				case wakeCh <- struct{}{}:
-				default:
-				}
-			}
-		}
-	}()
+	// Watch for system sleep and wake and run device loop
+	powerCh := platform.PowerEvents()
+
+	// Commands to run as the deck comes and goes
+	hooks := loadDeviceHooks()
+
+	// Bound how often URLs are opened, across every module
+	runner.URLThrottle.Window = openWindow()
 
 	// Main device loop - wait for device, run, repeat on disconnect
 	for {
@@ -66,7 +108,7 @@ func main() {
 			break
 		}
 
-		runWithDevice(ctx, dev, wakeCh)
+		runWithDevice(ctx, dev, powerCh, hooks)
 
 		// Check if we should exit or wait for reconnect
 		select {
@@ -79,6 +121,21 @@ func main() {
 	}
 }
 
+// openWindow reads BELOWDECK_OPEN_THROTTLE, the window within which the
+// same URL isn't opened twice and only a few are opened at all.
+func openWindow() time.Duration {
+	v := os.Getenv("BELOWDECK_OPEN_THROTTLE")
+	if v == "" {
+		return runner.DefaultOpenWindow
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("Invalid BELOWDECK_OPEN_THROTTLE %q, using %s", v, runner.DefaultOpenWindow)
+		return runner.DefaultOpenWindow
+	}
+	return d
+}
+
 // waitForHardwareDevice polls for a Stream Deck device until one is available.
 // Uses polling since macOS doesn't have a simple USB hotplug event API.
 func waitForHardwareDevice(ctx context.Context) device.Device {
@@ -117,41 +174,40 @@ func waitForHardwareDevice(ctx context.Context) device.Device {
 	}
 }
 
+// discardPowerEvents drops sleep and wake events received while no device
+// was connected, so a stale sleep isn't passed on to the next coordinator's
+// modules.
+func discardPowerEvents(powerCh <-chan platform.PowerEvent) {
+	for {
+		select {
+		case ev := <-powerCh:
+			log.Printf("Ignoring %s from before the device connected", ev)
+		default:
+			return
+		}
+	}
+}
+
 // runWithDevice runs the coordinator with the given device until disconnect, wake, or context cancel.
-func runWithDevice(ctx context.Context, dev device.Device, wakeCh <-chan struct{}) {
+// The connect hook runs as it starts and the disconnect hook once the device is closed.
+func runWithDevice(ctx context.Context, dev device.Device, powerCh <-chan platform.PowerEvent, hooks deviceHooks) {
 	log.Printf("Connected to: %s", dev.GetModelName())
+	hooks.connected()
+	dev = recordInput(dev)
+
+	// Only sleep and wake from while the coordinator runs are passed on
+	discardPowerEvents(powerCh)
 
-	// Set brightness and clear keys
-	dev.SetBrightness(80)
+	// Clear keys
 	dev.ForEachKey(func(key device.KeyID) error {
 		return dev.ClearKey(key)
 	})
 
 	// Create coordinator and modules fresh for each connection
 	coord := coordinator.New(dev)
+	coord.SetBrightness(80)
 
-	np := nowplaying.New(dev)
-	coord.RegisterModule(np, module.Resources{
-		Keys:      []module.KeyID{module.Key5, module.Key6},
-		StripRect: image.Rect(0, 0, 400, 100),
-		Dials:     []module.DialID{module.Dial1, module.Dial2},
-	})
-
-	w := weather.New(dev)
-	coord.RegisterModule(w, module.Resources{
-		StripRect: image.Rect(400, 0, 800, 100),
-	})
-
-	ha := homeassistant.New(dev)
-	coord.RegisterModule(ha, module.Resources{
-		Keys:  []module.KeyID{module.Key1, module.Key2},
-		Dials: []module.DialID{module.Dial4},
-	})
-
-	gh := github.New(dev)
-	coord.RegisterModule(gh, module.Resources{
-		Keys: []module.KeyID{module.Key3, module.Key4},
-	})
+	registerModules(coord, dev)
 
 	// Run coordinator with a child context so we can stop it independently
 	runCtx, runCancel := context.WithCancel(ctx)
@@ -164,19 +220,30 @@ func runWithDevice(ctx context.Context, dev device.Device, wakeCh <-chan struct{
 
 	log.Println("Ready! Media on left, weather on right")
 
-	// Wait for parent context cancel, device error, or system wake
-	select {
-	case <-ctx.Done():
-		log.Println("Shutting down...")
-	case err := <-errChan:
-		if err != nil {
-			log.Printf("Device disconnected: %v", err)
+	// Wait for parent context cancel, device error, or system wake. Sleep
+	// and wake are passed on to modules first.
+wait:
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Shutting down...")
+			break wait
+		case err := <-errChan:
+			if err != nil {
+				log.Printf("Device disconnected: %v", err)
+			}
+			break wait
+		case ev := <-powerCh:
+			coord.Bus().Publish(bus.TopicPower, ev)
+			if ev == platform.Wake {
+				log.Println("Reconnecting device after wake...")
+				break wait
+			}
 		}
-	case <-wakeCh:
-		log.Println("Reconnecting device after wake...")
 	}
 
-	// Stop coordinator with timeout
+	// Stop coordinator with timeout: the render loop, then the modules and
+	// their goroutines, so nothing touches the device once it is closed
 	runCancel()
 
 	done := make(chan struct{})
@@ -191,25 +258,72 @@ func runWithDevice(ctx context.Context, dev device.Device, wakeCh <-chan struct{
 		log.Println("Cleanup timed out")
 	}
 
-	// Close device - need to wait for this on wake to avoid race condition
-	// where we try to reopen before close completes
-	closeDone := make(chan struct{})
-	go func() {
-		dev.Close()
-		close(closeDone)
-	}()
+	// Close device - this waits on wake to avoid a race where we try to
+	// reopen before close completes. Closing the handle ends the listener's
+	// read, and Close waits for the listener to return, so no old listener
+	// is reading when the device is opened again.
+	if err := dev.Close(); err != nil {
+		log.Printf("Device close: %v", err)
+	}
+	hooks.disconnected()
+}
 
-	// If parent context is cancelled (shutdown signal), force exit
-	// since device.Close() may block indefinitely
-	select {
-	case <-ctx.Done():
-		log.Println("Exiting...")
-		os.Exit(0)
-	case <-closeDone:
-		// Device closed cleanly
-	case <-time.After(3 * time.Second):
-		// Device close timed out - on wake, give it a bit more time
-		// then proceed anyway (might need to wait for device to reappear)
-		log.Println("Device close timed out")
+// registerModules creates the modules and registers those that are
+// configured and fit on dev, with keys, dials and strip regions allocated
+// based on what it has.
+func registerModules(coord *coordinator.Coordinator, dev device.Device) {
+	modules := []module.Module{
+		nowplaying.New(dev),
+		weather.New(dev),
+		homeassistant.New(dev),
+		github.New(dev),
+		dnd.New(dev),
+		audiooutput.New(dev),
+		bookmarks.New(dev),
+		ticker.New(dev),
+		commute.New(dev),
+		quicknote.New(dev),
+		clock.New(dev),
+		macro.New(dev),
+	}
+
+	// Leave unconfigured modules out so they don't hold strip space
+	var available []module.Module
+	var absent []string
+	for _, m := range modules {
+		if c, ok := m.(module.Configurable); ok && !c.Configured() {
+			log.Printf("Module %s is not configured (skipping)", m.ID())
+			absent = append(absent, m.ID())
+			continue
+		}
+		available = append(available, m)
+	}
+
+	lay := layout.ForDevice(dev, absent...)
+	for _, m := range available {
+		// The spare keys module needs no place in the layout, since it
+		// takes the keys nobody else uses
+		res, ok := lay[m.ID()]
+		if !ok && m.ID() != os.Getenv("BELOWDECK_SPARE_KEYS") {
+			log.Printf("No room for module %s on %s (skipping)", m.ID(), dev.GetModelName())
+			continue
+		}
+		coord.RegisterModule(m, res)
+	}
+}
+
+// recordInput wraps dev to log its input events when BELOWDECK_RECORD_INPUT
+// is set, for later use with "belowdeck replay".
+func recordInput(dev device.Device) device.Device {
+	path := os.Getenv("BELOWDECK_RECORD_INPUT")
+	if path == "" {
+		return dev
+	}
+	rec, err := device.NewFileRecorder(dev, path)
+	if err != nil {
+		log.Printf("Input recording disabled: %v", err)
+		return dev
 	}
+	log.Printf("Recording input to %s", path)
+	return rec
 }
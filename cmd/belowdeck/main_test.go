@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/platform"
+)
+
+func TestStartsWithoutMediaControl(t *testing.T) {
+	// An empty PATH has no media-control, or any other tool
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("BELOWDECK_KEYS", "clock:8")
+	if platform.MediaControlAvailable() {
+		t.Fatal("media-control found on an empty PATH")
+	}
+	if err := platform.CheckDependencies(); err != nil {
+		t.Fatalf("CheckDependencies = %v, want only a warning", err)
+	}
+
+	dev := device.NewFake()
+	dev.Open()
+	coord := coordinator.New(dev)
+	registerModules(coord, dev)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- coord.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		coord.Stop()
+		dev.Close()
+		<-done
+	}()
+
+	select {
+	case <-dev.Listening():
+	case err := <-done:
+		t.Fatalf("coordinator stopped on start: %v", err)
+	}
+
+	if err := coord.ExternalAction("nowplaying:info"); err == nil {
+		t.Error("now playing was registered without media-control")
+	}
+	if dev.KeyImage(device.KEY_8) == nil {
+		t.Error("clock key wasn't rendered, want other modules running")
+	}
+}
+
+func TestDiscardPowerEvents(t *testing.T) {
+	powerCh := make(chan platform.PowerEvent, 1)
+	powerCh <- platform.Sleep
+
+	discardPowerEvents(powerCh)
+	select {
+	case ev := <-powerCh:
+		t.Errorf("%s from before the device connected was kept", ev)
+	default:
+	}
+}
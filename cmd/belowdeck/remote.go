@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device/remote"
+)
+
+// defaultRemoteAddr is where the remote deck is served when no address is
+// given.
+const defaultRemoteAddr = "localhost:8790"
+
+// runRemote runs the coordinator and modules against a virtual deck served
+// to web browsers at addr, until interrupted.
+func runRemote(addr string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dev := remote.New(addr)
+	if err := dev.Open(); err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	wrapped := recordInput(dev)
+	coord := coordinator.New(wrapped)
+	registerModules(coord, wrapped)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- coord.Start(ctx)
+	}()
+	defer coord.Stop()
+
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down...")
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/coordinator"
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// replaySettle is how long to keep running after the last event so its
+// effects get rendered and logged.
+const replaySettle = 2 * time.Second
+
+// replay feeds an input log recorded with BELOWDECK_RECORD_INPUT through the
+// coordinator and modules against a fake device, at the recorded pace.
+func replay(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	events, err := device.ReadInputLog(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dev := device.NewFake()
+	if err := dev.Open(); err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	coord := coordinator.New(dev)
+	registerModules(coord, dev)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- coord.Start(ctx)
+	}()
+	defer coord.Stop()
+
+	// Handlers are registered by the time the coordinator starts listening
+	select {
+	case <-dev.Listening():
+	case err := <-errChan:
+		return err
+	}
+
+	log.Printf("Replaying %d events from %s", len(events), path)
+	if err := dev.Replay(ctx, events); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(replaySettle):
+	}
+	log.Println("Replay finished")
+	return nil
+}
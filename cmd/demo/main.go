@@ -182,7 +182,10 @@ func setupTouchStrip(device *streamdeck.Device) {
 	}
 
 	img := createGradient(rect, colornames.Blueviolet, colornames.Orangered)
-	device.SetTouchStripImage(img)
+	if err := device.SetTouchStripImage(img); err != nil {
+		log.Printf("Failed to set touch strip image: %v", err)
+		return
+	}
 
 	// Handle touch
 	device.AddTouchStripTouchHandler(func(d *streamdeck.Device, typ streamdeck.TouchStripTouchType, p image.Point) error {
@@ -8,7 +8,9 @@ require (
 	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef
 	golang.org/x/image v0.35.0
+	golang.org/x/net v0.57.0
 	rafaelmartins.com/p/streamdeck v0.0.0-20250810040445-3d55b1e87750
+	rafaelmartins.com/p/usbhid v0.0.0-20251225062232-1accdc9b433e
 )
 
 require (
@@ -16,9 +18,7 @@ require (
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.9.1 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	golang.org/x/net v0.0.0-20211118161319-6a13c67c3ce4 // indirect
-	golang.org/x/sync v0.19.0 // indirect
-	golang.org/x/sys v0.36.0 // indirect
-	golang.org/x/text v0.33.0 // indirect
-	rafaelmartins.com/p/usbhid v0.0.0-20251225062232-1accdc9b433e // indirect
+	golang.org/x/sync v0.22.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
 )
@@ -0,0 +1,135 @@
+// Package bus provides a simple publish/subscribe event bus for cross-module events.
+package bus
+
+import (
+	"context"
+	"sync"
+)
+
+// Well-known topics published by modules.
+const (
+	// TopicDND is published when Do Not Disturb changes. Data is a bool (true = on).
+	TopicDND = "dnd"
+
+	// TopicPower is published when the system goes to sleep or wakes up.
+	// Data is a platform.PowerEvent.
+	TopicPower = "power"
+
+	// TopicAway is published by the coordinator when the away state
+	// changes, e.g. when a meeting starts. Data is an Away.
+	TopicAway = "away"
+
+	// TopicHAService asks the Home Assistant module to call a service.
+	// Data is an HAServiceCall.
+	TopicHAService = "ha-service"
+)
+
+// Away describes the coordinator's away state.
+type Away struct {
+	// On is true while away.
+	On bool
+
+	// Reason says why, e.g. a calendar event title. Empty when not away.
+	Reason string
+}
+
+// HAServiceCall is a Home Assistant service call, e.g. domain "light" and
+// service "turn_on", with data such as the entity_id.
+type HAServiceCall struct {
+	Domain  string
+	Service string
+	Data    map[string]any
+
+	// Done, if set, receives the call's result. It should be buffered, as
+	// the result is sent from another goroutine once the call finishes.
+	Done chan<- error
+}
+
+// Event is a message published on the bus.
+type Event struct {
+	Topic string
+	Data  any
+}
+
+// Handler is called for each event published to a subscribed topic.
+// Handlers run synchronously on the publisher's goroutine and should return quickly.
+type Handler func(Event)
+
+// subscription is one handler subscribed to a topic. Subscriptions are
+// compared by identity, so the same handler can be subscribed twice and
+// removed once.
+type subscription struct {
+	fn Handler
+}
+
+// Bus routes published events to subscribers by topic.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string][]*subscription
+}
+
+// New creates an empty Bus.
+func New() *Bus {
+	return &Bus{subs: make(map[string][]*subscription)}
+}
+
+// Subscribe registers a handler for events on the given topic. It returns a
+// function that removes the handler again.
+func (b *Bus) Subscribe(topic string, fn Handler) (unsubscribe func()) {
+	sub := &subscription{fn: fn}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[topic] = append(b.subs[topic], sub)
+	return func() { b.unsubscribe(topic, sub) }
+}
+
+// SubscribeContext registers a handler for events on the given topic until
+// ctx is done. Modules subscribe with their own context, so stopping or
+// restarting one doesn't leave its old handlers behind. The handler stops
+// being called as soon as ctx is done, and is removed shortly after.
+func (b *Bus) SubscribeContext(ctx context.Context, topic string, fn Handler) {
+	unsubscribe := b.Subscribe(topic, func(e Event) {
+		if ctx.Err() == nil {
+			fn(e)
+		}
+	})
+	context.AfterFunc(ctx, unsubscribe)
+}
+
+// unsubscribe removes sub from topic. The topic's handlers are replaced
+// rather than edited in place, since Publish may be iterating over them.
+func (b *Bus) unsubscribe(topic string, sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var kept []*subscription
+	for _, s := range b.subs[topic] {
+		if s != sub {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == 0 {
+		delete(b.subs, topic)
+		return
+	}
+	b.subs[topic] = kept
+}
+
+// Publish delivers an event to all handlers subscribed to its topic.
+func (b *Bus) Publish(topic string, data any) {
+	b.mu.RLock()
+	subs := b.subs[topic]
+	b.mu.RUnlock()
+
+	event := Event{Topic: topic, Data: data}
+	for _, sub := range subs {
+		sub.fn(event)
+	}
+}
+
+// HasSubscribers reports whether any handler is subscribed to topic, so a
+// publisher waiting for a reply can tell nobody will send one.
+func (b *Bus) HasSubscribers(topic string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs[topic]) > 0
+}
@@ -0,0 +1,46 @@
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUnsubscribe(t *testing.T) {
+	b := New()
+	var a, c int
+	unsubA := b.Subscribe("t", func(Event) { a++ })
+	b.Subscribe("t", func(Event) { c++ })
+
+	b.Publish("t", nil)
+	unsubA()
+	unsubA() // removing twice is harmless
+	b.Publish("t", nil)
+
+	if a != 1 || c != 2 {
+		t.Errorf("handlers ran %d and %d times, want 1 and 2", a, c)
+	}
+}
+
+func TestSubscribeContextEndsWithContext(t *testing.T) {
+	b := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	b.SubscribeContext(ctx, "t", func(Event) { calls++ })
+
+	b.Publish("t", nil)
+	cancel()
+	b.Publish("t", nil)
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+
+	// The handler is removed in the background once ctx is done
+	deadline := time.Now().Add(time.Second)
+	for b.HasSubscribers("t") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if b.HasSubscribers("t") {
+		t.Error("handler still subscribed after its context ended")
+	}
+}
@@ -0,0 +1,102 @@
+// Package control implements the local socket used to trigger deck actions
+// from outside the daemon, e.g. from a global hotkey tool like skhd or
+// Hammerspoon running "belowdeck action nowplaying:info".
+package control
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds how long a single request may take end to end.
+const requestTimeout = 5 * time.Second
+
+// Dispatcher runs an action such as "nowplaying:info".
+type Dispatcher func(action string) error
+
+// Serve listens on a unix socket at path until ctx is cancelled. Each
+// connection sends one action per line and gets back "ok" or
+// "error: <message>" for each.
+func Serve(ctx context.Context, path string, dispatch Dispatcher) error {
+	// Remove a stale socket left behind by a previous run
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	log.Printf("Control socket listening on %s", path)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go handle(conn, dispatch)
+	}
+}
+
+// handle serves one connection.
+func handle(conn net.Conn, dispatch Dispatcher) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		action := strings.TrimSpace(scanner.Text())
+		if action == "" {
+			continue
+		}
+
+		reply := "ok"
+		if err := dispatch(action); err != nil {
+			reply = "error: " + err.Error()
+		}
+		log.Printf("Control: %s: %s", action, reply)
+		if _, err := fmt.Fprintln(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// Send asks the daemon listening at path to run action.
+func Send(path, action string) error {
+	conn, err := net.DialTimeout("unix", path, requestTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	if _, err := fmt.Fprintln(conn, action); err != nil {
+		return err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("read reply: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if msg, ok := strings.CutPrefix(reply, "error: "); ok {
+		return errors.New(msg)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSendRunsActionOverSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "control.sock")
+	actions := make(chan string, 2)
+	dispatch := func(action string) error {
+		actions <- action
+		if action == "nowplaying:nope" {
+			return errors.New(`unknown action "nope"`)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, path, dispatch) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+
+	// Wait for the socket to come up
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := Send(path, "nowplaying:info")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Send: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := <-actions; got != "nowplaying:info" {
+		t.Errorf("dispatched %q, want %q", got, "nowplaying:info")
+	}
+
+	err := Send(path, "nowplaying:nope")
+	if err == nil || err.Error() != `unknown action "nope"` {
+		t.Errorf("Send = %v, want the dispatcher's error", err)
+	}
+}
@@ -0,0 +1,83 @@
+package coordinator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// ExternalAction runs an action addressed as "<module ID>:<action>" on the
+// owning module, as if triggered from the deck. It's the entry point for
+// triggers outside the deck, such as the control socket. Like a key press,
+// it wakes the deck from standby. The "away", "present" and "diagnostics"
+// IDs address the coordinator's own away state, presentation mode and
+// diagnostics overlay (see handleAwayAction, handlePresentAction and
+// handleDiagnosticsAction), "refresh" refreshes any module.Refreshable, and
+// "stop" and "restart" stop or restart any module on its own.
+func (c *Coordinator) ExternalAction(ref string) error {
+	id, action, ok := strings.Cut(ref, ":")
+	if !ok || id == "" || action == "" {
+		return fmt.Errorf("invalid action %q (want \"module:action\")", ref)
+	}
+
+	if id == awayActionID {
+		c.noteActivity()
+		return c.handleAwayAction(action)
+	}
+	if id == presentActionID {
+		c.noteActivity()
+		return c.handlePresentAction(action)
+	}
+	if id == diagnosticsActionID {
+		c.noteActivity()
+		return c.handleDiagnosticsAction(action)
+	}
+
+	target := c.moduleByID(id)
+	if target == nil {
+		return fmt.Errorf("no module %q", id)
+	}
+
+	// Any module can be stopped or restarted, including one that failed
+	switch action {
+	case "stop":
+		return c.StopModule(id)
+	case "restart":
+		return c.RestartModule(id)
+	}
+
+	if c.isFailed(target) {
+		return fmt.Errorf("module %q failed to initialize", id)
+	}
+	if c.isStopped(target) {
+		return fmt.Errorf("module %q is stopped", id)
+	}
+	if c.isSnoozed(target) {
+		return fmt.Errorf("module %q is snoozed", id)
+	}
+
+	// Any refreshable module accepts "refresh"
+	if r, ok := target.(module.Refreshable); ok && action == "refresh" {
+		c.noteActivity()
+		r.Refresh()
+		return nil
+	}
+
+	handler, ok := target.(module.ActionHandler)
+	if !ok {
+		return fmt.Errorf("module %q has no actions", id)
+	}
+
+	c.noteActivity()
+	return handler.HandleAction(action)
+}
+
+// refresh asks a module to fetch fresh data now, if it supports it.
+func (c *Coordinator) refresh(m module.Module) {
+	if r, ok := m.(module.Refreshable); ok {
+		log.Printf("Refreshing module %s", m.ID())
+		r.Refresh()
+	}
+}
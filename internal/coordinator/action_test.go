@@ -0,0 +1,61 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// actionModule is a stub module with a "ping" action.
+type actionModule struct {
+	*stubModule
+	pings int
+}
+
+func (m *actionModule) HandleAction(action string) error {
+	if action != "ping" {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	m.pings++
+	return nil
+}
+
+func TestExternalActionRouting(t *testing.T) {
+	c := New(device.NewFake())
+	withActions := &actionModule{stubModule: newStubModule("act")}
+	c.RegisterModule(withActions, module.Resources{})
+	c.RegisterModule(newStubModule("plain"), module.Resources{})
+
+	if err := c.ExternalAction("act:ping"); err != nil {
+		t.Fatalf("act:ping: %v", err)
+	}
+	if withActions.pings != 1 {
+		t.Errorf("module handled %d pings, want 1", withActions.pings)
+	}
+
+	for ref, want := range map[string]string{
+		"act":        "invalid action",
+		":ping":      "invalid action",
+		"act:":       "invalid action",
+		"nope:ping":  `no module "nope"`,
+		"plain:ping": `module "plain" has no actions`,
+		"act:pong":   `unknown action "pong"`,
+	} {
+		err := c.ExternalAction(ref)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error %v, want %q", ref, err, want)
+		}
+	}
+
+	withActions.Snooze(time.Now().Add(time.Hour))
+	if err := c.ExternalAction("act:ping"); err == nil || !strings.Contains(err.Error(), "snoozed") {
+		t.Errorf("action on a snoozed module: error %v, want it refused", err)
+	}
+	if withActions.pings != 1 {
+		t.Errorf("snoozed module handled %d pings, want 1", withActions.pings)
+	}
+}
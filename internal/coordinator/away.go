@@ -0,0 +1,147 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// awayActionID addresses the coordinator's away state in external actions,
+// e.g. "away:toggle".
+const awayActionID = "away"
+
+// awayCommandTimeout bounds each run of the away calendar command.
+const awayCommandTimeout = 10 * time.Second
+
+// awayState tracks each source that can make the deck away. The deck is
+// away while any enabled source says so.
+type awayState struct {
+	manual bool   // set with the away:on/off/toggle actions
+	dnd    bool   // Do Not Disturb is on and AwayOnDND is set
+	event  string // current calendar event, if any
+}
+
+// current returns the away state to publish. A manual toggle takes
+// precedence for the reason, then a calendar event, then Focus.
+func (s awayState) current() bus.Away {
+	switch {
+	case s.manual:
+		return bus.Away{On: true, Reason: "Away"}
+	case s.event != "":
+		return bus.Away{On: true, Reason: s.event}
+	case s.dnd:
+		return bus.Away{On: true, Reason: "Focus"}
+	default:
+		return bus.Away{}
+	}
+}
+
+// setupAway follows Do Not Disturb when AwayOnDND is set. It runs before
+// modules initialize so their first DND event isn't missed.
+func (c *Coordinator) setupAway() {
+	if !c.config.AwayOnDND {
+		return
+	}
+	c.bus.Subscribe(bus.TopicDND, func(e bus.Event) {
+		on, _ := e.Data.(bool)
+		c.updateAway(func(s *awayState) { s.dnd = on })
+	})
+}
+
+// startAwayCalendar polls the away calendar command, if configured.
+func (c *Coordinator) startAwayCalendar() {
+	if c.config.AwayCalendarCommand == "" {
+		return
+	}
+	c.wg.Add(1)
+	go c.pollAwayCalendar()
+}
+
+// pollAwayCalendar runs the calendar command every AwayCalendarInterval.
+// Its trimmed output is the title of the current event; empty output means
+// no event. A failed run leaves the state unchanged.
+func (c *Coordinator) pollAwayCalendar() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.config.AwayCalendarInterval)
+	defer ticker.Stop()
+
+	for {
+		c.checkAwayCalendar()
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAwayCalendar runs the calendar command once and records its event.
+func (c *Coordinator) checkAwayCalendar() {
+	ctx, cancel := context.WithTimeout(c.ctx, awayCommandTimeout)
+	defer cancel()
+
+	out, err := runner.ShellOutput(ctx, runner.Default, c.config.AwayCalendarCommand)
+	if err != nil {
+		if c.ctx.Err() == nil {
+			log.Printf("Away calendar command failed: %v", err)
+		}
+		return
+	}
+
+	// Only the first line is used, so commands listing several
+	// overlapping events show the first
+	event, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	c.updateAway(func(s *awayState) { s.event = strings.TrimSpace(event) })
+}
+
+// handleAwayAction runs an "away:<action>" external action: "on" and "off"
+// set the manual away state and "toggle" flips it.
+func (c *Coordinator) handleAwayAction(action string) error {
+	switch action {
+	case "on":
+		c.updateAway(func(s *awayState) { s.manual = true })
+	case "off":
+		c.updateAway(func(s *awayState) { s.manual = false })
+	case "toggle":
+		c.updateAway(func(s *awayState) { s.manual = !s.manual })
+	default:
+		return fmt.Errorf("unknown away action %q (want on, off or toggle)", action)
+	}
+	return nil
+}
+
+// Away returns the current away state.
+func (c *Coordinator) Away() bus.Away {
+	c.awayMu.Lock()
+	defer c.awayMu.Unlock()
+	return c.away
+}
+
+// updateAway applies fn to the away sources and publishes TopicAway if the
+// resulting state changed. Publishing happens outside the lock, since
+// subscribers may call back into the coordinator.
+func (c *Coordinator) updateAway(fn func(*awayState)) {
+	c.awayMu.Lock()
+	fn(&c.awaySources)
+	next := c.awaySources.current()
+	changed := next != c.away
+	c.away = next
+	c.awayMu.Unlock()
+
+	if !changed {
+		return
+	}
+	if next.On {
+		log.Printf("Away: %s", next.Reason)
+	} else {
+		log.Println("Back from away")
+	}
+	c.bus.Publish(bus.TopicAway, next)
+	c.requestRender()
+}
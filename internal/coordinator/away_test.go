@@ -0,0 +1,119 @@
+package coordinator
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// awayRecorder collects the away states published on a bus.
+type awayRecorder struct {
+	mu     sync.Mutex
+	events []bus.Away
+}
+
+func recordAway(b *bus.Bus) *awayRecorder {
+	r := &awayRecorder{}
+	b.Subscribe(bus.TopicAway, func(e bus.Event) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, e.Data.(bus.Away))
+	})
+	return r
+}
+
+func (r *awayRecorder) published() []bus.Away {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]bus.Away(nil), r.events...)
+}
+
+func TestAwayStateCurrent(t *testing.T) {
+	tests := []struct {
+		name  string
+		state awayState
+		want  bus.Away
+	}{
+		{"nothing", awayState{}, bus.Away{}},
+		{"dnd", awayState{dnd: true}, bus.Away{On: true, Reason: "Focus"}},
+		{"event over dnd", awayState{dnd: true, event: "Standup"}, bus.Away{On: true, Reason: "Standup"}},
+		{"manual over event", awayState{manual: true, event: "Standup"}, bus.Away{On: true, Reason: "Away"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.current(); got != tt.want {
+				t.Errorf("current() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAwayActionPublishesChanges(t *testing.T) {
+	c := New(device.NewFake())
+	rec := recordAway(c.bus)
+
+	for _, ref := range []string{"away:on", "away:on", "away:toggle", "away:off"} {
+		if err := c.ExternalAction(ref); err != nil {
+			t.Fatalf("ExternalAction(%q): %v", ref, err)
+		}
+	}
+
+	// The repeated "on" and the "off" after toggling off change nothing
+	want := []bus.Away{{On: true, Reason: "Away"}, {}}
+	got := rec.published()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("published %+v, want %+v", got, want)
+	}
+
+	if err := c.ExternalAction("away:maybe"); err == nil {
+		t.Error("unknown away action accepted")
+	}
+}
+
+func TestAwayFollowsDND(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.AwayOnDND = true
+	c.setupAway()
+	rec := recordAway(c.bus)
+
+	c.bus.Publish(bus.TopicDND, true)
+	if got, want := c.Away(), (bus.Away{On: true, Reason: "Focus"}); got != want {
+		t.Errorf("Away() with DND on = %+v, want %+v", got, want)
+	}
+
+	c.bus.Publish(bus.TopicDND, false)
+	if got := c.Away(); got.On {
+		t.Errorf("Away() with DND off = %+v, want off", got)
+	}
+	if got := len(rec.published()); got != 2 {
+		t.Errorf("published %d away events, want 2", got)
+	}
+}
+
+func TestAwayCalendarEvent(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	startCoordinator(t, c, dev)
+
+	fake := &runner.Fake{Outputs: map[string]string{
+		"sh -c next-event": "  Standup\nLunch\n",
+	}}
+	saved := runner.Default
+	runner.Default = fake
+	t.Cleanup(func() { runner.Default = saved })
+
+	c.config.AwayCalendarCommand = "next-event"
+	c.checkAwayCalendar()
+	if got, want := c.Away(), (bus.Away{On: true, Reason: "Standup"}); got != want {
+		t.Errorf("Away() during an event = %+v, want %+v", got, want)
+	}
+
+	fake.Outputs["sh -c next-event"] = ""
+	c.checkAwayCalendar()
+	if got := c.Away(); got.On {
+		t.Errorf("Away() after the event = %+v, want off", got)
+	}
+}
@@ -0,0 +1,45 @@
+package coordinator
+
+import (
+	"log"
+	"time"
+)
+
+// SetBrightness requests a device brightness in percent. It's safe to call
+// from any goroutine and as often as needed: requests are coalesced, and
+// only the latest is written to the device on the next render cycle, and
+// only if it differs from what the device already has.
+func (c *Coordinator) SetBrightness(perc byte) {
+	if perc > 100 {
+		perc = 100
+	}
+
+	c.brightnessMu.Lock()
+	c.brightnessTarget = int(perc)
+	c.brightnessMu.Unlock()
+
+	c.requestRender()
+}
+
+// applyBrightness writes the pending brightness request, if any, to the
+// device, dimmed or mid-fade if the deck is idle. Called from the render
+// loop so device writes stay serialized.
+func (c *Coordinator) applyBrightness() {
+	c.brightnessMu.Lock()
+	target := c.brightnessLevel(time.Now())
+	if target < 0 || target == c.brightnessWritten {
+		c.brightnessMu.Unlock()
+		return
+	}
+	c.brightnessWritten = target
+	c.brightnessMu.Unlock()
+
+	if err := c.device.SetBrightness(byte(target)); err != nil {
+		log.Printf("Failed to set brightness: %v", err)
+
+		// Retry on the next cycle
+		c.brightnessMu.Lock()
+		c.brightnessWritten = -1
+		c.brightnessMu.Unlock()
+	}
+}
@@ -0,0 +1,53 @@
+package coordinator
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// brightnessDevice is a fake device that records brightness writes.
+type brightnessDevice struct {
+	*device.Fake
+
+	mu     sync.Mutex
+	writes []byte
+}
+
+func (d *brightnessDevice) SetBrightness(perc byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.writes = append(d.writes, perc)
+	return nil
+}
+
+func (d *brightnessDevice) brightnessWrites() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]byte(nil), d.writes...)
+}
+
+func TestBrightnessRequestsCoalesced(t *testing.T) {
+	dev := &brightnessDevice{Fake: device.NewFake()}
+	c := New(dev)
+	c.config.DimTimeout = 0
+
+	// Only the latest of several requests is written
+	c.SetBrightness(20)
+	c.SetBrightness(80)
+	c.SetBrightness(50)
+	c.applyBrightness()
+	c.applyBrightness()
+
+	// Repeating the current level writes nothing; out-of-range levels clamp
+	c.SetBrightness(50)
+	c.applyBrightness()
+	c.SetBrightness(150)
+	c.applyBrightness()
+
+	if got, want := dev.brightnessWrites(), []byte{50, 100}; !slices.Equal(got, want) {
+		t.Errorf("brightness writes = %v, want %v", got, want)
+	}
+}
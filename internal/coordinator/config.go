@@ -0,0 +1,230 @@
+package coordinator
+
+import (
+	"image/color"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// Config holds coordinator-wide behavior settings.
+type Config struct {
+	// KeyCooldown drops key presses that arrive within this window of the
+	// last accepted press on the same key. Zero disables the cooldown.
+	KeyCooldown time.Duration
+
+	// SnoozeHold is how long a module's key must be held to snooze (or
+	// unsnooze) that module. Zero, the default, disables snoozing; when it's
+	// enabled, presses on snoozable modules' keys are delivered at release
+	// so a snoozing hold doesn't act on the module first.
+	SnoozeHold time.Duration
+
+	// SnoozeDuration is how long a snoozed module stays silenced.
+	SnoozeDuration time.Duration
+
+	// StandbyTimeout is how long the deck must be idle before it goes to
+	// standby. Devices with native standby support show the last rendered
+	// strip; others are blanked until the next input. Zero disables standby.
+	StandbyTimeout time.Duration
+
+	// DimTimeout is how long the deck must be idle before its brightness
+	// fades down to DimBrightness. Input fades it back up. Zero disables
+	// dimming.
+	DimTimeout time.Duration
+
+	// DimBrightness is the brightness floor, in percent, the deck dims to,
+	// so it never looks switched off.
+	DimBrightness int
+
+	// DimFade is how long dimming and undimming fade over. Zero switches
+	// instantly.
+	DimFade time.Duration
+
+	// SlowRender is the render duration above which a module's render call
+	// is logged as slow. Zero disables the warning.
+	SlowRender time.Duration
+
+	// MetricsAddr is the listen address for the /metrics endpoint (e.g.
+	// "127.0.0.1:9091"). Empty disables the endpoint.
+	MetricsAddr string
+
+	// ControlSocket is the path of the unix socket accepting external
+	// actions (see ExternalAction). Empty disables the socket.
+	ControlSocket string
+
+	// StripMode is StripSplit to divide the strip between modules,
+	// StripCycle to show one module at a time across the full strip, or
+	// StripOff to leave the strip unused.
+	StripMode string
+
+	// StripCycleInterval is how long each module is shown in cycle mode
+	// before moving to the next. Zero cycles only on swipes.
+	StripCycleInterval time.Duration
+
+	// FlashDuration is how long a key flashes to confirm an action. Zero
+	// disables flashing.
+	FlashDuration time.Duration
+
+	// FlashSuccessColor and FlashErrorColor tint a key flashed for a
+	// successful or failed action.
+	FlashSuccessColor color.Color
+	FlashErrorColor   color.Color
+
+	// SpareKeys is the ID of the module given every key no other module
+	// owns, e.g. "bookmarks". Empty leaves spare keys blank.
+	SpareKeys string
+
+	// AwayOnDND marks the deck away while Do Not Disturb (Focus) is on,
+	// which also covers calendar apps that turn on Focus during meetings.
+	AwayOnDND bool
+
+	// AwayCalendarCommand is a shell command printing the title of the
+	// current calendar event, or nothing when there is none (e.g. with
+	// icalBuddy). The deck is away while it prints a title. Empty disables
+	// calendar polling.
+	AwayCalendarCommand string
+
+	// AwayCalendarInterval is how often AwayCalendarCommand runs.
+	AwayCalendarInterval time.Duration
+
+	// PresentKey is a key reserved for toggling presentation mode, which
+	// blanks the deck and stops rendering modules. Zero reserves no key;
+	// the "present" external action works either way.
+	PresentKey module.KeyID
+
+	// PresentImage is the path of an image shown on the strip during
+	// presentation mode. Empty blanks the strip.
+	PresentImage string
+
+	// DiagnosticsHold is how long a dial must be held to show the
+	// diagnostics overlay. Zero disables the gesture; the "diagnostics"
+	// external action works either way.
+	DiagnosticsHold time.Duration
+}
+
+// loadConfig loads configuration from environment variables.
+// All settings are optional; invalid values are logged and ignored.
+func loadConfig() Config {
+	var config Config
+
+	config.KeyCooldown = durationEnv("BELOWDECK_KEY_COOLDOWN", 0)
+	config.SnoozeHold = durationEnv("BELOWDECK_SNOOZE_HOLD", 0)
+	config.SnoozeDuration = durationEnv("BELOWDECK_SNOOZE_DURATION", time.Hour)
+	config.StandbyTimeout = durationEnv("BELOWDECK_STANDBY_TIMEOUT", 0)
+	config.DimTimeout = durationEnv("BELOWDECK_DIM_TIMEOUT", 0)
+	config.DimBrightness = percentEnv("BELOWDECK_DIM_BRIGHTNESS", 10)
+	config.DimFade = durationEnv("BELOWDECK_DIM_FADE", time.Second)
+	config.SlowRender = durationEnv("BELOWDECK_SLOW_RENDER", 100*time.Millisecond)
+	config.MetricsAddr = os.Getenv("BELOWDECK_METRICS_ADDR")
+	config.ControlSocket = os.Getenv("BELOWDECK_SOCKET")
+	config.SpareKeys = os.Getenv("BELOWDECK_SPARE_KEYS")
+	config.StripMode = stripModeEnv("BELOWDECK_STRIP_MODE")
+	config.StripCycleInterval = durationEnv("BELOWDECK_STRIP_CYCLE", 15*time.Second)
+	config.FlashDuration = durationEnv("BELOWDECK_FLASH_DURATION", 400*time.Millisecond)
+	config.FlashSuccessColor = colorEnv("BELOWDECK_FLASH_SUCCESS", color.RGBA{40, 180, 70, 255})
+	config.FlashErrorColor = colorEnv("BELOWDECK_FLASH_ERROR", color.RGBA{210, 45, 45, 255})
+	config.AwayOnDND = boolEnv("BELOWDECK_AWAY_ON_DND", false)
+	config.AwayCalendarCommand = os.Getenv("BELOWDECK_AWAY_CALENDAR")
+	config.AwayCalendarInterval = durationEnv("BELOWDECK_AWAY_CALENDAR_INTERVAL", time.Minute)
+	if config.AwayCalendarInterval <= 0 {
+		config.AwayCalendarInterval = time.Minute
+	}
+	config.PresentKey = keyEnv("BELOWDECK_PRESENT_KEY")
+	config.PresentImage = os.Getenv("BELOWDECK_PRESENT_IMAGE")
+	config.DiagnosticsHold = durationEnv("BELOWDECK_DIAGNOSTICS_HOLD", 5*time.Second)
+
+	return config
+}
+
+// durationEnv parses a duration from an environment variable, returning def if
+// the variable is unset or invalid.
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("Invalid %s %q, using default %v", name, v, def)
+		return def
+	}
+	return d
+}
+
+// percentEnv parses a percentage from 0 to 100 from an environment
+// variable, returning def if the variable is unset or invalid.
+func percentEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 || n > 100 {
+		log.Printf("Invalid %s %q, using default %v", name, v, def)
+		return def
+	}
+	return n
+}
+
+// keyEnv parses a key number, counting from 1, from an environment
+// variable, returning 0 if the variable is unset or invalid.
+func keyEnv(name string) module.KeyID {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > 255 {
+		log.Printf("Invalid %s %q, ignoring", name, v)
+		return 0
+	}
+	return module.KeyID(n)
+}
+
+// boolEnv parses a boolean from an environment variable, returning def if
+// the variable is unset or invalid.
+func boolEnv(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %v", name, v, def)
+		return def
+	}
+	return b
+}
+
+// stripModeEnv reads a strip mode from an environment variable, defaulting
+// to StripSplit if it's unset or invalid.
+func stripModeEnv(name string) string {
+	switch v := os.Getenv(name); v {
+	case "", StripSplit:
+		return StripSplit
+	case StripCycle, StripOff:
+		return v
+	default:
+		log.Printf("Invalid %s %q, using %q", name, v, StripSplit)
+		return StripSplit
+	}
+}
+
+// colorEnv parses a hex color such as "#28b446" from an environment
+// variable, returning def if the variable is unset or invalid.
+func colorEnv(name string, def color.RGBA) color.RGBA {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	c, err := render.ParseHexColor(v)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default: %v", name, v, err)
+		return def
+	}
+	return c
+}
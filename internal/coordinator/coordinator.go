@@ -3,35 +3,82 @@ package coordinator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
 	"log"
 	"sync"
 	"time"
 
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/control"
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/metrics"
 	"github.com/phinze/belowdeck/internal/module"
 )
 
+// colorStripBg is the base color modules' strip output is composited onto.
+var colorStripBg = color.RGBA{25, 25, 25, 255}
+
+// maxConcurrentRenders bounds how many modules render at the same time.
+const maxConcurrentRenders = 4
+
 // Coordinator manages the lifecycle of modules and routes events to them.
 type Coordinator struct {
 	device  device.Device
 	modules []module.Module
+	config  Config
+	bus     *bus.Bus
+	metrics *metrics.Registry
 
-	// Resource tracking
+	// Resource tracking, guarded by mu. Modules can't be registered once
+	// Start has begun, but resources are adjusted as Start sets up.
 	moduleResources map[module.Module]module.Resources
 
-	// Ownership maps for event routing
+	// Ownership maps for event routing, guarded by mu
 	keyOwners  map[module.KeyID]module.Module
 	dialOwners map[module.DialID]module.Module
 
-	// Track modules that failed to initialize
-	failedModules map[module.Module]bool
+	// Whether Start has begun, after which modules is fixed
+	started bool
+
+	// Each started module's context, and the modules that failed to
+	// initialize or were stopped, guarded by moduleMu
+	moduleMu       sync.RWMutex
+	moduleRuns     map[module.Module]*moduleRun
+	failedModules  map[module.Module]bool
+	stoppedModules map[module.Module]bool
 
 	// Strip compositing
 	stripRect image.Rectangle
 
-	// Lifecycle
+	// Setup help shown on the strip when no modules are active, rendered
+	// on first use
+	helpStrip *image.RGBA
+
+	// Strip cycle mode: the modules taking turns on the strip and which one
+	// is showing
+	stripCycleMu sync.Mutex
+	stripModules []module.Module
+	stripActive  int
+	stripShownAt time.Time
+
+	// Strip write failure tracking
+	stripMu       sync.Mutex
+	stripFailures int
+	stripDisabled bool
+
+	// Per-module strip render times and images, for modules with a strip
+	// interval
+	stripSchedule stripSchedule
+
+	// Each module's last frame, for modules that miss the render deadline
+	renders moduleRenders
+
+	// Lifecycle: wg counts the coordinator's own goroutines; each module's
+	// are counted in its moduleRun
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
@@ -41,26 +88,87 @@ type Coordinator struct {
 
 	// Overlay state tracking
 	overlayWasActive bool
+
+	// Key cooldown tracking (last accepted press per key)
+	cooldownMu sync.Mutex
+	lastPress  map[module.KeyID]time.Time
+
+	// Key confirmation flashes
+	flashMu   sync.Mutex
+	flashes   map[module.KeyID]flashState
+	renderNow chan struct{}
+
+	// Brightness requests, coalesced to one write per render cycle. -1 means
+	// none requested or nothing written yet.
+	brightnessMu      sync.Mutex
+	brightnessTarget  int
+	brightnessWritten int
+
+	// Idle dimming: whether the deck is dimmed, and the level and time the
+	// current fade started from. fadeFrom is -1 before the first fade.
+	dimmed    bool
+	fadeFrom  int
+	fadeStart time.Time
+
+	// Standby tracking
+	standbyMu       sync.Mutex
+	lastActivity    time.Time
+	inStandby       bool
+	standbyImageSet time.Time
+
+	// Away state: the sources that can set it and the last state published
+	awayMu      sync.Mutex
+	awaySources awayState
+	away        bus.Away
+
+	// Presentation mode: whether it's on, whether the deck has been blanked
+	// for it yet, and the strip image shown meanwhile, if any
+	presentMu    sync.Mutex
+	presenting   bool
+	presentShown bool
+	presentImage image.Image
+
+	// Diagnostics overlay, and when Start began for its uptime
+	diagnostics diagnostics
+	startTime   time.Time
 }
 
 // New creates a new Coordinator for the given device.
 func New(dev device.Device) *Coordinator {
-	return &Coordinator{
-		device:          dev,
-		modules:         make([]module.Module, 0),
-		moduleResources: make(map[module.Module]module.Resources),
-		keyOwners:       make(map[module.KeyID]module.Module),
-		dialOwners:      make(map[module.DialID]module.Module),
-		failedModules:   make(map[module.Module]bool),
+	c := &Coordinator{
+		device:            dev,
+		modules:           make([]module.Module, 0),
+		config:            loadConfig(),
+		bus:               bus.New(),
+		metrics:           metrics.New(),
+		moduleResources:   make(map[module.Module]module.Resources),
+		keyOwners:         make(map[module.KeyID]module.Module),
+		dialOwners:        make(map[module.DialID]module.Module),
+		moduleRuns:        make(map[module.Module]*moduleRun),
+		failedModules:     make(map[module.Module]bool),
+		stoppedModules:    make(map[module.Module]bool),
+		lastPress:         make(map[module.KeyID]time.Time),
+		flashes:           make(map[module.KeyID]flashState),
+		renderNow:         make(chan struct{}, 1),
+		brightnessTarget:  -1,
+		brightnessWritten: -1,
+		fadeFrom:          -1,
 	}
+	c.diagnostics.c = c
+	return c
 }
 
 // RegisterModule registers a module with its allocated resources.
-// Must be called before Start.
+// Must be called before Start; later registrations are rejected, since the
+// module would never be initialized.
 func (c *Coordinator) RegisterModule(m module.Module, res module.Resources) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.started {
+		return fmt.Errorf("module %s registered after Start", m.ID())
+	}
+
 	// Store resources for this module
 	c.moduleResources[m] = res
 
@@ -72,6 +180,16 @@ func (c *Coordinator) RegisterModule(m module.Module, res module.Resources) erro
 		c.dialOwners[dial] = m
 	}
 
+	// Share the event bus with modules that use it
+	if bu, ok := m.(module.BusUser); ok {
+		bu.SetBus(c.bus)
+	}
+
+	// Let modules confirm actions by flashing their keys
+	if fu, ok := m.(module.FlashUser); ok {
+		fu.SetFlasher(c)
+	}
+
 	// Track module
 	c.modules = append(c.modules, m)
 
@@ -80,28 +198,66 @@ func (c *Coordinator) RegisterModule(m module.Module, res module.Resources) erro
 
 // Start initializes all modules and begins the event/render loop.
 func (c *Coordinator) Start(ctx context.Context) error {
+	// Fix the module list, so it can be read without locking from here on
+	c.mu.Lock()
+	c.started = true
+	c.mu.Unlock()
+
 	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.lastActivity = time.Now()
+	c.startTime = c.lastActivity
 
-	// Get full strip rectangle for compositing
-	if c.device.GetTouchStripSupported() {
+	// Get full strip rectangle for compositing, unless the strip is off
+	if c.device.GetTouchStripSupported() && c.config.StripMode != StripOff {
 		rect, err := c.device.GetTouchStripImageRectangle()
 		if err == nil {
 			c.stripRect = rect
 		}
 	}
 
-	// Initialize all modules (continue on error, just skip failed modules)
+	// Hand unowned keys to the configured module before it initializes,
+	// then take back the presentation key
+	c.assignSpareKeys()
+	c.reservePresentKey()
+	c.loadPresentImage()
+
+	// In cycle mode, strip modules lay out for the full strip; with the
+	// strip off, they get none
+	c.setupStripCycle()
+	c.setupStripOff()
+
+	// Follow Do Not Disturb for the away state before modules start
+	// publishing it
+	c.setupAway()
+
+	// Initialize all modules (continue on error, just skip failed modules),
+	// each under its own context so it can be stopped or restarted alone.
+	// Their supervised goroutines are counted so Stop can wait for them.
 	for _, m := range c.modules {
-		res := c.resourcesForModule(m)
-		if err := m.Init(c.ctx, res); err != nil {
+		if err := c.startModule(m); err != nil {
 			log.Printf("Module %s failed to initialize: %v (skipping)", m.ID(), err)
-			c.failedModules[m] = true
 		}
 	}
+	if c.noActiveModules() {
+		log.Println("No modules are active; showing setup help on the deck")
+	}
 
 	// Setup event handlers
 	c.setupEventHandlers()
 
+	// Poll the calendar for the away state once modules are listening
+	c.startAwayCalendar()
+
+	// Push the first frame now rather than on the render loop, so the deck
+	// shows every module's initial state (loading, if its first fetch
+	// hasn't finished) as soon as it connects. This also loads each
+	// module's fonts and icons before the loop's first tick.
+	c.render()
+
+	// Count the render loop before the listener starts, since once input
+	// arrives Stop may be called and wait for it
+	c.wg.Add(1)
+
 	// Start device listener
 	listenErr := make(chan error, 1)
 	go func() {
@@ -112,8 +268,21 @@ func (c *Coordinator) Start(ctx context.Context) error {
 		close(listenErr)
 	}()
 
+	// Expose render metrics if configured
+	if c.config.MetricsAddr != "" {
+		go metrics.Serve(c.ctx, c.config.MetricsAddr, c.metrics)
+	}
+
+	// Accept actions from hotkey tools and scripts if configured
+	if c.config.ControlSocket != "" {
+		go func() {
+			if err := control.Serve(c.ctx, c.config.ControlSocket, c.ExternalAction); err != nil {
+				log.Printf("Control socket: %v", err)
+			}
+		}()
+	}
+
 	// Start render loop
-	c.wg.Add(1)
 	go c.renderLoop()
 
 	// Wait for context cancellation or device disconnect
@@ -126,30 +295,51 @@ func (c *Coordinator) Start(ctx context.Context) error {
 	}
 }
 
-// Stop gracefully shuts down all modules.
+// Stop gracefully shuts down all modules. It stops the render loop first,
+// so nothing renders a module or writes to the device while modules shut
+// down, then stops the modules and waits for their goroutines, including
+// any subprocesses they run, to exit. The device can be closed once Stop
+// returns.
 func (c *Coordinator) Stop() error {
 	if c.cancel != nil {
 		c.cancel()
 	}
+	c.wg.Wait()
 
 	// Stop all modules
-	for _, m := range c.modules {
-		m.Stop()
-	}
-
-	c.wg.Wait()
+	c.stopModules()
 	return nil
 }
 
 // resourcesForModule returns the stored resources for a module.
 func (c *Coordinator) resourcesForModule(m module.Module) module.Resources {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.moduleResources[m]
 }
 
-// getActiveOverlay returns the active overlay provider, if any.
+// keyOwner returns the module owning key, or nil if none does.
+func (c *Coordinator) keyOwner(key module.KeyID) module.Module {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.keyOwners[key]
+}
+
+// dialOwner returns the module owning dial, or nil if none does.
+func (c *Coordinator) dialOwner(dial module.DialID) module.Module {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dialOwners[dial]
+}
+
+// getActiveOverlay returns the active overlay provider, if any. The
+// diagnostics overlay comes before any module's.
 func (c *Coordinator) getActiveOverlay() module.OverlayProvider {
+	if c.diagnostics.IsOverlayActive() {
+		return &c.diagnostics
+	}
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if c.isInactive(m) || c.isSnoozed(m) {
 			continue
 		}
 		if overlay, ok := m.(module.OverlayProvider); ok && overlay.IsOverlayActive() {
@@ -161,51 +351,91 @@ func (c *Coordinator) getActiveOverlay() module.OverlayProvider {
 
 // setupEventHandlers registers device event handlers that route to modules.
 func (c *Coordinator) setupEventHandlers() {
-	// Key handlers - register for ALL keys, not just owned ones
-	allKeys := []module.KeyID{
-		module.Key1, module.Key2, module.Key3, module.Key4,
-		module.Key5, module.Key6, module.Key7, module.Key8,
-	}
-
-	for _, keyID := range allKeys {
+	// Key handlers - register for ALL keys, not just owned ones. Owners
+	// are looked up as events arrive rather than read from the maps here.
+	for _, keyID := range c.allKeys() {
 		key := keyID
-		owner := c.keyOwners[key] // may be nil for unowned keys
 		c.device.AddKeyHandler(device.KeyID(key), func(d device.Device, k device.Key) error {
+			// The first press after standby only wakes the deck
+			if c.noteActivity() {
+				return nil
+			}
+
+			// Drop bouncy or impatient re-presses within the cooldown window
+			if !c.acceptKeyPress(key) {
+				return nil
+			}
+
+			// The presentation key toggles presentation mode, during
+			// which other presses are ignored
+			if key == c.config.PresentKey {
+				c.setPresenting(!c.Presenting())
+				return nil
+			}
+			if c.Presenting() {
+				return nil
+			}
+
 			// Check for active overlay first
 			if overlay := c.getActiveOverlay(); overlay != nil {
 				// Route to overlay handler
-				event := module.KeyEvent{Pressed: true}
-				if err := overlay.HandleOverlayKey(key, event); err != nil {
-					return err
-				}
+				pressErr := overlay.HandleOverlayKey(key, module.KeyEvent{Pressed: true})
 				duration := k.WaitForRelease()
-				event = module.KeyEvent{Pressed: false, Duration: duration}
-				return overlay.HandleOverlayKey(key, event)
+				releaseErr := overlay.HandleOverlayKey(key, module.KeyEvent{Pressed: false, Duration: duration})
+				return errors.Join(pressErr, releaseErr)
 			}
 
 			// No overlay - route to owner if exists
-			if owner == nil || c.failedModules[owner] {
+			owner := c.keyOwner(key) // may be nil for unowned keys
+			if owner == nil || c.isInactive(owner) {
 				return nil
 			}
-			// Create press event
-			event := module.KeyEvent{Pressed: true}
-			if err := owner.HandleKey(key, event); err != nil {
-				return err
+
+			// Snoozed modules ignore presses; a long hold wakes them up
+			if c.isSnoozed(owner) {
+				if duration := k.WaitForRelease(); c.isSnoozeHold(duration) {
+					c.toggleSnooze(owner)
+				}
+				return nil
+			}
+
+			// A key that may be held to snooze its module gets its press
+			// at release, so a snoozing hold doesn't act on the module first
+			if c.canSnoozeFrom(owner, key) {
+				duration := k.WaitForRelease()
+				if c.isSnoozeHold(duration) {
+					c.toggleSnooze(owner)
+					return nil
+				}
+				pressErr := owner.HandleKey(key, module.KeyEvent{Pressed: true})
+				releaseErr := owner.HandleKey(key, module.KeyEvent{Pressed: false, Duration: duration})
+				if duration >= module.RefreshHold {
+					c.refresh(owner)
+				}
+				return errors.Join(pressErr, releaseErr)
 			}
 
-			// Wait for release and create release event
+			// Deliver the press, then the release even if the press
+			// failed, so momentary actions are always turned back off
+			pressErr := owner.HandleKey(key, module.KeyEvent{Pressed: true})
 			duration := k.WaitForRelease()
-			event = module.KeyEvent{Pressed: false, Duration: duration}
-			return owner.HandleKey(key, event)
+			releaseErr := owner.HandleKey(key, module.KeyEvent{Pressed: false, Duration: duration})
+			if duration >= module.RefreshHold {
+				c.refresh(owner)
+			}
+			return errors.Join(pressErr, releaseErr)
 		})
 	}
 
-	// Dial rotation handlers
-	for dialID, m := range c.dialOwners {
+	// Dial rotation handlers, for all dials like keys
+	for _, dialID := range c.allDials() {
 		dial := dialID
-		mod := m
 		c.device.AddDialRotateHandler(device.DialID(dial), func(d device.Device, di device.Dial, delta int8) error {
-			if c.failedModules[mod] {
+			if c.noteActivity() {
+				return nil
+			}
+			mod := c.dialOwner(dial)
+			if mod == nil || c.isInactive(mod) || c.isSnoozed(mod) || c.Presenting() {
 				return nil
 			}
 			event := module.DialEvent{
@@ -217,30 +447,45 @@ func (c *Coordinator) setupEventHandlers() {
 	}
 
 	// Dial press handlers
-	for dialID, m := range c.dialOwners {
+	for _, dialID := range c.allDials() {
 		dial := dialID
-		mod := m
 		c.device.AddDialSwitchHandler(device.DialID(dial), func(d device.Device, di device.Dial) error {
-			if c.failedModules[mod] {
+			if c.noteActivity() {
 				return nil
 			}
+			mod := c.dialOwner(dial)
+			routed := mod != nil && !c.isInactive(mod) && !c.isSnoozed(mod) && !c.Presenting()
+
 			// Create press event
-			event := module.DialEvent{Type: module.DialPress}
-			if err := mod.HandleDial(dial, event); err != nil {
-				return err
+			if routed {
+				event := module.DialEvent{Type: module.DialPress}
+				if err := mod.HandleDial(dial, event); err != nil {
+					return err
+				}
 			}
 
-			// Wait for release and create release event
+			// Wait for release; a long enough hold of any dial shows
+			// diagnostics instead of releasing it to the module
 			duration := di.WaitForRelease()
-			event = module.DialEvent{Type: module.DialRelease, Duration: duration}
+			if c.isDiagnosticsHold(duration) && !c.Presenting() {
+				c.diagnostics.show()
+				return nil
+			}
+			if !routed {
+				return nil
+			}
+			event := module.DialEvent{Type: module.DialRelease, Duration: duration}
 			return mod.HandleDial(dial, event)
 		})
 	}
 
 	// Touch strip handler - route based on X coordinate
-	if c.device.GetTouchStripSupported() {
+	if c.device.GetTouchStripSupported() && c.config.StripMode != StripOff {
 		c.device.AddTouchStripTouchHandler(func(d device.Device, touchType device.TouchStripTouchType, point image.Point) error {
-			event := module.TouchStripEventFromDeviceTap(touchType, point)
+			if c.noteActivity() || c.Presenting() {
+				return nil
+			}
+			event := module.TouchStripEventFromDeviceTap(touchType, point).Normalized(c.stripRect)
 			// Check for active overlay first
 			if overlay := c.getActiveOverlay(); overlay != nil {
 				return overlay.HandleOverlayStripTouch(event)
@@ -249,7 +494,10 @@ func (c *Coordinator) setupEventHandlers() {
 		})
 
 		c.device.AddTouchStripSwipeHandler(func(d device.Device, origin, dest image.Point) error {
-			event := module.TouchStripEventFromSwipe(origin, dest)
+			if c.noteActivity() || c.Presenting() {
+				return nil
+			}
+			event := module.TouchStripEventFromSwipe(origin, dest).Normalized(c.stripRect)
 			// Check for active overlay first
 			if overlay := c.getActiveOverlay(); overlay != nil {
 				return overlay.HandleOverlayStripTouch(event)
@@ -259,16 +507,40 @@ func (c *Coordinator) setupEventHandlers() {
 	}
 }
 
+// acceptKeyPress reports whether a press on the given key should be handled.
+// Presses arriving within the configured cooldown of the last accepted press
+// on the same key are dropped. Always accepts when the cooldown is disabled.
+func (c *Coordinator) acceptKeyPress(key module.KeyID) bool {
+	if c.config.KeyCooldown <= 0 {
+		return true
+	}
+
+	c.cooldownMu.Lock()
+	defer c.cooldownMu.Unlock()
+
+	now := time.Now()
+	if last, ok := c.lastPress[key]; ok && now.Sub(last) < c.config.KeyCooldown {
+		return false
+	}
+	c.lastPress[key] = now
+	return true
+}
+
 // routeStripEvent finds the owning module for a strip event and dispatches it.
 func (c *Coordinator) routeStripEvent(event module.TouchStripEvent) error {
-	// For now, route to first module that has a strip region
-	// Future: check which module's strip rect contains the event point
-	for _, m := range c.modules {
-		if c.failedModules[m] {
+	if c.cyclingStrip() {
+		return c.routeCycledStripEvent(event)
+	}
+
+	// Route to the topmost module whose strip region was touched; swipes go
+	// by where they started
+	order := c.stripOrder()
+	for j := len(order) - 1; j >= 0; j-- {
+		m := c.modules[order[j]]
+		if c.isInactive(m) || c.isSnoozed(m) {
 			continue
 		}
-		res := c.resourcesForModule(m)
-		if res.HasStrip() {
+		if event.Point.In(c.resourcesForModule(m).StripRect) {
 			return m.HandleStripTouch(event)
 		}
 	}
@@ -282,38 +554,181 @@ func (c *Coordinator) renderLoop() {
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
 
-	// Initial render
-	c.renderKeys()
-	c.renderStrip()
+	// Step brightness fades quickly, without rendering modules each step
+	var fadeTick <-chan time.Time
+	if c.config.DimTimeout > 0 {
+		fade := time.NewTicker(fadeStep)
+		defer fade.Stop()
+		fadeTick = fade.C
+	}
 
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
+		case <-fadeTick:
+			c.applyBrightness()
+			continue
 		case <-ticker.C:
-			c.renderKeys()
-			c.renderStrip()
+		case <-c.renderNow:
+		}
+
+		c.checkDim()
+
+		// Leave the deck blank while in software standby or presenting
+		if c.checkStandby() || c.checkPresentation() {
+			continue
+		}
+		c.render()
+	}
+}
+
+// recordRender records a module's render duration and warns when it is slow
+// enough to hold up the shared render loop.
+func (c *Coordinator) recordRender(m module.Module, kind string, d time.Duration) {
+	c.metrics.ObserveRender(m.ID(), kind, d)
+	if c.config.SlowRender > 0 && d > c.config.SlowRender {
+		log.Printf("Slow render: module %s %s took %v (threshold %v)", m.ID(), kind, d, c.config.SlowRender)
+	}
+}
+
+// frame holds one module's rendered output for a render cycle.
+type frame struct {
+	keys  map[module.KeyID]image.Image
+	strip image.Image
+
+	// stripFresh is set if strip was rendered this cycle rather than
+	// reused from an earlier one.
+	stripFresh bool
+}
+
+// render runs one render cycle. Modules render concurrently so a slow module
+// doesn't hold up the others; the results are then written to the device
+// from this goroutine only, keeping device I/O serialized.
+func (c *Coordinator) render() {
+	c.applyBrightness()
+	if c.noActiveModules() && !c.diagnostics.IsOverlayActive() {
+		c.renderHelp()
+		return
+	}
+	c.advanceStripCycle()
+
+	var frames []frame
+	if c.getActiveOverlay() == nil {
+		frames = c.collectFrames()
+	}
+	c.renderKeys(frames)
+	c.renderStrip(frames)
+}
+
+// collectFrames renders every active module's keys and strip, running at
+// most maxConcurrentRenders modules at once. The returned frames are indexed
+// like c.modules; skipped modules leave an empty frame. Modules still
+// rendering after renderDeadline get their last frame instead; their
+// renders finish in the background and are shown on a later cycle.
+func (c *Coordinator) collectFrames() []frame {
+	frames := make([]frame, len(c.modules))
+	sem := make(chan struct{}, maxConcurrentRenders)
+	stripEnabled := c.stripEnabled()
+
+	type rendered struct {
+		i int
+		f frame
+	}
+	results := make(chan rendered, len(c.modules))
+	waiting := make(map[int]module.Module)
+
+	for i, m := range c.modules {
+		if c.isInactive(m) || c.isSnoozed(m) {
+			continue
+		}
+		if !c.renders.begin(m) {
+			frames[i] = c.renders.lastFrame(m)
+			continue
+		}
+		waiting[i] = m
+		c.wg.Add(1)
+		go func(i int, m module.Module) {
+			defer c.wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			f := c.renderFrame(m, stripEnabled)
+			if c.renders.finish(m, f) {
+				c.requestRender()
+				return
+			}
+			results <- rendered{i, f}
+		}(i, m)
+	}
+
+	deadline := time.NewTimer(renderDeadline)
+	defer deadline.Stop()
+	for len(waiting) > 0 {
+		select {
+		case r := <-results:
+			frames[r.i] = r.f
+			delete(waiting, r.i)
+		case <-deadline.C:
+			for i, m := range waiting {
+				frames[i] = c.renders.abandon(m)
+			}
+			return frames
+		}
+	}
+	return frames
+}
+
+// renderFrame renders m's keys, and its strip if it shows one this cycle.
+func (c *Coordinator) renderFrame(m module.Module, stripEnabled bool) frame {
+	var f frame
+	start := time.Now()
+	f.keys = m.RenderKeys()
+	c.recordRender(m, "keys", time.Since(start))
+
+	if stripEnabled && c.resourcesForModule(m).HasStrip() && c.showsStrip(m) {
+		start = time.Now()
+		if !c.stripDue(m, start) {
+			f.strip = c.cachedStrip(m)
+			return f
 		}
+		f.strip = m.RenderStrip()
+		f.stripFresh = true
+		c.recordStrip(m, f.strip, start)
+		c.recordRender(m, "strip", time.Since(start))
 	}
+	return f
 }
 
-// renderKeys collects key images from all modules and applies them to the device.
-func (c *Coordinator) renderKeys() {
+// renderKeys applies the collected key images to the device, or the active
+// overlay's keys if one is up.
+func (c *Coordinator) renderKeys(frames []frame) {
+	// The diagnostics overlay takes over all keys
+	if c.diagnostics.IsOverlayActive() {
+		c.setKeyImages(c.diagnostics.RenderOverlayKeys())
+		c.overlayWasActive = true
+		return
+	}
+
 	// Check for active overlays first
 	overlayActive := false
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if c.isInactive(m) || c.isSnoozed(m) {
 			continue
 		}
 		if overlay, ok := m.(module.OverlayProvider); ok && overlay.IsOverlayActive() {
 			overlayActive = true
 			// Overlay takes over all keys
+			start := time.Now()
 			keyImages := overlay.RenderOverlayKeys()
+			c.recordRender(m, "overlay_keys", time.Since(start))
+			images := make(map[module.KeyID]image.Image, len(keyImages))
 			for keyID, img := range keyImages {
 				if img != nil {
-					c.device.SetKeyImage(device.KeyID(keyID), img)
+					images[keyID] = img
 				}
 			}
+			c.setKeyImages(images)
 			c.overlayWasActive = true
 			return
 		}
@@ -326,64 +741,102 @@ func (c *Coordinator) renderKeys() {
 	}
 
 	// Normal rendering
-	for _, m := range c.modules {
-		if c.failedModules[m] {
+	images := make(map[module.KeyID]image.Image)
+	for i, m := range c.modules {
+		if c.isStopped(m) {
+			c.renderStoppedKeys(m, images)
+			continue
+		}
+		if c.isInactive(m) {
+			continue
+		}
+		if until := c.snoozedUntil(m); !until.IsZero() {
+			c.renderSnoozedKeys(m, until, images)
 			continue
 		}
-		keyImages := m.RenderKeys()
-		for keyID, img := range keyImages {
-			if img != nil {
-				c.device.SetKeyImage(device.KeyID(keyID), img)
+		if i >= len(frames) {
+			continue
+		}
+		for keyID, img := range frames[i].keys {
+			if img = c.flashed(keyID, img); img != nil {
+				images[keyID] = img
 			}
 		}
 	}
+	c.renderPresentKey(images)
+	c.setKeyImages(images)
 }
 
-// renderStrip composites strip images from all modules and applies to the device.
-func (c *Coordinator) renderStrip() {
-	if c.stripRect.Empty() {
+// renderStrip composites the collected strip images and applies them to the
+// device, or the active overlay's strip if one is up.
+func (c *Coordinator) renderStrip(frames []frame) {
+	if !c.stripEnabled() {
+		return
+	}
+
+	// The diagnostics overlay takes over the strip
+	if c.diagnostics.IsOverlayActive() {
+		c.writeStrip(c.diagnostics.RenderOverlayStrip())
+		c.markStripDirty()
 		return
 	}
 
 	// Check for active overlays first
 	for _, m := range c.modules {
-		if c.failedModules[m] {
+		if c.isInactive(m) || c.isSnoozed(m) {
 			continue
 		}
 		if overlay, ok := m.(module.OverlayProvider); ok && overlay.IsOverlayActive() {
 			// Overlay takes over the strip
+			start := time.Now()
 			stripImg := overlay.RenderOverlayStrip()
+			c.recordRender(m, "overlay_strip", time.Since(start))
 			if stripImg != nil {
-				c.device.SetTouchStripImage(stripImg)
+				c.writeStrip(stripImg)
 			}
+			c.markStripDirty()
 			return
 		}
 	}
 
-	// Create composite strip image
+	// Skip the write when every strip on it is unchanged
+	var mods []module.Module
+	fresh := false
+	for i, m := range c.modules {
+		if i >= len(frames) || c.isInactive(m) || c.isSnoozed(m) || frames[i].strip == nil {
+			continue
+		}
+		mods = append(mods, m)
+		fresh = fresh || frames[i].stripFresh
+	}
+	if !c.needsComposite(mods, fresh) {
+		return
+	}
+
+	// Create composite strip image on an opaque background so modules that
+	// leave pixels transparent composite onto a defined base
 	composite := image.NewRGBA(c.stripRect)
+	draw.Draw(composite, composite.Bounds(), &image.Uniform{colorStripBg}, image.Point{}, draw.Src)
 
-	// Collect and composite each module's strip output
-	for _, m := range c.modules {
-		if c.failedModules[m] {
+	// Composite each module's strip output, bottom layer first
+	for _, i := range c.stripOrder() {
+		m := c.modules[i]
+		if i >= len(frames) || c.isInactive(m) || c.isSnoozed(m) {
 			continue
 		}
 		res := c.resourcesForModule(m)
-		if !res.HasStrip() {
-			continue
-		}
-
-		stripImg := m.RenderStrip()
+		stripImg := frames[i].strip
 		if stripImg == nil {
 			continue
 		}
 
-		// Draw module's strip at its allocated region
-		// For now, we draw at 0,0 - in future, we'd use res.StripRect offset
-		draw.Draw(composite, stripImg.Bounds(), stripImg, image.Point{}, draw.Over)
+		// Alpha-composite the module's strip, clipped to its allocated region
+		region := res.StripRect.Intersect(stripImg.Bounds())
+		draw.Draw(composite, region, stripImg, region.Min, draw.Over)
 	}
 
-	c.device.SetTouchStripImage(composite)
+	c.writeStrip(composite)
+	c.updateStandbyImage(composite)
 }
 
 // Device returns the underlying device.
@@ -392,13 +845,13 @@ func (c *Coordinator) Device() device.Device {
 	return c.device
 }
 
+// Bus returns the event bus shared with modules.
+func (c *Coordinator) Bus() *bus.Bus {
+	return c.bus
+}
+
 // clearAllKeys sets all keys to black.
 func (c *Coordinator) clearAllKeys() {
-	allKeys := []module.KeyID{
-		module.Key1, module.Key2, module.Key3, module.Key4,
-		module.Key5, module.Key6, module.Key7, module.Key8,
-	}
-
 	// Create a black image for clearing
 	keyRect, err := c.device.GetKeyImageRectangle()
 	if err != nil {
@@ -406,7 +859,27 @@ func (c *Coordinator) clearAllKeys() {
 	}
 	blackImg := image.NewRGBA(keyRect)
 
-	for _, keyID := range allKeys {
-		c.device.SetKeyImage(device.KeyID(keyID), blackImg)
+	images := make(map[module.KeyID]image.Image)
+	for _, keyID := range c.allKeys() {
+		images[keyID] = blackImg
+	}
+	c.setKeyImages(images)
+}
+
+// allKeys returns every key on the device.
+func (c *Coordinator) allKeys() []module.KeyID {
+	keys := make([]module.KeyID, 0, c.device.GetKeyCount())
+	for k := 1; k <= int(c.device.GetKeyCount()); k++ {
+		keys = append(keys, module.KeyID(k))
+	}
+	return keys
+}
+
+// allDials returns all dial IDs for the device.
+func (c *Coordinator) allDials() []module.DialID {
+	dials := make([]module.DialID, 0, c.device.GetDialCount())
+	for d := 1; d <= int(c.device.GetDialCount()); d++ {
+		dials = append(dials, module.DialID(d))
 	}
+	return dials
 }
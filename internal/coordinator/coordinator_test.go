@@ -0,0 +1,115 @@
+package coordinator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestKeyCooldownDropsRepeatPresses(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.KeyCooldown = time.Hour
+
+	m := newStubModule("stub")
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1, 2}})
+	startCoordinator(t, c, dev)
+
+	pressKey(t, dev, device.KEY_1, 0)
+	pressKey(t, dev, device.KEY_1, 0)
+	if got := len(m.keyEvents()); got != 2 {
+		t.Errorf("got %d key events after a quick re-press, want 2 (one press and release)", got)
+	}
+
+	// The cooldown is per key
+	pressKey(t, dev, device.KEY_2, 0)
+	if got := len(m.keyEvents()); got != 4 {
+		t.Errorf("got %d key events after pressing another key, want 4", got)
+	}
+}
+
+func TestKeyCooldownDisabled(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.KeyCooldown = 0
+	for i := range 3 {
+		if !c.acceptKeyPress(1) {
+			t.Fatalf("press %d dropped with the cooldown disabled", i+1)
+		}
+	}
+}
+
+func TestStartRendersFirstFrame(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	red := color.RGBA{200, 0, 0, 255}
+	m := &keyModule{stubModule: newStubModule("red"), col: red}
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}})
+
+	// The device starts listening after Start's own render, well before the
+	// render loop's first tick
+	start := time.Now()
+	startCoordinator(t, c, dev)
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Fatalf("Start took %v to listen, too long to tell its render from the loop's", elapsed)
+	}
+
+	if m.renders.Load() != 1 {
+		t.Errorf("rendered %d times before listening, want 1", m.renders.Load())
+	}
+	key := dev.KeyImage(device.KEY_1)
+	if key == nil {
+		t.Fatal("no key image when the device started listening")
+	}
+	if got := rgbaAt(key, 36, 36); got != red {
+		t.Errorf("key = %v, want %v", got, red)
+	}
+}
+
+func TestSlowRenderWarns(t *testing.T) {
+	var logged bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.SlowRender = 10 * time.Millisecond
+	strip, _ := dev.GetTouchStripImageRectangle()
+	c.stripRect = strip
+
+	slow := newStubModule("slow")
+	slow.strip = func(rect image.Rectangle) image.Image {
+		time.Sleep(30 * time.Millisecond)
+		return fillStrip(color.RGBA{255, 0, 0, 255})(rect)
+	}
+	fast := newStubModule("fast")
+	fast.strip = fillStrip(color.RGBA{0, 255, 0, 255})
+	c.RegisterModule(slow, module.Resources{StripRect: strip})
+	c.RegisterModule(fast, module.Resources{Keys: []module.KeyID{1}})
+
+	c.renderFrame(slow, true)
+	c.renderFrame(fast, true)
+
+	out := logged.String()
+	if !strings.Contains(out, "Slow render: module slow strip") {
+		t.Errorf("log = %q, want a slow render warning for the slow strip", out)
+	}
+	if strings.Contains(out, "module fast") {
+		t.Errorf("log = %q, want no warning for the fast module", out)
+	}
+
+	// The timings are served as metrics too
+	rec := httptest.NewRecorder()
+	c.metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if want := `belowdeck_render_count_total{module="slow",kind="strip"} 1`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("metrics missing %q:\n%s", want, rec.Body)
+	}
+}
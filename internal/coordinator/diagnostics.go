@@ -0,0 +1,215 @@
+package coordinator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/draw"
+)
+
+// diagnosticsActionID addresses the diagnostics overlay in external
+// actions, e.g. "diagnostics:show".
+const diagnosticsActionID = "diagnostics"
+
+// diagnosticsDuration is how long the diagnostics overlay stays up.
+const diagnosticsDuration = 30 * time.Second
+
+// basicCharWidth is the advance of each character in the basic font.
+const basicCharWidth = 7
+
+var (
+	colorDiagBg    = color.RGBA{30, 30, 30, 255}
+	colorDiagLabel = color.RGBA{110, 110, 110, 255}
+	colorDiagValue = color.RGBA{255, 255, 255, 255}
+)
+
+// diagnostics is an overlay showing what the deck is and what's driving
+// it, for support and troubleshooting on a headless run: the device's
+// model, serial number, firmware and controls, and the daemon's version
+// and uptime. It's shown by holding a dial for DiagnosticsHold or with the
+// "diagnostics" action, and any key or strip tap dismisses it.
+type diagnostics struct {
+	c *Coordinator
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+// diagItem is one labeled value on the diagnostics overlay.
+type diagItem struct {
+	label, value string
+}
+
+// show puts the overlay up for diagnosticsDuration.
+func (d *diagnostics) show() {
+	d.mu.Lock()
+	d.until = time.Now().Add(diagnosticsDuration)
+	d.mu.Unlock()
+	d.c.requestRender()
+}
+
+// hide takes the overlay down.
+func (d *diagnostics) hide() {
+	d.mu.Lock()
+	d.until = time.Time{}
+	d.mu.Unlock()
+	d.c.invalidateStrips()
+	d.c.requestRender()
+}
+
+// IsOverlayActive reports whether the overlay is up.
+func (d *diagnostics) IsOverlayActive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return time.Now().Before(d.until)
+}
+
+// items returns the diagnostics shown, in order.
+func (d *diagnostics) items() []diagItem {
+	dev := d.c.device
+	serial, firmware := "unknown", "unknown"
+	if info, ok := dev.(device.InfoProvider); ok {
+		if s := info.GetSerialNumber(); s != "" {
+			serial = s
+		}
+		if fw, err := info.GetFirmwareVersion(); err == nil && fw != "" {
+			firmware = fw
+		}
+	}
+
+	return []diagItem{
+		{"Model", dev.GetModelName()},
+		{"Serial", serial},
+		{"Firmware", firmware},
+		{"Keys", fmt.Sprint(dev.GetKeyCount())},
+		{"Dials", fmt.Sprint(dev.GetDialCount())},
+		{"Version", buildVersion()},
+		{"Uptime", time.Since(d.c.startTime).Truncate(time.Second).String()},
+	}
+}
+
+// RenderOverlayKeys shows a diagnostic on each key, in order, with the
+// last key as a way back.
+func (d *diagnostics) RenderOverlayKeys() map[module.KeyID]image.Image {
+	keyRect, err := d.c.device.GetKeyImageRectangle()
+	if err != nil {
+		return nil
+	}
+
+	keys := d.c.allKeys()
+	items := d.items()
+	images := make(map[module.KeyID]image.Image, len(keys))
+	for i, keyID := range keys {
+		img := image.NewRGBA(keyRect)
+		draw.Draw(img, img.Bounds(), &image.Uniform{colorDiagBg}, image.Point{}, draw.Src)
+		centerX, centerY := keyRect.Dx()/2, keyRect.Dy()/2
+		switch {
+		case i == len(keys)-1:
+			drawCenteredBasic(img, "Back", centerX, centerY+4, colorDiagLabel)
+		case i < len(items):
+			drawCenteredBasic(img, items[i].label, centerX, centerY-6, colorDiagLabel)
+			drawCenteredBasic(img, fitKey(items[i].value, keyRect.Dx()), centerX, centerY+12, colorDiagValue)
+		}
+		images[keyID] = img
+	}
+	return images
+}
+
+// RenderOverlayStrip shows every diagnostic in full, for values too long
+// for a key: the device's on one line and the daemon's on the next.
+func (d *diagnostics) RenderOverlayStrip() image.Image {
+	rect := d.c.stripRect
+	img := image.NewRGBA(rect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorDiagBg}, image.Point{}, draw.Src)
+
+	items := d.items()
+	lines := [][]diagItem{items[:5], items[5:]}
+	centerX := rect.Min.X + rect.Dx()/2
+	y := rect.Min.Y + rect.Dy()/2 - 16
+	drawCenteredBasic(img, "belowdeck diagnostics", centerX, y, colorDiagLabel)
+	for _, line := range lines {
+		var parts []string
+		for _, item := range line {
+			parts = append(parts, strings.ToLower(item.label)+" "+item.value)
+		}
+		y += 20
+		drawCenteredBasic(img, strings.Join(parts, "   "), centerX, y, colorDiagValue)
+	}
+	return img
+}
+
+// HandleOverlayKey dismisses the overlay on any key press.
+func (d *diagnostics) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		d.hide()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch dismisses the overlay on a tap.
+func (d *diagnostics) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	if event.Type != module.TouchSwipe {
+		d.hide()
+	}
+	return nil
+}
+
+// isDiagnosticsHold reports whether a dial held this long shows the
+// diagnostics overlay.
+func (c *Coordinator) isDiagnosticsHold(held time.Duration) bool {
+	return c.config.DiagnosticsHold > 0 && held >= c.config.DiagnosticsHold
+}
+
+// handleDiagnosticsAction runs a "diagnostics:<action>" external action:
+// "show" and "hide" put the overlay up and take it down, and "toggle" flips
+// it.
+func (c *Coordinator) handleDiagnosticsAction(action string) error {
+	switch action {
+	case "show":
+		c.diagnostics.show()
+	case "hide":
+		c.diagnostics.hide()
+	case "toggle":
+		if c.diagnostics.IsOverlayActive() {
+			c.diagnostics.hide()
+		} else {
+			c.diagnostics.show()
+		}
+	default:
+		return fmt.Errorf("unknown diagnostics action %q (want show, hide or toggle)", action)
+	}
+	return nil
+}
+
+// buildVersion returns the daemon's module version, with the commit it was
+// built from when known.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	version := info.Main.Version
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && len(s.Value) >= 7 {
+			return version + " " + s.Value[:7]
+		}
+	}
+	return version
+}
+
+// fitKey shortens s, ending it with "..", to fit a key width pixels wide in
+// the basic font.
+func fitKey(s string, width int) string {
+	maxChars := width / basicCharWidth
+	if len(s) <= maxChars {
+		return s
+	}
+	return s[:maxChars-2] + ".."
+}
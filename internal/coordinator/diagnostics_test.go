@@ -0,0 +1,183 @@
+package coordinator
+
+import (
+	"image"
+	"image/draw"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// plainDevice hides a device's InfoProvider methods.
+type plainDevice struct {
+	device.Device
+}
+
+func TestDiagnosticsItemsFromDevice(t *testing.T) {
+	c := New(device.NewFake())
+
+	got := map[string]string{}
+	for _, item := range c.diagnostics.items() {
+		got[item.label] = item.value
+	}
+	want := map[string]string{
+		"Model":    "Fake Stream Deck +",
+		"Serial":   "FAKE00000001",
+		"Firmware": "1.00.000",
+		"Keys":     "8",
+		"Dials":    "4",
+	}
+	for label, value := range want {
+		if got[label] != value {
+			t.Errorf("%s = %q, want %q", label, got[label], value)
+		}
+	}
+	if got["Version"] == "" || got["Uptime"] == "" {
+		t.Errorf("version %q and uptime %q, want both shown", got["Version"], got["Uptime"])
+	}
+}
+
+func TestDiagnosticsWithoutDeviceInfo(t *testing.T) {
+	c := New(plainDevice{device.NewFake()})
+
+	for _, item := range c.diagnostics.items() {
+		if (item.label == "Serial" || item.label == "Firmware") && item.value != "unknown" {
+			t.Errorf("%s = %q, want unknown", item.label, item.value)
+		}
+	}
+}
+
+func TestDiagnosticsKeysShowDeviceInfo(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+
+	keys := c.diagnostics.RenderOverlayKeys()
+	if len(keys) != 8 {
+		t.Fatalf("rendered %d keys, want 8", len(keys))
+	}
+
+	// The second key shows the serial number
+	keyRect, _ := dev.GetKeyImageRectangle()
+	want := image.NewRGBA(keyRect)
+	draw.Draw(want, want.Bounds(), &image.Uniform{colorDiagBg}, image.Point{}, draw.Src)
+	drawCenteredBasic(want, "Serial", 60, 54, colorDiagLabel)
+	drawCenteredBasic(want, "FAKE00000001", 60, 72, colorDiagValue)
+	if !sameImage(keys[2], want) {
+		t.Error("serial key doesn't show the fake's serial number")
+	}
+}
+
+func TestDiagnosticsStripShowsDeviceInfo(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	strip, _ := dev.GetTouchStripImageRectangle()
+	c.stripRect = strip
+
+	got := c.diagnostics.RenderOverlayStrip()
+	want := image.NewRGBA(strip)
+	draw.Draw(want, want.Bounds(), &image.Uniform{colorDiagBg}, image.Point{}, draw.Src)
+	drawCenteredBasic(want, "model Fake Stream Deck +   serial FAKE00000001   firmware 1.00.000   keys 8   dials 4",
+		strip.Dx()/2, strip.Dy()/2+4, colorDiagValue)
+
+	// Only the device's line; the daemon's has the uptime in it
+	for y := 38; y <= 60; y++ {
+		for x := strip.Min.X; x < strip.Max.X; x++ {
+			if rgbaAt(got, x, y) != rgbaAt(want, x, y) {
+				t.Fatalf("strip differs at (%d, %d); want the device's info", x, y)
+			}
+		}
+	}
+}
+
+func TestDiagnosticsAction(t *testing.T) {
+	c := New(device.NewFake())
+
+	steps := []struct {
+		ref  string
+		want bool
+	}{
+		{"diagnostics:show", true},
+		{"diagnostics:toggle", false},
+		{"diagnostics:toggle", true},
+		{"diagnostics:hide", false},
+	}
+	for _, step := range steps {
+		if err := c.ExternalAction(step.ref); err != nil {
+			t.Fatalf("ExternalAction(%q): %v", step.ref, err)
+		}
+		if got := c.diagnostics.IsOverlayActive(); got != step.want {
+			t.Errorf("after %s overlay active = %v, want %v", step.ref, got, step.want)
+		}
+	}
+
+	if err := c.ExternalAction("diagnostics:maybe"); err == nil {
+		t.Error("unknown diagnostics action accepted")
+	}
+}
+
+func TestDiagnosticsDialHoldAndDismiss(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.DiagnosticsHold = 5 * time.Second
+
+	m := newStubModule("stub")
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}, Dials: []module.DialID{1}})
+	startCoordinator(t, c, dev)
+
+	if err := dev.Dispatch(device.InputEvent{Type: device.InputDialPress, Dial: 1, Held: time.Second}); err != nil {
+		t.Fatalf("dispatch dial press: %v", err)
+	}
+	if c.diagnostics.IsOverlayActive() {
+		t.Fatal("short dial press showed diagnostics")
+	}
+
+	if err := dev.Dispatch(device.InputEvent{Type: device.InputDialPress, Dial: 1, Held: 6 * time.Second}); err != nil {
+		t.Fatalf("dispatch dial hold: %v", err)
+	}
+	if !c.diagnostics.IsOverlayActive() {
+		t.Fatal("dial hold didn't show diagnostics")
+	}
+
+	// Dismissing it doesn't press the module's key
+	before := len(m.keyEvents())
+	pressKey(t, dev, device.KEY_1, 0)
+	if c.diagnostics.IsOverlayActive() {
+		t.Error("key press didn't dismiss diagnostics")
+	}
+	if got := len(m.keyEvents()); got != before {
+		t.Errorf("dismissing key press delivered %d key events to the module", got-before)
+	}
+}
+
+func TestFitKey(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"short", "short"},
+		{"exactly17charssss", "exactly17charssss"},
+		{"eighteen-chars-xyz", "eighteen-chars-.."},
+	}
+	for _, tt := range tests {
+		if got := fitKey(tt.s, 120); got != tt.want {
+			t.Errorf("fitKey(%q, 120) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+// sameImage reports whether two images have the same bounds and pixels.
+func sameImage(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if rgbaAt(a, x, y) != rgbaAt(b, x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
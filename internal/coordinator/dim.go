@@ -0,0 +1,85 @@
+package coordinator
+
+import (
+	"log"
+	"time"
+)
+
+// fadeStep is how often brightness is updated during a fade.
+const fadeStep = 50 * time.Millisecond
+
+// checkDim dims the deck once it has been idle for DimTimeout.
+func (c *Coordinator) checkDim() {
+	if c.config.DimTimeout <= 0 {
+		return
+	}
+
+	c.standbyMu.Lock()
+	idle := time.Since(c.lastActivity)
+	c.standbyMu.Unlock()
+	if idle < c.config.DimTimeout {
+		return
+	}
+
+	c.brightnessMu.Lock()
+	defer c.brightnessMu.Unlock()
+	if c.dimmed {
+		return
+	}
+	log.Println("Dimming deck")
+	c.startFade(true, time.Now())
+}
+
+// undim fades back to the requested brightness after input on a dimmed
+// deck.
+func (c *Coordinator) undim() {
+	if c.config.DimTimeout <= 0 {
+		return
+	}
+
+	c.brightnessMu.Lock()
+	defer c.brightnessMu.Unlock()
+	if !c.dimmed {
+		return
+	}
+	log.Println("Undimming deck")
+	c.startFade(false, time.Now())
+}
+
+// startFade begins fading from the current level toward the dimmed or
+// normal level. Called with brightnessMu held.
+func (c *Coordinator) startFade(dimmed bool, now time.Time) {
+	c.fadeFrom = c.brightnessLevel(now)
+	c.fadeStart = now
+	c.dimmed = dimmed
+}
+
+// brightnessLevel returns the brightness to show at now: the requested
+// brightness, or the dim floor while dimmed, eased between the two during
+// a fade. It's -1 if no brightness has been requested. Called with
+// brightnessMu held.
+func (c *Coordinator) brightnessLevel(now time.Time) int {
+	if c.brightnessTarget < 0 {
+		return -1
+	}
+	goal := c.brightnessTarget
+	if c.dimmed {
+		goal = min(c.brightnessTarget, c.config.DimBrightness)
+	}
+	if c.fadeFrom < 0 {
+		return goal
+	}
+	return fadeLevel(c.fadeFrom, goal, now.Sub(c.fadeStart), c.config.DimFade)
+}
+
+// fadeLevel returns the level elapsed into a linear fade of length d from
+// one level to another. Past the end of the fade, it's the final level.
+func fadeLevel(from, to int, elapsed, d time.Duration) int {
+	if d <= 0 || elapsed >= d {
+		return to
+	}
+	if elapsed <= 0 {
+		return from
+	}
+	return from + int(int64(to-from)*int64(elapsed)/int64(d))
+}
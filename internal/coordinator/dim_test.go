@@ -0,0 +1,70 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+func TestFadeLevel(t *testing.T) {
+	for _, tt := range []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{-time.Second, 80},
+		{0, 80},
+		{250 * time.Millisecond, 63},
+		{500 * time.Millisecond, 45},
+		{time.Second, 10},
+		{time.Hour, 10},
+	} {
+		if got := fadeLevel(80, 10, tt.elapsed, time.Second); got != tt.want {
+			t.Errorf("fadeLevel(80, 10, %v, 1s) = %d, want %d", tt.elapsed, got, tt.want)
+		}
+	}
+	if got := fadeLevel(80, 10, 0, 0); got != 10 {
+		t.Errorf("fadeLevel with no fade = %d, want 10", got)
+	}
+}
+
+func TestDimFadeStaysAboveFloor(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.DimBrightness = 10
+	c.config.DimFade = time.Second
+
+	c.brightnessMu.Lock()
+	defer c.brightnessMu.Unlock()
+	c.brightnessTarget = 80
+
+	start := time.Now()
+	c.startFade(true, start)
+	prev := 80
+	for at := time.Duration(0); at <= 2*time.Second; at += fadeStep {
+		level := c.brightnessLevel(start.Add(at))
+		if level < 10 || level > prev {
+			t.Fatalf("level %v into the fade = %d, want between 10 and %d", at, level, prev)
+		}
+		prev = level
+	}
+	if prev != 10 {
+		t.Errorf("dimmed level = %d, want the floor, 10", prev)
+	}
+
+	// Undimming partway through fades back up from where the fade was
+	mid := start.Add(500 * time.Millisecond)
+	c.startFade(false, mid)
+	if got := c.brightnessLevel(mid); got != 45 {
+		t.Errorf("level as undimming starts = %d, want 45", got)
+	}
+	if got := c.brightnessLevel(mid.Add(time.Second)); got != 80 {
+		t.Errorf("level after undimming = %d, want 80", got)
+	}
+
+	// A requested level already below the floor is left alone
+	c.brightnessTarget = 5
+	c.startFade(true, start)
+	if got := c.brightnessLevel(start.Add(2 * time.Second)); got != 5 {
+		t.Errorf("dimmed level below the floor = %d, want 5", got)
+	}
+}
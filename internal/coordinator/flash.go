@@ -0,0 +1,94 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// flashAlpha is how strongly a flash tints the key it covers, out of 255.
+const flashAlpha = 200
+
+// flashState is a flash in progress on one key.
+type flashState struct {
+	color color.Color
+	until time.Time
+}
+
+// FlashKey briefly tints a key with the configured color for the kind of
+// flash, then lets the next render restore it. Keys under an overlay or on
+// a snoozed module aren't flashed. A zero FlashDuration disables flashing.
+func (c *Coordinator) FlashKey(id module.KeyID, flash module.Flash) {
+	if c.config.FlashDuration <= 0 {
+		return
+	}
+
+	col := c.config.FlashSuccessColor
+	if flash == module.FlashError {
+		col = c.config.FlashErrorColor
+	}
+
+	c.flashMu.Lock()
+	c.flashes[id] = flashState{color: col, until: time.Now().Add(c.config.FlashDuration)}
+	c.flashMu.Unlock()
+
+	// Show the flash now and restore the key as soon as it ends, rather than
+	// waiting for the next render tick
+	c.requestRender()
+	time.AfterFunc(c.config.FlashDuration, c.requestRender)
+}
+
+// flashColor returns the color a key is currently flashing, if any. Expired
+// flashes are dropped.
+func (c *Coordinator) flashColor(id module.KeyID) (color.Color, bool) {
+	c.flashMu.Lock()
+	defer c.flashMu.Unlock()
+
+	f, ok := c.flashes[id]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(f.until) {
+		delete(c.flashes, id)
+		return nil, false
+	}
+	return f.color, true
+}
+
+// flashed returns img with the key's flash tint applied, or img unchanged if
+// the key isn't flashing. A nil img is tinted over black.
+func (c *Coordinator) flashed(id module.KeyID, img image.Image) image.Image {
+	col, ok := c.flashColor(id)
+	if !ok {
+		return img
+	}
+
+	bounds, err := c.device.GetKeyImageRectangle()
+	if err != nil {
+		return img
+	}
+	if img != nil {
+		bounds = img.Bounds()
+	}
+
+	out := image.NewRGBA(bounds)
+	draw.Draw(out, bounds, image.Black, image.Point{}, draw.Src)
+	if img != nil {
+		draw.Draw(out, bounds, img, bounds.Min, draw.Over)
+	}
+	mask := image.NewUniform(color.Alpha{flashAlpha})
+	draw.DrawMask(out, bounds, image.NewUniform(col), image.Point{}, mask, image.Point{}, draw.Over)
+	return out
+}
+
+// requestRender asks the render loop to run a cycle now. Requests made while
+// one is already pending are merged.
+func (c *Coordinator) requestRender() {
+	select {
+	case c.renderNow <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,45 @@
+package coordinator
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestModuleFlashTintsKeyUntilItEnds(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.FlashDuration = 50 * time.Millisecond
+	c.config.FlashErrorColor = color.RGBA{255, 0, 0, 255}
+
+	m := newStubModule("stub")
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}})
+	m.FlashResult(1, errors.New("failed"))
+
+	key := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	got := rgbaAt(c.flashed(1, key), 0, 0)
+	if got.R < 150 || got.G > 50 || got.B > 50 {
+		t.Errorf("flashing key pixel = %v, want tinted red", got)
+	}
+	if out := c.flashed(2, key); out != image.Image(key) {
+		t.Error("key that isn't flashing was tinted")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if out := c.flashed(1, key); out != image.Image(key) {
+		t.Error("key still tinted after its flash ended")
+	}
+}
+
+func TestFlashDisabled(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.FlashDuration = 0
+	c.FlashKey(1, module.FlashSuccess)
+	if _, ok := c.flashColor(1); ok {
+		t.Error("key flashing with FlashDuration zero")
+	}
+}
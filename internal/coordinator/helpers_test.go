@@ -0,0 +1,136 @@
+package coordinator
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// stubModule is a module whose output and input handling tests set up
+// directly.
+type stubModule struct {
+	module.BaseModule
+
+	// strip renders the module's strip given its region; nil renders none.
+	strip func(rect image.Rectangle) image.Image
+
+	mu      sync.Mutex
+	inits   int
+	keys    []module.KeyEvent
+	dials   []module.DialEvent
+	touches []module.TouchStripEvent
+}
+
+func newStubModule(id string) *stubModule {
+	return &stubModule{BaseModule: module.NewBaseModule(id)}
+}
+
+func (m *stubModule) Init(ctx context.Context, res module.Resources) error {
+	m.mu.Lock()
+	m.inits++
+	m.mu.Unlock()
+	return m.BaseModule.Init(ctx, res)
+}
+
+func (m *stubModule) RenderStrip() image.Image {
+	if m.strip == nil {
+		return nil
+	}
+	return m.strip(m.Resources().StripRect)
+}
+
+func (m *stubModule) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = append(m.keys, event)
+	return nil
+}
+
+func (m *stubModule) HandleDial(id module.DialID, event module.DialEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dials = append(m.dials, event)
+	return nil
+}
+
+func (m *stubModule) HandleStripTouch(event module.TouchStripEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.touches = append(m.touches, event)
+	return nil
+}
+
+// keyEvents returns the key events the module has handled.
+func (m *stubModule) keyEvents() []module.KeyEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]module.KeyEvent(nil), m.keys...)
+}
+
+// touchCount returns how many strip touches the module has handled.
+func (m *stubModule) touchCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.touches)
+}
+
+// fillStrip returns a strip renderer filling the module's region with col.
+func fillStrip(col color.Color) func(image.Rectangle) image.Image {
+	return func(rect image.Rectangle) image.Image {
+		img := image.NewRGBA(rect)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				img.Set(x, y, col)
+			}
+		}
+		return img
+	}
+}
+
+// startCoordinator starts c against dev and waits until it's listening for
+// input. The coordinator is stopped when the test ends.
+func startCoordinator(t *testing.T, c *Coordinator, dev *device.Fake) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		c.Stop()
+		dev.Close()
+		<-done
+	})
+
+	select {
+	case <-dev.Listening():
+	case err := <-done:
+		t.Fatalf("Start returned early: %v", err)
+	}
+}
+
+// rgbaAt returns the color of img at (x, y) as RGBA.
+func rgbaAt(img image.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
+
+// waitFor polls cond until it holds, failing the test if it doesn't within
+// a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
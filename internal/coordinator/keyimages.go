@@ -0,0 +1,29 @@
+package coordinator
+
+import (
+	"image"
+	"log"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// setKeyImages applies a frame's key images to the device. Devices that can
+// update several keys at once get them in one transaction, so overlay
+// transitions don't tear across keys; others get them one at a time.
+func (c *Coordinator) setKeyImages(images map[module.KeyID]image.Image) {
+	if setter, ok := c.device.(device.KeyImagesSetter); ok && len(images) > 1 {
+		batch := make(map[device.KeyID]image.Image, len(images))
+		for keyID, img := range images {
+			batch[device.KeyID(keyID)] = img
+		}
+		if err := setter.SetKeyImages(batch); err != nil {
+			log.Printf("Failed to set key images: %v", err)
+		}
+		return
+	}
+
+	for keyID, img := range images {
+		c.device.SetKeyImage(device.KeyID(keyID), img)
+	}
+}
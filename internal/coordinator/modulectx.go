@@ -0,0 +1,153 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// moduleRun is a started module's own context, a child of the
+// coordinator's, and the supervised goroutines running under it.
+type moduleRun struct {
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// startModule initializes m under its own context, so it can later be
+// stopped or restarted without touching the other modules. A module that
+// fails to initialize is marked failed and its context canceled.
+func (c *Coordinator) startModule(m module.Module) error {
+	wg := &sync.WaitGroup{}
+	ctx, cancel := context.WithCancel(module.WithWaitGroup(c.ctx, wg))
+	err := m.Init(ctx, c.resourcesForModule(m))
+	if err != nil {
+		cancel()
+	}
+
+	c.moduleMu.Lock()
+	defer c.moduleMu.Unlock()
+	c.moduleRuns[m] = &moduleRun{cancel: cancel, wg: wg}
+	c.failedModules[m] = err != nil
+	delete(c.stoppedModules, m)
+	return err
+}
+
+// stopModule stops m and waits for its goroutines to exit. It's marked
+// stopped first, so it's no longer rendered or sent input while it shuts
+// down.
+func (c *Coordinator) stopModule(m module.Module) {
+	c.moduleMu.Lock()
+	c.stoppedModules[m] = true
+	run := c.moduleRuns[m]
+	delete(c.moduleRuns, m)
+	c.moduleMu.Unlock()
+
+	if run == nil {
+		return
+	}
+	run.cancel()
+	m.Stop()
+	run.wg.Wait()
+}
+
+// StopModule stops the module with the given ID, leaving the others
+// running. Its keys go blank and its part of the strip is left to the
+// background until it's restarted.
+func (c *Coordinator) StopModule(id string) error {
+	m := c.moduleByID(id)
+	if m == nil {
+		return fmt.Errorf("no module %q", id)
+	}
+	log.Printf("Stopping module %s", id)
+	c.stopModule(m)
+	c.invalidateStrips()
+	c.requestRender()
+	return nil
+}
+
+// RestartModule stops the module with the given ID, if it's running, and
+// initializes it again, e.g. to retry one that failed to initialize. The
+// other modules keep running throughout.
+func (c *Coordinator) RestartModule(id string) error {
+	m := c.moduleByID(id)
+	if m == nil {
+		return fmt.Errorf("no module %q", id)
+	}
+	log.Printf("Restarting module %s", id)
+	c.stopModule(m)
+	err := c.startModule(m)
+	c.invalidateStrips()
+	c.requestRender()
+	if err != nil {
+		return fmt.Errorf("module %s failed to initialize: %w", id, err)
+	}
+	return nil
+}
+
+// moduleByID returns the registered module with the given ID, or nil.
+func (c *Coordinator) moduleByID(id string) module.Module {
+	for _, m := range c.modules {
+		if m.ID() == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// isInactive reports whether m isn't running, having failed to initialize
+// or been stopped, and so is neither rendered nor sent input.
+func (c *Coordinator) isInactive(m module.Module) bool {
+	c.moduleMu.RLock()
+	defer c.moduleMu.RUnlock()
+	return c.failedModules[m] || c.stoppedModules[m]
+}
+
+// isFailed reports whether m failed to initialize.
+func (c *Coordinator) isFailed(m module.Module) bool {
+	c.moduleMu.RLock()
+	defer c.moduleMu.RUnlock()
+	return c.failedModules[m]
+}
+
+// isStopped reports whether m was stopped with StopModule.
+func (c *Coordinator) isStopped(m module.Module) bool {
+	c.moduleMu.RLock()
+	defer c.moduleMu.RUnlock()
+	return c.stoppedModules[m]
+}
+
+// renderStoppedKeys adds blank images for a stopped module's keys to
+// images, so they don't keep showing its last state.
+func (c *Coordinator) renderStoppedKeys(m module.Module, images map[module.KeyID]image.Image) {
+	keyRect, err := c.device.GetKeyImageRectangle()
+	if err != nil {
+		return
+	}
+	blackImg := image.NewRGBA(keyRect)
+	for _, keyID := range c.resourcesForModule(m).Keys {
+		images[keyID] = blackImg
+	}
+}
+
+// stopModules stops every started module that wasn't already stopped,
+// then waits for all of their goroutines to exit.
+func (c *Coordinator) stopModules() {
+	c.moduleMu.Lock()
+	runs := c.moduleRuns
+	c.moduleRuns = make(map[module.Module]*moduleRun)
+	c.moduleMu.Unlock()
+
+	for _, m := range c.modules {
+		if run, ok := runs[m]; ok {
+			run.cancel()
+			m.Stop()
+		}
+	}
+	for _, run := range runs {
+		run.wg.Wait()
+	}
+}
@@ -0,0 +1,63 @@
+package coordinator
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// subscribingModule counts the events it gets on a bus topic it subscribes
+// to on each Init, as modules like Home Assistant do.
+type subscribingModule struct {
+	*stubModule
+	events atomic.Int32
+}
+
+func (m *subscribingModule) Init(ctx context.Context, res module.Resources) error {
+	if err := m.stubModule.Init(ctx, res); err != nil {
+		return err
+	}
+	m.Bus().SubscribeContext(m.Context(), "test", func(e bus.Event) {
+		m.events.Add(1)
+	})
+	return nil
+}
+
+func TestRestartModuleDoesNotDuplicateSubscriptions(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	m := &subscribingModule{stubModule: newStubModule("sub")}
+	c.RegisterModule(m, module.Resources{})
+	startCoordinator(t, c, dev)
+
+	for i := 0; i < 3; i++ {
+		if err := c.RestartModule("sub"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Bus().Publish("test", nil)
+	if got := m.events.Load(); got != 1 {
+		t.Errorf("event handled %d times after restarts, want 1", got)
+	}
+
+	if err := c.StopModule("sub"); err != nil {
+		t.Fatal(err)
+	}
+	c.Bus().Publish("test", nil)
+	if got := m.events.Load(); got != 1 {
+		t.Errorf("stopped module handled an event")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for c.Bus().HasSubscribers("test") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if c.Bus().HasSubscribers("test") {
+		t.Error("stopped module's handler is still subscribed")
+	}
+}
@@ -0,0 +1,77 @@
+package coordinator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestRegisterAfterStartRejected(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	startCoordinator(t, c, dev)
+
+	m := newStubModule("late")
+	if err := c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}}); err == nil {
+		t.Fatal("registration after Start accepted")
+	}
+	if c.keyOwner(1) != nil {
+		t.Error("rejected module owns its key")
+	}
+}
+
+// Run with -race: registrations racing Start, and events arriving from
+// several goroutines, must not touch the ownership maps unguarded.
+func TestConcurrentRegisterAndDispatch(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.KeyCooldown = 0
+
+	m := newStubModule("stub")
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}, Dials: []module.DialID{1}})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			late := newStubModule(fmt.Sprintf("late%d", i))
+			c.RegisterModule(late, module.Resources{Keys: []module.KeyID{module.KeyID(2 + i%6)}})
+		}
+	}()
+
+	startCoordinator(t, c, dev)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				dev.Dispatch(device.InputEvent{Type: device.InputKey, Key: device.KEY_1})
+				dev.Dispatch(device.InputEvent{Type: device.InputDialRotate, Dial: device.DIAL_1, Delta: 1})
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-done
+
+	if err := c.RegisterModule(newStubModule("after"), module.Resources{}); err == nil {
+		t.Error("registration after Start accepted")
+	}
+	if c.keyOwner(1) != m || c.dialOwner(1) != m {
+		t.Error("stub lost its key or dial")
+	}
+	if got := len(m.keyEvents()); got == 0 {
+		t.Error("no key events routed to the stub")
+	}
+}
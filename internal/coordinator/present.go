@@ -0,0 +1,171 @@
+package coordinator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"os"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/draw"
+)
+
+// presentActionID addresses presentation mode in external actions, e.g.
+// "present:toggle".
+const presentActionID = "present"
+
+var (
+	colorPresentBg   = color.RGBA{30, 30, 30, 255}
+	colorPresentText = color.RGBA{110, 110, 110, 255}
+)
+
+// reservePresentKey takes PresentKey away from whichever module owns it, so
+// its presses toggle presentation mode instead. It runs after spare keys
+// are assigned and before modules are initialized, so the owner never sees
+// the key in its Resources.
+func (c *Coordinator) reservePresentKey() {
+	key := c.config.PresentKey
+	if key == 0 {
+		return
+	}
+	if int(key) > int(c.device.GetKeyCount()) {
+		log.Printf("Presentation key %d: device has %d keys, not reserving it", key, c.device.GetKeyCount())
+		c.config.PresentKey = 0
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if owner, ok := c.keyOwners[key]; ok {
+		res := c.moduleResources[owner]
+		var keys []module.KeyID
+		for _, k := range res.Keys {
+			if k != key {
+				keys = append(keys, k)
+			}
+		}
+		res.Keys = keys
+		c.moduleResources[owner] = res
+		delete(c.keyOwners, key)
+		log.Printf("Key %d reserved for presentation mode (taken from %s)", key, owner.ID())
+	}
+}
+
+// loadPresentImage reads PresentImage, the image shown on the strip during
+// presentation mode, scaled to fill the strip. Without one, or if it can't
+// be read, the strip is blanked.
+func (c *Coordinator) loadPresentImage() {
+	path := c.config.PresentImage
+	if path == "" || c.stripRect.Empty() {
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("Presentation image: %v (blanking the strip instead)", err)
+		return
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		log.Printf("Presentation image %s: %v (blanking the strip instead)", path, err)
+		return
+	}
+
+	img := image.NewRGBA(c.stripRect)
+	draw.CatmullRom.Scale(img, img.Bounds(), src, src.Bounds(), draw.Src, nil)
+	c.presentImage = img
+}
+
+// handlePresentAction runs a "present:<action>" external action: "on" and
+// "off" enter and leave presentation mode and "toggle" flips it.
+func (c *Coordinator) handlePresentAction(action string) error {
+	switch action {
+	case "on":
+		c.setPresenting(true)
+	case "off":
+		c.setPresenting(false)
+	case "toggle":
+		c.setPresenting(!c.Presenting())
+	default:
+		return fmt.Errorf("unknown present action %q (want on, off or toggle)", action)
+	}
+	return nil
+}
+
+// Presenting reports whether the deck is in presentation mode.
+func (c *Coordinator) Presenting() bool {
+	c.presentMu.Lock()
+	defer c.presentMu.Unlock()
+	return c.presenting
+}
+
+// setPresenting enters or leaves presentation mode. The deck is blanked, or
+// restored, on the next render cycle, which is requested now.
+func (c *Coordinator) setPresenting(on bool) {
+	c.presentMu.Lock()
+	changed := c.presenting != on
+	c.presenting = on
+	c.presentShown = false
+	c.presentMu.Unlock()
+
+	if !changed {
+		return
+	}
+	if on {
+		log.Println("Entering presentation mode")
+	} else {
+		log.Println("Leaving presentation mode")
+		c.invalidateStrips()
+	}
+	c.requestRender()
+}
+
+// checkPresentation blanks the deck when presentation mode begins and
+// reports whether rendering should be skipped. Modules keep running but
+// aren't rendered until presentation mode ends.
+func (c *Coordinator) checkPresentation() bool {
+	c.presentMu.Lock()
+	defer c.presentMu.Unlock()
+
+	if !c.presenting {
+		return false
+	}
+	if c.presentShown {
+		return true
+	}
+
+	c.presentShown = true
+	c.clearAllKeys()
+	if c.stripEnabled() {
+		if c.presentImage != nil {
+			c.writeStrip(c.presentImage)
+		} else {
+			c.writeStrip(image.NewRGBA(c.stripRect))
+		}
+		c.markStripDirty()
+	}
+	return true
+}
+
+// renderPresentKey adds the reserved presentation key's image to images, if
+// there is one.
+func (c *Coordinator) renderPresentKey(images map[module.KeyID]image.Image) {
+	if c.config.PresentKey == 0 {
+		return
+	}
+	keyRect, err := c.device.GetKeyImageRectangle()
+	if err != nil {
+		return
+	}
+
+	img := image.NewRGBA(keyRect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorPresentBg}, image.Point{}, draw.Src)
+	drawCenteredBasic(img, "Present", keyRect.Dx()/2, keyRect.Dy()/2+4, colorPresentText)
+	images[c.config.PresentKey] = img
+}
@@ -0,0 +1,75 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// keyModule is a stub module that fills its keys with one color and counts
+// its key renders.
+type keyModule struct {
+	*stubModule
+	col     color.Color
+	renders atomic.Int32
+}
+
+func (m *keyModule) RenderKeys() map[module.KeyID]image.Image {
+	m.renders.Add(1)
+	img := image.NewRGBA(image.Rect(0, 0, 72, 72))
+	draw.Draw(img, img.Bounds(), &image.Uniform{m.col}, image.Point{}, draw.Src)
+	keys := make(map[module.KeyID]image.Image)
+	for _, key := range m.Resources().Keys {
+		keys[key] = img
+	}
+	return keys
+}
+
+func TestPresentationModeBlanksAndRestores(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	strip, _ := dev.GetTouchStripImageRectangle()
+
+	red := color.RGBA{200, 0, 0, 255}
+	m := &keyModule{stubModule: newStubModule("red"), col: red}
+	m.strip = fillStrip(red)
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}, StripRect: strip})
+	startCoordinator(t, c, dev)
+
+	showing := func(want color.RGBA) func() bool {
+		return func() bool {
+			key, strip := dev.KeyImage(device.KEY_1), dev.StripImage()
+			return key != nil && strip != nil && rgbaAt(key, 36, 36) == want && rgbaAt(strip, 20, 20) == want
+		}
+	}
+	waitFor(t, "the module's images", showing(red))
+
+	if err := c.ExternalAction("present:on"); err != nil {
+		t.Fatalf("present:on: %v", err)
+	}
+	waitFor(t, "the deck to blank", showing(color.RGBA{}))
+
+	// Modules aren't rendered, however often a render is asked for
+	renders := m.renders.Load()
+	for range 3 {
+		c.requestRender()
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := m.renders.Load(); got != renders {
+		t.Errorf("module rendered %d times while presenting", got-renders)
+	}
+	if !showing(color.RGBA{})() {
+		t.Error("module images pushed while presenting")
+	}
+
+	if err := c.ExternalAction("present:off"); err != nil {
+		t.Fatalf("present:off: %v", err)
+	}
+	waitFor(t, "the module's images to return", showing(red))
+}
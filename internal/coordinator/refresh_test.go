@@ -0,0 +1,48 @@
+package coordinator
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// refreshModule is a stub module that counts refresh requests.
+type refreshModule struct {
+	*stubModule
+	refreshes atomic.Int32
+}
+
+func (m *refreshModule) Refresh() { m.refreshes.Add(1) }
+
+func TestLongHoldRefreshes(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.SnoozeHold = 3 * time.Second
+
+	m := &refreshModule{stubModule: newStubModule("poll")}
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}})
+	startCoordinator(t, c, dev)
+
+	pressKey(t, dev, device.KEY_1, 100*time.Millisecond)
+	if got := m.refreshes.Load(); got != 0 {
+		t.Errorf("short press refreshed %d times, want 0", got)
+	}
+
+	pressKey(t, dev, device.KEY_1, module.RefreshHold+200*time.Millisecond)
+	if got := m.refreshes.Load(); got != 1 {
+		t.Errorf("long hold refreshed %d times, want 1", got)
+	}
+	if c.isSnoozed(m) {
+		t.Error("hold shorter than the snooze hold snoozed the module")
+	}
+
+	if err := c.ExternalAction("poll:refresh"); err != nil {
+		t.Fatalf("poll:refresh: %v", err)
+	}
+	if got := m.refreshes.Load(); got != 2 {
+		t.Errorf("refresh action left %d refreshes, want 2", got)
+	}
+}
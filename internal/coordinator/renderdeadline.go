@@ -0,0 +1,85 @@
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// renderDeadline is how long a render cycle waits for each module. A module
+// that misses it keeps showing its last frame, and isn't rendered again
+// until its late render finishes, so one slow module can't stall the rest.
+const renderDeadline = 200 * time.Millisecond
+
+// moduleRenders tracks each module's last frame and renders still running
+// past the deadline.
+type moduleRenders struct {
+	mu   sync.Mutex
+	last map[module.Module]frame
+
+	// running holds modules whose render hasn't finished; late marks those
+	// a cycle stopped waiting for.
+	running map[module.Module]bool
+	late    map[module.Module]bool
+}
+
+// begin marks m as rendering, reporting false if an earlier render of it is
+// still running.
+func (r *moduleRenders) begin(m module.Module) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running == nil {
+		r.last = make(map[module.Module]frame)
+		r.running = make(map[module.Module]bool)
+		r.late = make(map[module.Module]bool)
+	}
+	if r.running[m] {
+		return false
+	}
+	r.running[m] = true
+	return true
+}
+
+// finish stores f as m's last frame once its render is done, reporting
+// whether it missed the deadline.
+func (r *moduleRenders) finish(m module.Module, f frame) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.running, m)
+	late := r.late[m]
+	delete(r.late, m)
+	r.last[m] = f
+	return late
+}
+
+// abandon marks m's render as late and returns its last frame instead.
+func (r *moduleRenders) abandon(m module.Module) frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running[m] {
+		r.late[m] = true
+	}
+	return r.reuse(m)
+}
+
+// lastFrame returns m's last frame, for a cycle in which it's still
+// rendering.
+func (r *moduleRenders) lastFrame(m module.Module) frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reuse(m)
+}
+
+// reuse returns m's last frame. A fresh strip is only reported fresh the
+// first time it's reused, so a late render's strip is composited once.
+// r.mu must be held.
+func (r *moduleRenders) reuse(m module.Module) frame {
+	f := r.last[m]
+	if f.stripFresh {
+		stale := f
+		stale.stripFresh = false
+		r.last[m] = stale
+	}
+	return f
+}
@@ -0,0 +1,96 @@
+package coordinator
+
+import (
+	"image"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// slowModule renders one key, taking delay to do it, and blocking while
+// block is set until it's closed.
+type slowModule struct {
+	*stubModule
+	delay   time.Duration
+	block   atomic.Pointer[chan struct{}]
+	renders atomic.Int32
+}
+
+func newSlowModule(id string, delay time.Duration) *slowModule {
+	return &slowModule{stubModule: newStubModule(id), delay: delay}
+}
+
+func (m *slowModule) RenderKeys() map[module.KeyID]image.Image {
+	n := m.renders.Add(1)
+	time.Sleep(m.delay)
+	if block := m.block.Load(); block != nil {
+		<-*block
+	}
+	// Each render's image is distinct, so reuse can be told apart
+	return map[module.KeyID]image.Image{0: image.NewRGBA(image.Rect(0, 0, int(n), 1))}
+}
+
+// renderNumber returns which of m's renders produced f.
+func renderNumber(f frame) int {
+	return f.keys[0].Bounds().Dx()
+}
+
+func TestSlowModuleReusesLastFrame(t *testing.T) {
+	c := New(device.NewFake())
+	slow := newSlowModule("slow", 0)
+	fast := newSlowModule("fast", 0)
+	c.RegisterModule(slow, module.Resources{Keys: []module.KeyID{0}})
+	c.RegisterModule(fast, module.Resources{Keys: []module.KeyID{1}})
+
+	if frames := c.collectFrames(); renderNumber(frames[0]) != 1 {
+		t.Fatalf("first frame is from render %d, want 1", renderNumber(frames[0]))
+	}
+
+	block := make(chan struct{})
+	slow.block.Store(&block)
+	defer c.wg.Wait()
+	defer close(block)
+
+	start := time.Now()
+	frames := c.collectFrames()
+	if elapsed := time.Since(start); elapsed > 2*renderDeadline {
+		t.Errorf("cycle with a stuck module took %v, want about the %v deadline", elapsed, renderDeadline)
+	}
+	if got := renderNumber(frames[0]); got != 1 {
+		t.Errorf("stuck module's frame is from render %d, want its last frame, from render 1", got)
+	}
+	if got := renderNumber(frames[1]); got != 2 {
+		t.Errorf("fast module's frame is from render %d, want a fresh one, from render 2", got)
+	}
+
+	// While its render is still stuck, the module isn't rendered again
+	start = time.Now()
+	frames = c.collectFrames()
+	if elapsed := time.Since(start); elapsed > renderDeadline/2 {
+		t.Errorf("cycle with a module still rendering took %v, want no wait", elapsed)
+	}
+	if got := slow.renders.Load(); got != 2 {
+		t.Errorf("stuck module rendered %d times, want 2", got)
+	}
+	if got := renderNumber(frames[0]); got != 1 {
+		t.Errorf("stuck module's frame is from render %d, want 1", got)
+	}
+}
+
+// BenchmarkCollectFrames renders modules taking 1 to 8ms each. Since they
+// render concurrently, a cycle takes about as long as the slowest module,
+// 8ms, rather than the 15ms sum.
+func BenchmarkCollectFrames(b *testing.B) {
+	c := New(device.NewFake())
+	for i, delay := range []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond} {
+		m := newSlowModule(string(rune('a'+i)), delay)
+		c.RegisterModule(m, module.Resources{Keys: []module.KeyID{module.KeyID(i)}})
+	}
+
+	for b.Loop() {
+		c.collectFrames()
+	}
+}
@@ -0,0 +1,40 @@
+package coordinator
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// slowStopModule is a stub module whose background goroutine takes a while
+// to exit once canceled, like a stream waiting on its subprocess.
+type slowStopModule struct {
+	*stubModule
+	exited atomic.Bool
+}
+
+func (m *slowStopModule) Init(ctx context.Context, res module.Resources) error {
+	module.Supervise(ctx, "slow", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond)
+		m.exited.Store(true)
+	})
+	return m.stubModule.Init(ctx, res)
+}
+
+func TestStopWaitsForModuleGoroutines(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	m := &slowStopModule{stubModule: newStubModule("slow")}
+	c.RegisterModule(m, module.Resources{})
+	startCoordinator(t, c, dev)
+
+	c.Stop()
+	if !m.exited.Load() {
+		t.Error("Stop returned before the module's goroutine exited")
+	}
+}
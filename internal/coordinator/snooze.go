@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	colorSnoozedBg   = color.RGBA{30, 30, 30, 255}
+	colorSnoozedText = color.RGBA{110, 110, 110, 255}
+)
+
+// snoozedUntil returns when a module's snooze ends, or the zero time if the
+// module is not snoozed (or doesn't support snoozing).
+func (c *Coordinator) snoozedUntil(m module.Module) time.Time {
+	s, ok := m.(module.Snoozable)
+	if !ok {
+		return time.Time{}
+	}
+	return s.SnoozedUntil()
+}
+
+// isSnoozed returns true if the module is currently snoozed.
+func (c *Coordinator) isSnoozed(m module.Module) bool {
+	return !c.snoozedUntil(m).IsZero()
+}
+
+// isSnoozeHold returns true if a key held for the given duration should
+// toggle snooze on its owning module.
+func (c *Coordinator) isSnoozeHold(held time.Duration) bool {
+	return c.config.SnoozeHold > 0 && held >= c.config.SnoozeHold
+}
+
+// canSnoozeFrom returns true if holding key snoozes m: snoozing is enabled,
+// m is snoozable, and it doesn't act on the key while it's held.
+func (c *Coordinator) canSnoozeFrom(m module.Module, key module.KeyID) bool {
+	if c.config.SnoozeHold <= 0 {
+		return false
+	}
+	if _, ok := m.(module.Snoozable); !ok {
+		return false
+	}
+	h, ok := m.(module.KeyHolder)
+	return !ok || !h.HoldsKey(key)
+}
+
+// toggleSnooze snoozes an active module for the configured duration, or wakes
+// a snoozed one.
+func (c *Coordinator) toggleSnooze(m module.Module) {
+	s, ok := m.(module.Snoozable)
+	if !ok {
+		return
+	}
+
+	if c.isSnoozed(m) {
+		s.Unsnooze()
+		log.Printf("Module %s unsnoozed", m.ID())
+		return
+	}
+
+	until := time.Now().Add(c.config.SnoozeDuration)
+	s.Snooze(until)
+	log.Printf("Module %s snoozed until %s", m.ID(), until.Format("15:04"))
+}
+
+// renderSnoozedKeys adds a muted "snoozed until" image for each of a
+// module's keys to images.
+func (c *Coordinator) renderSnoozedKeys(m module.Module, until time.Time, images map[module.KeyID]image.Image) {
+	keyRect, err := c.device.GetKeyImageRectangle()
+	if err != nil {
+		return
+	}
+
+	img := image.NewRGBA(keyRect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorSnoozedBg}, image.Point{}, draw.Src)
+
+	size := keyRect.Dx()
+	drawCenteredBasic(img, "Snoozed", size/2, size/2-4, colorSnoozedText)
+	drawCenteredBasic(img, "til "+until.Format("15:04"), size/2, size/2+12, colorSnoozedText)
+
+	for _, key := range c.resourcesForModule(m).Keys {
+		images[key] = img
+	}
+}
+
+// drawCenteredBasic draws text horizontally centered using the built-in bitmap face.
+func drawCenteredBasic(img *image.RGBA, text string, centerX, y int, col color.Color) {
+	face := basicfont.Face7x13
+	width := font.MeasureString(face, text).Ceil()
+
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(centerX - width/2), Y: fixed.I(y)},
+	}
+	d.DrawString(text)
+}
@@ -0,0 +1,93 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// holdingModule is a stub module whose keys all act while held.
+type holdingModule struct {
+	*stubModule
+}
+
+func (m holdingModule) HoldsKey(id module.KeyID) bool { return true }
+
+// pressKey dispatches a press of key held for the given duration.
+func pressKey(t *testing.T, dev *device.Fake, key device.KeyID, held time.Duration) {
+	t.Helper()
+	if err := dev.Dispatch(device.InputEvent{Type: device.InputKey, Key: key, Held: held}); err != nil {
+		t.Fatalf("dispatch key %d: %v", key, err)
+	}
+}
+
+func TestSnoozeHoldDoesNotPressModule(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.SnoozeHold = 2 * time.Second
+	c.config.SnoozeDuration = time.Hour
+
+	m := newStubModule("stub")
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}})
+	startCoordinator(t, c, dev)
+
+	pressKey(t, dev, device.KEY_1, 3*time.Second)
+	if got := m.keyEvents(); len(got) != 0 {
+		t.Errorf("snoozing hold delivered %v, want no key events", got)
+	}
+	if !c.isSnoozed(m) {
+		t.Fatal("module not snoozed after a snoozing hold")
+	}
+
+	// Waking it up doesn't press it either
+	pressKey(t, dev, device.KEY_1, 3*time.Second)
+	if c.isSnoozed(m) {
+		t.Fatal("module still snoozed after a second snoozing hold")
+	}
+	if got := m.keyEvents(); len(got) != 0 {
+		t.Errorf("waking hold delivered %v, want no key events", got)
+	}
+
+	pressKey(t, dev, device.KEY_1, 100*time.Millisecond)
+	got := m.keyEvents()
+	if len(got) != 2 || !got[0].Pressed || got[1].Pressed || got[1].Duration != 100*time.Millisecond {
+		t.Errorf("short press delivered %v, want a press then a 100ms release", got)
+	}
+}
+
+func TestHeldKeyPressesDeliveredWithoutSnoozing(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.SnoozeHold = 2 * time.Second
+
+	m := holdingModule{newStubModule("stub")}
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}})
+	startCoordinator(t, c, dev)
+
+	pressKey(t, dev, device.KEY_1, 3*time.Second)
+	if c.isSnoozed(m) {
+		t.Error("holding a key the module acts on while held snoozed it")
+	}
+	got := m.keyEvents()
+	if len(got) != 2 || !got[0].Pressed || got[1].Pressed {
+		t.Errorf("held key delivered %v, want a press then a release", got)
+	}
+}
+
+func TestSnoozeOptIn(t *testing.T) {
+	t.Setenv("BELOWDECK_SNOOZE_HOLD", "")
+	c := New(device.NewFake())
+	m := newStubModule("stub")
+
+	// Off by default, so presses aren't held back until release
+	if c.canSnoozeFrom(m, 1) {
+		t.Error("key can snooze its module with the default config")
+	}
+
+	c.config.SnoozeHold = 2 * time.Second
+	if !c.canSnoozeFrom(m, 1) {
+		t.Error("key can't snooze its module with a snooze hold configured")
+	}
+}
@@ -0,0 +1,48 @@
+package coordinator
+
+import (
+	"log"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// assignSpareKeys gives every key no module owns to the module configured
+// as SpareKeys, so its presses and renders go there instead of the key
+// staying black. It runs before modules are initialized, so the owner sees
+// the extra keys in its Resources like any others.
+func (c *Coordinator) assignSpareKeys() {
+	if c.config.SpareKeys == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var owner module.Module
+	for _, m := range c.modules {
+		if m.ID() == c.config.SpareKeys {
+			owner = m
+			break
+		}
+	}
+	if owner == nil {
+		log.Printf("Spare keys: no module %q registered", c.config.SpareKeys)
+		return
+	}
+
+	res := c.moduleResources[owner]
+	var spare []module.KeyID
+	for _, key := range c.allKeys() {
+		if _, owned := c.keyOwners[key]; !owned {
+			spare = append(spare, key)
+			c.keyOwners[key] = owner
+		}
+	}
+	if len(spare) == 0 {
+		return
+	}
+
+	res.Keys = append(append([]module.KeyID(nil), res.Keys...), spare...)
+	c.moduleResources[owner] = res
+	log.Printf("Spare keys %v assigned to %s", spare, owner.ID())
+}
@@ -0,0 +1,46 @@
+package coordinator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestSpareKeysGoToConfiguredModule(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.SpareKeys = "spare"
+
+	owned := newStubModule("owned")
+	spare := newStubModule("spare")
+	c.RegisterModule(owned, module.Resources{Keys: []module.KeyID{1, 2}})
+	c.RegisterModule(spare, module.Resources{Keys: []module.KeyID{3}})
+	startCoordinator(t, c, dev)
+
+	want := []module.KeyID{3, 4, 5, 6, 7, 8}
+	if got := spare.Resources().Keys; !slices.Equal(got, want) {
+		t.Errorf("spare module keys = %v, want %v", got, want)
+	}
+	if got := owned.Resources().Keys; !slices.Equal(got, []module.KeyID{1, 2}) {
+		t.Errorf("other module keys = %v, want its own", got)
+	}
+
+	pressKey(t, dev, device.KEY_8, 0)
+	if got := len(spare.keyEvents()); got != 2 {
+		t.Errorf("spare module got %d key events from a spare key, want 2", got)
+	}
+}
+
+func TestSpareKeysUnknownModule(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.SpareKeys = "missing"
+	m := newStubModule("m")
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{1}})
+
+	c.assignSpareKeys()
+	if owner := c.keyOwner(2); owner != nil {
+		t.Errorf("key 2 owned by %s, want no owner", owner.ID())
+	}
+}
@@ -0,0 +1,91 @@
+package coordinator
+
+import (
+	"image"
+	"image/draw"
+	"log"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+)
+
+// standbyImageInterval is how often the native standby image is refreshed
+// from the rendered strip.
+const standbyImageInterval = time.Minute
+
+// noteActivity records user input for the screensaver, brings a dimmed deck
+// back to full brightness and has every strip re-rendered. It returns true if
+// the input woke the deck from software standby, in which case the input
+// should be swallowed rather than routed to a module.
+func (c *Coordinator) noteActivity() bool {
+	c.undim()
+	c.invalidateStrips()
+
+	c.standbyMu.Lock()
+	defer c.standbyMu.Unlock()
+
+	c.lastActivity = time.Now()
+	if !c.inStandby {
+		return false
+	}
+
+	c.inStandby = false
+	log.Println("Waking from standby")
+	return true
+}
+
+// checkStandby enters software standby once the deck has been idle for the
+// configured timeout and reports whether rendering should be skipped.
+// Devices with native standby support never enter software standby.
+func (c *Coordinator) checkStandby() bool {
+	if c.config.StandbyTimeout <= 0 {
+		return false
+	}
+	if device.SupportsStandby(c.device) {
+		return false
+	}
+
+	c.standbyMu.Lock()
+	defer c.standbyMu.Unlock()
+
+	if c.inStandby {
+		return true
+	}
+	if time.Since(c.lastActivity) < c.config.StandbyTimeout {
+		return false
+	}
+
+	c.inStandby = true
+	log.Println("Entering standby")
+	c.clearAllKeys()
+	if c.stripEnabled() {
+		c.writeStrip(image.NewRGBA(c.stripRect))
+		c.markStripDirty()
+	}
+	return true
+}
+
+// updateStandbyImage uploads the composited strip as the device's native
+// standby image, at most once per standbyImageInterval.
+func (c *Coordinator) updateStandbyImage(strip image.Image) {
+	if !device.SupportsStandby(c.device) || c.config.StandbyTimeout <= 0 {
+		return
+	}
+
+	c.standbyMu.Lock()
+	due := time.Since(c.standbyImageSet) >= standbyImageInterval
+	if due {
+		c.standbyImageSet = time.Now()
+	}
+	c.standbyMu.Unlock()
+	if !due {
+		return
+	}
+
+	// Copy so the device can hold onto the image while we keep rendering
+	img := image.NewRGBA(strip.Bounds())
+	draw.Draw(img, img.Bounds(), strip, strip.Bounds().Min, draw.Src)
+	if err := c.device.(device.StandbySetter).SetStandbyImage(img, c.config.StandbyTimeout); err != nil {
+		log.Printf("Failed to set standby image: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package coordinator
+
+import (
+	"image/color"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestNativeStandbyImageSetFromStrip(t *testing.T) {
+	dev := device.NewStandbyFake()
+	c := New(dev)
+	c.config.StandbyTimeout = 5 * time.Minute
+	strip, _ := dev.GetTouchStripImageRectangle()
+
+	red := color.RGBA{200, 0, 0, 255}
+	m := newStubModule("art")
+	m.strip = fillStrip(red)
+	c.RegisterModule(m, module.Resources{StripRect: strip})
+	startCoordinator(t, c, dev.Fake)
+
+	img, timeout := dev.StandbyImage()
+	if img == nil {
+		t.Fatal("standby image not set on a device with native standby")
+	}
+	if timeout != c.config.StandbyTimeout {
+		t.Errorf("standby timeout = %v, want %v", timeout, c.config.StandbyTimeout)
+	}
+	if got := rgbaAt(img, 20, 20); got != red {
+		t.Errorf("standby image pixel = %v, want the strip's %v", got, red)
+	}
+}
+
+func TestNativeStandbySkipsSoftwareStandby(t *testing.T) {
+	c := New(device.NewStandbyFake())
+	c.config.StandbyTimeout = time.Minute
+	c.lastActivity = time.Now().Add(-time.Hour)
+
+	if c.checkStandby() {
+		t.Error("idle deck with native standby entered software standby")
+	}
+}
+
+func TestSoftwareStandbyWithoutNativeSupport(t *testing.T) {
+	c := New(device.NewFake())
+	c.config.StandbyTimeout = time.Minute
+	c.lastActivity = time.Now().Add(-time.Hour)
+
+	if !c.checkStandby() {
+		t.Error("idle deck without native standby stayed awake, want software standby")
+	}
+}
+
+func TestRecordedDeckStandby(t *testing.T) {
+	c := New(device.NewRecorder(device.NewFake(), io.Discard))
+	c.config.StandbyTimeout = time.Minute
+	c.lastActivity = time.Now().Add(-time.Hour)
+	if !c.checkStandby() {
+		t.Error("recorded deck without native standby stayed awake, want software standby")
+	}
+
+	c = New(device.NewRecorder(device.NewStandbyFake(), io.Discard))
+	c.config.StandbyTimeout = time.Minute
+	c.lastActivity = time.Now().Add(-time.Hour)
+	if c.checkStandby() {
+		t.Error("recorded deck with native standby entered software standby")
+	}
+}
@@ -0,0 +1,83 @@
+package coordinator
+
+import (
+	"image"
+	"log"
+	"slices"
+)
+
+// maxStripFailures is how many consecutive touch strip writes may fail before
+// the coordinator gives up on the strip for the rest of the session.
+const maxStripFailures = 5
+
+// writeStrip sends img to the touch strip, tracking consecutive failures.
+// Once maxStripFailures writes in a row have failed, the strip is disabled:
+// further writes are skipped and modules' strips are no longer rendered.
+// Keys are unaffected.
+func (c *Coordinator) writeStrip(img image.Image) {
+	c.stripMu.Lock()
+	defer c.stripMu.Unlock()
+
+	if c.stripDisabled {
+		return
+	}
+
+	if err := c.device.SetTouchStripImage(img); err != nil {
+		c.stripFailures++
+		if c.stripFailures >= maxStripFailures {
+			c.stripDisabled = true
+			log.Printf("Touch strip: %d writes in a row failed (last: %v), no longer updating the strip", c.stripFailures, err)
+		}
+		return
+	}
+	c.stripFailures = 0
+}
+
+// setupStripOff prepares strip-off mode: the strip is blanked once and
+// modules lose their strip regions, so nothing renders for it or handles
+// its touches. It runs before modules are initialized.
+func (c *Coordinator) setupStripOff() {
+	if c.config.StripMode != StripOff || !c.device.GetTouchStripSupported() {
+		return
+	}
+
+	if rect, err := c.device.GetTouchStripImageRectangle(); err == nil {
+		if err := c.device.SetTouchStripImage(image.NewRGBA(rect)); err != nil {
+			log.Printf("Touch strip: blanking failed: %v", err)
+		}
+	}
+
+	c.mu.Lock()
+	for m, res := range c.moduleResources {
+		res.StripRect = image.Rectangle{}
+		c.moduleResources[m] = res
+	}
+	c.mu.Unlock()
+
+	log.Println("Touch strip off, rendering keys only")
+}
+
+// stripEnabled reports whether the device has a strip that's still accepting
+// writes.
+func (c *Coordinator) stripEnabled() bool {
+	if c.stripRect.Empty() {
+		return false
+	}
+	c.stripMu.Lock()
+	defer c.stripMu.Unlock()
+	return !c.stripDisabled
+}
+
+// stripOrder returns the indices of c.modules in compositing order for the
+// strip: lowest StripZ first, so backgrounds go under the regions above
+// them, and otherwise in registration order.
+func (c *Coordinator) stripOrder() []int {
+	order := make([]int, len(c.modules))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return c.resourcesForModule(c.modules[a]).StripZ - c.resourcesForModule(c.modules[b]).StripZ
+	})
+	return order
+}
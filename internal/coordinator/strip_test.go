@@ -0,0 +1,180 @@
+package coordinator
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestBackgroundStripCompositesUnderForeground(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	strip, _ := dev.GetTouchStripImageRectangle()
+
+	red := color.RGBA{200, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	// The foreground registers first, so registration order alone would
+	// put the background on top of it
+	fg := newStubModule("fg")
+	fg.strip = func(rect image.Rectangle) image.Image {
+		img := image.NewRGBA(rect)
+		img.Set(10, 10, white)
+		return img
+	}
+	bg := newStubModule("bg")
+	bg.strip = fillStrip(red)
+
+	left := image.Rect(strip.Min.X, strip.Min.Y, strip.Dx()/2, strip.Max.Y)
+	c.RegisterModule(fg, module.Resources{StripRect: left})
+	c.RegisterModule(bg, module.Resources{StripRect: strip, StripZ: -1})
+	startCoordinator(t, c, dev)
+
+	out := dev.StripImage()
+	if got := rgbaAt(out, 10, 10); got != white {
+		t.Errorf("foreground pixel = %v, want %v", got, white)
+	}
+	if got := rgbaAt(out, 20, 20); got != red {
+		t.Errorf("pixel the foreground leaves transparent = %v, want background %v", got, red)
+	}
+	if got := rgbaAt(out, strip.Max.X-10, 20); got != red {
+		t.Errorf("pixel outside the foreground = %v, want background %v", got, red)
+	}
+
+	c.routeStripEvent(module.TouchStripEvent{Type: module.TouchTap, Point: image.Pt(50, 50)})
+	c.routeStripEvent(module.TouchStripEvent{Type: module.TouchTap, Point: image.Pt(strip.Max.X-50, 50)})
+	if got := fg.touchCount(); got != 1 {
+		t.Errorf("foreground got %d touches, want 1", got)
+	}
+	if got := bg.touchCount(); got != 1 {
+		t.Errorf("background got %d touches, want 1 (only outside the foreground)", got)
+	}
+}
+
+func TestStripCompositesOverBackgroundClippedToRegion(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	strip, _ := dev.GetTouchStripImageRectangle()
+
+	// Half-transparent white across the whole strip, though the module only
+	// owns the left half
+	m := newStubModule("half")
+	m.strip = func(image.Rectangle) image.Image {
+		img := image.NewRGBA(strip)
+		draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{128, 128, 128, 128}}, image.Point{}, draw.Src)
+		return img
+	}
+	left := image.Rect(strip.Min.X, strip.Min.Y, strip.Dx()/2, strip.Max.Y)
+	c.RegisterModule(m, module.Resources{StripRect: left})
+	startCoordinator(t, c, dev)
+
+	out := dev.StripImage()
+	got := rgbaAt(out, 10, 10)
+	if got.A != 255 || got.R <= colorStripBg.R || got.R >= 255 {
+		t.Errorf("pixel in region = %v, want white blended over %v", got, colorStripBg)
+	}
+	if got := rgbaAt(out, strip.Max.X-10, 10); got != colorStripBg {
+		t.Errorf("pixel outside region = %v, want background %v", got, colorStripBg)
+	}
+}
+
+// failingStrip is a fake device whose strip writes fail while failing is set.
+type failingStrip struct {
+	*device.Fake
+	failing bool
+	writes  int
+}
+
+func (d *failingStrip) SetTouchStripImage(img image.Image) error {
+	d.writes++
+	if d.failing {
+		return errors.New("write failed")
+	}
+	return d.Fake.SetTouchStripImage(img)
+}
+
+func TestStripDisabledAfterRepeatedWriteFailures(t *testing.T) {
+	dev := &failingStrip{Fake: device.NewFake(), failing: true}
+	c := New(dev)
+	c.stripRect, _ = dev.GetTouchStripImageRectangle()
+	img := image.NewRGBA(c.stripRect)
+
+	// A success in between resets the count
+	for range maxStripFailures - 1 {
+		c.writeStrip(img)
+	}
+	dev.failing = false
+	c.writeStrip(img)
+	dev.failing = true
+	for range maxStripFailures - 1 {
+		c.writeStrip(img)
+	}
+	if !c.stripEnabled() {
+		t.Fatal("strip disabled without maxStripFailures failures in a row")
+	}
+
+	c.writeStrip(img)
+	if c.stripEnabled() {
+		t.Fatal("strip still enabled after maxStripFailures failures in a row")
+	}
+	writes := dev.writes
+	c.writeStrip(img)
+	if dev.writes != writes {
+		t.Error("disabled strip was still written")
+	}
+}
+
+func TestStripOffRendersKeysOnly(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.StripMode = StripOff
+
+	m := newStubModule("m")
+	m.strip = fillStrip(color.RGBA{200, 0, 0, 255})
+	strip, _ := dev.GetTouchStripImageRectangle()
+	c.RegisterModule(m, module.Resources{StripRect: strip})
+	startCoordinator(t, c, dev)
+
+	if m.Resources().HasStrip() {
+		t.Errorf("module has strip region %v in strip-off mode, want none", m.Resources().StripRect)
+	}
+	if got := rgbaAt(dev.StripImage(), 10, 10); got != (color.RGBA{}) {
+		t.Errorf("strip pixel = %v, want blank", got)
+	}
+
+	c.routeStripEvent(module.TouchStripEvent{Type: module.TouchTap, Point: image.Pt(10, 10)})
+	if got := m.touchCount(); got != 0 {
+		t.Errorf("module got %d touches in strip-off mode, want 0", got)
+	}
+}
+
+func TestStripTouchesNormalized(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	strip, _ := dev.GetTouchStripImageRectangle()
+
+	m := newStubModule("stub")
+	c.RegisterModule(m, module.Resources{StripRect: strip})
+	startCoordinator(t, c, dev)
+
+	tap, end := image.Pt(200, 50), image.Pt(600, 25)
+	dev.Dispatch(device.InputEvent{Type: device.InputTouch, TouchType: device.TOUCH_STRIP_TOUCH_TYPE_SHORT, Point: &tap})
+	dev.Dispatch(device.InputEvent{Type: device.InputSwipe, Point: &tap, Dest: &end})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.touches) != 2 {
+		t.Fatalf("got %d touches, want a tap and a swipe", len(m.touches))
+	}
+	if got, want := m.touches[0].Pos, (module.StripPoint{X: 0.25, Y: 0.5}); got != want {
+		t.Errorf("tap Pos = %v, want %v", got, want)
+	}
+	if got, want := m.touches[1].SwipeEndPos, (module.StripPoint{X: 0.75, Y: 0.25}); got != want {
+		t.Errorf("swipe SwipeEndPos = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package coordinator
+
+import (
+	"log"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// Strip modes accepted in BELOWDECK_STRIP_MODE.
+const (
+	// StripSplit divides the strip between modules side by side.
+	StripSplit = "split"
+
+	// StripCycle gives the whole strip to one module at a time, taking
+	// turns on a timer or when the strip is swiped.
+	StripCycle = "cycle"
+
+	// StripOff leaves the strip dark and unused, rendering keys only.
+	StripOff = "off"
+)
+
+// setupStripCycle prepares cycle mode: every module with a strip region is
+// given the full strip instead, and the first of them is shown. It runs
+// before modules are initialized, so they lay out for the full width.
+func (c *Coordinator) setupStripCycle() {
+	if c.config.StripMode != StripCycle || c.stripRect.Empty() {
+		return
+	}
+
+	c.mu.Lock()
+	var cycled []module.Module
+	for _, m := range c.modules {
+		res := c.moduleResources[m]
+		if !res.HasStrip() {
+			continue
+		}
+		res.StripRect = c.stripRect
+		c.moduleResources[m] = res
+		cycled = append(cycled, m)
+	}
+	c.mu.Unlock()
+
+	c.stripCycleMu.Lock()
+	c.stripModules = cycled
+	c.stripActive = 0
+	c.stripShownAt = time.Now()
+	c.stripCycleMu.Unlock()
+
+	if len(cycled) > 0 {
+		log.Printf("Strip cycling between %d modules", len(cycled))
+	}
+}
+
+// cyclingStrip reports whether modules take turns on the strip.
+func (c *Coordinator) cyclingStrip() bool {
+	c.stripCycleMu.Lock()
+	defer c.stripCycleMu.Unlock()
+	return len(c.stripModules) > 0
+}
+
+// showsStrip reports whether m's strip output is currently visible. Outside
+// cycle mode every module with a strip region is.
+func (c *Coordinator) showsStrip(m module.Module) bool {
+	c.stripCycleMu.Lock()
+	defer c.stripCycleMu.Unlock()
+	if len(c.stripModules) == 0 {
+		return true
+	}
+	return c.stripModules[c.stripActive] == m
+}
+
+// activeStripModule returns the module currently shown on the strip in cycle
+// mode, or nil outside it.
+func (c *Coordinator) activeStripModule() module.Module {
+	c.stripCycleMu.Lock()
+	defer c.stripCycleMu.Unlock()
+	if len(c.stripModules) == 0 {
+		return nil
+	}
+	return c.stripModules[c.stripActive]
+}
+
+// advanceStripCycle moves to the next strip module once the current one has
+// been shown for StripCycleInterval. A zero interval only cycles on swipes.
+func (c *Coordinator) advanceStripCycle() {
+	if c.config.StripCycleInterval <= 0 {
+		return
+	}
+
+	c.stripCycleMu.Lock()
+	due := len(c.stripModules) > 1 && time.Since(c.stripShownAt) >= c.config.StripCycleInterval
+	c.stripCycleMu.Unlock()
+
+	if due {
+		c.cycleStrip(1)
+	}
+}
+
+// cycleStrip shows the next strip module in the given direction (1 for
+// forward, -1 for back), skipping failed and snoozed modules. The timer
+// restarts either way.
+func (c *Coordinator) cycleStrip(dir int) {
+	c.stripCycleMu.Lock()
+	defer c.stripCycleMu.Unlock()
+
+	n := len(c.stripModules)
+	if n == 0 {
+		return
+	}
+
+	c.stripShownAt = time.Now()
+	for step := 1; step <= n; step++ {
+		i := ((c.stripActive+dir*step)%n + n) % n
+		m := c.stripModules[i]
+		if c.isInactive(m) || c.isSnoozed(m) {
+			continue
+		}
+		c.stripActive = i
+		return
+	}
+}
+
+// routeCycledStripEvent handles a strip event in cycle mode: a horizontal
+// swipe switches modules (swiping left shows the next one), anything else
+// goes to the module being shown.
+func (c *Coordinator) routeCycledStripEvent(event module.TouchStripEvent) error {
+	if event.Type == module.TouchSwipe {
+		if event.SwipeEnd.X < event.SwipeStart.X {
+			c.cycleStrip(1)
+		} else {
+			c.cycleStrip(-1)
+		}
+		c.requestRender()
+		return nil
+	}
+
+	m := c.activeStripModule()
+	if m == nil || c.isInactive(m) || c.isSnoozed(m) {
+		return nil
+	}
+	return m.HandleStripTouch(event)
+}
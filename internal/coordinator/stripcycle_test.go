@@ -0,0 +1,55 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestStripCycleSwipesBetweenModules(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	c.config.StripMode = StripCycle
+	c.config.StripCycleInterval = 0
+	strip, _ := dev.GetTouchStripImageRectangle()
+
+	red, blue := color.RGBA{200, 0, 0, 255}, color.RGBA{0, 0, 200, 255}
+	first, second, snoozed := newStubModule("first"), newStubModule("second"), newStubModule("snoozed")
+	first.strip, second.strip, snoozed.strip = fillStrip(red), fillStrip(blue), fillStrip(red)
+	half := image.Rect(0, 0, strip.Dx()/2, strip.Dy())
+	c.RegisterModule(first, module.Resources{StripRect: half})
+	c.RegisterModule(snoozed, module.Resources{StripRect: half})
+	c.RegisterModule(second, module.Resources{StripRect: half.Add(image.Pt(strip.Dx()/2, 0))})
+	snoozed.Snooze(time.Now().Add(time.Hour))
+	startCoordinator(t, c, dev)
+
+	// Each module gets the whole strip
+	if got := first.Resources().StripRect; got != strip {
+		t.Errorf("cycled module's strip region = %v, want the whole strip %v", got, strip)
+	}
+	if got := rgbaAt(dev.StripImage(), strip.Max.X-10, 10); got != red {
+		t.Errorf("strip shows %v, want the first module's %v across it", got, red)
+	}
+
+	// Swiping left skips the snoozed module
+	c.routeStripEvent(module.TouchStripEvent{Type: module.TouchSwipe, SwipeStart: image.Pt(300, 50), SwipeEnd: image.Pt(100, 50)})
+	waitFor(t, "the second module's strip", func() bool {
+		return rgbaAt(dev.StripImage(), 10, 10) == blue
+	})
+
+	// Taps go to the module shown
+	c.routeStripEvent(module.TouchStripEvent{Type: module.TouchTap, Point: image.Pt(10, 10)})
+	if first.touchCount() != 0 || second.touchCount() != 1 {
+		t.Errorf("touches: first %d, second %d; want only the shown module touched", first.touchCount(), second.touchCount())
+	}
+
+	// Swiping right goes back
+	c.routeStripEvent(module.TouchStripEvent{Type: module.TouchSwipe, SwipeStart: image.Pt(100, 50), SwipeEnd: image.Pt(300, 50)})
+	if got := c.activeStripModule(); got != first {
+		t.Errorf("after swiping back, showing %v, want first", got.ID())
+	}
+}
@@ -0,0 +1,104 @@
+package coordinator
+
+import (
+	"image"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// stripSchedule tracks each module's last strip render so modules
+// implementing module.StripScheduler are only re-rendered when due, and the
+// strip is only re-composited when something on it may have changed.
+type stripSchedule struct {
+	mu       sync.Mutex
+	rendered map[module.Module]time.Time
+	images   map[module.Module]image.Image
+
+	// invalidated is when strips were last invalidated; strips rendered
+	// before it are due.
+	invalidated time.Time
+
+	// composited lists the modules in the last composite; dirty means the
+	// device strip no longer shows it, e.g. after an overlay or standby.
+	composited []module.Module
+	dirty      bool
+}
+
+// stripDue reports whether m's strip should be rendered this cycle.
+func (c *Coordinator) stripDue(m module.Module, now time.Time) bool {
+	sched, ok := m.(module.StripScheduler)
+	if !ok || sched.StripInterval() <= 0 {
+		return true
+	}
+
+	// New data makes the strip due, even within its interval
+	var updated time.Time
+	if u, ok := m.(module.StripUpdater); ok {
+		updated = u.StripUpdated()
+	}
+
+	s := &c.stripSchedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.rendered[m]
+	return !ok || last.Before(s.invalidated) || last.Before(updated) || now.Sub(last) >= sched.StripInterval()
+}
+
+// recordStrip stores the strip m rendered at now for reuse until it's due.
+func (c *Coordinator) recordStrip(m module.Module, img image.Image, now time.Time) {
+	s := &c.stripSchedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rendered == nil {
+		s.rendered = make(map[module.Module]time.Time)
+		s.images = make(map[module.Module]image.Image)
+	}
+	s.rendered[m] = now
+	s.images[m] = img
+}
+
+// cachedStrip returns the strip m last rendered.
+func (c *Coordinator) cachedStrip(m module.Module) image.Image {
+	s := &c.stripSchedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.images[m]
+}
+
+// invalidateStrips makes every strip due and forces the next composite.
+// Input may change what any module shows, so it's called on every input.
+func (c *Coordinator) invalidateStrips() {
+	s := &c.stripSchedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invalidated = time.Now()
+	s.dirty = true
+}
+
+// markStripDirty forces the next composite, after something other than
+// the composite was written to the strip.
+func (c *Coordinator) markStripDirty() {
+	s := &c.stripSchedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+}
+
+// needsComposite reports whether the strip must be re-composited from the
+// given modules: one of them rendered a fresh strip, the set of modules
+// changed, or the strip was overwritten since the last composite. It
+// records the modules as composited.
+func (c *Coordinator) needsComposite(mods []module.Module, fresh bool) bool {
+	s := &c.stripSchedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !fresh && !s.dirty && slices.Equal(mods, s.composited) {
+		return false
+	}
+	s.composited = mods
+	s.dirty = false
+	return true
+}
@@ -0,0 +1,99 @@
+package coordinator
+
+import (
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// scheduledModule is a stub module with a slow strip interval whose data
+// tests update.
+type scheduledModule struct {
+	*stubModule
+
+	mu      sync.Mutex
+	updated time.Time
+}
+
+func (m *scheduledModule) StripInterval() time.Duration { return time.Hour }
+
+func (m *scheduledModule) StripUpdated() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updated
+}
+
+func (m *scheduledModule) update(at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updated = at
+}
+
+func TestStripDueOnNewData(t *testing.T) {
+	c := New(device.NewFake())
+	m := &scheduledModule{stubModule: newStubModule("slow")}
+
+	start := time.Now()
+	if !c.stripDue(m, start) {
+		t.Fatal("strip not due before its first render")
+	}
+	c.recordStrip(m, image.NewRGBA(image.Rect(0, 0, 1, 1)), start)
+
+	if c.stripDue(m, start.Add(time.Second)) {
+		t.Error("strip due within its interval without new data")
+	}
+
+	m.update(start.Add(2 * time.Second))
+	if !c.stripDue(m, start.Add(3*time.Second)) {
+		t.Error("strip not due after new data")
+	}
+
+	c.recordStrip(m, image.NewRGBA(image.Rect(0, 0, 1, 1)), start.Add(3*time.Second))
+	if c.stripDue(m, start.Add(4*time.Second)) {
+		t.Error("strip still due after rendering the new data")
+	}
+}
+
+func TestSlowStripNotRenderedEveryCycle(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	strip, _ := dev.GetTouchStripImageRectangle()
+	left := image.Rect(strip.Min.X, strip.Min.Y, strip.Dx()/2, strip.Max.Y)
+	right := image.Rect(strip.Dx()/2, strip.Min.Y, strip.Max.X, strip.Max.Y)
+
+	counted := func(n *int) func(image.Rectangle) image.Image {
+		return func(rect image.Rectangle) image.Image {
+			*n++
+			return image.NewRGBA(rect)
+		}
+	}
+	var fastRenders, slowRenders int
+	fast := newStubModule("fast")
+	fast.strip = counted(&fastRenders)
+	slow := &scheduledModule{stubModule: newStubModule("slow")}
+	slow.strip = counted(&slowRenders)
+	c.RegisterModule(fast, module.Resources{StripRect: left})
+	c.RegisterModule(slow, module.Resources{StripRect: right})
+
+	for range 5 {
+		for _, m := range []module.Module{fast, slow} {
+			if f := c.renderFrame(m, true); f.strip == nil {
+				t.Fatalf("%s has no strip this cycle", m.ID())
+			}
+		}
+	}
+	if fastRenders != 5 || slowRenders != 1 {
+		t.Errorf("rendered the fast strip %d times and the slow one %d, want 5 and 1", fastRenders, slowRenders)
+	}
+
+	// Input makes the slow strip due again
+	c.invalidateStrips()
+	c.renderFrame(slow, true)
+	if slowRenders != 2 {
+		t.Errorf("rendered the slow strip %d times after input, want 2", slowRenders)
+	}
+}
@@ -0,0 +1,85 @@
+package coordinator
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/draw"
+)
+
+var (
+	colorHelpBg    = color.RGBA{30, 30, 30, 255}
+	colorHelpTitle = color.RGBA{255, 255, 255, 255}
+	colorHelpText  = color.RGBA{160, 160, 160, 255}
+)
+
+// helpKeyLines are shown one per key, in order, when no modules are
+// active, so the message gets across even without a strip.
+var helpKeyLines = []string{"No", "modules", "active", "", "Set up", ".env.local", "& restart"}
+
+// helpStripLines are shown on the strip when no modules are active, the
+// first as a title.
+var helpStripLines = []string{
+	"No modules are active",
+	"Configure them in .env.local (see .env.local.example), then restart belowdeck.",
+	"The log says which modules were skipped and why.",
+}
+
+// noActiveModules reports whether no module is running: none registered,
+// because none was configured or fit the device, or every one failed to
+// initialize or was stopped.
+func (c *Coordinator) noActiveModules() bool {
+	for _, m := range c.modules {
+		if !c.isInactive(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// renderHelp shows how to set up modules in place of a dead, black deck
+// when none are active. The strip is only rewritten after something else
+// has drawn over it.
+func (c *Coordinator) renderHelp() {
+	keyRect, err := c.device.GetKeyImageRectangle()
+	if err == nil {
+		images := make(map[module.KeyID]image.Image)
+		for i, keyID := range c.allKeys() {
+			img := image.NewRGBA(keyRect)
+			draw.Draw(img, img.Bounds(), &image.Uniform{colorHelpBg}, image.Point{}, draw.Src)
+			if i < len(helpKeyLines) {
+				drawCenteredBasic(img, helpKeyLines[i], keyRect.Dx()/2, keyRect.Dy()/2+4, colorHelpTitle)
+			}
+			images[keyID] = img
+		}
+		c.setKeyImages(images)
+	}
+
+	if !c.stripEnabled() || !c.needsComposite(nil, c.helpStrip == nil) {
+		return
+	}
+	if c.helpStrip == nil {
+		c.helpStrip = renderHelpStrip(c.stripRect)
+	}
+	c.writeStrip(c.helpStrip)
+}
+
+// renderHelpStrip renders helpStripLines centered on a strip covering rect.
+func renderHelpStrip(rect image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorHelpBg}, image.Point{}, draw.Src)
+
+	const lineHeight = 20
+	centerX := rect.Min.X + rect.Dx()/2
+	y := rect.Min.Y + (rect.Dy()-len(helpStripLines)*lineHeight)/2 + 14
+	for i, line := range helpStripLines {
+		col := colorHelpText
+		if i == 0 {
+			col = colorHelpTitle
+		}
+		drawCenteredBasic(img, line, centerX, y, col)
+		y += lineHeight
+	}
+	return img
+}
@@ -0,0 +1,78 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// failingInitModule is a stub module that fails to initialize.
+type failingInitModule struct {
+	*stubModule
+}
+
+func (m failingInitModule) Init(ctx context.Context, res module.Resources) error {
+	return errors.New("not configured")
+}
+
+// showsHelp reports whether the deck shows the setup help, on the keys and
+// the strip.
+func showsHelp(t *testing.T, dev *device.Fake) bool {
+	t.Helper()
+	key, strip := dev.KeyImage(device.KEY_8), dev.StripImage()
+	if key == nil || strip == nil {
+		return false
+	}
+	return rgbaAt(key, 1, 1) == colorHelpBg && rgbaAt(strip, 1, 1) == colorHelpBg
+}
+
+func TestHelpShownWithoutModules(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(c *Coordinator, res module.Resources)
+	}{
+		{"none registered", func(c *Coordinator, res module.Resources) {}},
+		{"all failed", func(c *Coordinator, res module.Resources) {
+			c.RegisterModule(failingInitModule{newStubModule("broken")}, res)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := device.NewFake()
+			c := New(dev)
+			strip, _ := dev.GetTouchStripImageRectangle()
+			tt.register(c, module.Resources{Keys: []module.KeyID{1}, StripRect: strip})
+			startCoordinator(t, c, dev)
+
+			if !c.noActiveModules() {
+				t.Fatal("modules reported active")
+			}
+			if !showsHelp(t, dev) {
+				t.Error("deck not showing the setup help")
+			}
+		})
+	}
+}
+
+func TestHelpNotShownWithActiveModule(t *testing.T) {
+	dev := device.NewFake()
+	c := New(dev)
+	red := color.RGBA{200, 0, 0, 255}
+	m := &keyModule{stubModule: newStubModule("red"), col: red}
+	strip, _ := dev.GetTouchStripImageRectangle()
+	m.strip = fillStrip(red)
+	c.RegisterModule(m, module.Resources{Keys: []module.KeyID{8}, StripRect: strip})
+	c.RegisterModule(failingInitModule{newStubModule("broken")}, module.Resources{Keys: []module.KeyID{1}})
+	startCoordinator(t, c, dev)
+
+	if c.noActiveModules() {
+		t.Fatal("no modules reported active")
+	}
+	if showsHelp(t, dev) {
+		t.Error("deck showing the setup help with a module active")
+	}
+}
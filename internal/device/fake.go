@@ -0,0 +1,363 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory Device shaped like a Stream Deck Plus. It has no
+// display or input of its own; images are kept in memory and input is fed in
+// with Replay. Use it to reproduce interaction bugs from an input log.
+type Fake struct {
+	mu         sync.Mutex
+	open       bool
+	keyImages  map[KeyID]image.Image
+	stripImage image.Image
+	keyBatches int
+
+	keyHandlers        map[KeyID][]KeyHandler
+	dialRotateHandlers map[DialID][]DialRotateHandler
+	dialSwitchHandlers map[DialID][]DialSwitchHandler
+	touchHandlers      []TouchStripTouchHandler
+	swipeHandlers      []TouchStripSwipeHandler
+
+	listening chan struct{}
+	closing   chan struct{}
+}
+
+// NewFake creates a fake device.
+func NewFake() *Fake {
+	return &Fake{
+		keyImages:          make(map[KeyID]image.Image),
+		keyHandlers:        make(map[KeyID][]KeyHandler),
+		dialRotateHandlers: make(map[DialID][]DialRotateHandler),
+		dialSwitchHandlers: make(map[DialID][]DialSwitchHandler),
+		listening:          make(chan struct{}),
+		closing:            make(chan struct{}),
+	}
+}
+
+// Open marks the device open.
+func (f *Fake) Open() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.open = true
+	return nil
+}
+
+// Close marks the device closed and stops Listen.
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.open = false
+	select {
+	case <-f.closing:
+	default:
+		close(f.closing)
+	}
+	return nil
+}
+
+// IsOpen returns whether the device is open.
+func (f *Fake) IsOpen() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.open
+}
+
+// GetModelName returns the device model name.
+func (f *Fake) GetModelName() string { return "Fake Stream Deck +" }
+
+// GetSerialNumber returns a fixed serial number.
+func (f *Fake) GetSerialNumber() string { return "FAKE00000001" }
+
+// GetFirmwareVersion returns a fixed firmware version.
+func (f *Fake) GetFirmwareVersion() (string, error) { return "1.00.000", nil }
+
+// GetKeyCount returns the number of keys.
+func (f *Fake) GetKeyCount() byte { return 8 }
+
+// GetDialCount returns the number of dials.
+func (f *Fake) GetDialCount() byte { return 4 }
+
+// GetTouchStripSupported returns true; the fake has a strip.
+func (f *Fake) GetTouchStripSupported() bool { return true }
+
+// GetKeyImageRectangle returns the key image size.
+func (f *Fake) GetKeyImageRectangle() (image.Rectangle, error) {
+	return image.Rect(0, 0, 120, 120), nil
+}
+
+// GetTouchStripImageRectangle returns the strip image size.
+func (f *Fake) GetTouchStripImageRectangle() (image.Rectangle, error) {
+	return image.Rect(0, 0, 800, 100), nil
+}
+
+// SetBrightness is a no-op.
+func (f *Fake) SetBrightness(perc byte) error { return nil }
+
+// SetKeyImage stores the image for a key.
+func (f *Fake) SetKeyImage(key KeyID, img image.Image) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keyImages[key] = img
+	return nil
+}
+
+// SetKeyImages stores the images for several keys at once.
+func (f *Fake) SetKeyImages(images map[KeyID]image.Image) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, img := range images {
+		f.keyImages[key] = img
+	}
+	f.keyBatches++
+	return nil
+}
+
+// SetTouchStripImage stores the strip image.
+func (f *Fake) SetTouchStripImage(img image.Image) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stripImage = img
+	return nil
+}
+
+// ClearKey removes a key's image.
+func (f *Fake) ClearKey(key KeyID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.keyImages, key)
+	return nil
+}
+
+// KeyImage returns the last image set for a key, or nil.
+func (f *Fake) KeyImage(key KeyID) image.Image {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.keyImages[key]
+}
+
+// KeyBatches returns how many times SetKeyImages has been called.
+func (f *Fake) KeyBatches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.keyBatches
+}
+
+// StripImage returns the last strip image, or nil.
+func (f *Fake) StripImage() image.Image {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stripImage
+}
+
+// ForEachKey calls the callback for each key.
+func (f *Fake) ForEachKey(cb func(KeyID) error) error {
+	for k := KeyID(1); k <= KeyID(f.GetKeyCount()); k++ {
+		if err := cb(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachDial calls the callback for each dial.
+func (f *Fake) ForEachDial(cb func(DialID) error) error {
+	for d := DialID(1); d <= DialID(f.GetDialCount()); d++ {
+		if err := cb(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddKeyHandler registers a key press handler.
+func (f *Fake) AddKeyHandler(key KeyID, fn KeyHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keyHandlers[key] = append(f.keyHandlers[key], fn)
+	return nil
+}
+
+// AddDialRotateHandler registers a dial rotation handler.
+func (f *Fake) AddDialRotateHandler(dial DialID, fn DialRotateHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dialRotateHandlers[dial] = append(f.dialRotateHandlers[dial], fn)
+	return nil
+}
+
+// AddDialSwitchHandler registers a dial press handler.
+func (f *Fake) AddDialSwitchHandler(dial DialID, fn DialSwitchHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dialSwitchHandlers[dial] = append(f.dialSwitchHandlers[dial], fn)
+	return nil
+}
+
+// AddTouchStripTouchHandler registers a touch strip touch handler.
+func (f *Fake) AddTouchStripTouchHandler(fn TouchStripTouchHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.touchHandlers = append(f.touchHandlers, fn)
+	return nil
+}
+
+// AddTouchStripSwipeHandler registers a touch strip swipe handler.
+func (f *Fake) AddTouchStripSwipeHandler(fn TouchStripSwipeHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.swipeHandlers = append(f.swipeHandlers, fn)
+	return nil
+}
+
+// Listen blocks until Close is called.
+func (f *Fake) Listen(errCh chan error) error {
+	f.mu.Lock()
+	select {
+	case <-f.listening:
+	default:
+		close(f.listening)
+	}
+	f.mu.Unlock()
+
+	<-f.closing
+	return nil
+}
+
+// Listening is closed once Listen has been called, i.e. once the owner of
+// the device has registered its handlers.
+func (f *Fake) Listening() <-chan struct{} {
+	return f.listening
+}
+
+// Replay delivers events to the registered handlers in order, waiting
+// between them so each arrives at its recorded offset from the start of the
+// replay. Handlers run one at a time; a key or dial released by a handler
+// reports the recorded hold duration immediately.
+func (f *Fake) Replay(ctx context.Context, events []InputEvent) error {
+	start := time.Now()
+	for _, ev := range events {
+		if wait := ev.At - time.Since(start); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		if err := f.Dispatch(ev); err != nil {
+			return fmt.Errorf("replay %s at %v: %w", ev.Type, ev.At, err)
+		}
+	}
+	return nil
+}
+
+// Dispatch delivers a single event to the registered handlers.
+func (f *Fake) Dispatch(ev InputEvent) error {
+	f.mu.Lock()
+	keyHandlers := f.keyHandlers[ev.Key]
+	rotateHandlers := f.dialRotateHandlers[ev.Dial]
+	switchHandlers := f.dialSwitchHandlers[ev.Dial]
+	touchHandlers := f.touchHandlers
+	swipeHandlers := f.swipeHandlers
+	f.mu.Unlock()
+
+	var err error
+	switch ev.Type {
+	case InputKey:
+		for _, h := range keyHandlers {
+			err = firstErr(err, h(f, &fakeKey{id: ev.Key, held: ev.Held}))
+		}
+	case InputDialRotate:
+		for _, h := range rotateHandlers {
+			err = firstErr(err, h(f, &fakeDial{id: ev.Dial}, ev.Delta))
+		}
+	case InputDialPress:
+		for _, h := range switchHandlers {
+			err = firstErr(err, h(f, &fakeDial{id: ev.Dial, held: ev.Held}))
+		}
+	case InputTouch:
+		for _, h := range touchHandlers {
+			err = firstErr(err, h(f, ev.TouchType, pointOrZero(ev.Point)))
+		}
+	case InputSwipe:
+		for _, h := range swipeHandlers {
+			err = firstErr(err, h(f, pointOrZero(ev.Point), pointOrZero(ev.Dest)))
+		}
+	default:
+		return fmt.Errorf("unknown input type %q", ev.Type)
+	}
+	return err
+}
+
+// firstErr returns err if set, otherwise next.
+func firstErr(err, next error) error {
+	if err != nil {
+		return err
+	}
+	return next
+}
+
+// pointOrZero dereferences p, treating nil as the origin.
+func pointOrZero(p *image.Point) image.Point {
+	if p == nil {
+		return image.Point{}
+	}
+	return *p
+}
+
+// fakeKey is a replayed key press.
+type fakeKey struct {
+	id   KeyID
+	held time.Duration
+}
+
+func (k *fakeKey) GetID() KeyID                  { return k.id }
+func (k *fakeKey) WaitForRelease() time.Duration { return k.held }
+
+// fakeDial is a replayed dial input.
+type fakeDial struct {
+	id   DialID
+	held time.Duration
+}
+
+func (d *fakeDial) GetID() DialID                 { return d.id }
+func (d *fakeDial) WaitForRelease() time.Duration { return d.held }
+
+// StandbyFake is a Fake whose firmware supports standby, for exercising the
+// StandbySetter path. A plain Fake falls back to the software screensaver.
+type StandbyFake struct {
+	*Fake
+
+	mu             sync.Mutex
+	standbyImage   image.Image
+	standbyTimeout time.Duration
+}
+
+// NewStandbyFake creates a fake device with firmware standby support.
+func NewStandbyFake() *StandbyFake {
+	return &StandbyFake{Fake: NewFake()}
+}
+
+// StandbySupported reports that the fake handles standby.
+func (f *StandbyFake) StandbySupported() bool { return true }
+
+// SetStandbyImage stores the standby image and timeout.
+func (f *StandbyFake) SetStandbyImage(img image.Image, timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.standbyImage = img
+	f.standbyTimeout = timeout
+	return nil
+}
+
+// StandbyImage returns the last standby image set, or nil, and its timeout.
+func (f *StandbyFake) StandbyImage() (image.Image, time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.standbyImage, f.standbyTimeout
+}
@@ -1,30 +1,99 @@
 package device
 
 import (
+	"errors"
 	"image"
+	"sync"
 	"time"
 
 	"rafaelmartins.com/p/streamdeck"
+	"rafaelmartins.com/p/usbhid"
 )
 
+// closeTimeout bounds how long Close waits for the listener to stop once
+// the handle is closed. Closing the handle fails a pending HID read on
+// Linux, but the macOS backend only wakes its read for the next report or
+// a removal; a listener left waiting there has no callbacks behind it, so
+// it can never deliver input from the closed handle.
+var closeTimeout = 3 * time.Second
+
+// ErrCloseTimeout is returned by Close when the listener didn't stop in time.
+var ErrCloseTimeout = errors.New("device: listener didn't stop after close")
+
+// listenCloser is the part of streamdeck.Device that Listen and Close
+// drive, so tests can stand in for the HID handle.
+type listenCloser interface {
+	Listen(errCh chan error) error
+	Close() error
+}
+
 // HardwareDevice wraps the real streamdeck.Device to implement the Device interface.
 type HardwareDevice struct {
-	dev *streamdeck.Device
+	dev  *streamdeck.Device
+	conn listenCloser
+
+	// hid is dev's HID handle if its firmware handles standby, for
+	// sending the sleep timer; nil otherwise
+	hid *usbhid.Device
+
+	// closed is set when Close starts, and listening is closed when the
+	// running Listen returns, so Close can wait for it
+	mu        sync.Mutex
+	closed    bool
+	listening chan struct{}
 }
 
 // NewHardware creates a new hardware device wrapper.
 func NewHardware(dev *streamdeck.Device) *HardwareDevice {
-	return &HardwareDevice{dev: dev}
+	h := &HardwareDevice{
+		dev:  dev,
+		conn: dev,
+	}
+	if standbyModels[dev.GetModelID()] {
+		h.hid, _ = hidHandle(dev)
+	}
+	return h
 }
 
 // Open opens the device for use.
 func (h *HardwareDevice) Open() error {
-	return h.dev.Open()
+	if err := h.dev.Open(); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.closed = false
+	h.mu.Unlock()
+	return nil
 }
 
-// Close closes the device.
+// Close closes the device and waits for Listen to return. Closing the
+// handle is what ends the listener's blocking read, so the listener is
+// stopped rather than left reading while a new handle is opened. Returns
+// ErrCloseTimeout if the listener is still in its read after closeTimeout.
 func (h *HardwareDevice) Close() error {
-	return h.dev.Close()
+	h.mu.Lock()
+	h.closed = true
+	listening := h.listening
+	h.mu.Unlock()
+
+	err := h.conn.Close()
+	if listening == nil {
+		return err
+	}
+
+	select {
+	case <-listening:
+		return err
+	case <-time.After(closeTimeout):
+		return errors.Join(err, ErrCloseTimeout)
+	}
+}
+
+// isClosed reports whether Close has been called since the device was opened.
+func (h *HardwareDevice) isClosed() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.closed
 }
 
 // IsOpen returns whether the device is open.
@@ -37,6 +106,16 @@ func (h *HardwareDevice) GetModelName() string {
 	return h.dev.GetModelName()
 }
 
+// GetSerialNumber returns the device's serial number.
+func (h *HardwareDevice) GetSerialNumber() string {
+	return h.dev.GetSerialNumber()
+}
+
+// GetFirmwareVersion returns the device's firmware version.
+func (h *HardwareDevice) GetFirmwareVersion() (string, error) {
+	return h.dev.GetFirmwareVersion()
+}
+
 // GetKeyCount returns the number of keys on the device.
 func (h *HardwareDevice) GetKeyCount() byte {
 	return h.dev.GetKeyCount()
@@ -157,9 +236,25 @@ func (h *HardwareDevice) AddTouchStripSwipeHandler(fn TouchStripSwipeHandler) er
 	})
 }
 
-// Listen starts the device event loop.
+// Listen starts the device event loop, returning once the device is
+// closed or fails. The read error from a handle closed by Close is a
+// clean stop, and is reported as nil.
 func (h *HardwareDevice) Listen(errCh chan error) error {
-	return h.dev.Listen(errCh)
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	done := make(chan struct{})
+	h.listening = done
+	h.mu.Unlock()
+	defer close(done)
+
+	err := h.conn.Listen(errCh)
+	if h.isClosed() {
+		return nil
+	}
+	return err
 }
 
 // Underlying returns the underlying streamdeck.Device for direct access when needed.
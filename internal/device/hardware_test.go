@@ -0,0 +1,118 @@
+package device
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeConn is a HID handle whose reads block until it's closed, as on
+// Linux, or until unstuck is closed if it's set, as on macOS.
+type fakeConn struct {
+	unstuck chan struct{}
+
+	reading chan struct{}
+	handle  chan struct{}
+	stopped atomic.Bool
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{reading: make(chan struct{}), handle: make(chan struct{})}
+}
+
+func (c *fakeConn) Listen(errCh chan error) error {
+	close(c.reading)
+	if c.unstuck != nil {
+		<-c.unstuck
+		return nil
+	}
+	<-c.handle
+	c.stopped.Store(true)
+	return errors.New("read: file already closed")
+}
+
+func (c *fakeConn) Close() error {
+	close(c.handle)
+	return nil
+}
+
+// startListening runs h.Listen, returning once the read is pending, and
+// a channel with what Listen returns.
+func startListening(t *testing.T, h *HardwareDevice, conn *fakeConn) <-chan error {
+	t.Helper()
+	result := make(chan error, 1)
+	go func() {
+		result <- h.Listen(nil)
+	}()
+	select {
+	case <-conn.reading:
+	case <-time.After(time.Second):
+		t.Fatal("Listen never started reading")
+	}
+	return result
+}
+
+func TestCloseWaitsForListenToStop(t *testing.T) {
+	conn := newFakeConn()
+	h := &HardwareDevice{conn: conn}
+	result := startListening(t, h, conn)
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if !conn.stopped.Load() {
+		t.Fatal("Close returned while the listener was still reading")
+	}
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("Listen = %v after Close, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Listen didn't return after Close")
+	}
+}
+
+func TestCloseTimesOutOnStuckListener(t *testing.T) {
+	saved := closeTimeout
+	closeTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { closeTimeout = saved })
+
+	conn := newFakeConn()
+	conn.unstuck = make(chan struct{})
+	t.Cleanup(func() { close(conn.unstuck) })
+	h := &HardwareDevice{conn: conn}
+	startListening(t, h, conn)
+
+	start := time.Now()
+	if err := h.Close(); !errors.Is(err, ErrCloseTimeout) {
+		t.Errorf("Close = %v, want ErrCloseTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close took %v, want about the timeout", elapsed)
+	}
+}
+
+func TestListenAfterCloseReturns(t *testing.T) {
+	conn := newFakeConn()
+	h := &HardwareDevice{conn: conn}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Listen(nil); err != nil {
+		t.Errorf("Listen after Close = %v, want nil", err)
+	}
+}
+
+func TestListenReportsDisconnect(t *testing.T) {
+	conn := newFakeConn()
+	h := &HardwareDevice{conn: conn}
+	result := startListening(t, h, conn)
+
+	// The handle fails without Close, as when the deck is unplugged
+	close(conn.handle)
+	if err := <-result; err == nil {
+		t.Error("Listen = nil after the handle failed, want the read error")
+	}
+}
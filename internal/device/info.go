@@ -0,0 +1,12 @@
+package device
+
+// InfoProvider is implemented by devices that can identify themselves
+// beyond their model, for diagnostics. Devices without it are reported as
+// having an unknown serial number and firmware.
+type InfoProvider interface {
+	// GetSerialNumber returns the device's serial number.
+	GetSerialNumber() string
+
+	// GetFirmwareVersion returns the device's firmware version.
+	GetFirmwareVersion() (string, error)
+}
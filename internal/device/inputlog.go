@@ -0,0 +1,257 @@
+package device
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Input event types as written to an input log.
+const (
+	InputKey        = "key"
+	InputDialRotate = "dial_rotate"
+	InputDialPress  = "dial_press"
+	InputTouch      = "touch"
+	InputSwipe      = "swipe"
+)
+
+// InputEvent is one raw device input, as recorded by Recorder and replayed
+// by Fake. Input logs are JSON lines, one event per line.
+type InputEvent struct {
+	// Time is when the input started.
+	Time time.Time `json:"time"`
+
+	// At is Time relative to the first event in the log. It's filled in by
+	// ReadInputLog and isn't stored.
+	At time.Duration `json:"-"`
+
+	Type string `json:"type"`
+
+	Key  KeyID  `json:"key,omitempty"`
+	Dial DialID `json:"dial,omitempty"`
+
+	// Delta is the dial rotation for dial_rotate events.
+	Delta int8 `json:"delta,omitempty"`
+
+	// Held is how long a key or dial was held before release. It's only
+	// known when a handler waited for the release.
+	Held time.Duration `json:"held,omitempty"`
+
+	// TouchType and Point describe touch events. For swipes, Point is where
+	// the swipe started and Dest where it ended.
+	TouchType TouchStripTouchType `json:"touch_type,omitempty"`
+	Point     *image.Point        `json:"point,omitempty"`
+	Dest      *image.Point        `json:"dest,omitempty"`
+}
+
+// Recorder wraps a Device and writes every input event it delivers to an
+// input log. Everything else passes through to the wrapped device,
+// including batched key updates and device info when it supports them.
+type Recorder struct {
+	Device
+
+	mu     sync.Mutex
+	enc    *json.Encoder
+	out    io.Closer
+	closed bool
+}
+
+// NewRecorder returns dev wrapped to log its input events to w.
+func NewRecorder(dev Device, w io.Writer) *Recorder {
+	return &Recorder{
+		Device: dev,
+		enc:    json.NewEncoder(w),
+	}
+}
+
+// NewFileRecorder returns dev wrapped to append its input events to the
+// file at path. Closing the recorder closes the file too.
+func NewFileRecorder(dev Device, path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(dev, f)
+	r.out = f
+	return r, nil
+}
+
+// Close closes the wrapped device and the log file, if any.
+func (r *Recorder) Close() error {
+	err := r.Device.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.out != nil && !r.closed {
+		if cerr := r.out.Close(); err == nil {
+			err = cerr
+		}
+	}
+	r.closed = true
+	return err
+}
+
+// SetKeyImages passes a batch of key images to the wrapped device, in one
+// transaction if it supports that and key by key otherwise.
+func (r *Recorder) SetKeyImages(images map[KeyID]image.Image) error {
+	if setter, ok := r.Device.(KeyImagesSetter); ok {
+		return setter.SetKeyImages(images)
+	}
+	for key, img := range images {
+		if err := r.Device.SetKeyImage(key, img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSerialNumber returns the wrapped device's serial number, or "" if it
+// can't report one.
+func (r *Recorder) GetSerialNumber() string {
+	if info, ok := r.Device.(InfoProvider); ok {
+		return info.GetSerialNumber()
+	}
+	return ""
+}
+
+// GetFirmwareVersion returns the wrapped device's firmware version, or an
+// error if it can't report one.
+func (r *Recorder) GetFirmwareVersion() (string, error) {
+	if info, ok := r.Device.(InfoProvider); ok {
+		return info.GetFirmwareVersion()
+	}
+	return "", fmt.Errorf("%s doesn't report its firmware version", r.Device.GetModelName())
+}
+
+// StandbySupported reports whether the wrapped device handles standby in
+// firmware.
+func (r *Recorder) StandbySupported() bool {
+	return SupportsStandby(r.Device)
+}
+
+// SetStandbyImage passes the standby image to the wrapped device, or returns
+// ErrStandbyUnsupported if it doesn't handle standby.
+func (r *Recorder) SetStandbyImage(img image.Image, timeout time.Duration) error {
+	if setter, ok := r.Device.(StandbySetter); ok {
+		return setter.SetStandbyImage(img, timeout)
+	}
+	return ErrStandbyUnsupported
+}
+
+// write appends an event to the log. Events are written once the handler
+// returns, so lines may be slightly out of order; ReadInputLog sorts them.
+func (r *Recorder) write(ev InputEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	if err := r.enc.Encode(ev); err != nil {
+		log.Printf("Input log: %v", err)
+	}
+}
+
+// AddKeyHandler adds a key handler whose presses are logged.
+func (r *Recorder) AddKeyHandler(key KeyID, fn KeyHandler) error {
+	return r.Device.AddKeyHandler(key, func(d Device, k Key) error {
+		ev := InputEvent{Time: time.Now(), Type: InputKey, Key: key}
+		rk := &recordedKey{Key: k}
+		err := fn(r, rk)
+		ev.Held = rk.held
+		r.write(ev)
+		return err
+	})
+}
+
+// AddDialRotateHandler adds a dial rotation handler whose turns are logged.
+func (r *Recorder) AddDialRotateHandler(dial DialID, fn DialRotateHandler) error {
+	return r.Device.AddDialRotateHandler(dial, func(d Device, di Dial, delta int8) error {
+		r.write(InputEvent{Time: time.Now(), Type: InputDialRotate, Dial: dial, Delta: delta})
+		return fn(r, di, delta)
+	})
+}
+
+// AddDialSwitchHandler adds a dial press handler whose presses are logged.
+func (r *Recorder) AddDialSwitchHandler(dial DialID, fn DialSwitchHandler) error {
+	return r.Device.AddDialSwitchHandler(dial, func(d Device, di Dial) error {
+		ev := InputEvent{Time: time.Now(), Type: InputDialPress, Dial: dial}
+		rd := &recordedDial{Dial: di}
+		err := fn(r, rd)
+		ev.Held = rd.held
+		r.write(ev)
+		return err
+	})
+}
+
+// AddTouchStripTouchHandler adds a touch handler whose taps are logged.
+func (r *Recorder) AddTouchStripTouchHandler(fn TouchStripTouchHandler) error {
+	return r.Device.AddTouchStripTouchHandler(func(d Device, t TouchStripTouchType, p image.Point) error {
+		r.write(InputEvent{Time: time.Now(), Type: InputTouch, TouchType: t, Point: &p})
+		return fn(r, t, p)
+	})
+}
+
+// AddTouchStripSwipeHandler adds a swipe handler whose swipes are logged.
+func (r *Recorder) AddTouchStripSwipeHandler(fn TouchStripSwipeHandler) error {
+	return r.Device.AddTouchStripSwipeHandler(func(d Device, origin, destination image.Point) error {
+		r.write(InputEvent{Time: time.Now(), Type: InputSwipe, Point: &origin, Dest: &destination})
+		return fn(r, origin, destination)
+	})
+}
+
+// recordedKey captures how long a key was held when the handler waits for
+// its release.
+type recordedKey struct {
+	Key
+	held time.Duration
+}
+
+func (k *recordedKey) WaitForRelease() time.Duration {
+	k.held = k.Key.WaitForRelease()
+	return k.held
+}
+
+// recordedDial captures how long a dial was held when the handler waits for
+// its release.
+type recordedDial struct {
+	Dial
+	held time.Duration
+}
+
+func (d *recordedDial) WaitForRelease() time.Duration {
+	d.held = d.Dial.WaitForRelease()
+	return d.held
+}
+
+// ReadInputLog reads an input log written by Recorder, sorted by time, with
+// each event's At set relative to the first.
+func ReadInputLog(r io.Reader) ([]InputEvent, error) {
+	var events []InputEvent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ev InputEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	for i := range events {
+		events[i].At = events[i].Time.Sub(events[0].Time)
+	}
+	return events, nil
+}
@@ -0,0 +1,153 @@
+package device
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderForwardsOptionalInterfaces(t *testing.T) {
+	fake := NewFake()
+	var dev Device = NewRecorder(fake, io.Discard)
+
+	setter, ok := dev.(KeyImagesSetter)
+	if !ok {
+		t.Fatal("recorder doesn't implement KeyImagesSetter")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if err := setter.SetKeyImages(map[KeyID]image.Image{KEY_1: img, KEY_2: img}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.KeyBatches(); got != 1 {
+		t.Errorf("wrapped device got %d batches, want 1", got)
+	}
+
+	info, ok := dev.(InfoProvider)
+	if !ok {
+		t.Fatal("recorder doesn't implement InfoProvider")
+	}
+	if got := info.GetSerialNumber(); got != "FAKE00000001" {
+		t.Errorf("serial = %q, want the wrapped device's", got)
+	}
+	if got, err := info.GetFirmwareVersion(); err != nil || got != "1.00.000" {
+		t.Errorf("firmware = %q, %v; want the wrapped device's", got, err)
+	}
+}
+
+func TestRecorderFallsBackWithoutOptionalInterfaces(t *testing.T) {
+	fake := NewFake()
+	// Hide the fake's optional methods
+	rec := NewRecorder(struct{ Device }{fake}, io.Discard)
+
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if err := rec.SetKeyImages(map[KeyID]image.Image{KEY_1: img, KEY_2: img}); err != nil {
+		t.Fatal(err)
+	}
+	if fake.KeyImage(KEY_1) == nil || fake.KeyImage(KEY_2) == nil {
+		t.Error("keys not set one by one on a device without batches")
+	}
+	if got := rec.GetSerialNumber(); got != "" {
+		t.Errorf("serial = %q, want none", got)
+	}
+	if _, err := rec.GetFirmwareVersion(); err == nil {
+		t.Error("firmware reported for a device that can't report it")
+	}
+}
+
+func TestRecorderForwardsStandby(t *testing.T) {
+	fake := NewStandbyFake()
+	var dev Device = NewRecorder(fake, io.Discard)
+
+	if !SupportsStandby(dev) {
+		t.Fatal("recorder over a device with standby reports no support")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if err := dev.(StandbySetter).SetStandbyImage(img, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if got, timeout := fake.StandbyImage(); got != img || timeout != time.Minute {
+		t.Errorf("wrapped device got %v after %v, want the image after 1m", got, timeout)
+	}
+
+	plain := NewRecorder(NewFake(), io.Discard)
+	if SupportsStandby(plain) {
+		t.Error("recorder over a device without standby reports support")
+	}
+	if err := plain.SetStandbyImage(img, time.Minute); err != ErrStandbyUnsupported {
+		t.Errorf("SetStandbyImage without support = %v, want ErrStandbyUnsupported", err)
+	}
+}
+
+func TestRecordedInputReplays(t *testing.T) {
+	// Record a key hold, a dial turn and a swipe
+	var log bytes.Buffer
+	src := NewFake()
+	rec := NewRecorder(src, &log)
+	rec.AddKeyHandler(KEY_2, func(d Device, k Key) error {
+		k.WaitForRelease()
+		return nil
+	})
+	rec.AddDialRotateHandler(DIAL_1, func(d Device, di Dial, delta int8) error { return nil })
+	rec.AddTouchStripSwipeHandler(func(d Device, origin, dest image.Point) error { return nil })
+
+	from, to := image.Pt(10, 20), image.Pt(300, 20)
+	for _, ev := range []InputEvent{
+		{Type: InputKey, Key: KEY_2, Held: 1500 * time.Millisecond},
+		{Type: InputDialRotate, Dial: DIAL_1, Delta: -3},
+		{Type: InputSwipe, Point: &from, Dest: &to},
+	} {
+		if err := src.Dispatch(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rec.Close()
+
+	events, err := ReadInputLog(&log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("read %d events, want 3", len(events))
+	}
+
+	// Replay them into a fresh device
+	var got []string
+	dst := NewFake()
+	dst.AddKeyHandler(KEY_2, func(d Device, k Key) error {
+		got = append(got, fmt.Sprintf("key %d held %v", k.GetID(), k.WaitForRelease()))
+		return nil
+	})
+	dst.AddDialRotateHandler(DIAL_1, func(d Device, di Dial, delta int8) error {
+		got = append(got, fmt.Sprintf("dial %d by %d", di.GetID(), delta))
+		return nil
+	})
+	dst.AddTouchStripSwipeHandler(func(d Device, origin, dest image.Point) error {
+		got = append(got, fmt.Sprintf("swipe %v to %v", origin, dest))
+		return nil
+	})
+	if err := dst.Replay(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"key 2 held 1.5s",
+		"dial 1 by -3",
+		"swipe (10,20) to (300,20)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("replayed %q, want %q", got, want)
+	}
+}
+
+func TestReadInputLogBadLine(t *testing.T) {
+	_, err := ReadInputLog(strings.NewReader(`{"type":"key","key":1}` + "\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ReadInputLog = %v, want an error for line 2", err)
+	}
+}
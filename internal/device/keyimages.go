@@ -0,0 +1,12 @@
+package device
+
+import "image"
+
+// KeyImagesSetter is implemented by devices that can update several keys in
+// one transaction, so a frame that changes many keys at once, such as an
+// overlay appearing, lands together rather than key by key. Devices without
+// it get one SetKeyImage call per key.
+type KeyImagesSetter interface {
+	// SetKeyImages sets the image for each key in images.
+	SetKeyImages(images map[KeyID]image.Image) error
+}
@@ -0,0 +1,350 @@
+// Package remote provides a virtual Stream Deck that is displayed and
+// operated from a web browser. Key and strip images are streamed to the page
+// over a websocket and the page sends back key, dial and touch input.
+package remote
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"golang.org/x/net/websocket"
+)
+
+// clientQueue is how many messages may be waiting for a slow client before
+// further ones are dropped. A dropped image is replaced by the next render.
+const clientQueue = 64
+
+//go:embed client.html
+var clientHTML []byte
+
+// Message is sent to the web client. Images are PNG data URLs.
+type Message struct {
+	Type string `json:"type"`
+
+	// Geometry, sent in the "hello" message when a client connects.
+	Keys        int `json:"keys,omitempty"`
+	Dials       int `json:"dials,omitempty"`
+	KeySize     int `json:"key_size,omitempty"`
+	StripWidth  int `json:"strip_width,omitempty"`
+	StripHeight int `json:"strip_height,omitempty"`
+
+	Key   device.KeyID `json:"key,omitempty"`
+	Image string       `json:"image,omitempty"`
+}
+
+// Message types sent to the web client.
+const (
+	MessageHello = "hello"
+	MessageKey   = "key"
+	MessageClear = "clear"
+	MessageStrip = "strip"
+)
+
+// Remote is a Device shaped like a Stream Deck Plus whose display and input
+// live in any number of connected browsers. Input from the page arrives as
+// device.InputEvent values, the same format as an input log.
+type Remote struct {
+	*device.Fake
+
+	addr string
+	srv  *http.Server
+
+	mu      sync.Mutex
+	ln      net.Listener
+	clients map[*client]struct{}
+}
+
+// New creates a virtual deck that serves its web client on addr once opened.
+func New(addr string) *Remote {
+	r := &Remote{
+		Fake:    device.NewFake(),
+		addr:    addr,
+		clients: make(map[*client]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(clientHTML)
+	})
+	mux.Handle("/ws", websocket.Server{
+		Handler:   r.serveClient,
+		Handshake: checkOrigin,
+	})
+	r.srv = &http.Server{Handler: mux}
+
+	return r
+}
+
+// Open starts serving the web client.
+func (r *Remote) Open() error {
+	ln, err := net.Listen("tcp", r.addr)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.ln = ln
+	r.mu.Unlock()
+
+	go func() {
+		if err := r.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Remote deck server: %v", err)
+		}
+	}()
+
+	log.Printf("Remote deck listening on http://%s/", ln.Addr())
+	return r.Fake.Open()
+}
+
+// Close stops the server, disconnects all clients and stops Listen.
+func (r *Remote) Close() error {
+	err := r.srv.Close()
+
+	r.mu.Lock()
+	for c := range r.clients {
+		c.conn.Close()
+	}
+	r.mu.Unlock()
+
+	return errors.Join(err, r.Fake.Close())
+}
+
+// Addr returns the address the web client is served on, or nil before Open.
+func (r *Remote) Addr() net.Addr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ln == nil {
+		return nil
+	}
+	return r.ln.Addr()
+}
+
+// GetModelName returns the device model name.
+func (r *Remote) GetModelName() string { return "Remote Stream Deck +" }
+
+// SetKeyImage stores the image for a key and sends it to connected clients.
+func (r *Remote) SetKeyImage(key device.KeyID, img image.Image) error {
+	if err := r.Fake.SetKeyImage(key, img); err != nil {
+		return err
+	}
+	msg, err := keyMessage(key, img)
+	if err != nil {
+		return err
+	}
+	r.broadcast(msg)
+	return nil
+}
+
+// SetKeyImages stores the images for several keys at once and sends them
+// to connected clients.
+func (r *Remote) SetKeyImages(images map[device.KeyID]image.Image) error {
+	if err := r.Fake.SetKeyImages(images); err != nil {
+		return err
+	}
+	msgs := make([]Message, 0, len(images))
+	for key, img := range images {
+		msg, err := keyMessage(key, img)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+	r.broadcast(msgs...)
+	return nil
+}
+
+// SetTouchStripImage stores the strip image and sends it to connected
+// clients.
+func (r *Remote) SetTouchStripImage(img image.Image) error {
+	if err := r.Fake.SetTouchStripImage(img); err != nil {
+		return err
+	}
+	msg, err := stripMessage(img)
+	if err != nil {
+		return err
+	}
+	r.broadcast(msg)
+	return nil
+}
+
+// ClearKey removes a key's image and blanks it on connected clients.
+func (r *Remote) ClearKey(key device.KeyID) error {
+	if err := r.Fake.ClearKey(key); err != nil {
+		return err
+	}
+	r.broadcast(Message{Type: MessageClear, Key: key})
+	return nil
+}
+
+// hello describes the deck's geometry to a newly connected client.
+func (r *Remote) hello() Message {
+	msg := Message{
+		Type:  MessageHello,
+		Keys:  int(r.GetKeyCount()),
+		Dials: int(r.GetDialCount()),
+	}
+	if rect, err := r.GetKeyImageRectangle(); err == nil {
+		msg.KeySize = rect.Dx()
+	}
+	if rect, err := r.GetTouchStripImageRectangle(); err == nil {
+		msg.StripWidth, msg.StripHeight = rect.Dx(), rect.Dy()
+	}
+	return msg
+}
+
+// snapshot returns the messages that bring a new client up to date with
+// what's currently displayed.
+func (r *Remote) snapshot() []Message {
+	msgs := []Message{r.hello()}
+	r.ForEachKey(func(key device.KeyID) error {
+		if img := r.KeyImage(key); img != nil {
+			if msg, err := keyMessage(key, img); err == nil {
+				msgs = append(msgs, msg)
+			}
+		}
+		return nil
+	})
+	if img := r.StripImage(); img != nil {
+		if msg, err := stripMessage(img); err == nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// broadcast queues msgs, in order, for every connected client.
+func (r *Remote) broadcast(msgs ...Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for c := range r.clients {
+		for _, msg := range msgs {
+			c.send(msg)
+		}
+	}
+}
+
+// checkOrigin accepts a websocket only from the page this server serves,
+// so another site open in the browser can't drive the deck. Browsers always
+// send the Origin of the page opening the websocket; it must name the same
+// host the request was made to.
+func checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil || origin.Host != req.Host {
+		return fmt.Errorf("websocket from origin %q rejected", req.Header.Get("Origin"))
+	}
+	config.Origin = origin
+	return nil
+}
+
+// serveClient runs one websocket connection: it sends the current display,
+// then forwards display updates to the page and the page's input to the
+// registered handlers until the connection closes.
+func (r *Remote) serveClient(conn *websocket.Conn) {
+	c := &client{conn: conn, out: make(chan Message, clientQueue)}
+	for _, msg := range r.snapshot() {
+		c.send(msg)
+	}
+
+	r.mu.Lock()
+	r.clients[c] = struct{}{}
+	r.mu.Unlock()
+
+	remoteAddr := conn.Request().RemoteAddr
+	log.Printf("Remote deck client connected from %s", remoteAddr)
+
+	done := make(chan struct{})
+	go c.writeLoop(done)
+
+	for {
+		var ev device.InputEvent
+		if err := websocket.JSON.Receive(conn, &ev); err != nil {
+			break
+		}
+		ev.Time = time.Now()
+		if err := r.Dispatch(ev); err != nil {
+			log.Printf("Remote deck input %s: %v", ev.Type, err)
+		}
+	}
+
+	r.mu.Lock()
+	delete(r.clients, c)
+	r.mu.Unlock()
+	close(done)
+	conn.Close()
+	log.Printf("Remote deck client %s disconnected", remoteAddr)
+}
+
+// client is one connected web page.
+type client struct {
+	conn *websocket.Conn
+	out  chan Message
+}
+
+// send queues msg, dropping it if the client has fallen behind.
+func (c *client) send(msg Message) {
+	select {
+	case c.out <- msg:
+	default:
+	}
+}
+
+// writeLoop sends queued messages until done is closed or a write fails.
+func (c *client) writeLoop(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case msg := <-c.out:
+			if err := websocket.JSON.Send(c.conn, msg); err != nil {
+				c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// keyMessage encodes a key image for the client.
+func keyMessage(key device.KeyID, img image.Image) (Message, error) {
+	data, err := encodeImage(img)
+	if err != nil {
+		return Message{}, fmt.Errorf("key %d: %w", key, err)
+	}
+	return Message{Type: MessageKey, Key: key, Image: data}, nil
+}
+
+// stripMessage encodes the strip image for the client.
+func stripMessage(img image.Image) (Message, error) {
+	data, err := encodeImage(img)
+	if err != nil {
+		return Message{}, fmt.Errorf("strip: %w", err)
+	}
+	return Message{Type: MessageStrip, Image: data}, nil
+}
+
+// encodeImage returns img as a PNG data URL.
+func encodeImage(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,51 @@
+package remote
+
+import (
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+// openRemote opens a remote deck on a free local port, closed when the test
+// ends.
+func openRemote(t *testing.T) *Remote {
+	t.Helper()
+	r := New("127.0.0.1:0")
+	if err := r.Open(); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestAcceptsWebsocketFromServedPage(t *testing.T) {
+	r := openRemote(t)
+	addr := r.Addr().String()
+
+	conn, err := websocket.Dial("ws://"+addr+"/ws", "", "http://"+addr)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	var msg Message
+	if err := websocket.JSON.Receive(conn, &msg); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if msg.Type != MessageHello {
+		t.Errorf("first message type = %q, want %q", msg.Type, MessageHello)
+	}
+}
+
+func TestRejectsWebsocketFromOtherOrigin(t *testing.T) {
+	r := openRemote(t)
+	addr := r.Addr().String()
+
+	for _, origin := range []string{"http://evil.example", "http://localhost:1"} {
+		conn, err := websocket.Dial("ws://"+addr+"/ws", "", origin)
+		if err == nil {
+			conn.Close()
+			t.Errorf("websocket from origin %s was accepted", origin)
+		}
+	}
+}
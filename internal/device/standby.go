@@ -0,0 +1,96 @@
+package device
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"rafaelmartins.com/p/streamdeck"
+	"rafaelmartins.com/p/usbhid"
+)
+
+// StandbySetter is implemented by devices whose firmware may be able to put
+// the deck on standby by itself after a period of inactivity, leaving the
+// standby image up even if the daemon stops rendering. Devices that don't
+// implement it, or report no support, fall back to the coordinator's
+// software screensaver.
+type StandbySetter interface {
+	// StandbySupported reports whether this device's firmware handles
+	// standby, so SetStandbyImage can be used.
+	StandbySupported() bool
+
+	// SetStandbyImage sets the image shown until the deck goes on standby
+	// after timeout of inactivity.
+	SetStandbyImage(img image.Image, timeout time.Duration) error
+}
+
+// ErrStandbyUnsupported is returned by SetStandbyImage on devices whose
+// firmware doesn't handle standby.
+var ErrStandbyUnsupported = errors.New("device: firmware standby not supported")
+
+// SupportsStandby reports whether dev handles standby in firmware.
+func SupportsStandby(dev Device) bool {
+	s, ok := dev.(StandbySetter)
+	return ok && s.StandbySupported()
+}
+
+// sleepTimerCommand is the Gen2 feature report command that sets how long
+// the firmware waits without input before blanking the deck, in seconds.
+const sleepTimerCommand = 0x0d
+
+// gen2FeatureReport is the feature report ID of Gen2 commands, the one the
+// streamdeck package sends brightness and reset on.
+const gen2FeatureReport = 3
+
+// standbyModels are the model IDs speaking the Gen2 protocol, whose firmware
+// has the sleep timer.
+var standbyModels = map[string]bool{
+	"mk2":  true,
+	"plus": true,
+	"neo":  true,
+}
+
+// hidHandle returns the HID handle inside dev. The streamdeck package sends
+// only the reports it knows about, so the sleep timer is sent on its handle
+// directly. ok is false if the package no longer keeps the handle where
+// expected, and standby is then left to software.
+func hidHandle(dev *streamdeck.Device) (hid *usbhid.Device, ok bool) {
+	field := reflect.ValueOf(dev).Elem().FieldByName("dev")
+	if !field.IsValid() || field.Type() != reflect.TypeFor[*usbhid.Device]() {
+		return nil, false
+	}
+	return *(**usbhid.Device)(unsafe.Pointer(field.UnsafeAddr())), true
+}
+
+// StandbySupported reports whether the deck's firmware has the Gen2 sleep
+// timer.
+func (h *HardwareDevice) StandbySupported() bool {
+	return h.hid != nil
+}
+
+// SetStandbyImage shows img on the touch strip, if there is one, and arms
+// the firmware's sleep timer, which blanks the deck after timeout without
+// input. Gen2 firmware keeps the last frame written up until then, so img
+// stays on the deck even if the daemon stops rendering.
+func (h *HardwareDevice) SetStandbyImage(img image.Image, timeout time.Duration) error {
+	if !h.StandbySupported() {
+		return ErrStandbyUnsupported
+	}
+
+	if h.dev.GetTouchStripSupported() {
+		if err := h.dev.SetTouchStripImage(img); err != nil {
+			return err
+		}
+	}
+
+	payload := make([]byte, h.hid.GetFeatureReportLength())
+	if len(payload) < 5 {
+		return ErrStandbyUnsupported
+	}
+	payload[0] = sleepTimerCommand
+	binary.LittleEndian.PutUint32(payload[1:5], uint32(timeout/time.Second))
+	return h.hid.SetFeatureReport(gen2FeatureReport, payload)
+}
@@ -0,0 +1,28 @@
+package device
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"rafaelmartins.com/p/streamdeck"
+)
+
+func TestHIDHandleFound(t *testing.T) {
+	// Guards against the streamdeck package moving its handle, which
+	// would silently leave every deck on software standby
+	if _, ok := hidHandle(&streamdeck.Device{}); !ok {
+		t.Error("HID handle not found in streamdeck.Device")
+	}
+}
+
+func TestHardwareStandbyUnsupported(t *testing.T) {
+	h := &HardwareDevice{}
+	if SupportsStandby(h) {
+		t.Error("deck without a sleep timer reports standby support")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if err := h.SetStandbyImage(img, time.Minute); err != ErrStandbyUnsupported {
+		t.Errorf("SetStandbyImage = %v, want ErrStandbyUnsupported", err)
+	}
+}
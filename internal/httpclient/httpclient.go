@@ -0,0 +1,63 @@
+// Package httpclient provides a shared, tuned HTTP client for module API calls.
+package httpclient
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultTimeout is the per-request timeout used when none is configured.
+const DefaultTimeout = 10 * time.Second
+
+// transport is shared by all clients so that modules polling the same hosts
+// (and the GitHub fan-out in particular) reuse keep-alive connections instead
+// of dialing and handshaking on every request.
+var transport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          64,
+	MaxIdleConnsPerHost:   16,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// Transport returns the shared transport.
+func Transport() http.RoundTripper {
+	return transport
+}
+
+// New returns a client using the shared transport and the timeout from
+// BELOWDECK_HTTP_TIMEOUT (default 10s).
+func New() *http.Client {
+	return NewWithTimeout(timeoutFromEnv())
+}
+
+// NewWithTimeout returns a client using the shared transport and the given timeout.
+func NewWithTimeout(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+// timeoutFromEnv reads the request timeout from the environment.
+func timeoutFromEnv() time.Duration {
+	v := os.Getenv("BELOWDECK_HTTP_TIMEOUT")
+	if v == "" {
+		return DefaultTimeout
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid BELOWDECK_HTTP_TIMEOUT %q, using default %v", v, DefaultTimeout)
+		return DefaultTimeout
+	}
+	return d
+}
@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientsReuseConnections(t *testing.T) {
+	var dials atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			dials.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	// Separate clients share the transport, and so its idle connections
+	for _, c := range []*http.Client{New(), New(), NewWithTimeout(time.Second)} {
+		for range 5 {
+			resp, err := c.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+
+	if got := dials.Load(); got != 1 {
+		t.Errorf("15 sequential requests opened %d connections, want 1", got)
+	}
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", DefaultTimeout},
+		{"3s", 3 * time.Second},
+		{"soon", DefaultTimeout},
+		{"-1s", DefaultTimeout},
+	}
+	for _, tt := range tests {
+		t.Setenv("BELOWDECK_HTTP_TIMEOUT", tt.env)
+		if got := timeoutFromEnv(); got != tt.want {
+			t.Errorf("timeoutFromEnv() with %q = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkSequentialRequests(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	c := New()
+	for b.Loop() {
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			b.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,79 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Retry defaults for NewRetrying.
+const (
+	// DefaultRetryAttempts is how many times in all a request is tried.
+	DefaultRetryAttempts = 3
+
+	// retryBackoff is the delay before the first retry. It doubles before
+	// each one after.
+	retryBackoff = time.Second
+)
+
+// NewRetrying returns a client like New that retries requests failing with
+// a network error, 429 Too Many Requests or a 5xx status, up to
+// DefaultRetryAttempts times in all with a doubling delay between tries.
+// The client's timeout covers every try. Requests with a body are only
+// retried if it can be replayed, as it can for bodies from bytes and
+// strings readers.
+func NewRetrying() *http.Client {
+	c := New()
+	c.Transport = &retryTransport{base: transport, attempts: DefaultRetryAttempts, backoff: retryBackoff}
+	return c
+}
+
+// retryTransport retries failed round trips on base.
+type retryTransport struct {
+	base     http.RoundTripper
+	attempts int
+	backoff  time.Duration
+}
+
+// RoundTrip sends req, retrying while it fails in a way worth retrying.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if !shouldRetry(resp, err) || attempt >= t.attempts {
+			return resp, err
+		}
+
+		// Replay the body for the next try, or give up if it can't be
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// shouldRetry reports whether a round trip's failure may be temporary.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	var tries atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("try %d body = %q, want the original", tries.Load()+1, body)
+		}
+		if tries.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, attempts: 3, backoff: time.Millisecond}}
+	resp, err := c.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || tries.Load() != 3 {
+		t.Errorf("got %s after %d tries, want 200 OK after 3", resp.Status, tries.Load())
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantTries int32
+	}{
+		{"server error", http.StatusBadGateway, 2},
+		{"rate limited", http.StatusTooManyRequests, 2},
+		{"client error", http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tries atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tries.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			c := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, attempts: 2, backoff: time.Millisecond}}
+			resp, err := c.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || tries.Load() != tt.wantTries {
+				t.Errorf("got %d after %d tries, want %d after %d", resp.StatusCode, tries.Load(), tt.status, tt.wantTries)
+			}
+		})
+	}
+}
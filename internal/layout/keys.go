@@ -0,0 +1,101 @@
+package layout
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// KeyAssignment moves a key to a module, whichever module the layout gave it
+// to.
+type KeyAssignment struct {
+	Module string
+	Key    module.KeyID
+}
+
+// WithKeys returns a copy of l with each assigned key moved to its module,
+// in order. Modules that appear only in assignments are added to the layout
+// with just their keys. Assignments of keys the device doesn't have are
+// skipped.
+func (l Layout) WithKeys(assignments []KeyAssignment, caps Capabilities) Layout {
+	out := make(Layout, len(l)+len(assignments))
+	for id, res := range l {
+		out[id] = res
+	}
+
+	for _, a := range assignments {
+		if int(a.Key) > caps.Keys {
+			log.Printf("Key %d for %s: device has %d keys, skipping", a.Key, a.Module, caps.Keys)
+			continue
+		}
+
+		for id, res := range out {
+			if !res.OwnsKey(a.Key) {
+				continue
+			}
+			var keys []module.KeyID
+			for _, k := range res.Keys {
+				if k != a.Key {
+					keys = append(keys, k)
+				}
+			}
+			res.Keys = keys
+			out[id] = res
+		}
+
+		res := out[a.Module]
+		res.Keys = append(res.Keys, a.Key)
+		out[a.Module] = res
+	}
+	return out
+}
+
+// keysFromEnv reads key assignments from BELOWDECK_KEYS. It's empty when
+// unset or invalid.
+func keysFromEnv() []KeyAssignment {
+	v := os.Getenv("BELOWDECK_KEYS")
+	if v == "" {
+		return nil
+	}
+
+	assignments, err := parseKeys(v)
+	if err != nil {
+		log.Printf("Invalid BELOWDECK_KEYS %q: %v (ignoring)", v, err)
+		return nil
+	}
+	return assignments
+}
+
+// parseKeys parses a comma-separated list of "module:key" entries, with
+// keys numbered from 1, e.g. "quicknote:8,clock:7". A module listed more
+// than once gets each key, in order.
+func parseKeys(v string) ([]KeyAssignment, error) {
+	var assignments []KeyAssignment
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, keyStr, ok := strings.Cut(part, ":")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("want module:key in %q", part)
+		}
+		key, err := strconv.Atoi(strings.TrimSpace(keyStr))
+		if err != nil || key < 1 || key > int(^module.KeyID(0)) {
+			return nil, fmt.Errorf("invalid key in %q", part)
+		}
+
+		assignments = append(assignments, KeyAssignment{Module: id, Key: module.KeyID(key)})
+	}
+
+	if len(assignments) == 0 {
+		return nil, fmt.Errorf("no keys listed")
+	}
+	return assignments, nil
+}
@@ -0,0 +1,52 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestParseKeys(t *testing.T) {
+	got, err := parseKeys(" quicknote:8, clock : 7,,quicknote:2")
+	if err != nil {
+		t.Fatalf("parseKeys: %v", err)
+	}
+	want := []KeyAssignment{{"quicknote", 8}, {"clock", 7}, {"quicknote", 2}}
+	if len(got) != len(want) {
+		t.Fatalf("got = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("assignment %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	for _, v := range []string{"", ",", "quicknote", ":8", "quicknote:0", "quicknote:x", "quicknote:300"} {
+		if _, err := parseKeys(v); err == nil {
+			t.Errorf("parseKeys(%q) accepted", v)
+		}
+	}
+}
+
+func TestWithKeysMovesKeys(t *testing.T) {
+	l := Layout{
+		"clock":   module.Resources{Keys: []module.KeyID{7, 8}},
+		"weather": module.Resources{Keys: []module.KeyID{2}},
+	}
+	got := l.WithKeys([]KeyAssignment{{"quicknote", 8}, {"clock", 2}, {"quicknote", 9}}, Capabilities{Keys: 8})
+
+	want := map[string][]module.KeyID{
+		"clock":     {7, 2},
+		"weather":   nil,
+		"quicknote": {8},
+	}
+	for id, keys := range want {
+		if !slices.Equal(got[id].Keys, keys) {
+			t.Errorf("%s keys = %v, want %v", id, got[id].Keys, keys)
+		}
+	}
+	if !slices.Equal(l["clock"].Keys, []module.KeyID{7, 8}) {
+		t.Errorf("original layout changed: clock keys = %v", l["clock"].Keys)
+	}
+}
@@ -0,0 +1,219 @@
+// Package layout decides which keys, dials and strip regions each module is
+// allocated on a given device.
+package layout
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// Capabilities describes the controls a device offers.
+type Capabilities struct {
+	Keys  int
+	Dials int
+
+	// StripRect is the full touch strip area, empty if there is no strip.
+	StripRect image.Rectangle
+}
+
+// CapabilitiesOf reads the capabilities of a connected device.
+func CapabilitiesOf(dev device.Device) Capabilities {
+	caps := Capabilities{
+		Keys:  int(dev.GetKeyCount()),
+		Dials: int(dev.GetDialCount()),
+	}
+	if dev.GetTouchStripSupported() {
+		if rect, err := dev.GetTouchStripImageRectangle(); err == nil {
+			caps.StripRect = rect
+		}
+	}
+	return caps
+}
+
+// Layout maps module IDs to the resources allocated to them. Modules missing
+// from a layout are not registered.
+type Layout map[string]module.Resources
+
+// layouts are the built-in layouts, keyed by the name accepted in
+// BELOWDECK_LAYOUT.
+var layouts = map[string]func(caps Capabilities) Layout{
+	"plus":     plusLayout,
+	"standard": standardLayout,
+	"mini":     miniLayout,
+}
+
+// ForDevice returns the layout for a device. BELOWDECK_LAYOUT may name a
+// built-in layout ("plus", "standard", "mini"); when unset or "auto", or if
+// the named layout needs controls the device doesn't have, the layout is
+// picked from the device's capabilities instead. Keys listed in
+// BELOWDECK_KEYS are then moved to the modules named there. The strip, if
+// any, is divided according to BELOWDECK_STRIP, leaving out the absent
+// modules so the others widen to fill their space, with the modules in
+// BELOWDECK_STRIP_BACKGROUND spanning it underneath.
+func ForDevice(dev device.Device, absent ...string) Layout {
+	caps := CapabilitiesOf(dev)
+	background := withoutIDs(backgroundFromEnv(), absent)
+	return controlsForDevice(dev, caps).
+		WithKeys(keysFromEnv(), caps).
+		WithStrip(caps.StripRect, withoutModules(stripFromEnv(), slices.Concat(absent, background))).
+		WithStripBackground(caps.StripRect, background)
+}
+
+// controlsForDevice returns the key and dial allocation for a device,
+// honoring BELOWDECK_LAYOUT.
+func controlsForDevice(dev device.Device, caps Capabilities) Layout {
+	name := os.Getenv("BELOWDECK_LAYOUT")
+	if name == "" || name == "auto" {
+		return resolveControls(caps)
+	}
+
+	build, ok := layouts[name]
+	if !ok {
+		log.Printf("Unknown BELOWDECK_LAYOUT %q, picking a layout for %s", name, dev.GetModelName())
+		return resolveControls(caps)
+	}
+
+	l := build(caps)
+	if err := l.Validate(caps); err != nil {
+		log.Printf("Layout %q doesn't fit %s: %v (picking a layout for the device)", name, dev.GetModelName(), err)
+		return resolveControls(caps)
+	}
+	return l
+}
+
+// resolveControls picks the built-in key and dial allocation that best fits
+// the given capabilities, dropping any controls the device still lacks.
+func resolveControls(caps Capabilities) Layout {
+	switch {
+	case caps.Keys >= 8 && caps.Dials >= 4 && !caps.StripRect.Empty():
+		return plusLayout(caps)
+	case caps.Keys >= 15:
+		return standardLayout(caps)
+	default:
+		return miniLayout(caps).trim(caps)
+	}
+}
+
+// Validate checks that every resource in the layout exists on the device.
+func (l Layout) Validate(caps Capabilities) error {
+	var problems []string
+
+	ids := make([]string, 0, len(l))
+	for id := range l {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		res := l[id]
+		for _, key := range res.Keys {
+			if int(key) > caps.Keys {
+				problems = append(problems, fmt.Sprintf("%s: key %d (device has %d)", id, key, caps.Keys))
+			}
+		}
+		for _, dial := range res.Dials {
+			if int(dial) > caps.Dials {
+				problems = append(problems, fmt.Sprintf("%s: dial %d (device has %d)", id, dial, caps.Dials))
+			}
+		}
+		if res.HasStrip() && !res.StripRect.In(caps.StripRect) {
+			problems = append(problems, fmt.Sprintf("%s: strip region %v outside %v", id, res.StripRect, caps.StripRect))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// trim removes keys and dials the device doesn't have, and modules left with
+// nothing allocated. Strip regions are assigned afterwards by WithStrip.
+func (l Layout) trim(caps Capabilities) Layout {
+	out := make(Layout, len(l))
+	for id, res := range l {
+		var keys []module.KeyID
+		for _, key := range res.Keys {
+			if int(key) <= caps.Keys {
+				keys = append(keys, key)
+			}
+		}
+		var dials []module.DialID
+		for _, dial := range res.Dials {
+			if int(dial) <= caps.Dials {
+				dials = append(dials, dial)
+			}
+		}
+		res.Keys, res.Dials = keys, dials
+
+		if res.HasKeys() || res.HasDials() {
+			out[id] = res
+		}
+	}
+	return out
+}
+
+// plusLayout is the layout for the Stream Deck Plus, with dials for media and
+// lights.
+func plusLayout(caps Capabilities) Layout {
+	return Layout{
+		"nowplaying": {
+			Keys:  []module.KeyID{module.Key5, module.Key6},
+			Dials: []module.DialID{module.Dial1, module.Dial2},
+		},
+		"homeassistant": {
+			Keys:  []module.KeyID{module.Key1, module.Key2},
+			Dials: []module.DialID{module.Dial4},
+		},
+		"github": {
+			Keys: []module.KeyID{module.Key3, module.Key4},
+		},
+		"dnd": {
+			Keys: []module.KeyID{module.Key7},
+		},
+		"bookmarks": {
+			Keys: []module.KeyID{module.Key8},
+		},
+	}
+}
+
+// standardLayout is for 15-key and larger decks without dials or a strip.
+// Bookmarks get the spare keys.
+func standardLayout(caps Capabilities) Layout {
+	return Layout{
+		"homeassistant": {Keys: []module.KeyID{module.Key1, module.Key2}},
+		"github":        {Keys: []module.KeyID{module.Key3, module.Key4}},
+		"nowplaying":    {Keys: []module.KeyID{module.Key5, module.Key6}},
+		"dnd":           {Keys: []module.KeyID{module.Key7}},
+		"audiooutput":   {Keys: []module.KeyID{module.Key8}},
+		"bookmarks":     {Keys: keyRange(9, caps.Keys)},
+	}
+}
+
+// miniLayout is for small decks (6 keys). Each module gets its primary key.
+func miniLayout(caps Capabilities) Layout {
+	return Layout{
+		"nowplaying":    {Keys: []module.KeyID{module.Key1, module.Key2}},
+		"homeassistant": {Keys: []module.KeyID{module.Key3}},
+		"github":        {Keys: []module.KeyID{module.Key4}},
+		"dnd":           {Keys: []module.KeyID{module.Key5}},
+		"bookmarks":     {Keys: keyRange(6, caps.Keys)},
+	}
+}
+
+// keyRange returns the keys from first to last inclusive.
+func keyRange(first, last int) []module.KeyID {
+	var keys []module.KeyID
+	for k := first; k <= last; k++ {
+		keys = append(keys, module.KeyID(k))
+	}
+	return keys
+}
@@ -0,0 +1,148 @@
+package layout
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// modelDevice is a fake deck with another model's controls.
+type modelDevice struct {
+	*device.Fake
+	keys, dials byte
+	strip       bool
+}
+
+func (d modelDevice) GetKeyCount() byte            { return d.keys }
+func (d modelDevice) GetDialCount() byte           { return d.dials }
+func (d modelDevice) GetTouchStripSupported() bool { return d.strip }
+func (d modelDevice) GetModelName() string         { return "Test Deck" }
+
+var (
+	mini     = modelDevice{Fake: device.NewFake(), keys: 6}
+	standard = modelDevice{Fake: device.NewFake(), keys: 15}
+	plus     = modelDevice{Fake: device.NewFake(), keys: 8, dials: 4, strip: true}
+)
+
+// clearLayoutEnv unsets the layout settings for the length of the test.
+func clearLayoutEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"BELOWDECK_LAYOUT", "BELOWDECK_KEYS", "BELOWDECK_STRIP", "BELOWDECK_STRIP_BACKGROUND"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestForDeviceFitsCapabilities(t *testing.T) {
+	clearLayoutEnv(t)
+
+	tests := []struct {
+		name string
+		dev  modelDevice
+		keys map[string][]module.KeyID
+	}{
+		{"6 keys", mini, map[string][]module.KeyID{
+			"nowplaying":    {1, 2},
+			"homeassistant": {3},
+			"github":        {4},
+			"dnd":           {5},
+			"bookmarks":     {6},
+		}},
+		{"15 keys", standard, map[string][]module.KeyID{
+			"homeassistant": {1, 2},
+			"github":        {3, 4},
+			"nowplaying":    {5, 6},
+			"dnd":           {7},
+			"audiooutput":   {8},
+			"bookmarks":     {9, 10, 11, 12, 13, 14, 15},
+		}},
+		{"plus", plus, map[string][]module.KeyID{
+			"homeassistant": {1, 2},
+			"github":        {3, 4},
+			"nowplaying":    {5, 6},
+			"dnd":           {7},
+			"bookmarks":     {8},
+			"weather":       nil,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps := CapabilitiesOf(tt.dev)
+			l := ForDevice(tt.dev)
+			if err := l.Validate(caps); err != nil {
+				t.Errorf("layout doesn't fit the device: %v", err)
+			}
+
+			if len(l) != len(tt.keys) {
+				t.Errorf("layout has %d modules, want %d", len(l), len(tt.keys))
+			}
+			for id, want := range tt.keys {
+				if got := l[id].Keys; !slices.Equal(got, want) {
+					t.Errorf("%s keys = %v, want %v", id, got, want)
+				}
+			}
+
+			for id, res := range l {
+				if res.HasDials() && tt.dev.dials == 0 {
+					t.Errorf("%s got dials %v on a deck without dials", id, res.Dials)
+				}
+				if res.HasStrip() && !tt.dev.strip {
+					t.Errorf("%s got strip %v on a deck without a strip", id, res.StripRect)
+				}
+			}
+		})
+	}
+}
+
+func TestForDevicePlusControls(t *testing.T) {
+	clearLayoutEnv(t)
+	l := ForDevice(plus)
+
+	if got, want := l["nowplaying"].Dials, []module.DialID{module.Dial1, module.Dial2}; !slices.Equal(got, want) {
+		t.Errorf("nowplaying dials = %v, want %v", got, want)
+	}
+	if got, want := l["homeassistant"].Dials, []module.DialID{module.Dial4}; !slices.Equal(got, want) {
+		t.Errorf("homeassistant dials = %v, want %v", got, want)
+	}
+	if got, want := l["nowplaying"].StripRect, image.Rect(0, 0, 400, 100); got != want {
+		t.Errorf("nowplaying strip = %v, want the left half %v", got, want)
+	}
+	if got, want := l["weather"].StripRect, image.Rect(400, 0, 800, 100); got != want {
+		t.Errorf("weather strip = %v, want the right half %v", got, want)
+	}
+}
+
+func TestForDeviceNamedLayoutThatDoesntFit(t *testing.T) {
+	clearLayoutEnv(t)
+	t.Setenv("BELOWDECK_LAYOUT", "plus")
+
+	// The Plus layout's dials and eighth key aren't on a 6-key deck, so
+	// the device's own layout is used
+	l := ForDevice(mini)
+	if err := l.Validate(CapabilitiesOf(mini)); err != nil {
+		t.Fatalf("layout doesn't fit the device: %v", err)
+	}
+	if got := l["bookmarks"].Keys; !slices.Equal(got, []module.KeyID{6}) {
+		t.Errorf("bookmarks keys = %v, want the 6-key layout's [6]", got)
+	}
+
+	// A named layout that fits is used as is
+	t.Setenv("BELOWDECK_LAYOUT", "mini")
+	if got := ForDevice(standard)["bookmarks"].Keys; !slices.Equal(got, keyRange(6, 15)) {
+		t.Errorf("bookmarks keys = %v, want the mini layout's 6 to 15", got)
+	}
+}
+
+func TestForDeviceGivesAbsentModulesStrip(t *testing.T) {
+	clearLayoutEnv(t)
+	l := ForDevice(plus, "weather")
+
+	if got, want := l["nowplaying"].StripRect, image.Rect(0, 0, 800, 100); got != want {
+		t.Errorf("nowplaying strip = %v, want the full strip %v", got, want)
+	}
+	if res, ok := l["weather"]; ok && res.HasStrip() {
+		t.Errorf("absent weather module kept strip %v", res.StripRect)
+	}
+}
@@ -0,0 +1,184 @@
+package layout
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// StripRegion gives a module a share of the touch strip. Regions are laid
+// out left to right, each as wide as its weight's share of the total.
+type StripRegion struct {
+	Module string
+	Weight int
+}
+
+// DefaultStrip puts media on the left half of the strip and weather on the
+// right half.
+var DefaultStrip = []StripRegion{
+	{Module: "nowplaying", Weight: 1},
+	{Module: "weather", Weight: 1},
+}
+
+// SplitStrip divides strip horizontally into one rectangle per weight, each
+// proportional to its weight. Rounding is absorbed so the rectangles tile
+// the strip exactly.
+func SplitStrip(strip image.Rectangle, weights []int) []image.Rectangle {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	rects := make([]image.Rectangle, len(weights))
+	x := strip.Min.X
+	acc := 0
+	for i, w := range weights {
+		acc += w
+		right := strip.Min.X + strip.Dx()*acc/total
+		rects[i] = image.Rect(x, strip.Min.Y, right, strip.Max.Y)
+		x = right
+	}
+	return rects
+}
+
+// WithStrip returns a copy of l with the strip divided between modules
+// according to regions. Modules that appear only in regions are added to
+// the layout with just their strip region.
+func (l Layout) WithStrip(strip image.Rectangle, regions []StripRegion) Layout {
+	out := make(Layout, len(l)+len(regions))
+	for id, res := range l {
+		out[id] = res
+	}
+	if strip.Empty() || len(regions) == 0 {
+		return out
+	}
+
+	weights := make([]int, len(regions))
+	for i, r := range regions {
+		weights[i] = r.Weight
+	}
+
+	for i, rect := range SplitStrip(strip, weights) {
+		res := out[regions[i].Module]
+		res.StripRect = rect
+		out[regions[i].Module] = res
+	}
+	return out
+}
+
+// WithStripBackground returns a copy of l with the given modules spanning
+// the whole strip beneath the split regions, the first lowest, so their
+// output shows through wherever the modules above leave it transparent.
+// Modules that appear only here are added to the layout with just the
+// strip.
+func (l Layout) WithStripBackground(strip image.Rectangle, ids []string) Layout {
+	out := make(Layout, len(l)+len(ids))
+	for id, res := range l {
+		out[id] = res
+	}
+	if strip.Empty() {
+		return out
+	}
+
+	for i, id := range ids {
+		res := out[id]
+		res.StripRect = strip
+		res.StripZ = i - len(ids)
+		out[id] = res
+	}
+	return out
+}
+
+// withoutModules returns regions minus those belonging to the given modules.
+func withoutModules(regions []StripRegion, ids []string) []StripRegion {
+	if len(ids) == 0 {
+		return regions
+	}
+
+	var out []StripRegion
+	for _, r := range regions {
+		if !slices.Contains(ids, r.Module) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// withoutIDs returns ids minus the given modules.
+func withoutIDs(ids, absent []string) []string {
+	var out []string
+	for _, id := range ids {
+		if !slices.Contains(absent, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// backgroundFromEnv reads the strip background modules, bottom first, from
+// the comma-separated BELOWDECK_STRIP_BACKGROUND.
+func backgroundFromEnv() []string {
+	var ids []string
+	for _, id := range strings.Split(os.Getenv("BELOWDECK_STRIP_BACKGROUND"), ",") {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// stripFromEnv reads the strip regions from BELOWDECK_STRIP, falling back to
+// DefaultStrip when unset or invalid.
+func stripFromEnv() []StripRegion {
+	v := os.Getenv("BELOWDECK_STRIP")
+	if v == "" {
+		return DefaultStrip
+	}
+
+	regions, err := parseStrip(v)
+	if err != nil {
+		log.Printf("Invalid BELOWDECK_STRIP %q: %v (using default)", v, err)
+		return DefaultStrip
+	}
+	return regions
+}
+
+// parseStrip parses a comma-separated list of module IDs, left to right,
+// each optionally followed by ":weight" (default 1), e.g.
+// "nowplaying:2,weather".
+func parseStrip(v string) ([]StripRegion, error) {
+	var regions []StripRegion
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, weightStr, hasWeight := strings.Cut(part, ":")
+		region := StripRegion{Module: strings.TrimSpace(id), Weight: 1}
+		if region.Module == "" {
+			return nil, fmt.Errorf("missing module in %q", part)
+		}
+		if hasWeight {
+			weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("invalid weight in %q", part)
+			}
+			region.Weight = weight
+		}
+
+		regions = append(regions, region)
+	}
+
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("no modules listed")
+	}
+	return regions, nil
+}
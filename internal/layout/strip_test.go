@@ -0,0 +1,103 @@
+package layout
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestWithStripBackgroundSpansStripUnderRegions(t *testing.T) {
+	strip := image.Rect(0, 0, 800, 100)
+	l := Layout{"clock": module.Resources{Keys: []module.KeyID{module.Key1}}}.
+		WithStrip(strip, []StripRegion{{Module: "nowplaying", Weight: 1}, {Module: "weather", Weight: 1}}).
+		WithStripBackground(strip, []string{"clock", "art"})
+
+	for id, want := range map[string]int{"clock": -2, "art": -1, "nowplaying": 0, "weather": 0} {
+		if got := l[id].StripZ; got != want {
+			t.Errorf("%s StripZ = %d, want %d", id, got, want)
+		}
+	}
+	for _, id := range []string{"clock", "art"} {
+		if got := l[id].StripRect; got != strip {
+			t.Errorf("%s StripRect = %v, want the whole strip %v", id, got, strip)
+		}
+	}
+	if got := len(l["clock"].Keys); got != 1 {
+		t.Errorf("clock kept %d keys, want 1", got)
+	}
+	if got, want := l["nowplaying"].StripRect, image.Rect(0, 0, 400, 100); got != want {
+		t.Errorf("nowplaying StripRect = %v, want %v", got, want)
+	}
+}
+
+func TestSplitStrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		strip   image.Rectangle
+		weights []int
+		widths  []int
+	}{
+		{"equal halves", image.Rect(0, 0, 800, 100), []int{1, 1}, []int{400, 400}},
+		{"equal thirds", image.Rect(0, 0, 800, 100), []int{1, 1, 1}, []int{266, 267, 267}},
+		{"equal on a narrow strip", image.Rect(0, 0, 480, 60), []int{1, 1}, []int{240, 240}},
+		{"whole strip", image.Rect(0, 0, 800, 100), []int{5}, []int{800}},
+		{"weighted", image.Rect(0, 0, 800, 100), []int{2, 1}, []int{533, 267}},
+		{"weighted on a narrow strip", image.Rect(0, 0, 480, 60), []int{1, 2, 1}, []int{120, 240, 120}},
+		{"weighted with rounding", image.Rect(0, 0, 799, 100), []int{1, 1, 3}, []int{159, 160, 480}},
+		{"offset strip", image.Rect(100, 10, 400, 110), []int{1, 1}, []int{150, 150}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rects := SplitStrip(tt.strip, tt.weights)
+			if len(rects) != len(tt.widths) {
+				t.Fatalf("got %d regions, want %d", len(rects), len(tt.widths))
+			}
+
+			// Regions tile the strip left to right without gaps
+			x, total := tt.strip.Min.X, 0
+			for i, r := range rects {
+				if r.Dx() != tt.widths[i] {
+					t.Errorf("region %d is %dpx wide, want %d", i, r.Dx(), tt.widths[i])
+				}
+				if r.Min.X != x {
+					t.Errorf("region %d starts at %d, want %d", i, r.Min.X, x)
+				}
+				if r.Min.Y != tt.strip.Min.Y || r.Max.Y != tt.strip.Max.Y {
+					t.Errorf("region %d spans %d to %d, want the strip's height", i, r.Min.Y, r.Max.Y)
+				}
+				x = r.Max.X
+				total += r.Dx()
+			}
+			if total != tt.strip.Dx() {
+				t.Errorf("regions sum to %dpx, want the strip's %d", total, tt.strip.Dx())
+			}
+		})
+	}
+}
+
+func TestSplitStripNoWeight(t *testing.T) {
+	for _, weights := range [][]int{nil, {0, 0}} {
+		if rects := SplitStrip(image.Rect(0, 0, 800, 100), weights); rects != nil {
+			t.Errorf("SplitStrip with weights %v = %v, want nil", weights, rects)
+		}
+	}
+}
+
+func TestParseStrip(t *testing.T) {
+	got, err := parseStrip(" nowplaying:2, weather ,,clock:1")
+	if err != nil {
+		t.Fatalf("parseStrip: %v", err)
+	}
+	want := []StripRegion{{"nowplaying", 2}, {"weather", 1}, {"clock", 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+
+	for _, v := range []string{"", ",", ":2", "nowplaying:0", "nowplaying:-1", "nowplaying:x"} {
+		if _, err := parseStrip(v); err == nil {
+			t.Errorf("parseStrip(%q) accepted", v)
+		}
+	}
+}
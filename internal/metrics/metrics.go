@@ -0,0 +1,121 @@
+// Package metrics collects runtime measurements and serves them over HTTP in
+// the Prometheus text exposition format.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RenderStats accumulates timings for one kind of render from one module.
+type RenderStats struct {
+	Count uint64
+	Total time.Duration
+	Max   time.Duration
+	Last  time.Duration
+}
+
+// renderKey identifies a module's render call, e.g. {"nowplaying", "strip"}.
+type renderKey struct {
+	module string
+	kind   string
+}
+
+// Registry holds the collected metrics. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	renders map[renderKey]*RenderStats
+}
+
+// New creates an empty Registry.
+func New() *Registry {
+	return &Registry{
+		renders: make(map[renderKey]*RenderStats),
+	}
+}
+
+// ObserveRender records how long a module's render call took.
+func (r *Registry) ObserveRender(module, kind string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := renderKey{module, kind}
+	s, ok := r.renders[key]
+	if !ok {
+		s = &RenderStats{}
+		r.renders[key] = s
+	}
+	s.Count++
+	s.Total += d
+	s.Last = d
+	if d > s.Max {
+		s.Max = d
+	}
+}
+
+// ServeHTTP writes all metrics in the Prometheus text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	keys := make([]renderKey, 0, len(r.renders))
+	stats := make(map[renderKey]RenderStats, len(r.renders))
+	for k, s := range r.renders {
+		keys = append(keys, k)
+		stats[k] = *s
+	}
+	r.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].module != keys[j].module {
+			return keys[i].module < keys[j].module
+		}
+		return keys[i].kind < keys[j].kind
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	metrics := []struct {
+		name, typ, help string
+		value           func(RenderStats) string
+	}{
+		{"belowdeck_render_count_total", "counter", "Number of render calls per module.",
+			func(s RenderStats) string { return fmt.Sprint(s.Count) }},
+		{"belowdeck_render_seconds_total", "counter", "Total time spent rendering per module.",
+			func(s RenderStats) string { return fmt.Sprint(s.Total.Seconds()) }},
+		{"belowdeck_render_seconds_max", "gauge", "Slowest render call per module.",
+			func(s RenderStats) string { return fmt.Sprint(s.Max.Seconds()) }},
+		{"belowdeck_render_seconds_last", "gauge", "Most recent render call duration per module.",
+			func(s RenderStats) string { return fmt.Sprint(s.Last.Seconds()) }},
+	}
+
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.typ)
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s{module=%q,kind=%q} %s\n", m.name, k.module, k.kind, m.value(stats[k]))
+		}
+	}
+}
+
+// Serve exposes the registry at /metrics on addr until ctx is cancelled.
+func Serve(ctx context.Context, addr string, r *Registry) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r)
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving metrics on http://%s/metrics", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Metrics server: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package module
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default acceleration curve, used when acceleration is turned on with "on".
+const (
+	defaultAccelFast  = 150 * time.Millisecond
+	defaultAccelPower = 1.5
+	defaultAccelMax   = 8
+)
+
+// Acceleration scales dial turns up when the dial spins quickly, so a fast
+// spin covers a large change while slow ticks stay precise. Each tick that
+// comes sooner than Fast after the previous one is multiplied by
+// (Fast / gap) ^ Power, up to Max. Reversing direction starts over at
+// normal speed.
+//
+// A nil *Acceleration leaves turns unchanged.
+type Acceleration struct {
+	// Fast is the gap between ticks below which turns accelerate.
+	Fast time.Duration
+
+	// Power shapes the curve: 1 grows the multiplier in step with the
+	// speed, higher values hold back on moderate spins and ramp up on
+	// fast ones.
+	Power float64
+
+	// Max is the largest multiplier.
+	Max float64
+
+	mu   sync.Mutex
+	last time.Time
+	dir  int
+}
+
+// ParseAcceleration parses a dial acceleration setting: "" or "off" for
+// none, "on" for the default curve, or "<fast>,<power>,<max>" such as
+// "150ms,1.5,8".
+func ParseAcceleration(v string) (*Acceleration, error) {
+	switch strings.TrimSpace(v) {
+	case "", "off":
+		return nil, nil
+	case "on":
+		return &Acceleration{Fast: defaultAccelFast, Power: defaultAccelPower, Max: defaultAccelMax}, nil
+	}
+
+	parts := strings.Split(v, ",")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("want \"off\", \"on\" or \"<fast>,<power>,<max>\" such as \"150ms,1.5,8\"")
+	}
+	fast, err := time.ParseDuration(strings.TrimSpace(parts[0]))
+	if err != nil || fast <= 0 {
+		return nil, fmt.Errorf("invalid speed %q", parts[0])
+	}
+	power, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || power <= 0 {
+		return nil, fmt.Errorf("invalid power %q", parts[1])
+	}
+	maxMul, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+	if err != nil || maxMul < 1 {
+		return nil, fmt.Errorf("invalid max %q (want 1 or more)", parts[2])
+	}
+	return &Acceleration{Fast: fast, Power: power, Max: maxMul}, nil
+}
+
+// Apply returns the accelerated delta for a dial turn happening now.
+func (a *Acceleration) Apply(delta int8) int {
+	return a.apply(delta, time.Now())
+}
+
+// apply returns the accelerated delta for a dial turn at now. The device
+// may report several ticks in one turn, so the gap is measured per tick.
+func (a *Acceleration) apply(delta int8, now time.Time) int {
+	if a == nil || delta == 0 {
+		return int(delta)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ticks := int(delta)
+	dir := 1
+	if ticks < 0 {
+		ticks, dir = -ticks, -1
+	}
+
+	mul := 1.0
+	if !a.last.IsZero() && dir == a.dir {
+		gap := now.Sub(a.last) / time.Duration(ticks)
+		if gap < a.Fast {
+			mul = math.Pow(float64(a.Fast)/float64(max(gap, time.Millisecond)), a.Power)
+			mul = min(mul, a.Max)
+		}
+	}
+	a.last, a.dir = now, dir
+
+	return dir * int(math.Round(float64(ticks)*mul))
+}
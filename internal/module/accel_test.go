@@ -0,0 +1,78 @@
+package module
+
+import (
+	"testing"
+	"time"
+)
+
+// spin returns the total delta of ticks one-step turns gap apart.
+func spin(a *Acceleration, ticks int, gap time.Duration) int {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	total := 0
+	for i := range ticks {
+		total += a.apply(1, start.Add(time.Duration(i)*gap))
+	}
+	return total
+}
+
+func TestAccelerationFastSpinCoversMore(t *testing.T) {
+	curve := func() *Acceleration {
+		return &Acceleration{Fast: 150 * time.Millisecond, Power: 1.5, Max: 8}
+	}
+
+	slow := spin(curve(), 10, 500*time.Millisecond)
+	fast := spin(curve(), 10, 30*time.Millisecond)
+	if slow != 10 {
+		t.Errorf("slow spin = %d, want 10", slow)
+	}
+	if fast <= slow {
+		t.Errorf("fast spin = %d, want more than the slow spin's %d", fast, slow)
+	}
+
+	// The multiplier is capped at Max, after the first tick
+	if got, want := spin(curve(), 10, time.Millisecond), 1+9*8; got != want {
+		t.Errorf("very fast spin = %d, want %d", got, want)
+	}
+}
+
+func TestAccelerationReversalStartsOver(t *testing.T) {
+	a := &Acceleration{Fast: 150 * time.Millisecond, Power: 1, Max: 8}
+	start := time.Now()
+	a.apply(1, start)
+	if got := a.apply(1, start.Add(50*time.Millisecond)); got != 3 {
+		t.Errorf("fast tick = %d, want 3", got)
+	}
+	if got := a.apply(-1, start.Add(60*time.Millisecond)); got != -1 {
+		t.Errorf("reversed tick = %d, want -1", got)
+	}
+}
+
+func TestAccelerationNil(t *testing.T) {
+	var a *Acceleration
+	if got := a.Apply(-3); got != -3 {
+		t.Errorf("nil Apply(-3) = %d, want -3", got)
+	}
+}
+
+func TestParseAcceleration(t *testing.T) {
+	for _, v := range []string{"", "off"} {
+		if a, err := ParseAcceleration(v); a != nil || err != nil {
+			t.Errorf("ParseAcceleration(%q) = %v, %v; want none", v, a, err)
+		}
+	}
+
+	a, err := ParseAcceleration("on")
+	if err != nil || a.Fast != defaultAccelFast || a.Power != defaultAccelPower || a.Max != defaultAccelMax {
+		t.Errorf("ParseAcceleration(on) = %+v, %v; want the default curve", a, err)
+	}
+	a, err = ParseAcceleration("100ms, 2, 4")
+	if err != nil || a.Fast != 100*time.Millisecond || a.Power != 2 || a.Max != 4 {
+		t.Errorf("ParseAcceleration(100ms, 2, 4) = %+v, %v", a, err)
+	}
+
+	for _, v := range []string{"fast", "100ms,2", "0s,2,4", "100ms,0,4", "100ms,2,0.5"} {
+		if _, err := ParseAcceleration(v); err == nil {
+			t.Errorf("ParseAcceleration(%q) accepted", v)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package module
+
+// ActionHandler is an interface that modules can implement to expose named
+// actions to triggers outside the deck, such as global hotkeys or scripts.
+// Actions are addressed as "<module ID>:<action>", e.g. "nowplaying:info".
+type ActionHandler interface {
+	// HandleAction runs the named action. Unknown actions return an error.
+	HandleAction(action string) error
+}
@@ -3,6 +3,10 @@ package module
 import (
 	"context"
 	"image"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
 )
 
 // BaseModule provides default no-op implementations of the Module interface.
@@ -12,6 +16,12 @@ type BaseModule struct {
 	resources Resources
 	ctx       context.Context
 	cancel    context.CancelFunc
+	bus       *bus.Bus
+	flasher   Flasher
+
+	// Snooze state
+	snoozeMu     sync.RWMutex
+	snoozedUntil time.Time
 }
 
 // NewBaseModule creates a BaseModule with the given ID.
@@ -77,3 +87,67 @@ func (b *BaseModule) Resources() Resources {
 func (b *BaseModule) Context() context.Context {
 	return b.ctx
 }
+
+// Snooze silences the module until the given time.
+func (b *BaseModule) Snooze(until time.Time) {
+	b.snoozeMu.Lock()
+	defer b.snoozeMu.Unlock()
+	b.snoozedUntil = until
+}
+
+// Unsnooze resumes the module immediately.
+func (b *BaseModule) Unsnooze() {
+	b.snoozeMu.Lock()
+	defer b.snoozeMu.Unlock()
+	b.snoozedUntil = time.Time{}
+}
+
+// SnoozedUntil returns when the current snooze ends, or the zero time if the
+// module is not snoozed.
+func (b *BaseModule) SnoozedUntil() time.Time {
+	b.snoozeMu.RLock()
+	defer b.snoozeMu.RUnlock()
+	if time.Now().After(b.snoozedUntil) {
+		return time.Time{}
+	}
+	return b.snoozedUntil
+}
+
+// IsSnoozed returns true if the module is currently snoozed.
+// Polling modules should skip fetches while snoozed.
+func (b *BaseModule) IsSnoozed() bool {
+	return !b.SnoozedUntil().IsZero()
+}
+
+// SetBus gives the module access to the shared event bus.
+func (b *BaseModule) SetBus(eb *bus.Bus) {
+	b.bus = eb
+}
+
+// Bus returns the shared event bus, or nil if none was provided.
+func (b *BaseModule) Bus() *bus.Bus {
+	return b.bus
+}
+
+// SetFlasher gives the module a way to flash its keys.
+func (b *BaseModule) SetFlasher(f Flasher) {
+	b.flasher = f
+}
+
+// FlashKey briefly flashes a key to confirm an action. It does nothing if
+// no flasher was provided.
+func (b *BaseModule) FlashKey(id KeyID, flash Flash) {
+	if b.flasher != nil {
+		b.flasher.FlashKey(id, flash)
+	}
+}
+
+// FlashResult flashes a key for success if err is nil, or for failure
+// otherwise.
+func (b *BaseModule) FlashResult(id KeyID, err error) {
+	if err != nil {
+		b.FlashKey(id, FlashError)
+		return
+	}
+	b.FlashKey(id, FlashSuccess)
+}
@@ -0,0 +1,33 @@
+package module
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozeEndsAtDeadline(t *testing.T) {
+	b := NewBaseModule("test")
+	if b.IsSnoozed() {
+		t.Fatal("new module is snoozed")
+	}
+
+	until := time.Now().Add(50 * time.Millisecond)
+	b.Snooze(until)
+	if !b.IsSnoozed() || !b.SnoozedUntil().Equal(until) {
+		t.Fatalf("SnoozedUntil = %v, want %v", b.SnoozedUntil(), until)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if b.IsSnoozed() {
+		t.Error("module still snoozed after its snooze ended")
+	}
+}
+
+func TestUnsnooze(t *testing.T) {
+	b := NewBaseModule("test")
+	b.Snooze(time.Now().Add(time.Hour))
+	b.Unsnooze()
+	if b.IsSnoozed() {
+		t.Error("module snoozed after Unsnooze")
+	}
+}
@@ -0,0 +1,61 @@
+package module
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Booster polls faster for a short while after an action, so the display
+// catches up with a light that was just toggled or a track that was just
+// skipped instead of waiting for the next regular poll. After each action
+// it runs Fetch Polls times, the first after Interval and each later one
+// after twice the previous delay, tapering off until regular polling takes
+// over again.
+type Booster struct {
+	// Fetch fetches the affected state. It runs on its own goroutine.
+	Fetch func(ctx context.Context)
+
+	// Polls is how many extra fetches follow each action. Zero disables
+	// boosting.
+	Polls int
+
+	// Interval is the delay before the first extra fetch.
+	Interval time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Boost starts the extra fetches in the background, replacing any boost
+// still running from an earlier action.
+func (b *Booster) Boost(ctx context.Context) {
+	if b.Polls <= 0 || b.Fetch == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	done := track(ctx)
+	go func() {
+		defer done()
+		defer cancel()
+
+		delay := b.Interval
+		for range b.Polls {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			runRecovered(ctx, "boost", b.Fetch)
+			delay *= 2
+		}
+	}()
+}
@@ -0,0 +1,77 @@
+package module
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// collectFetches returns the times of fetches sent on fetches until none
+// arrives for quiet.
+func collectFetches(fetches <-chan time.Time, quiet time.Duration) []time.Time {
+	var got []time.Time
+	for {
+		select {
+		case at := <-fetches:
+			got = append(got, at)
+		case <-time.After(quiet):
+			return got
+		}
+	}
+}
+
+func TestBoosterTapersOff(t *testing.T) {
+	fetches := make(chan time.Time, 10)
+	b := &Booster{
+		Fetch:    func(ctx context.Context) { fetches <- time.Now() },
+		Polls:    3,
+		Interval: 20 * time.Millisecond,
+	}
+
+	start := time.Now()
+	b.Boost(context.Background())
+	got := collectFetches(fetches, 300*time.Millisecond)
+	if len(got) != 3 {
+		t.Fatalf("got %d fetches, want 3", len(got))
+	}
+
+	// 20ms, then 40ms and 80ms gaps
+	prev, prevGap := start, time.Duration(0)
+	for i, at := range got {
+		gap := at.Sub(prev)
+		if gap < 20*time.Millisecond<<i {
+			t.Errorf("fetch %d came %v after the last, want at least %v", i+1, gap, 20*time.Millisecond<<i)
+		}
+		if gap <= prevGap {
+			t.Errorf("fetch %d gap %v not longer than the last, %v", i+1, gap, prevGap)
+		}
+		prev, prevGap = at, gap
+	}
+}
+
+func TestBoosterRestartsOnAction(t *testing.T) {
+	fetches := make(chan time.Time, 10)
+	b := &Booster{
+		Fetch:    func(ctx context.Context) { fetches <- time.Now() },
+		Polls:    2,
+		Interval: 50 * time.Millisecond,
+	}
+
+	b.Boost(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	b.Boost(context.Background())
+
+	// Only the second boost's fetches run
+	if got := len(collectFetches(fetches, 300*time.Millisecond)); got != 2 {
+		t.Errorf("got %d fetches after two boosts, want 2", got)
+	}
+}
+
+func TestBoosterDisabled(t *testing.T) {
+	fetches := make(chan time.Time, 1)
+	b := &Booster{Fetch: func(ctx context.Context) { fetches <- time.Now() }, Interval: time.Millisecond}
+	b.Boost(context.Background())
+	if got := len(collectFetches(fetches, 50*time.Millisecond)); got != 0 {
+		t.Errorf("got %d fetches with no boost polls, want 0", got)
+	}
+}
@@ -0,0 +1,10 @@
+package module
+
+import "github.com/phinze/belowdeck/internal/bus"
+
+// BusUser is an interface that modules can implement to publish or subscribe
+// to cross-module events. BaseModule implements it; the coordinator provides
+// the shared bus when the module is registered.
+type BusUser interface {
+	SetBus(b *bus.Bus)
+}
@@ -0,0 +1,9 @@
+package module
+
+// Configurable is an interface that modules can implement to report, before
+// Init, whether they have the configuration they need. Unconfigured modules
+// aren't registered, and their share of the touch strip goes to the others.
+type Configurable interface {
+	// Configured reports whether the module's required settings are present.
+	Configured() bool
+}
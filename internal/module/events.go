@@ -69,4 +69,41 @@ type TouchStripEvent struct {
 	// SwipeEnd is the ending point of a swipe gesture.
 	// Only meaningful for TouchSwipe events.
 	SwipeEnd image.Point
+
+	// Pos, SwipeStartPos and SwipeEndPos are Point, SwipeStart and
+	// SwipeEnd as fractions of the whole strip, for hit-testing that
+	// doesn't depend on the strip's resolution.
+	Pos           StripPoint
+	SwipeStartPos StripPoint
+	SwipeEndPos   StripPoint
+}
+
+// StripPoint is a position on the touch strip as a fraction of its width
+// and height, from 0.0 at the left and top edges to 1.0 at the right and
+// bottom.
+type StripPoint struct {
+	X, Y float64
+}
+
+// NormalizeStripPoint returns p's position on strip, clamped to the strip.
+// An empty strip gives the zero StripPoint.
+func NormalizeStripPoint(p image.Point, strip image.Rectangle) StripPoint {
+	if strip.Empty() {
+		return StripPoint{}
+	}
+	return StripPoint{
+		X: min(max(float64(p.X-strip.Min.X)/float64(strip.Dx()), 0), 1),
+		Y: min(max(float64(p.Y-strip.Min.Y)/float64(strip.Dy()), 0), 1),
+	}
+}
+
+// Normalized returns the event with its normalized positions set for a
+// strip covering strip in device pixels.
+func (e TouchStripEvent) Normalized(strip image.Rectangle) TouchStripEvent {
+	e.Pos = NormalizeStripPoint(e.Point, strip)
+	if e.Type == TouchSwipe {
+		e.SwipeStartPos = NormalizeStripPoint(e.SwipeStart, strip)
+		e.SwipeEndPos = NormalizeStripPoint(e.SwipeEnd, strip)
+	}
+	return e
 }
@@ -0,0 +1,39 @@
+package module
+
+import (
+	"image"
+	"testing"
+)
+
+func TestNormalizeStripPoint(t *testing.T) {
+	tests := []struct {
+		p     image.Point
+		strip image.Rectangle
+		want  StripPoint
+	}{
+		{image.Pt(200, 50), image.Rect(0, 0, 800, 100), StripPoint{0.25, 0.5}},
+		{image.Pt(600, 20), image.Rect(0, 0, 1200, 80), StripPoint{0.5, 0.25}},
+		{image.Pt(150, 0), image.Rect(100, 0, 300, 100), StripPoint{0.25, 0}},
+		{image.Pt(900, -5), image.Rect(0, 0, 800, 100), StripPoint{1, 0}},
+		{image.Pt(200, 50), image.Rectangle{}, StripPoint{}},
+	}
+	for _, tt := range tests {
+		if got := NormalizeStripPoint(tt.p, tt.strip); got != tt.want {
+			t.Errorf("NormalizeStripPoint(%v, %v) = %v, want %v", tt.p, tt.strip, got, tt.want)
+		}
+	}
+}
+
+func TestTouchStripEventNormalized(t *testing.T) {
+	strip := image.Rect(0, 0, 800, 100)
+
+	tap := TouchStripEvent{Type: TouchTap, Point: image.Pt(400, 100)}.Normalized(strip)
+	if tap.Pos != (StripPoint{0.5, 1}) || tap.SwipeEndPos != (StripPoint{}) {
+		t.Errorf("tap Pos, SwipeEndPos = %v, %v; want {0.5 1}, zero", tap.Pos, tap.SwipeEndPos)
+	}
+
+	swipe := TouchStripEvent{Type: TouchSwipe, Point: image.Pt(80, 50), SwipeStart: image.Pt(80, 50), SwipeEnd: image.Pt(1000, 50)}.Normalized(strip)
+	if swipe.SwipeStartPos != (StripPoint{0.1, 0.5}) || swipe.SwipeEndPos != (StripPoint{1, 0.5}) {
+		t.Errorf("swipe positions = %v to %v, want {0.1 0.5} to {1 0.5}", swipe.SwipeStartPos, swipe.SwipeEndPos)
+	}
+}
@@ -0,0 +1,26 @@
+package module
+
+// Flash is the kind of confirmation flash shown on a key. The coordinator
+// decides the color and how long it lasts.
+type Flash int
+
+const (
+	// FlashSuccess confirms an action worked (green by default).
+	FlashSuccess Flash = iota
+
+	// FlashError signals an action failed (red by default).
+	FlashError
+)
+
+// Flasher briefly flashes a key to confirm an action, then restores it.
+// The coordinator implements it.
+type Flasher interface {
+	FlashKey(id KeyID, flash Flash)
+}
+
+// FlashUser is an interface that modules can implement to flash their keys.
+// BaseModule implements it; the coordinator provides the flasher when the
+// module is registered.
+type FlashUser interface {
+	SetFlasher(f Flasher)
+}
@@ -0,0 +1,134 @@
+package module
+
+import (
+	"fmt"
+
+	"rafaelmartins.com/p/streamdeck"
+)
+
+// KeyGrid maps between the ways a key is addressed: its KeyID, its
+// zero-based index in reading order, its column and row on the deck, and
+// the streamdeck library's KeyID. Keys are numbered left to right, top to
+// bottom, starting at Key1 and streamdeck.KEY_1. Use it instead of
+// arithmetic on KeyID values, so lookups outside the grid are caught.
+type KeyGrid struct {
+	Cols int
+	Rows int
+}
+
+// PlusKeyGrid is the 4x2 key grid of the Stream Deck Plus, which overlays
+// lay their keys out on.
+var PlusKeyGrid = KeyGrid{Cols: 4, Rows: 2}
+
+// NewKeyGrid returns the grid for a deck with the given columns and rows.
+func NewKeyGrid(cols, rows int) (KeyGrid, error) {
+	if cols <= 0 || rows <= 0 {
+		return KeyGrid{}, fmt.Errorf("invalid key grid %dx%d", cols, rows)
+	}
+	if cols*rows > int(^KeyID(0)) {
+		return KeyGrid{}, fmt.Errorf("key grid %dx%d has more keys than a KeyID can address", cols, rows)
+	}
+	return KeyGrid{Cols: cols, Rows: rows}, nil
+}
+
+// KeyGridForCount returns the grid of a deck with count keys: 3x2 for the
+// Mini, 4x2 for the Plus, 5x3 for the original and MK.2, 8x4 for the XL,
+// and a single row for anything else.
+func KeyGridForCount(count int) (KeyGrid, error) {
+	switch count {
+	case 6:
+		return NewKeyGrid(3, 2)
+	case 8:
+		return NewKeyGrid(4, 2)
+	case 15:
+		return NewKeyGrid(5, 3)
+	case 32:
+		return NewKeyGrid(8, 4)
+	default:
+		return NewKeyGrid(count, 1)
+	}
+}
+
+// Len returns the number of keys in the grid.
+func (g KeyGrid) Len() int {
+	return g.Cols * g.Rows
+}
+
+// Contains reports whether the key is on the grid.
+func (g KeyGrid) Contains(k KeyID) bool {
+	_, ok := g.Index(k)
+	return ok
+}
+
+// Index returns the key's zero-based index in reading order, reporting
+// false if it isn't on the grid.
+func (g KeyGrid) Index(k KeyID) (int, bool) {
+	i := int(k) - int(Key1)
+	if i < 0 || i >= g.Len() {
+		return 0, false
+	}
+	return i, true
+}
+
+// Key returns the key at a zero-based index in reading order, reporting
+// false if the index is outside the grid.
+func (g KeyGrid) Key(index int) (KeyID, bool) {
+	if index < 0 || index >= g.Len() {
+		return 0, false
+	}
+	return KeyID(index + int(Key1)), true
+}
+
+// Position returns the key's zero-based column and row, reporting false if
+// it isn't on the grid.
+func (g KeyGrid) Position(k KeyID) (col, row int, ok bool) {
+	i, ok := g.Index(k)
+	if !ok {
+		return 0, 0, false
+	}
+	return i % g.Cols, i / g.Cols, true
+}
+
+// At returns the key at a zero-based column and row, reporting false if
+// the position is outside the grid.
+func (g KeyGrid) At(col, row int) (KeyID, bool) {
+	if col < 0 || col >= g.Cols || row < 0 || row >= g.Rows {
+		return 0, false
+	}
+	return g.Key(row*g.Cols + col)
+}
+
+// Keys returns every key on the grid in reading order.
+func (g KeyGrid) Keys() []KeyID {
+	keys := make([]KeyID, 0, g.Len())
+	for i := 0; i < g.Len(); i++ {
+		k, _ := g.Key(i)
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Last returns the bottom right key.
+func (g KeyGrid) Last() KeyID {
+	k, _ := g.Key(g.Len() - 1)
+	return k
+}
+
+// Streamdeck returns the streamdeck library's KeyID for a key, reporting
+// false if it isn't on the grid.
+func (g KeyGrid) Streamdeck(k KeyID) (streamdeck.KeyID, bool) {
+	i, ok := g.Index(k)
+	if !ok {
+		return 0, false
+	}
+	return streamdeck.KEY_1 + streamdeck.KeyID(i), true
+}
+
+// FromStreamdeck returns the key for a streamdeck library KeyID, reporting
+// false if it isn't on the grid.
+func (g KeyGrid) FromStreamdeck(k streamdeck.KeyID) (KeyID, bool) {
+	if k < streamdeck.KEY_1 {
+		return 0, false
+	}
+	return g.Key(int(k - streamdeck.KEY_1))
+}
@@ -0,0 +1,104 @@
+package module
+
+import (
+	"slices"
+	"testing"
+
+	"rafaelmartins.com/p/streamdeck"
+)
+
+func TestKeyGridRoundTrips(t *testing.T) {
+	g := PlusKeyGrid
+	for i, k := range g.Keys() {
+		if got, ok := g.Index(k); !ok || got != i {
+			t.Errorf("Index(%d) = %d, %v; want %d", k, got, ok, i)
+		}
+		col, row, ok := g.Position(k)
+		if back, _ := g.At(col, row); !ok || back != k {
+			t.Errorf("At(Position(%d)) = %d, want %d", k, back, k)
+		}
+		sd, ok := g.Streamdeck(k)
+		if back, _ := g.FromStreamdeck(sd); !ok || back != k {
+			t.Errorf("FromStreamdeck(Streamdeck(%d)) = %d, want %d", k, back, k)
+		}
+	}
+}
+
+func TestKeyGridEdges(t *testing.T) {
+	g := PlusKeyGrid
+	for _, tt := range []struct {
+		key      KeyID
+		col, row int
+	}{
+		{Key1, 0, 0},
+		{Key4, 3, 0},
+		{Key5, 0, 1},
+		{Key8, 3, 1},
+	} {
+		if col, row, ok := g.Position(tt.key); !ok || col != tt.col || row != tt.row {
+			t.Errorf("Position(%d) = %d, %d, %v; want %d, %d", tt.key, col, row, ok, tt.col, tt.row)
+		}
+	}
+	if sd, _ := g.Streamdeck(Key8); sd != streamdeck.KEY_8 {
+		t.Errorf("Streamdeck(Key8) = %d, want KEY_8", sd)
+	}
+	if got := g.Last(); got != Key8 {
+		t.Errorf("Last() = %d, want Key8", got)
+	}
+
+	if g.Contains(0) || g.Contains(9) {
+		t.Error("grid contains a key outside 1-8")
+	}
+	if _, ok := g.Key(-1); ok {
+		t.Error("Key(-1) found a key")
+	}
+	if _, ok := g.Key(8); ok {
+		t.Error("Key(8) found a key")
+	}
+	if _, ok := g.At(4, 0); ok {
+		t.Error("At(4, 0) found a key past the last column")
+	}
+	if _, ok := g.At(0, 2); ok {
+		t.Error("At(0, 2) found a key past the last row")
+	}
+	if _, ok := g.FromStreamdeck(streamdeck.KEY_1 + 8); ok {
+		t.Error("FromStreamdeck found a ninth key")
+	}
+}
+
+func TestKeyGridForCount(t *testing.T) {
+	for _, tt := range []struct {
+		count      int
+		cols, rows int
+	}{
+		{6, 3, 2},
+		{8, 4, 2},
+		{15, 5, 3},
+		{32, 8, 4},
+		{5, 5, 1},
+	} {
+		g, err := KeyGridForCount(tt.count)
+		if err != nil || g.Cols != tt.cols || g.Rows != tt.rows {
+			t.Errorf("KeyGridForCount(%d) = %dx%d, %v; want %dx%d", tt.count, g.Cols, g.Rows, err, tt.cols, tt.rows)
+		}
+	}
+
+	xl, _ := KeyGridForCount(32)
+	if k, ok := xl.At(7, 3); !ok || k != 32 || xl.Last() != 32 {
+		t.Errorf("XL bottom right = %d, %v; want 32", k, ok)
+	}
+
+	if _, err := NewKeyGrid(0, 2); err == nil {
+		t.Error("NewKeyGrid(0, 2) succeeded")
+	}
+	if _, err := NewKeyGrid(16, 16); err == nil {
+		t.Error("NewKeyGrid(16, 16) succeeded with more keys than a KeyID holds")
+	}
+}
+
+func TestKeyGridKeys(t *testing.T) {
+	g := KeyGrid{Cols: 3, Rows: 1}
+	if got, want := g.Keys(), []KeyID{Key1, Key2, Key3}; !slices.Equal(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+}
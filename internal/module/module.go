@@ -22,6 +22,9 @@ type Module interface {
 	RenderKeys() map[KeyID]image.Image
 
 	// RenderStrip returns an image for this module's touch strip region.
+	// The image is in full-strip coordinates; pixels outside the module's
+	// StripRect are ignored, and transparent pixels inside it let the strip
+	// background (or modules beneath) show through.
 	// Returns nil if the module has no strip content to render.
 	RenderStrip() image.Image
 
@@ -0,0 +1,69 @@
+package module
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RefreshHold is how long a key must be held to refresh its Refreshable
+// module, when that's shorter than the snooze hold. Modules that act on key
+// release should ignore releases held this long.
+const RefreshHold = time.Second
+
+// Refreshable is an interface that polling modules can implement to fetch
+// fresh data on demand rather than waiting for their next poll. The
+// coordinator calls Refresh on a long key hold or a "<module ID>:refresh"
+// action.
+type Refreshable interface {
+	// Refresh starts a fetch in the background and returns immediately.
+	Refresh()
+}
+
+// Refresher runs a module's fetch on demand, at most once per cooldown so a
+// flurry of requests doesn't hammer an API, and reports whether a fetch is
+// in progress so keys can show it.
+type Refresher struct {
+	// Fetch fetches fresh data. It runs on its own goroutine.
+	Fetch func(ctx context.Context)
+
+	// Cooldown is the minimum time between the starts of two refreshes.
+	Cooldown time.Duration
+
+	mu      sync.Mutex
+	last    time.Time
+	running bool
+}
+
+// Trigger starts Fetch in the background, unless a refresh is running or
+// one started within Cooldown. It reports whether a fetch was started.
+func (r *Refresher) Trigger(ctx context.Context) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.running || (!r.last.IsZero() && now.Sub(r.last) < r.Cooldown) {
+		return false
+	}
+	r.running = true
+	r.last = now
+
+	done := track(ctx)
+	go func() {
+		defer done()
+		defer func() {
+			r.mu.Lock()
+			r.running = false
+			r.mu.Unlock()
+		}()
+		runRecovered(ctx, "refresh", r.Fetch)
+	}()
+	return true
+}
+
+// Refreshing reports whether a triggered fetch is still running.
+func (r *Refresher) Refreshing() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
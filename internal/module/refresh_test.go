@@ -0,0 +1,49 @@
+package module
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRefresherDebounced(t *testing.T) {
+	release := make(chan struct{})
+	fetches := make(chan struct{}, 3)
+	r := &Refresher{
+		Fetch: func(ctx context.Context) {
+			fetches <- struct{}{}
+			<-release
+		},
+		Cooldown: 200 * time.Millisecond,
+	}
+	ctx := context.Background()
+
+	if !r.Trigger(ctx) {
+		t.Fatal("first trigger rejected")
+	}
+	<-fetches
+	if !r.Refreshing() {
+		t.Error("not refreshing while the fetch runs")
+	}
+	if r.Trigger(ctx) {
+		t.Error("trigger accepted while a refresh was running")
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for r.Refreshing() {
+		if time.Now().After(deadline) {
+			t.Fatal("still refreshing after the fetch returned")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if r.Trigger(ctx) {
+		t.Error("trigger accepted within the cooldown")
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if !r.Trigger(ctx) {
+		t.Error("trigger rejected after the cooldown")
+	}
+	<-fetches
+}
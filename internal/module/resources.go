@@ -38,6 +38,11 @@ type Resources struct {
 	// A zero rect means no strip region is allocated.
 	StripRect image.Rectangle
 
+	// StripZ orders strip regions that overlap: lower values are composited
+	// first, underneath, and higher ones get touches first. Regions from
+	// the split are at 0 and backgrounds below it.
+	StripZ int
+
 	// Dials assigned to this module (may be empty).
 	Dials []DialID
 }
@@ -0,0 +1,27 @@
+package module
+
+import "time"
+
+// Snoozable is an interface that modules can implement to be temporarily
+// silenced. BaseModule implements it, so every module embedding BaseModule is
+// snoozable; modules opt in to pausing background work by checking IsSnoozed.
+type Snoozable interface {
+	// Snooze silences the module until the given time.
+	Snooze(until time.Time)
+
+	// Unsnooze resumes the module immediately.
+	Unsnooze()
+
+	// SnoozedUntil returns when the current snooze ends, or the zero time if
+	// the module is not snoozed.
+	SnoozedUntil() time.Time
+}
+
+// KeyHolder is an interface that modules can implement when some of their
+// keys act for as long as they're held, like momentary switches. The
+// coordinator delivers presses on those keys as they happen rather than at
+// release, so holding them doesn't snooze the module.
+type KeyHolder interface {
+	// HoldsKey reports whether the given key acts while held.
+	HoldsKey(id KeyID) bool
+}
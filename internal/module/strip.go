@@ -0,0 +1,22 @@
+package module
+
+import "time"
+
+// StripScheduler is an interface that modules can implement to have their
+// strip rendered less often than every render cycle, for strips that change
+// slowly. Between renders the coordinator reuses the last image, and it
+// skips rewriting the strip entirely when no contributing module is due.
+// Modules that don't implement it have their strip rendered every cycle.
+type StripScheduler interface {
+	// StripInterval returns the minimum time between renders of the
+	// module's strip. Zero renders it every cycle.
+	StripInterval() time.Duration
+}
+
+// StripUpdater is an interface that StripScheduler modules can implement so
+// new data shows on the strip right away rather than once the interval is up.
+type StripUpdater interface {
+	// StripUpdated returns when the data the strip shows last changed. A
+	// strip rendered before then is due.
+	StripUpdated() time.Time
+}
@@ -0,0 +1,96 @@
+package module
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Restart backoff for Supervise; variables so tests can shorten them.
+var (
+	// superviseMinBackoff is the delay before the first restart.
+	superviseMinBackoff = time.Second
+
+	// superviseMaxBackoff caps the delay between restarts.
+	superviseMaxBackoff = time.Minute
+
+	// superviseStableAfter is how long a goroutine must run before its
+	// backoff resets to the minimum.
+	superviseStableAfter = time.Minute
+)
+
+// Supervise runs fn in a background goroutine and keeps it running for the
+// lifetime of ctx. If fn returns or panics while ctx is still live, the
+// panic is logged and fn is restarted after an exponential backoff. Use this
+// for long-running poll and stream loops instead of a bare go statement so a
+// module doesn't silently go stale.
+func Supervise(ctx context.Context, name string, fn func(ctx context.Context)) {
+	done := track(ctx)
+	go func() {
+		defer done()
+		supervise(ctx, name, fn)
+	}()
+}
+
+// waitGroupKey is the context key for the WaitGroup set by WithWaitGroup.
+type waitGroupKey struct{}
+
+// WithWaitGroup returns a copy of ctx whose background goroutines, those
+// started by Supervise and Refresher, are counted in wg. The coordinator
+// waits on it at shutdown so modules have finished, and any subprocesses
+// they run have exited, before the device is closed.
+func WithWaitGroup(ctx context.Context, wg *sync.WaitGroup) context.Context {
+	return context.WithValue(ctx, waitGroupKey{}, wg)
+}
+
+// track counts a goroutine about to start under ctx in its WaitGroup, if
+// it has one, returning the func to call when the goroutine exits.
+func track(ctx context.Context) func() {
+	wg, ok := ctx.Value(waitGroupKey{}).(*sync.WaitGroup)
+	if !ok {
+		return func() {}
+	}
+	wg.Add(1)
+	return wg.Done
+}
+
+// supervise is the restart loop behind Supervise.
+func supervise(ctx context.Context, name string, fn func(ctx context.Context)) {
+	backoff := superviseMinBackoff
+	for {
+		started := time.Now()
+		runRecovered(ctx, name, fn)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) >= superviseStableAfter {
+			backoff = superviseMinBackoff
+		}
+		log.Printf("%s: exited unexpectedly, restarting in %v", name, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > superviseMaxBackoff {
+			backoff = superviseMaxBackoff
+		}
+	}
+}
+
+// runRecovered calls fn, converting a panic into a logged error.
+func runRecovered(ctx context.Context, name string, fn func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%s: panic: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+	fn(ctx)
+}
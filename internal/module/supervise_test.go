@@ -0,0 +1,114 @@
+package module
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSuperviseCountedInWaitGroup(t *testing.T) {
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(WithWaitGroup(context.Background(), &wg))
+
+	var exited atomic.Bool
+	Supervise(ctx, "test", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		exited.Store(true)
+	})
+
+	cancel()
+	wg.Wait()
+	if !exited.Load() {
+		t.Error("wait returned before the supervised goroutine exited")
+	}
+}
+
+// fastBackoff shortens Supervise's restart backoff for the test.
+func fastBackoff(t *testing.T, min, max time.Duration) {
+	t.Helper()
+	savedMin, savedMax := superviseMinBackoff, superviseMaxBackoff
+	superviseMinBackoff, superviseMaxBackoff = min, max
+	t.Cleanup(func() { superviseMinBackoff, superviseMaxBackoff = savedMin, savedMax })
+}
+
+func TestSuperviseRestartsWithBackoff(t *testing.T) {
+	fastBackoff(t, 20*time.Millisecond, 80*time.Millisecond)
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(WithWaitGroup(context.Background(), &wg))
+	defer wg.Wait()
+	defer cancel()
+
+	var mu sync.Mutex
+	var starts []time.Time
+	fourth := make(chan struct{})
+	Supervise(ctx, "test", func(ctx context.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		starts = append(starts, time.Now())
+		if len(starts) == 4 {
+			close(fourth)
+		}
+		// Exits at once, as a loop whose stream failed would
+	})
+
+	select {
+	case <-fourth:
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervised function not restarted")
+	}
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, want := range []time.Duration{20, 40, 80} {
+		gap := starts[i+1].Sub(starts[i])
+		if gap < want*time.Millisecond {
+			t.Errorf("restart %d after %v, want at least %v", i+1, gap, want*time.Millisecond)
+		}
+	}
+}
+
+func TestSuperviseRestartsAfterPanic(t *testing.T) {
+	fastBackoff(t, time.Millisecond, time.Millisecond)
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(WithWaitGroup(context.Background(), &wg))
+	defer wg.Wait()
+	defer cancel()
+
+	var runs atomic.Int32
+	again := make(chan struct{})
+	Supervise(ctx, "test", func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+		close(again)
+		<-ctx.Done()
+	})
+
+	select {
+	case <-again:
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervised function not restarted after a panic")
+	}
+}
+
+func TestSuperviseNotRestartedAfterCancel(t *testing.T) {
+	fastBackoff(t, time.Millisecond, time.Millisecond)
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(WithWaitGroup(context.Background(), &wg))
+
+	var runs atomic.Int32
+	Supervise(ctx, "test", func(ctx context.Context) {
+		runs.Add(1)
+		<-ctx.Done()
+	})
+
+	cancel()
+	wg.Wait()
+	if got := runs.Load(); got != 1 {
+		t.Errorf("ran %d times, want once with no restart after cancel", got)
+	}
+}
@@ -0,0 +1,281 @@
+// Package audiooutput provides a Stream Deck module for cycling the macOS
+// audio output device, e.g. between headphones and speakers.
+package audiooutput
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+	"golang.org/x/image/font"
+)
+
+// pollInterval is how often the current output device is read, to follow
+// changes made outside the deck.
+const pollInterval = 5 * time.Second
+
+// Output is an audio output device the key cycles through.
+type Output struct {
+	// Device is the device name as SwitchAudioSource reports it, e.g.
+	// "MacBook Pro Speakers".
+	Device string
+
+	// Label is shown on the key. When empty, the device name is used.
+	Label string
+}
+
+// label returns the text shown on the key for the output.
+func (o Output) label() string {
+	if o.Label != "" {
+		return o.Label
+	}
+	return o.Device
+}
+
+// Config holds the audio output module configuration.
+type Config struct {
+	// Outputs are cycled through in order.
+	Outputs []Output
+}
+
+// Module implements the audio output module.
+type Module struct {
+	module.BaseModule
+
+	device  device.Device
+	config  Config
+	runner  runner.Runner
+	enabled bool
+
+	// State: the current output device, and whether the last read failed
+	// so repeated failures are only logged once
+	mu      sync.RWMutex
+	current string
+	failed  bool
+
+	// Fonts
+	labelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new audio output module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("audiooutput"),
+		device:     dev,
+		runner:     runner.Default,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "audiooutput"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	// Load config from environment (optional - module disabled if not configured)
+	config, err := loadConfig()
+	if err != nil {
+		log.Printf("Audio output module disabled: %v", err)
+		m.enabled = false
+		return nil
+	}
+	if !platform.SwitchAudioSourceAvailable() {
+		log.Println("Audio output module disabled: SwitchAudioSource not found (brew install switchaudio-osx)")
+		m.enabled = false
+		return nil
+	}
+	m.config = config
+	m.enabled = true
+
+	// Initialize fonts
+	m.initFonts()
+
+	// Keep the key in sync with the system output
+	module.Supervise(ctx, "audiooutput poll", m.pollCurrent)
+
+	log.Printf("Audio output module initialized (%d outputs)", len(m.config.Outputs))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+//
+// AUDIO_OUTPUTS is a semicolon-separated list of entries, each of the form
+// "Device" or "Device|Label".
+func loadConfig() (Config, error) {
+	v := os.Getenv("AUDIO_OUTPUTS")
+	if v == "" {
+		return Config{}, fmt.Errorf("AUDIO_OUTPUTS environment variable not set")
+	}
+
+	var config Config
+	for _, entry := range strings.Split(v, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) > 2 {
+			return Config{}, fmt.Errorf("invalid AUDIO_OUTPUTS entry %q: want Device or Device|Label", entry)
+		}
+		o := Output{Device: strings.TrimSpace(parts[0])}
+		if o.Device == "" {
+			return Config{}, fmt.Errorf("invalid AUDIO_OUTPUTS entry %q: device is required", entry)
+		}
+		if len(parts) == 2 {
+			o.Label = strings.TrimSpace(parts[1])
+		}
+		config.Outputs = append(config.Outputs, o)
+	}
+	if len(config.Outputs) == 0 {
+		return Config{}, fmt.Errorf("AUDIO_OUTPUTS has no outputs")
+	}
+	return config, nil
+}
+
+// pollCurrent periodically reads the current output device.
+func (m *Module) pollCurrent(ctx context.Context) {
+	m.fetchCurrent(ctx)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if m.IsSnoozed() {
+				continue
+			}
+			m.fetchCurrent(ctx)
+		}
+	}
+}
+
+// fetchCurrent reads the current output device from SwitchAudioSource.
+func (m *Module) fetchCurrent(ctx context.Context) {
+	out, err := m.runner.Output(ctx, "SwitchAudioSource", "-c", "-t", "output")
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		if !m.failed {
+			log.Printf("Failed to read audio output: %v", err)
+		}
+		m.failed = true
+		return
+	}
+	m.failed = false
+	m.current = strings.TrimSpace(string(out))
+}
+
+// getCurrent returns the current output device, empty until it's been read.
+func (m *Module) getCurrent() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// nextOutput returns the output after current in outputs, wrapping around.
+// If current isn't one of them, the first output is next.
+func nextOutput(outputs []Output, current string) Output {
+	for i, o := range outputs {
+		if o.Device == current {
+			return outputs[(i+1)%len(outputs)]
+		}
+	}
+	return outputs[0]
+}
+
+// outputFor returns the configured output for a device, or an output
+// labeled with the device name for one that isn't configured.
+func (m *Module) outputFor(dev string) Output {
+	for _, o := range m.config.Outputs {
+		if o.Device == dev {
+			return o
+		}
+	}
+	return Output{Device: dev}
+}
+
+// cycle switches to the next configured output.
+func (m *Module) cycle() error {
+	next := nextOutput(m.config.Outputs, m.getCurrent())
+
+	log.Printf("Switching audio output to %s...", next.Device)
+	if err := m.runner.Run(m.Context(), "SwitchAudioSource", "-t", "output", "-s", next.Device); err != nil {
+		log.Printf("Failed to switch audio output to %s: %v", next.Device, err)
+		return err
+	}
+
+	m.mu.Lock()
+	m.current = next.Device
+	m.failed = false
+	m.mu.Unlock()
+	return nil
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled || len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderOutputButton(m.getCurrent()),
+	}
+}
+
+// HandleAction runs an external action: "next" switches to the next output.
+func (m *Module) HandleAction(action string) error {
+	if action != "next" {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	if !m.enabled {
+		return fmt.Errorf("not configured")
+	}
+	return m.cycle()
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	if len(m.resources.Keys) > 0 && id == m.resources.Keys[0] {
+		err := m.cycle()
+		m.FlashResult(id, err)
+		return err
+	}
+
+	return nil
+}
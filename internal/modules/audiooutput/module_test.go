@@ -0,0 +1,88 @@
+package audiooutput
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+var testOutputs = []Output{{Device: "MacBook Pro Speakers", Label: "Speakers"}, {Device: "AirPods"}, {Device: "Studio Display"}}
+
+func TestNextOutput(t *testing.T) {
+	for _, tt := range []struct {
+		current string
+		want    string
+	}{
+		{"MacBook Pro Speakers", "AirPods"},
+		{"AirPods", "Studio Display"},
+		{"Studio Display", "MacBook Pro Speakers"}, // wraps around
+		{"HDMI", "MacBook Pro Speakers"},           // not configured
+		{"", "MacBook Pro Speakers"},
+	} {
+		if got := nextOutput(testOutputs, tt.current); got.Device != tt.want {
+			t.Errorf("nextOutput(%q) = %q, want %q", tt.current, got.Device, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("AUDIO_OUTPUTS", " MacBook Pro Speakers|Speakers ; AirPods;")
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	want := []Output{{Device: "MacBook Pro Speakers", Label: "Speakers"}, {Device: "AirPods"}}
+	if !slices.Equal(config.Outputs, want) {
+		t.Errorf("outputs = %+v, want %+v", config.Outputs, want)
+	}
+
+	for _, bad := range []string{"", ";", "|Label", "a|b|c"} {
+		t.Setenv("AUDIO_OUTPUTS", bad)
+		if _, err := loadConfig(); err == nil {
+			t.Errorf("loadConfig with AUDIO_OUTPUTS=%q succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestPressSwitchesToNextOutput(t *testing.T) {
+	m := New(device.NewFake())
+	res := module.Resources{Keys: []module.KeyID{6}}
+	m.BaseModule.Init(context.Background(), res)
+	m.resources = res
+	m.config = Config{Outputs: testOutputs}
+	m.enabled = true
+	fake := &runner.Fake{Outputs: map[string]string{
+		"SwitchAudioSource -c -t output": "AirPods\n",
+	}}
+	m.runner = fake
+
+	m.fetchCurrent(context.Background())
+	if got := m.getCurrent(); got != "AirPods" {
+		t.Fatalf("current output = %q, want AirPods", got)
+	}
+
+	if err := m.HandleKey(6, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatalf("HandleKey: %v", err)
+	}
+	want := []string{"SwitchAudioSource -c -t output", "SwitchAudioSource -t output -s Studio Display"}
+	if got := fake.Commands(); !slices.Equal(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+	if got := m.getCurrent(); got != "Studio Display" {
+		t.Errorf("current output after the press = %q, want Studio Display", got)
+	}
+
+	// A failed switch leaves the current output alone
+	fake.Err = errors.New("no such device")
+	if err := m.HandleKey(6, module.KeyEvent{Pressed: true}); err == nil {
+		t.Error("HandleKey succeeded though the switch failed")
+	}
+	if got := m.getCurrent(); got != "Studio Display" {
+		t.Errorf("current output after a failed switch = %q, want Studio Display", got)
+	}
+}
@@ -0,0 +1,267 @@
+// Package bookmarks provides a Stream Deck module of keys that open URLs.
+package bookmarks
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/httpclient"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+	"golang.org/x/image/font"
+)
+
+// Bookmark is a single configured bookmark key.
+type Bookmark struct {
+	Label string
+	URL   string
+
+	// Icon is an optional image URL. When unset, the site's
+	// apple-touch-icon is tried; if no icon can be fetched the key shows
+	// the label's first letter instead.
+	Icon string
+}
+
+// Config holds the bookmarks module configuration.
+type Config struct {
+	Bookmarks []Bookmark
+}
+
+// Module implements the bookmarks module.
+type Module struct {
+	module.BaseModule
+
+	device     device.Device
+	config     Config
+	runner     runner.Runner
+	httpClient *http.Client
+	enabled    bool
+
+	// Fetched icons, keyed by icon URL. A nil entry records a failed fetch
+	// so it isn't retried.
+	mu    sync.RWMutex
+	icons map[string]image.Image
+
+	// Fonts
+	labelFace  font.Face
+	letterFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new bookmarks module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("bookmarks"),
+		device:     dev,
+		runner:     runner.Default,
+		httpClient: httpclient.New(),
+		icons:      make(map[string]image.Image),
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "bookmarks"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	// Load config from environment (optional - module disabled if not configured)
+	config, err := loadConfig()
+	if err != nil {
+		log.Printf("Bookmarks module disabled: %v", err)
+		m.enabled = false
+		return nil
+	}
+	m.config = config
+	m.enabled = true
+
+	if len(m.config.Bookmarks) > len(res.Keys) {
+		log.Printf("Bookmarks: %d configured but only %d keys allocated, extra bookmarks hidden",
+			len(m.config.Bookmarks), len(res.Keys))
+	}
+
+	// Initialize fonts
+	m.initFonts()
+
+	// Fetch icons in the background; keys show letters until they arrive
+	go m.fetchIcons(ctx)
+
+	log.Printf("Bookmarks module initialized (%d bookmarks)", len(m.config.Bookmarks))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+//
+// BOOKMARKS is a semicolon-separated list of entries, each of the form
+// "Label|URL" or "Label|URL|IconURL".
+func loadConfig() (Config, error) {
+	v := os.Getenv("BOOKMARKS")
+	if v == "" {
+		return Config{}, fmt.Errorf("BOOKMARKS environment variable not set")
+	}
+
+	var config Config
+	for _, entry := range strings.Split(v, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) < 2 || len(parts) > 3 {
+			return Config{}, fmt.Errorf("invalid BOOKMARKS entry %q: want Label|URL[|IconURL]", entry)
+		}
+
+		b := Bookmark{
+			Label: strings.TrimSpace(parts[0]),
+			URL:   strings.TrimSpace(parts[1]),
+		}
+		if len(parts) == 3 {
+			b.Icon = strings.TrimSpace(parts[2])
+		}
+		if b.Label == "" || b.URL == "" {
+			return Config{}, fmt.Errorf("invalid BOOKMARKS entry %q: label and URL are required", entry)
+		}
+
+		config.Bookmarks = append(config.Bookmarks, b)
+	}
+
+	if len(config.Bookmarks) == 0 {
+		return Config{}, fmt.Errorf("BOOKMARKS has no entries")
+	}
+
+	return config, nil
+}
+
+// iconURL returns the icon to fetch for a bookmark, or "" if there is none.
+func iconURL(b Bookmark) string {
+	if b.Icon != "" {
+		return b.Icon
+	}
+
+	u, err := url.Parse(b.URL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host + "/apple-touch-icon.png"
+}
+
+// fetchIcons fetches and caches the icon for every bookmark.
+func (m *Module) fetchIcons(ctx context.Context) {
+	for _, b := range m.config.Bookmarks {
+		src := iconURL(b)
+		if src == "" {
+			continue
+		}
+
+		m.mu.RLock()
+		_, cached := m.icons[src]
+		m.mu.RUnlock()
+		if cached {
+			continue
+		}
+
+		img, err := m.fetchIcon(ctx, src)
+		if err != nil {
+			log.Printf("Bookmarks: no icon for %s, using letter: %v", b.Label, err)
+		}
+
+		m.mu.Lock()
+		m.icons[src] = img
+		m.mu.Unlock()
+	}
+}
+
+// fetchIcon downloads and decodes a single icon image.
+func (m *Module) fetchIcon(ctx context.Context, src string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", src, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch icon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("icon request returned %d", resp.StatusCode)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode icon: %w", err)
+	}
+	return img, nil
+}
+
+// icon returns the cached icon for a bookmark, or nil if it has none.
+func (m *Module) icon(b Bookmark) image.Image {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.icons[iconURL(b)]
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled {
+		return nil
+	}
+
+	keys := make(map[module.KeyID]image.Image)
+	for i, keyID := range m.resources.Keys {
+		if i >= len(m.config.Bookmarks) {
+			break
+		}
+		b := m.config.Bookmarks[i]
+		keys[keyID] = m.renderBookmarkKey(b, m.icon(b))
+	}
+	return keys
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	for i, keyID := range m.resources.Keys {
+		if keyID != id || i >= len(m.config.Bookmarks) {
+			continue
+		}
+		m.openURL(m.config.Bookmarks[i].URL)
+	}
+
+	return nil
+}
+
+// openURL opens a URL in the default browser.
+func (m *Module) openURL(url string) {
+	log.Printf("Bookmarks: opening %s", url)
+	if err := runner.OpenURL(m.Context(), m.runner, url); err != nil {
+		log.Printf("Failed to open URL %s: %v", url, err)
+	}
+}
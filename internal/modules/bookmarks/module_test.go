@@ -0,0 +1,156 @@
+package bookmarks
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// newTestModule returns an enabled module with bookmarks on keys 1 and 2,
+// running commands with the returned runner.
+func newTestModule(t *testing.T, bookmarks ...Bookmark) (*Module, *runner.Fake) {
+	t.Helper()
+	fake := &runner.Fake{}
+
+	m := New(device.NewFake())
+	res := module.Resources{Keys: []module.KeyID{1, 2}}
+	if err := m.BaseModule.Init(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+	m.resources = res
+	m.config = Config{Bookmarks: bookmarks}
+	m.enabled = true
+	m.runner = fake
+	m.initFonts()
+	return m, fake
+}
+
+// iconServer serves a solid red PNG at /icon.png and 404s everything else,
+// counting requests by path.
+func iconServer(t *testing.T) (*httptest.Server, map[string]*atomic.Int32) {
+	t.Helper()
+	hits := map[string]*atomic.Int32{"/icon.png": {}, "/missing.png": {}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n, ok := hits[r.URL.Path]; ok {
+			n.Add(1)
+		}
+		if r.URL.Path != "/icon.png" {
+			http.NotFound(w, r)
+			return
+		}
+		img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+		draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+		png.Encode(w, img)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, hits
+}
+
+func TestPressOpensBookmark(t *testing.T) {
+	saved := runner.URLThrottle
+	runner.URLThrottle = &runner.OpenThrottle{}
+	t.Cleanup(func() { runner.URLThrottle = saved })
+
+	m, fake := newTestModule(t,
+		Bookmark{Label: "Docs", URL: "https://docs.example.com"},
+		Bookmark{Label: "Mail", URL: "https://mail.example.com"},
+	)
+
+	m.HandleKey(2, module.KeyEvent{Pressed: true})
+	m.HandleKey(2, module.KeyEvent{Pressed: false})
+
+	name, args := platform.OpenURL("https://mail.example.com")
+	want := strings.Join(append([]string{name}, args...), " ")
+	if got := fake.Commands(); len(got) != 1 || got[0] != want {
+		t.Errorf("ran %q, want only %q", got, want)
+	}
+}
+
+func TestPressWithoutBookmarkDoesNothing(t *testing.T) {
+	m, fake := newTestModule(t, Bookmark{Label: "Docs", URL: "https://docs.example.com"})
+
+	m.HandleKey(2, module.KeyEvent{Pressed: true})
+	if got := fake.Commands(); len(got) != 0 {
+		t.Errorf("key without a bookmark ran %q", got)
+	}
+}
+
+func TestIconFetchFailureShowsLetter(t *testing.T) {
+	srv, _ := iconServer(t)
+	b := Bookmark{Label: "Docs", URL: srv.URL + "/docs", Icon: srv.URL + "/missing.png"}
+	m, _ := newTestModule(t, b)
+
+	m.fetchIcons(context.Background())
+
+	if m.icon(b) != nil {
+		t.Fatal("failed fetch left an icon")
+	}
+	key := m.RenderKeys()[1].(*image.RGBA)
+	if got := key.RGBAAt(keySize/2, 12); !slices.Contains(letterColors, got) {
+		t.Errorf("icon area = %v, want a letter icon background", got)
+	}
+}
+
+func TestIconFetched(t *testing.T) {
+	srv, _ := iconServer(t)
+	b := Bookmark{Label: "Docs", URL: srv.URL + "/docs", Icon: srv.URL + "/icon.png"}
+	m, _ := newTestModule(t, b)
+
+	m.fetchIcons(context.Background())
+
+	if m.icon(b) == nil {
+		t.Fatal("icon not cached after fetch")
+	}
+	key := m.RenderKeys()[1].(*image.RGBA)
+	if got, want := key.RGBAAt(keySize/2, 12), (color.RGBA{255, 0, 0, 255}); got != want {
+		t.Errorf("icon area = %v, want the fetched icon's %v", got, want)
+	}
+}
+
+func TestIconsCached(t *testing.T) {
+	srv, hits := iconServer(t)
+	m, _ := newTestModule(t,
+		Bookmark{Label: "Docs", URL: srv.URL + "/docs", Icon: srv.URL + "/icon.png"},
+		Bookmark{Label: "Wiki", URL: srv.URL + "/wiki", Icon: srv.URL + "/icon.png"},
+		Bookmark{Label: "Old", URL: srv.URL + "/old", Icon: srv.URL + "/missing.png"},
+	)
+
+	m.fetchIcons(context.Background())
+	m.fetchIcons(context.Background())
+
+	// Shared icons are fetched once, and failures aren't retried
+	for path, n := range hits {
+		if got := n.Load(); got != 1 {
+			t.Errorf("%s fetched %d times, want 1", path, got)
+		}
+	}
+}
+
+func TestIconURL(t *testing.T) {
+	tests := []struct {
+		b    Bookmark
+		want string
+	}{
+		{Bookmark{URL: "https://example.com/a/b?c=d"}, "https://example.com/apple-touch-icon.png"},
+		{Bookmark{URL: "https://example.com", Icon: "https://cdn.example.com/i.png"}, "https://cdn.example.com/i.png"},
+		{Bookmark{URL: "not a url"}, ""},
+	}
+	for _, tt := range tests {
+		if got := iconURL(tt.b); got != tt.want {
+			t.Errorf("iconURL(%+v) = %q, want %q", tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package bookmarks
+
+import (
+	_ "embed"
+	"hash/fnv"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+// Common colors
+var (
+	colorKeyBg = color.RGBA{40, 40, 40, 255}
+	colorWhite = color.RGBA{255, 255, 255, 255}
+)
+
+// letterColors are the backgrounds for letter icons, picked by label hash
+// so each bookmark keeps a stable color.
+var letterColors = []color.RGBA{
+	{66, 133, 244, 255},
+	{219, 68, 55, 255},
+	{244, 160, 0, 255},
+	{15, 157, 88, 255},
+	{138, 110, 255, 255},
+	{0, 172, 193, 255},
+}
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("bookmarks bold", render.BoldFont, fontBold)
+	m.labelFace = render.NewFace(ttBold, 11)
+	m.letterFace = render.NewFace(ttBold, 26)
+}
+
+// renderBookmarkKey renders a bookmark with its icon (or a letter icon if
+// icon is nil) above its label.
+func (m *Module) renderBookmarkKey(b Bookmark, icon image.Image) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	// Draw icon in upper portion
+	const iconSize = 40
+	iconX := (keySize - iconSize) / 2
+	iconY := 8
+	iconRect := image.Rect(iconX, iconY, iconX+iconSize, iconY+iconSize)
+	if icon != nil {
+		c.DrawImage(iconRect, icon)
+	} else {
+		m.drawLetterIcon(c, b.Label, iconRect)
+	}
+
+	// Draw label at bottom
+	c.DrawStringCentered(b.Label, keySize/2, 62, m.labelFace, colorWhite)
+
+	return c.Image()
+}
+
+// drawLetterIcon draws the first letter of label on a colored square.
+func (m *Module) drawLetterIcon(c *render.Canvas, label string, rect image.Rectangle) {
+	h := fnv.New32a()
+	h.Write([]byte(label))
+	bg := letterColors[h.Sum32()%uint32(len(letterColors))]
+	c.Fill(rect, bg)
+
+	r, _ := utf8.DecodeRuneInString(label)
+	letter := strings.ToUpper(string(r))
+
+	// Center the letter vertically using the face's cap height
+	metrics := m.letterFace.Metrics()
+	baseline := rect.Min.Y + (rect.Dy()+metrics.CapHeight.Ceil())/2
+	c.DrawStringCentered(letter, rect.Min.X+rect.Dx()/2, baseline, m.letterFace, colorWhite)
+}
@@ -0,0 +1,171 @@
+// Package clock provides a Stream Deck module showing the time on a key.
+package clock
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+// Config holds the clock module configuration.
+type Config struct {
+	// Hour24 shows the time as 24-hour rather than 12-hour with AM/PM.
+	Hour24 bool
+
+	// Date adds the weekday and date under the time.
+	Date bool
+
+	// Seconds adds seconds to the time, so the key changes every second
+	// instead of every minute.
+	Seconds bool
+}
+
+// Module implements the clock module.
+type Module struct {
+	module.BaseModule
+
+	device device.Device
+	config Config
+
+	// now tells the time
+	now func() time.Time
+
+	// The last rendered key, reused until the time shown changes
+	mu       sync.Mutex
+	shown    string
+	rendered image.Image
+
+	// Fonts
+	timeFaces []font.Face // largest first
+	smallFace font.Face
+	dateFace  font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new clock module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("clock"),
+		device:     dev,
+		now:        time.Now,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "clock"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	m.config = config
+
+	// Initialize fonts
+	m.initFonts()
+
+	log.Println("Clock module initialized")
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+func loadConfig() (Config, error) {
+	config := Config{Hour24: true}
+
+	switch v := os.Getenv("CLOCK_FORMAT"); v {
+	case "", "24h":
+	case "12h":
+		config.Hour24 = false
+	default:
+		return Config{}, fmt.Errorf("invalid CLOCK_FORMAT %q (want \"12h\" or \"24h\")", v)
+	}
+
+	for name, dst := range map[string]*bool{"CLOCK_DATE": &config.Date, "CLOCK_SECONDS": &config.Seconds} {
+		if v := os.Getenv(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid %s: %q", name, v)
+			}
+			*dst = b
+		}
+	}
+
+	return config, nil
+}
+
+// display is the time shown on the key, split for drawing.
+type display struct {
+	clock   string // hours and minutes, e.g. "9:41"
+	seconds string // e.g. ":07", empty unless Seconds
+	period  string // "AM" or "PM", empty for 24-hour
+	date    string // e.g. "Fri 16 Oct", empty unless Date
+}
+
+// key returns a string that changes whenever anything shown does.
+func (d display) key() string {
+	return d.clock + d.seconds + " " + d.period + " " + d.date
+}
+
+// displayAt returns what the key shows at t.
+func (c Config) displayAt(t time.Time) display {
+	var d display
+	if c.Hour24 {
+		d.clock = t.Format("15:04")
+	} else {
+		d.clock = t.Format("3:04")
+		d.period = t.Format("PM")
+	}
+	if c.Seconds {
+		d.seconds = t.Format(":05")
+	}
+	if c.Date {
+		d.date = t.Format("Mon 2 Jan")
+	}
+	return d
+}
+
+// RenderKeys returns images for the module's keys. The key is only redrawn
+// when the time shown changes, once a minute unless seconds are shown.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	d := m.config.displayAt(m.now())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.rendered == nil || m.shown != d.key() {
+		m.rendered = m.renderClock(d)
+		m.shown = d.key()
+	}
+
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.rendered,
+	}
+}
@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestDisplayAt(t *testing.T) {
+	at := time.Date(2026, 10, 16, 21, 41, 7, 0, time.UTC)
+	tests := []struct {
+		name   string
+		config Config
+		want   display
+	}{
+		{"24h", Config{Hour24: true}, display{clock: "21:41"}},
+		{"12h", Config{}, display{clock: "9:41", period: "PM"}},
+		{"seconds", Config{Hour24: true, Seconds: true}, display{clock: "21:41", seconds: ":07"}},
+		{"date", Config{Hour24: true, Date: true}, display{clock: "21:41", date: "Fri 16 Oct"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.displayAt(at); got != tt.want {
+				t.Errorf("displayAt() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderKeysOncePerMinute(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 41, 0, 0, time.UTC)
+	m := New(device.NewFake())
+	m.resources = module.Resources{Keys: []module.KeyID{1}}
+	m.config = Config{Hour24: true}
+	m.now = func() time.Time { return now }
+	m.initFonts()
+
+	first := m.RenderKeys()[1]
+	if m.shown != (display{clock: "09:41"}).key() {
+		t.Errorf("shown = %q, want the injected time", m.shown)
+	}
+
+	now = now.Add(30 * time.Second)
+	if got := m.RenderKeys()[1]; got != first {
+		t.Error("key redrawn within the same minute")
+	}
+
+	now = now.Add(30 * time.Second)
+	if got := m.RenderKeys()[1]; got == first {
+		t.Error("key not redrawn when the minute changed")
+	}
+	if m.shown != (display{clock: "09:42"}).key() {
+		t.Errorf("shown = %q after a minute, want 09:42", m.shown)
+	}
+}
@@ -0,0 +1,100 @@
+package clock
+
+import (
+	_ "embed"
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+// Common colors
+var (
+	colorKeyBg = color.RGBA{40, 40, 40, 255}
+	colorWhite = color.RGBA{255, 255, 255, 255}
+	colorGray  = color.RGBA{150, 150, 150, 255}
+)
+
+const keySize = 72
+
+// timeSizes are the font sizes tried for the time, largest first; the
+// largest that fits the key with its seconds and AM/PM is used.
+var timeSizes = []float64{26, 22, 18}
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("clock bold", render.BoldFont, fontBold)
+	m.timeFaces = nil
+	for _, size := range timeSizes {
+		m.timeFaces = append(m.timeFaces, render.NewFace(ttBold, size))
+	}
+	m.smallFace = render.NewFace(ttBold, 12)
+	m.dateFace = render.NewFace(ttBold, 11)
+}
+
+// renderClock renders the clock key: the time, with any seconds and AM/PM
+// smaller beside it, and the date underneath. AM/PM moves under the time
+// when it doesn't fit beside it.
+func (m *Module) renderClock(d display) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	// Pick the largest face that fits the time with its suffix
+	suffix := d.seconds
+	if d.period != "" {
+		suffix += " " + d.period
+	}
+	face, fits := m.fitTime(c, d.clock, suffix)
+	var below []string
+	if !fits && d.period != "" {
+		suffix = d.seconds
+		below = append(below, d.period)
+		face, _ = m.fitTime(c, d.clock, suffix)
+	}
+	if d.date != "" {
+		below = append(below, d.date)
+	}
+
+	clockWidth := c.MeasureString(face, d.clock)
+	suffixWidth := 0
+	if suffix != "" {
+		suffixWidth = c.MeasureString(m.smallFace, suffix)
+	}
+
+	// Sit the time in the middle, or higher to make room for the lines
+	// below it
+	y := keySize/2 + 9 - len(below)*7
+
+	x := (keySize - clockWidth - suffixWidth) / 2
+	c.DrawString(d.clock, x, y, face, colorWhite)
+	if suffix != "" {
+		c.DrawString(suffix, x+clockWidth, y, m.smallFace, colorGray)
+	}
+
+	for i, line := range below {
+		c.DrawStringCentered(line, keySize/2, y+(i+1)*15, m.dateFace, colorGray)
+	}
+
+	return c.Image()
+}
+
+// fitTime returns the largest time face that fits clock and suffix across
+// the key, or the smallest face and false if none does.
+func (m *Module) fitTime(c *render.Canvas, clock, suffix string) (font.Face, bool) {
+	suffixWidth := 0
+	if suffix != "" {
+		suffixWidth = c.MeasureString(m.smallFace, suffix)
+	}
+	for _, face := range m.timeFaces {
+		if c.MeasureString(face, clock)+suffixWidth <= keySize-8 {
+			return face, true
+		}
+	}
+	return m.timeFaces[len(m.timeFaces)-1], false
+}
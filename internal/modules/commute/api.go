@@ -0,0 +1,103 @@
+package commute
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// distanceMatrixURL is the Google Maps Distance Matrix endpoint. Asked for
+// a departure time of now, it reports the drive time in current traffic.
+const distanceMatrixURL = "https://maps.googleapis.com/maps/api/distancematrix/json"
+
+// Trip is the drive from origin to destination as of a fetch.
+type Trip struct {
+	// Typical is the drive time without traffic.
+	Typical time.Duration
+
+	// Current is the drive time in current traffic.
+	Current time.Duration
+
+	// Meters is the length of the route.
+	Meters int
+}
+
+// distanceMatrixResponse is the part of the Distance Matrix response that's
+// used.
+type distanceMatrixResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Rows         []struct {
+		Elements []struct {
+			Status   string `json:"status"`
+			Duration struct {
+				Value int `json:"value"` // seconds
+			} `json:"duration"`
+			DurationInTraffic struct {
+				Value int `json:"value"` // seconds
+			} `json:"duration_in_traffic"`
+			Distance struct {
+				Value int `json:"value"` // meters
+			} `json:"distance"`
+		} `json:"elements"`
+	} `json:"rows"`
+}
+
+// fetchTrip fetches the drive from origin to destination, leaving now, from
+// the Distance Matrix API at baseURL.
+func fetchTrip(ctx context.Context, client *http.Client, baseURL, apiKey, origin, destination string) (Trip, error) {
+	params := url.Values{}
+	params.Set("origins", origin)
+	params.Set("destinations", destination)
+	params.Set("mode", "driving")
+	params.Set("departure_time", "now")
+	params.Set("key", apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return Trip{}, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Trip{}, fmt.Errorf("fetch drive time: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Trip{}, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	var data distanceMatrixResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Trip{}, fmt.Errorf("decode response: %w", err)
+	}
+	if data.Status != "OK" {
+		if data.ErrorMessage != "" {
+			return Trip{}, fmt.Errorf("API error: %s: %s", data.Status, data.ErrorMessage)
+		}
+		return Trip{}, fmt.Errorf("API error: %s", data.Status)
+	}
+	if len(data.Rows) == 0 || len(data.Rows[0].Elements) == 0 {
+		return Trip{}, fmt.Errorf("no route in response")
+	}
+
+	el := data.Rows[0].Elements[0]
+	if el.Status != "OK" {
+		return Trip{}, fmt.Errorf("no route from %q to %q: %s", origin, destination, el.Status)
+	}
+
+	trip := Trip{
+		Typical: time.Duration(el.Duration.Value) * time.Second,
+		Current: time.Duration(el.DurationInTraffic.Value) * time.Second,
+		Meters:  el.Distance.Value,
+	}
+	// Traffic isn't known for every route; fall back to the typical time
+	if trip.Current == 0 {
+		trip.Current = trip.Typical
+	}
+	return trip, nil
+}
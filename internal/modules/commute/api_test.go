@@ -0,0 +1,95 @@
+package commute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stubResponse is a Distance Matrix response for a 20 minute drive that
+// takes 29 minutes in traffic.
+const stubResponse = `{
+  "status": "OK",
+  "rows": [{"elements": [{
+    "status": "OK",
+    "duration": {"value": 1200, "text": "20 mins"},
+    "duration_in_traffic": {"value": 1740, "text": "29 mins"},
+    "distance": {"value": 16093, "text": "10.0 mi"}
+  }]}]
+}`
+
+func TestFetchTripFromStub(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("origins") != "Home" || q.Get("destinations") != "Work" || q.Get("key") != "k" || q.Get("departure_time") != "now" {
+			t.Errorf("unexpected query %v", q)
+		}
+		fmt.Fprint(w, stubResponse)
+	}))
+	defer srv.Close()
+
+	trip, err := fetchTrip(context.Background(), srv.Client(), srv.URL, "k", "Home", "Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trip.Typical != 20*time.Minute || trip.Current != 29*time.Minute || trip.Meters != 16093 {
+		t.Errorf("trip = %+v", trip)
+	}
+	if got := formatDuration(trip.Current); got != "29 min" {
+		t.Errorf("formatted drive time = %q, want %q", got, "29 min")
+	}
+	if got := congestionOf(trip); got != Heavy {
+		t.Errorf("congestion = %v, want Heavy", got)
+	}
+}
+
+func TestFetchTripErrors(t *testing.T) {
+	for name, body := range map[string]string{
+		"request denied": `{"status": "REQUEST_DENIED", "error_message": "bad key"}`,
+		"no route":       `{"status": "OK", "rows": [{"elements": [{"status": "ZERO_RESULTS"}]}]}`,
+		"empty":          `{"status": "OK", "rows": []}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, body)
+			}))
+			defer srv.Close()
+
+			if _, err := fetchTrip(context.Background(), srv.Client(), srv.URL, "k", "Home", "Work"); err == nil {
+				t.Error("fetchTrip succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestFailedFetchKeepsLastTrip(t *testing.T) {
+	ok := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ok {
+			http.Error(w, "nope", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, stubResponse)
+	}))
+	defer srv.Close()
+
+	m := New(nil)
+	m.httpClient = srv.Client()
+	m.baseURL = srv.URL
+	m.config = Config{APIKey: "k", Origin: "Home", Destination: "Work"}
+
+	m.fetchTrip(context.Background())
+	ok = false
+	m.fetchTrip(context.Background())
+
+	trip, loaded, failed := m.state()
+	if !loaded || !failed {
+		t.Fatalf("loaded = %v, failed = %v, want both", loaded, failed)
+	}
+	if trip.Current != 29*time.Minute {
+		t.Errorf("kept trip = %+v, want the last one fetched", trip)
+	}
+}
@@ -0,0 +1,235 @@
+// Package commute provides a Stream Deck module showing the current drive
+// time between two places on a key, colored by how congested the route is.
+package commute
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/httpclient"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// defaultInterval is how often the drive time is fetched by default.
+	// The API bills per request, so it's polled sparingly.
+	defaultInterval = 10 * time.Minute
+
+	// refreshCooldown is the minimum time between refreshes from the key.
+	refreshCooldown = 30 * time.Second
+)
+
+// Config holds the commute module configuration.
+type Config struct {
+	// APIKey is a Google Maps API key with the Distance Matrix API enabled.
+	APIKey string
+
+	// Origin and Destination are addresses, place names or "lat,lon".
+	Origin      string
+	Destination string
+
+	// Label names the trip on the key, e.g. "Work". Empty shows none.
+	Label string
+
+	// Interval is how often the drive time is fetched.
+	Interval time.Duration
+}
+
+// Module implements the commute module.
+type Module struct {
+	module.BaseModule
+
+	device     device.Device
+	config     Config
+	httpClient *http.Client
+	baseURL    string
+
+	// State: the last trip fetched, and whether the latest fetch failed so
+	// it's shown dimmed
+	mu     sync.RWMutex
+	trip   Trip
+	loaded bool
+	failed bool
+
+	refresher module.Refresher
+
+	// Fonts
+	timeFace  font.Face
+	labelFace font.Face
+
+	// Cancel function for polling
+	pollCancel context.CancelFunc
+}
+
+// New creates a new commute module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("commute"),
+		device:     dev,
+		httpClient: httpclient.NewRetrying(),
+		baseURL:    distanceMatrixURL,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "commute"
+}
+
+// Configured reports whether the API key and both ends of the trip are set.
+func (m *Module) Configured() bool {
+	_, err := loadConfig()
+	return err == nil
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	m.config = config
+	m.refresher = module.Refresher{Fetch: m.fetchTrip, Cooldown: refreshCooldown}
+
+	// The drive time only shows on a key; without one there's nothing to
+	// fetch for
+	if !res.HasKeys() {
+		log.Println("Commute module has no key, not polling")
+		return nil
+	}
+
+	m.initFonts()
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	m.pollCancel = cancel
+	module.Supervise(pollCtx, "commute poll", m.pollTrip)
+
+	log.Printf("Commute module initialized (%s to %s)", m.config.Origin, m.config.Destination)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.pollCancel != nil {
+		m.pollCancel()
+	}
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+func loadConfig() (Config, error) {
+	config := Config{
+		APIKey:      os.Getenv("COMMUTE_API_KEY"),
+		Origin:      os.Getenv("COMMUTE_ORIGIN"),
+		Destination: os.Getenv("COMMUTE_DESTINATION"),
+		Label:       os.Getenv("COMMUTE_LABEL"),
+		Interval:    defaultInterval,
+	}
+	if config.APIKey == "" {
+		return Config{}, fmt.Errorf("COMMUTE_API_KEY environment variable not set")
+	}
+	if config.Origin == "" || config.Destination == "" {
+		return Config{}, fmt.Errorf("COMMUTE_ORIGIN and COMMUTE_DESTINATION environment variables must be set")
+	}
+
+	if v := os.Getenv("COMMUTE_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			log.Printf("Invalid COMMUTE_INTERVAL %q, using default %v", v, defaultInterval)
+		} else {
+			config.Interval = interval
+		}
+	}
+
+	return config, nil
+}
+
+// pollTrip fetches the drive time periodically.
+func (m *Module) pollTrip(ctx context.Context) {
+	// Fetch immediately on start
+	m.fetchTrip(ctx)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Skip fetches while snoozed
+			if m.IsSnoozed() {
+				continue
+			}
+			m.fetchTrip(ctx)
+		}
+	}
+}
+
+// fetchTrip fetches the drive time. If it fails, the last trip is kept,
+// marked as failed.
+func (m *Module) fetchTrip(ctx context.Context) {
+	trip, err := fetchTrip(ctx, m.httpClient, m.baseURL, m.config.APIKey, m.config.Origin, m.config.Destination)
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		if !m.failed {
+			log.Printf("Commute fetch error: %v", err)
+		}
+		m.failed = true
+		return
+	}
+	m.trip = trip
+	m.loaded = true
+	m.failed = false
+	log.Printf("Commute updated: %s (%s traffic)", formatDuration(trip.Current), congestionOf(trip))
+}
+
+// state returns the last trip fetched, whether there is one, and whether
+// the latest fetch failed.
+func (m *Module) state() (trip Trip, loaded, failed bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.trip, m.loaded, m.failed
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	keys := m.Resources().Keys
+	if len(keys) == 0 {
+		return nil
+	}
+	trip, loaded, failed := m.state()
+	return map[module.KeyID]image.Image{
+		keys[0]: m.renderTripKey(trip, loaded, failed),
+	}
+}
+
+// Refresh fetches the drive time now, unless it was just fetched.
+func (m *Module) Refresh() {
+	m.refresher.Trigger(m.Context())
+}
+
+// HandleKey refreshes the drive time on a press.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		m.Refresh()
+	}
+	return nil
+}
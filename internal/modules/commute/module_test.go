@@ -0,0 +1,45 @@
+package commute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSnoozedModuleStopsPollingThenResumes(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		fmt.Fprint(w, stubResponse)
+	}))
+	defer srv.Close()
+
+	m := New(nil)
+	m.httpClient = srv.Client()
+	m.baseURL = srv.URL
+	m.config = Config{APIKey: "k", Origin: "Home", Destination: "Work", Interval: 10 * time.Millisecond}
+	m.Snooze(time.Now().Add(200 * time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.pollTrip(ctx)
+
+	// Only the fetch on start happens while snoozed
+	time.Sleep(100 * time.Millisecond)
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("%d fetches while snoozed, want 1 (on start)", got)
+	}
+
+	// Polling picks back up once the snooze ends
+	deadline := time.Now().Add(2 * time.Second)
+	for fetches.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d fetches after the snooze ended", fetches.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,168 @@
+package commute
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+//go:embed icons/car.svg
+var iconCarSVG string
+
+// Colors
+var (
+	colorKeyBg    = color.RGBA{40, 40, 40, 255}
+	colorWhite    = color.RGBA{255, 255, 255, 255}
+	colorGray     = color.RGBA{150, 150, 150, 255}
+	colorLight    = color.RGBA{46, 204, 113, 255}
+	colorModerate = color.RGBA{243, 156, 18, 255}
+	colorHeavy    = color.RGBA{231, 76, 60, 255}
+)
+
+const keySize = 72
+
+// Congestion is how much traffic slows a trip down.
+type Congestion int
+
+const (
+	Light Congestion = iota
+	Moderate
+	Heavy
+)
+
+// Delay ratios, drive time in traffic over typical drive time, from which
+// a trip counts as moderately and heavily congested.
+const (
+	moderateRatio = 1.15
+	heavyRatio    = 1.4
+)
+
+// String returns the congestion as shown on the key.
+func (c Congestion) String() string {
+	switch c {
+	case Moderate:
+		return "Moderate"
+	case Heavy:
+		return "Heavy"
+	default:
+		return "Light"
+	}
+}
+
+// Color returns the color the key shows the congestion in.
+func (c Congestion) Color() color.RGBA {
+	switch c {
+	case Moderate:
+		return colorModerate
+	case Heavy:
+		return colorHeavy
+	default:
+		return colorLight
+	}
+}
+
+// congestionOf rates a trip by how much longer it takes in traffic than
+// typically. A trip without a typical time counts as light.
+func congestionOf(t Trip) Congestion {
+	if t.Typical <= 0 {
+		return Light
+	}
+	ratio := float64(t.Current) / float64(t.Typical)
+	switch {
+	case ratio >= heavyRatio:
+		return Heavy
+	case ratio >= moderateRatio:
+		return Moderate
+	default:
+		return Light
+	}
+}
+
+// formatDuration formats a drive time to the minute, e.g. "24 min" or
+// "1h 05".
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%dh %02d", minutes/60, minutes%60)
+}
+
+// dim returns col at half brightness, for last-known values.
+func dim(col color.RGBA) color.RGBA {
+	return color.RGBA{col.R / 2, col.G / 2, col.B / 2, col.A}
+}
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("commute bold", render.BoldFont, fontBold)
+	m.timeFace = render.NewFace(ttBold, 18)
+	m.labelFace = render.NewFace(ttBold, 11)
+}
+
+// renderTripKey renders the drive time key: a car in the congestion's
+// color, the drive time, and the label or congestion underneath. Before the
+// first fetch it shows the car in gray; after a failed fetch it shows the
+// last trip dimmed.
+func (m *Module) renderTripKey(trip Trip, loaded, failed bool) image.Image {
+	c := render.NewKeyCanvas(keySize)
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconColor, textColor, labelColor := colorGray, colorWhite, colorGray
+	timeText, labelText := "--", m.config.Label
+	if loaded {
+		congestion := congestionOf(trip)
+		iconColor = congestion.Color()
+		timeText = formatDuration(trip.Current)
+		if labelText == "" {
+			labelText = congestion.String()
+		}
+	}
+	if failed {
+		iconColor, textColor, labelColor = dim(iconColor), dim(textColor), dim(labelColor)
+	}
+
+	iconImg := renderSVGIcon(iconCarSVG, c.Px(28), iconColor)
+	iconX := (keySize - 28) / 2
+	c.DrawImage(image.Rect(iconX, 6, iconX+28, 34), iconImg)
+
+	c.DrawStringCentered(timeText, keySize/2, 52, m.timeFace, textColor)
+	if labelText != "" {
+		c.DrawStringCentered(labelText, keySize/2, 66, m.labelFace, labelColor)
+	}
+
+	return c.Image()
+}
+
+// renderSVGIcon renders an SVG string to an image with the given size and color.
+func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
+	r, g, b, _ := iconColor.RGBA()
+	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+
+	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
+	if err != nil {
+		log.Printf("Failed to parse SVG: %v", err)
+		return image.NewRGBA(image.Rect(0, 0, size, size))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.SetTarget(0, 0, float64(size), float64(size))
+
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	raster := rasterx.NewDasher(size, size, scanner)
+	icon.Draw(raster, 1.0)
+
+	return img
+}
@@ -0,0 +1,56 @@
+package commute
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 min"},
+		{29 * time.Second, "0 min"},
+		{30 * time.Second, "1 min"},
+		{24*time.Minute + 10*time.Second, "24 min"},
+		{59*time.Minute + 29*time.Second, "59 min"},
+		{59*time.Minute + 30*time.Second, "1h 00"},
+		{65 * time.Minute, "1h 05"},
+		{2*time.Hour + 40*time.Minute, "2h 40"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestCongestionThresholds(t *testing.T) {
+	typical := 20 * time.Minute
+	tests := []struct {
+		current time.Duration
+		want    Congestion
+	}{
+		{18 * time.Minute, Light},
+		{20 * time.Minute, Light},
+		{22 * time.Minute, Light},
+		{23 * time.Minute, Moderate}, // 1.15
+		{27 * time.Minute, Moderate},
+		{28 * time.Minute, Heavy}, // 1.4
+		{45 * time.Minute, Heavy},
+	}
+	for _, tt := range tests {
+		got := congestionOf(Trip{Typical: typical, Current: tt.current})
+		if got != tt.want {
+			t.Errorf("congestionOf(%v of typical %v) = %v, want %v", tt.current, typical, got, tt.want)
+		}
+	}
+
+	if got := congestionOf(Trip{Current: 10 * time.Minute}); got != Light {
+		t.Errorf("congestionOf without a typical time = %v, want Light", got)
+	}
+	if Light.Color() == Moderate.Color() || Moderate.Color() == Heavy.Color() {
+		t.Error("congestion levels share a color")
+	}
+}
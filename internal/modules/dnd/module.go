@@ -0,0 +1,255 @@
+// Package dnd provides a Stream Deck module for toggling macOS Do Not Disturb.
+package dnd
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+	"golang.org/x/image/font"
+)
+
+// Config holds the Do Not Disturb module configuration.
+type Config struct {
+	// OnCommand and OffCommand are shell commands that enable and disable
+	// Do Not Disturb (e.g. `shortcuts run "DND On"`).
+	OnCommand  string
+	OffCommand string
+
+	// StatusCommand is an optional shell command whose output reports the
+	// current state ("on", "1", "true" or "yes" mean enabled). When unset,
+	// the module tracks the state it last set.
+	StatusCommand string
+}
+
+// Module implements the Do Not Disturb toggle module.
+type Module struct {
+	module.BaseModule
+
+	device  device.Device
+	config  Config
+	runner  runner.Runner
+	enabled bool
+
+	// State
+	mu   sync.RWMutex
+	on   bool
+	away bus.Away
+
+	// Fonts
+	labelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new Do Not Disturb module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("dnd"),
+		device:     dev,
+		runner:     runner.Default,
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "dnd"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	// Load config from environment (optional - module disabled if not configured)
+	config, err := loadConfig()
+	if err != nil {
+		log.Printf("DND module disabled: %v", err)
+		m.enabled = false
+		return nil
+	}
+	m.config = config
+	m.enabled = true
+
+	// Initialize fonts
+	m.initFonts()
+
+	// Show the away reason, e.g. a meeting title, on the key
+	if b := m.Bus(); b != nil {
+		b.SubscribeContext(m.Context(), bus.TopicAway, func(e bus.Event) {
+			if away, ok := e.Data.(bus.Away); ok {
+				m.mu.Lock()
+				m.away = away
+				m.mu.Unlock()
+			}
+		})
+	}
+
+	// Keep the deck in sync with the system state
+	if m.config.StatusCommand != "" {
+		module.Supervise(ctx, "dnd poll", m.pollState)
+	}
+
+	log.Println("DND module initialized")
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+func loadConfig() (Config, error) {
+	onCmd := os.Getenv("DND_ON_COMMAND")
+	offCmd := os.Getenv("DND_OFF_COMMAND")
+	if onCmd == "" || offCmd == "" {
+		return Config{}, fmt.Errorf("DND_ON_COMMAND and DND_OFF_COMMAND environment variables must be set")
+	}
+
+	return Config{
+		OnCommand:     onCmd,
+		OffCommand:    offCmd,
+		StatusCommand: os.Getenv("DND_STATUS_COMMAND"),
+	}, nil
+}
+
+// pollState periodically reads the system Do Not Disturb state.
+func (m *Module) pollState(ctx context.Context) {
+	m.fetchState(ctx)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.fetchState(ctx)
+		}
+	}
+}
+
+// fetchState runs the status command and updates the tracked state.
+func (m *Module) fetchState(ctx context.Context) {
+	out, err := runner.ShellOutput(ctx, m.runner, m.config.StatusCommand)
+	if err != nil {
+		log.Printf("Failed to read DND state: %v", err)
+		return
+	}
+	m.setState(parseState(string(out)))
+}
+
+// parseState interprets status command output as on/off.
+func parseState(out string) bool {
+	switch strings.ToLower(strings.TrimSpace(out)) {
+	case "on", "1", "true", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// setState updates the tracked state, publishing a bus event on change.
+func (m *Module) setState(on bool) {
+	m.mu.Lock()
+	changed := m.on != on
+	m.on = on
+	m.mu.Unlock()
+
+	if changed {
+		log.Printf("DND is now %s", onOff(on))
+		if b := m.Bus(); b != nil {
+			b.Publish(bus.TopicDND, on)
+		}
+	}
+}
+
+// getAway returns the last away state published on the bus.
+func (m *Module) getAway() bus.Away {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.away
+}
+
+// isOn returns the current tracked state.
+func (m *Module) isOn() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.on
+}
+
+// toggle runs the on or off command, depending on the current state.
+func (m *Module) toggle() error {
+	target := !m.isOn()
+	command := m.config.OffCommand
+	if target {
+		command = m.config.OnCommand
+	}
+
+	log.Printf("Turning DND %s...", onOff(target))
+	if err := runner.Shell(m.Context(), m.runner, command); err != nil {
+		log.Printf("Failed to turn DND %s: %v", onOff(target), err)
+		return err
+	}
+
+	m.setState(target)
+	return nil
+}
+
+// onOff formats a boolean state for logs.
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled || len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderToggleButton(m.isOn(), m.getAway()),
+	}
+}
+
+// HandleAction runs an external action: "toggle" toggles Do Not Disturb.
+func (m *Module) HandleAction(action string) error {
+	if action != "toggle" {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	if !m.enabled {
+		return fmt.Errorf("not configured")
+	}
+	return m.toggle()
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	if len(m.resources.Keys) > 0 && id == m.resources.Keys[0] {
+		return m.toggle()
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package dnd
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// newTestModule returns an enabled module with on and off commands,
+// running them with the returned runner.
+func newTestModule(t *testing.T) (*Module, *runner.Fake) {
+	t.Helper()
+	fake := &runner.Fake{}
+
+	m := New(device.NewFake())
+	m.SetBus(bus.New())
+	if err := m.BaseModule.Init(context.Background(), module.Resources{Keys: []module.KeyID{1}}); err != nil {
+		t.Fatal(err)
+	}
+	m.resources = module.Resources{Keys: []module.KeyID{1}}
+	m.config = Config{OnCommand: "dnd-on", OffCommand: "dnd-off"}
+	m.enabled = true
+	m.runner = fake
+	m.initFonts()
+	return m, fake
+}
+
+// countColor returns how many of img's pixels are exactly col.
+func countColor(img image.Image, col color.RGBA) int {
+	n := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == col {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestRenderStates(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	off := m.RenderKeys()[1]
+	if off == nil {
+		t.Fatal("no key rendered")
+	}
+	if countColor(off, colorPurple) != 0 || countColor(off, colorDimGray) == 0 {
+		t.Error("off key doesn't show a gray moon")
+	}
+
+	m.setState(true)
+	on := m.RenderKeys()[1]
+	if countColor(on, colorPurple) == 0 || countColor(on, colorDimGray) != 0 {
+		t.Error("on key doesn't show a purple moon")
+	}
+
+	// The away reason replaces the label, not the icon
+	m.mu.Lock()
+	m.away = bus.Away{On: true, Reason: "Standup"}
+	m.mu.Unlock()
+	away := m.RenderKeys()[1]
+	if countColor(away, colorPurple) != countColor(on, colorPurple) {
+		t.Error("away reason changed the icon")
+	}
+	if countColor(away, colorWhite) == countColor(on, colorWhite) {
+		t.Error("away key shows the same label as the on key")
+	}
+}
+
+func TestRenderKeysDisabled(t *testing.T) {
+	m, _ := newTestModule(t)
+	m.enabled = false
+	if keys := m.RenderKeys(); keys != nil {
+		t.Errorf("disabled module rendered %d keys, want none", len(keys))
+	}
+}
+
+func TestToggleRunsConfiguredCommand(t *testing.T) {
+	m, fake := newTestModule(t)
+	var published []bool
+	m.Bus().Subscribe(bus.TopicDND, func(e bus.Event) {
+		published = append(published, e.Data.(bool))
+	})
+
+	for range 2 {
+		if err := m.HandleKey(1, module.KeyEvent{Pressed: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.HandleKey(1, module.KeyEvent{Pressed: false}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"sh -c dnd-on", "sh -c dnd-off"}
+	if got := fake.Commands(); !slices.Equal(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+	if !slices.Equal(published, []bool{true, false}) {
+		t.Errorf("published %v, want [true false]", published)
+	}
+}
+
+func TestToggleFailureKeepsState(t *testing.T) {
+	m, fake := newTestModule(t)
+	fake.Err = errors.New("exit status 1")
+
+	if err := m.HandleAction("toggle"); err == nil {
+		t.Error("failed toggle returned no error")
+	}
+	if m.isOn() {
+		t.Error("DND on after the on command failed")
+	}
+}
+
+func TestFetchStateFromStatusCommand(t *testing.T) {
+	m, fake := newTestModule(t)
+	m.config.StatusCommand = "dnd-status"
+
+	tests := []struct {
+		out  string
+		want bool
+	}{
+		{"On\n", true},
+		{"1", true},
+		{"off", false},
+		{"yes", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		fake.Outputs = map[string]string{"sh -c dnd-status": tt.out}
+		m.fetchState(context.Background())
+		if got := m.isOn(); got != tt.want {
+			t.Errorf("state after status %q = %v, want %v", tt.out, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package dnd
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/font"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+//go:embed icons/moon.svg
+var iconMoonSVG string
+
+// Common colors
+var (
+	colorKeyBg   = color.RGBA{40, 40, 40, 255}
+	colorWhite   = color.RGBA{255, 255, 255, 255}
+	colorPurple  = color.RGBA{138, 110, 255, 255} // Focus purple
+	colorDimGray = color.RGBA{80, 80, 80, 255}
+)
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("dnd bold", render.BoldFont, fontBold)
+	m.labelFace = render.NewFace(ttBold, 11)
+}
+
+// renderToggleButton renders the DND toggle button for the given state.
+// While away, the label shows why (e.g. a meeting title) instead.
+func (m *Module) renderToggleButton(on bool, away bus.Away) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconColor := colorDimGray
+	labelText := "DND Off"
+	if on {
+		iconColor = colorPurple
+		labelText = "DND On"
+	}
+	if away.On {
+		labelText = fitLabel(c, m.labelFace, away.Reason, keySize-8)
+	}
+
+	// Draw icon in upper portion
+	iconImg := renderSVGIcon(iconMoonSVG, c.Px(40), iconColor)
+	iconX := (keySize - 40) / 2
+	iconY := 8
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
+
+	// Draw label at bottom
+	c.DrawStringCentered(labelText, keySize/2, 62, m.labelFace, colorWhite)
+
+	return c.Image()
+}
+
+// fitLabel shortens text with an ellipsis until it fits within maxWidth.
+func fitLabel(c *render.Canvas, face font.Face, text string, maxWidth int) string {
+	if c.MeasureString(face, text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for i := len(runes) - 1; i > 0; i-- {
+		if s := string(runes[:i]) + "..."; c.MeasureString(face, s) <= maxWidth {
+			return s
+		}
+	}
+	return "..."
+}
+
+// renderSVGIcon renders an SVG string to an image with the given size and color.
+func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
+	r, g, b, _ := iconColor.RGBA()
+	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+
+	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
+	if err != nil {
+		log.Printf("Failed to parse SVG: %v", err)
+		return image.NewRGBA(image.Rect(0, 0, size, size))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.SetTarget(0, 0, float64(size), float64(size))
+
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	raster := rasterx.NewDasher(size, size, scanner)
+	icon.Draw(raster, 1.0)
+
+	return img
+}
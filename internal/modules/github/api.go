@@ -8,7 +8,8 @@ import (
 	"net/url"
 	"os/exec"
 	"strings"
-	"time"
+
+	"github.com/phinze/belowdeck/internal/httpclient"
 )
 
 // PRStats holds counts of PRs in different states (for authored PRs).
@@ -22,6 +23,15 @@ type PRStats struct {
 // ReviewStats holds the count of PRs awaiting my review.
 type ReviewStats struct {
 	Total int
+
+	// Team is how many of Total were requested only through a team I'm on,
+	// rather than from me directly.
+	Team int
+}
+
+// Direct returns how many PRs request my review directly.
+func (s ReviewStats) Direct() int {
+	return s.Total - s.Team
 }
 
 // PRStatus represents the review status of a PR.
@@ -44,24 +54,57 @@ const (
 
 // PRInfo holds information about a single PR.
 type PRInfo struct {
-	Title    string
-	Repo     string
-	Number   int
-	Status   PRStatus
-	CI       CIStatus
-	URL      string
-	HeadSHA  string // For fetching CI status
+	Title   string
+	Repo    string
+	Number  int
+	Status  PRStatus
+	CI      CIStatus
+	URL     string
+	HeadSHA string // For fetching CI status
+
+	MergeState MergeState
+
+	// Labels are the PR's labels, in the order GitHub lists them.
+	Labels []Label
+
+	// TeamRequest is set for review-requested PRs that ask for my review
+	// only through a team I'm on.
+	TeamRequest bool
+
+	// Pinned is set for PRs listed in GITHUB_PINNED, which are kept at the
+	// front of the lists.
+	Pinned bool
+}
+
+// Landing reports whether the PR is queued or set to auto-merge, so it needs
+// no further attention.
+func (pr PRInfo) Landing() bool {
+	return pr.MergeState != MergeStateNone
 }
 
+// DefaultBaseURL is the public GitHub REST API endpoint.
+const DefaultBaseURL = "https://api.github.com"
+
 // Client is a GitHub API client.
 type Client struct {
+	baseURL    string
 	token      string
 	httpClient *http.Client
 	username   string // cached username
+
+	// Responses kept for conditional requests
+	cache responseCache
+
+	// maxConcurrent bounds per-PR fan-outs; requests holds a slot for each
+	// request in flight
+	maxConcurrent int
+	requests      chan struct{}
 }
 
 // NewClient creates a new GitHub API client using the gh CLI token.
-func NewClient() (*Client, error) {
+// If baseURL is empty, DefaultBaseURL is used. If httpClient is nil, a client
+// using the shared transport is created.
+func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	// Get token from gh CLI
 	cmd := exec.Command("gh", "auth", "token")
 	output, err := cmd.Output()
@@ -74,12 +117,25 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("gh auth token is empty")
 	}
 
-	return &Client{
-		token: token,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}, nil
+	return newClient(baseURL, token, httpClient), nil
+}
+
+// newClient creates a client with an explicit token.
+func newClient(baseURL, token string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = httpclient.New()
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+	c.setMaxConcurrent(defaultMaxConcurrent)
+	return c
 }
 
 // GetMyPRStats fetches stats about the authenticated user's PRs.
@@ -146,7 +202,7 @@ func (c *Client) getAuthenticatedUser(ctx context.Context) (string, error) {
 		return c.username, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/user", nil)
 	if err != nil {
 		return "", err
 	}
@@ -178,30 +234,17 @@ func (c *Client) getAuthenticatedUser(ctx context.Context) (string, error) {
 
 // searchPRCount searches for PRs matching a query and returns the count.
 func (c *Client) searchPRCount(ctx context.Context, query string) (int, error) {
-	apiURL := "https://api.github.com/search/issues?per_page=1&q=" + url.QueryEscape(query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
+	apiURL := c.baseURL + "/search/issues?per_page=1&q=" + url.QueryEscape(query)
 
-	resp, err := c.httpClient.Do(req)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("API error: %s", resp.Status)
-	}
 
 	var result struct {
 		TotalCount int `json:"total_count"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return 0, err
 	}
 
@@ -278,33 +321,17 @@ func (c *Client) GetMyPRList(ctx context.Context) ([]PRInfo, error) {
 
 	// Fetch CI status for all PRs in parallel
 	c.fetchCIStatuses(ctx, allPRs)
+	c.fetchMergeStates(ctx, allPRs)
 
 	return allPRs, nil
 }
 
-// fetchCIStatuses fetches CI status for a list of PRs in parallel.
+// fetchCIStatuses fetches CI status for a list of PRs in parallel, at most
+// maxConcurrent at once.
 func (c *Client) fetchCIStatuses(ctx context.Context, prs []PRInfo) {
-	if len(prs) == 0 {
-		return
-	}
-
-	type ciResult struct {
-		index int
-		ci    CIStatus
-	}
-	results := make(chan ciResult, len(prs))
-
-	for i, pr := range prs {
-		go func(idx int, pr PRInfo) {
-			ci := c.getCIStatus(ctx, pr.Repo, pr.HeadSHA)
-			results <- ciResult{idx, ci}
-		}(i, pr)
-	}
-
-	for range len(prs) {
-		r := <-results
-		prs[r.index].CI = r.ci
-	}
+	forEach(len(prs), c.maxConcurrent, func(i int) {
+		prs[i].CI = c.getCIStatus(ctx, prs[i].Repo, prs[i].HeadSHA)
+	})
 }
 
 // getCIStatus fetches the combined CI status for a commit.
@@ -314,30 +341,17 @@ func (c *Client) getCIStatus(ctx context.Context, repo, sha string) CIStatus {
 	}
 
 	// Use the combined status endpoint
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s/status", repo, sha)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return CIStatusPending
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
+	apiURL := fmt.Sprintf("%s/repos/%s/commits/%s/status", c.baseURL, repo, sha)
 
-	resp, err := c.httpClient.Do(req)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
 		return CIStatusPending
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return CIStatusPending
-	}
 
 	var status struct {
 		State string `json:"state"` // success, failure, pending, error
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+	if err := json.Unmarshal(body, &status); err != nil {
 		return CIStatusPending
 	}
 
@@ -353,35 +367,26 @@ func (c *Client) getCIStatus(ctx context.Context, repo, sha string) CIStatus {
 
 // searchPRs searches for PRs matching a query and returns details including head SHA.
 func (c *Client) searchPRs(ctx context.Context, query string, status PRStatus) ([]PRInfo, error) {
-	apiURL := "https://api.github.com/search/issues?per_page=10&q=" + url.QueryEscape(query)
+	apiURL := c.baseURL + "/search/issues?per_page=10&q=" + url.QueryEscape(query)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", resp.Status)
-	}
-
 	var searchResult struct {
 		Items []struct {
 			Title         string `json:"title"`
 			Number        int    `json:"number"`
 			HTMLURL       string `json:"html_url"`
 			RepositoryURL string `json:"repository_url"`
+			Labels        []struct {
+				Name  string `json:"name"`
+				Color string `json:"color"`
+			} `json:"labels"`
 		} `json:"items"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
+	if err := json.Unmarshal(body, &searchResult); err != nil {
 		return nil, err
 	}
 
@@ -394,12 +399,18 @@ func (c *Client) searchPRs(ctx context.Context, query string, status PRStatus) (
 			repoName = repoName[idx+7:]
 		}
 
+		var labels []Label
+		for _, l := range item.Labels {
+			labels = append(labels, Label{Name: l.Name, Color: l.Color})
+		}
+
 		prs = append(prs, PRInfo{
 			Title:  item.Title,
 			Repo:   repoName,
 			Number: item.Number,
 			Status: status,
 			URL:    item.HTMLURL,
+			Labels: labels,
 		})
 	}
 
@@ -409,59 +420,29 @@ func (c *Client) searchPRs(ctx context.Context, query string, status PRStatus) (
 	return prs, nil
 }
 
-// fetchHeadSHAs fetches the head SHA for each PR in parallel.
+// fetchHeadSHAs fetches the head SHA for each PR in parallel, at most
+// maxConcurrent at once.
 func (c *Client) fetchHeadSHAs(ctx context.Context, prs []PRInfo) {
-	if len(prs) == 0 {
-		return
-	}
-
-	type shaResult struct {
-		index int
-		sha   string
-	}
-	results := make(chan shaResult, len(prs))
-
-	for i, pr := range prs {
-		go func(idx int, pr PRInfo) {
-			sha := c.getPRHeadSHA(ctx, pr.Repo, pr.Number)
-			results <- shaResult{idx, sha}
-		}(i, pr)
-	}
-
-	for range len(prs) {
-		r := <-results
-		prs[r.index].HeadSHA = r.sha
-	}
+	forEach(len(prs), c.maxConcurrent, func(i int) {
+		prs[i].HeadSHA = c.getPRHeadSHA(ctx, prs[i].Repo, prs[i].Number)
+	})
 }
 
 // getPRHeadSHA fetches the head SHA for a specific PR.
 func (c *Client) getPRHeadSHA(ctx context.Context, repo string, number int) string {
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls/%d", repo, number)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return ""
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
+	apiURL := fmt.Sprintf("%s/repos/%s/pulls/%d", c.baseURL, repo, number)
 
-	resp, err := c.httpClient.Do(req)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
 		return ""
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return ""
-	}
 
 	var pr struct {
 		Head struct {
 			SHA string `json:"sha"`
 		} `json:"head"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+	if err := json.Unmarshal(body, &pr); err != nil {
 		return ""
 	}
 
@@ -469,7 +450,9 @@ func (c *Client) getPRHeadSHA(ctx context.Context, repo string, number int) stri
 }
 
 // GetReviewRequestedStats fetches the count of PRs awaiting my review.
-func (c *Client) GetReviewRequestedStats(ctx context.Context) (ReviewStats, error) {
+// Requests made through a team I'm on are counted only if includeTeams is
+// set, and are then broken out in ReviewStats.Team.
+func (c *Client) GetReviewRequestedStats(ctx context.Context, includeTeams bool) (ReviewStats, error) {
 	var stats ReviewStats
 
 	username, err := c.getAuthenticatedUser(ctx)
@@ -477,34 +460,76 @@ func (c *Client) GetReviewRequestedStats(ctx context.Context) (ReviewStats, erro
 		return stats, fmt.Errorf("failed to get username: %w", err)
 	}
 
-	// Query: is:open is:pr review-requested:{user} archived:false
-	query := fmt.Sprintf("is:open is:pr review-requested:%s archived:false", username)
-	count, err := c.searchPRCount(ctx, query)
+	// Query: is:open is:pr user-review-requested:{user} archived:false
+	// matches only direct requests
+	direct, err := c.searchPRCount(ctx, reviewQuery("user-review-requested", username))
 	if err != nil {
 		return stats, err
 	}
+	stats.Total = direct
+	if !includeTeams {
+		return stats, nil
+	}
 
-	stats.Total = count
+	// review-requested:{user} also matches requests to my teams
+	total, err := c.searchPRCount(ctx, reviewQuery("review-requested", username))
+	if err != nil {
+		return stats, err
+	}
+	stats.Total = max(total, direct)
+	stats.Team = stats.Total - direct
 	return stats, nil
 }
 
-// GetReviewRequestedPRList fetches PRs awaiting my review with details.
-func (c *Client) GetReviewRequestedPRList(ctx context.Context) ([]PRInfo, error) {
+// GetReviewRequestedPRList fetches PRs awaiting my review with details,
+// direct requests first. With includeTeams, PRs requested only through a
+// team I'm on follow, marked as TeamRequest.
+func (c *Client) GetReviewRequestedPRList(ctx context.Context, includeTeams bool) ([]PRInfo, error) {
 	username, err := c.getAuthenticatedUser(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get username: %w", err)
 	}
 
-	// Query: is:open is:pr review-requested:{user} archived:false
-	query := fmt.Sprintf("is:open is:pr review-requested:%s archived:false", username)
-	prs, err := c.searchPRs(ctx, query, PRStatusWaiting)
+	prs, err := c.searchPRs(ctx, reviewQuery("user-review-requested", username), PRStatusWaiting)
 	if err != nil {
 		return nil, err
 	}
 
+	if includeTeams {
+		all, err := c.searchPRs(ctx, reviewQuery("review-requested", username), PRStatusWaiting)
+		if err != nil {
+			return nil, err
+		}
+		prs = withTeamRequests(prs, all)
+	}
+
 	// For review-requested PRs, the status is always "waiting" (for my review)
 	// Fetch CI statuses
 	c.fetchCIStatuses(ctx, prs)
 
 	return prs, nil
 }
+
+// reviewQuery returns the search query for open PRs awaiting username's
+// review, using qualifier "review-requested" (direct or team requests) or
+// "user-review-requested" (direct only).
+func reviewQuery(qualifier, username string) string {
+	return fmt.Sprintf("is:open is:pr %s:%s archived:false", qualifier, username)
+}
+
+// withTeamRequests returns the direct requests followed by the PRs in all
+// that aren't among them, which were requested through a team and are
+// marked as such.
+func withTeamRequests(direct, all []PRInfo) []PRInfo {
+	seen := make(map[string]bool, len(direct))
+	for _, pr := range direct {
+		seen[pr.URL] = true
+	}
+	for _, pr := range all {
+		if !seen[pr.URL] {
+			pr.TeamRequest = true
+			direct = append(direct, pr)
+		}
+	}
+	return direct
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+// newStubClient returns a client for a stub GitHub API whose authenticated
+// user is "octocat".
+func newStubClient(t *testing.T) (*Client, *stubserver.Server) {
+	t.Helper()
+	srv := stubserver.New(t)
+	srv.JSON("GET /user", map[string]string{"login": "octocat"})
+	return newClient(srv.URL, "token", nil), srv
+}
+
+func TestGetMyPRStats(t *testing.T) {
+	client, srv := newStubClient(t)
+	srv.Handle("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		count := 5
+		switch {
+		case strings.Contains(q, "review:approved"):
+			count = 2
+		case strings.Contains(q, "review:changes_requested"):
+			count = 1
+		}
+		stubserver.WriteJSON(w, map[string]int{"total_count": count})
+	})
+
+	stats, err := client.GetMyPRStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetMyPRStats: %v", err)
+	}
+	want := PRStats{Approved: 2, ChangesRequested: 1, WaitingForReview: 2}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+
+	for _, req := range srv.Requests() {
+		if got := req.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("%s Authorization = %q, want %q", req.URL.Path, got, "Bearer token")
+		}
+	}
+}
+
+// handleReviewSearch answers review searches for octocat: #2 is requested
+// directly, and #1, #2 and #3 match once team requests are included.
+func handleReviewSearch(srv *stubserver.Server) {
+	pr := func(n int) map[string]any {
+		return map[string]any{
+			"number":         n,
+			"html_url":       fmt.Sprintf("https://github.com/o/r/pull/%d", n),
+			"repository_url": "https://api.github.com/repos/o/r",
+		}
+	}
+	srv.Handle("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+		items := []map[string]any{pr(1), pr(2), pr(3)}
+		if strings.Contains(r.URL.Query().Get("q"), "user-review-requested:octocat") {
+			items = items[1:2]
+		}
+		stubserver.WriteJSON(w, map[string]any{"total_count": len(items), "items": items})
+	})
+}
+
+func TestReviewRequestedDirectBeforeTeam(t *testing.T) {
+	client, srv := newStubClient(t)
+	handleReviewSearch(srv)
+
+	prs, err := client.GetReviewRequestedPRList(context.Background(), true)
+	if err != nil {
+		t.Fatalf("GetReviewRequestedPRList: %v", err)
+	}
+	var got []string
+	for _, pr := range prs {
+		got = append(got, fmt.Sprintf("#%d team=%v", pr.Number, pr.TeamRequest))
+	}
+	want := []string{"#2 team=false", "#1 team=true", "#3 team=true"}
+	if !slices.Equal(got, want) {
+		t.Errorf("PRs = %q, want %q", got, want)
+	}
+
+	stats, err := client.GetReviewRequestedStats(context.Background(), true)
+	if err != nil {
+		t.Fatalf("GetReviewRequestedStats: %v", err)
+	}
+	if stats.Total != 3 || stats.Team != 2 || stats.Direct() != 1 {
+		t.Errorf("stats = %+v (direct %d), want 3 total, 2 team, 1 direct", stats, stats.Direct())
+	}
+}
+
+func TestReviewRequestedWithoutTeams(t *testing.T) {
+	client, srv := newStubClient(t)
+	handleReviewSearch(srv)
+
+	prs, err := client.GetReviewRequestedPRList(context.Background(), false)
+	if err != nil {
+		t.Fatalf("GetReviewRequestedPRList: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 2 || prs[0].TeamRequest {
+		t.Errorf("PRs = %+v, want only the direct request #2", prs)
+	}
+
+	stats, err := client.GetReviewRequestedStats(context.Background(), false)
+	if err != nil {
+		t.Fatalf("GetReviewRequestedStats: %v", err)
+	}
+	if want := (ReviewStats{Total: 1}); stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// defaultCIStablePolls is how many consecutive polls must agree before the
+// stats key's CI failure alert appears or clears.
+const defaultCIStablePolls = 2
+
+// parseCIStablePolls parses GITHUB_CI_STABLE_POLLS, defaulting to
+// defaultCIStablePolls. 1 shows every change as soon as it's polled.
+func parseCIStablePolls(v string) (int, error) {
+	if v == "" {
+		return defaultCIStablePolls, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return defaultCIStablePolls, fmt.Errorf("invalid GITHUB_CI_STABLE_POLLS %q (want a number of polls, 1 or more)", v)
+	}
+	return n, nil
+}
+
+// ciAlert debounces the CI failure alert on the stats key, so a re-run that
+// flips a check from failed to pending and back doesn't make the key's top
+// row flicker between the icon and the "Fail" row. The alert only appears,
+// or clears, once the polled state has differed from what's shown for
+// stablePolls polls in a row.
+type ciAlert struct {
+	stablePolls int
+
+	polled     bool // whether anything has been polled yet
+	shown      bool // whether the alert is shown
+	streak     int  // consecutive polls disagreeing with shown
+	lastFailed int  // the most recent nonzero failure count
+}
+
+// update records a poll's CI failure count and returns the count to show:
+// zero while the alert is clear, otherwise the latest failure count. The
+// first poll is shown as is.
+func (a *ciAlert) update(failed int) int {
+	failing := failed > 0
+	if failing {
+		a.lastFailed = failed
+	}
+
+	switch {
+	case !a.polled:
+		a.polled = true
+		a.shown = failing
+	case failing == a.shown:
+		a.streak = 0
+	default:
+		a.streak++
+		if a.streak >= a.stablePolls {
+			a.shown = failing
+			a.streak = 0
+		}
+	}
+	return a.count()
+}
+
+// count returns the failure count to show, without recording a poll.
+func (a *ciAlert) count() int {
+	if !a.shown {
+		return 0
+	}
+	return a.lastFailed
+}
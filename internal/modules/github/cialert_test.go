@@ -0,0 +1,49 @@
+package github
+
+import "testing"
+
+func TestCIAlertHysteresis(t *testing.T) {
+	tests := []struct {
+		name        string
+		stablePolls int
+		polls       []int
+		want        []int
+	}{
+		{"transient failure", 2, []int{0, 1, 0, 0}, []int{0, 0, 0, 0}},
+		{"sustained failure", 2, []int{0, 1, 2, 2}, []int{0, 0, 2, 2}},
+		{"transient pass", 2, []int{3, 0, 3}, []int{3, 3, 3}},
+		{"sustained pass", 2, []int{3, 0, 0}, []int{3, 3, 0}},
+		{"latest count while shown", 2, []int{1, 4, 0, 2}, []int{1, 4, 4, 2}},
+		{"no debounce", 1, []int{0, 1, 0}, []int{0, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ciAlert{stablePolls: tt.stablePolls}
+			for i, failed := range tt.polls {
+				if got := a.update(failed); got != tt.want[i] {
+					t.Errorf("poll %d (%d failed): got = %d, want %d", i+1, failed, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCIStablePolls(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", defaultCIStablePolls, false},
+		{"1", 1, false},
+		{"5", 5, false},
+		{"0", defaultCIStablePolls, true},
+		{"two", defaultCIStablePolls, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCIStablePolls(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseCIStablePolls(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
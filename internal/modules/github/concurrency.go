@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// defaultMaxConcurrent is how many API requests the client makes at once
+// by default.
+const defaultMaxConcurrent = 4
+
+// parseMaxConcurrent parses GITHUB_MAX_CONCURRENT, defaulting to
+// defaultMaxConcurrent.
+func parseMaxConcurrent(v string) (int, error) {
+	if v == "" {
+		return defaultMaxConcurrent, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return defaultMaxConcurrent, fmt.Errorf("invalid GITHUB_MAX_CONCURRENT %q (want a number of requests, 1 or more)", v)
+	}
+	return n, nil
+}
+
+// setMaxConcurrent limits the client to n API requests in flight at once,
+// and each per-PR fan-out to n goroutines. It must be called before the
+// client is used.
+func (c *Client) setMaxConcurrent(n int) {
+	c.maxConcurrent = n
+	c.requests = make(chan struct{}, n)
+}
+
+// acquire waits for a request slot, returning a func that releases it. It
+// fails if ctx is done first.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	select {
+	case c.requests <- struct{}{}:
+		return func() { <-c.requests }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// forEach calls fn with each index below n, on at most limit goroutines at
+// once, and returns once every call has. Each call writes only its own
+// index's result, so results keep their order.
+func forEach(n, limit int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(n, limit) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
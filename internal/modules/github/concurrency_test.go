@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+func TestRequestsBoundedByMaxConcurrent(t *testing.T) {
+	const limit, prs = 2, 10
+
+	client, srv := newStubClient(t)
+	client.setMaxConcurrent(limit)
+
+	var items []string
+	for n := range prs {
+		items = append(items, fmt.Sprintf(`{"number": %d, "repository_url": "https://api.github.com/repos/octo/repo"}`, n))
+	}
+	srv.Handle("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ","))
+	})
+	// Slow responses, so requests overlap if they aren't bounded
+	srv.Handle("GET /repos/octo/repo/pulls/{n}", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		stubserver.WriteJSON(w, map[string]any{"head": map[string]string{"sha": "sha" + r.PathValue("n")}})
+	})
+	srv.Handle("GET /repos/octo/repo/commits/{sha}/status", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		stubserver.WriteJSON(w, map[string]string{"state": "success"})
+	})
+
+	list, err := client.GetReviewRequestedPRList(context.Background(), false)
+	if err != nil {
+		t.Fatalf("GetReviewRequestedPRList: %v", err)
+	}
+
+	if got := srv.MaxInFlight(); got > limit {
+		t.Errorf("%d requests in flight at once, want at most %d", got, limit)
+	}
+	if len(list) != prs {
+		t.Fatalf("got %d PRs, want %d", len(list), prs)
+	}
+	for i, pr := range list {
+		want := fmt.Sprintf("sha%d", i)
+		if pr.Number != i || pr.HeadSHA != want || pr.CI != CIStatusPassed {
+			t.Errorf("PR %d = #%d at %q (%v), want #%d at %q, passing", i, pr.Number, pr.HeadSHA, pr.CI, i, want)
+		}
+	}
+}
+
+func TestParseMaxConcurrent(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", defaultMaxConcurrent, false},
+		{"8", 8, false},
+		{"0", defaultMaxConcurrent, true},
+		{"lots", defaultMaxConcurrent, true},
+	} {
+		got, err := parseMaxConcurrent(tc.in)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("parseMaxConcurrent(%q) = %d, %v; want %d, error %v", tc.in, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"image"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// overlayDuration is how long a PR overlay stays up, from when it's shown
+// or a PR on it is selected.
+const overlayDuration = 5 * time.Second
+
+// selectionFrame is the width of the frame around the selected PR's key.
+const selectionFrame = 3
+
+// openPR opens pr in the browser. With confirmOpen set, the first tap only
+// selects it, and it opens on a second tap while still selected; tapping
+// another PR moves the selection there instead.
+func (m *Module) openPR(pr PRInfo) {
+	if pr.URL == "" {
+		return
+	}
+
+	if m.confirmOpen {
+		m.mu.Lock()
+		confirmed := m.selectedURL == pr.URL
+		if confirmed {
+			m.selectedURL = ""
+		} else {
+			m.selectedURL = pr.URL
+			m.overlayExpiry = time.Now().Add(overlayDuration)
+		}
+		m.mu.Unlock()
+		if !confirmed {
+			return
+		}
+	}
+
+	m.openURL(pr.URL)
+}
+
+// isSelected reports whether pr is selected, waiting for a second tap to
+// open it.
+func (m *Module) isSelected(pr PRInfo) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return pr.URL != "" && pr.URL == m.selectedURL
+}
+
+// drawSelection frames a selected PR's key.
+func (m *Module) drawSelection(c *render.Canvas) {
+	c.Fill(image.Rect(0, 0, keySize, selectionFrame), colorWhite)
+	c.Fill(image.Rect(0, keySize-selectionFrame, keySize, keySize), colorWhite)
+	c.Fill(image.Rect(0, 0, selectionFrame, keySize), colorWhite)
+	c.Fill(image.Rect(keySize-selectionFrame, 0, keySize, keySize), colorWhite)
+}
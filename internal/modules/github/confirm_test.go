@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// newOverlayModule returns a module showing the my PRs overlay of prs,
+// opening URLs with the returned runner.
+func newOverlayModule(t *testing.T, confirm bool, prs ...PRInfo) (*Module, *runner.Fake) {
+	t.Helper()
+	fake := &runner.Fake{}
+	m := New(device.NewFake())
+	m.BaseModule.Init(context.Background(), module.Resources{})
+	m.runner = fake
+	m.enabled = true
+	m.confirmOpen = confirm
+	m.initFonts()
+	m.prList = prs
+	m.showOverlay(OverlayMyPRs)
+	return m, fake
+}
+
+// overlayKey returns the overlay key showing the i-th PR.
+func overlayKey(t *testing.T, i int) module.KeyID {
+	t.Helper()
+	key, ok := overlayGrid.Key(i)
+	if !ok {
+		t.Fatalf("no overlay key %d", i)
+	}
+	return key
+}
+
+// tap presses an overlay key.
+func tap(t *testing.T, m *Module, key module.KeyID) {
+	t.Helper()
+	if err := m.HandleOverlayKey(key, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatalf("HandleOverlayKey: %v", err)
+	}
+}
+
+// waitOpened waits for the URLs opened to be want.
+func waitOpened(t *testing.T, fake *runner.Fake, want ...string) {
+	t.Helper()
+	var commands []string
+	for _, url := range want {
+		name, args := platform.OpenURL(url)
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+	}
+	deadline := time.Now().Add(time.Second)
+	for got := fake.Commands(); !slices.Equal(got, commands); got = fake.Commands() {
+		if time.Now().After(deadline) {
+			t.Fatalf("ran %q, want %q", got, commands)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConfirmOpenSelectsThenOpens(t *testing.T) {
+	first := PRInfo{Repo: "phinze/belowdeck", Number: 1, Title: "One", URL: "https://github.com/phinze/belowdeck/pull/9761"}
+	second := PRInfo{Repo: "phinze/belowdeck", Number: 2, Title: "Two", URL: "https://github.com/phinze/belowdeck/pull/9762"}
+	m, fake := newOverlayModule(t, true, first, second)
+	key0, key1 := overlayKey(t, 0), overlayKey(t, 1)
+
+	tap(t, m, key0)
+	if !m.isSelected(first) {
+		t.Fatal("first tap didn't select the PR")
+	}
+	if got := rgbaAt(m.RenderOverlayKeys()[key0], 1, 1); got != colorWhite {
+		t.Errorf("selected key corner = %v, want the white frame", got)
+	}
+	if got := rgbaAt(m.RenderOverlayKeys()[key1], 1, 1); got == colorWhite {
+		t.Error("unselected key framed")
+	}
+
+	// A tap on another PR moves the selection without opening anything
+	tap(t, m, key1)
+	if m.isSelected(first) || !m.isSelected(second) {
+		t.Error("tap on another PR didn't move the selection")
+	}
+
+	tap(t, m, key1)
+	waitOpened(t, fake, second.URL)
+	if m.isSelected(second) {
+		t.Error("PR still selected after opening")
+	}
+}
+
+func TestOpenWithoutConfirm(t *testing.T) {
+	pr := PRInfo{Repo: "phinze/belowdeck", Number: 1, URL: "https://github.com/phinze/belowdeck/pull/9763"}
+	m, fake := newOverlayModule(t, false, pr)
+
+	tap(t, m, overlayKey(t, 0))
+	waitOpened(t, fake, pr.URL)
+	if m.isSelected(pr) {
+		t.Error("PR selected without confirmation on")
+	}
+}
+
+func TestShowOverlayClearsSelection(t *testing.T) {
+	pr := PRInfo{Repo: "phinze/belowdeck", Number: 1, URL: "https://github.com/phinze/belowdeck/pull/9764"}
+	m, fake := newOverlayModule(t, true, pr)
+
+	tap(t, m, overlayKey(t, 0))
+	m.showOverlay(OverlayMyPRs)
+	tap(t, m, overlayKey(t, 0))
+	if !m.isSelected(pr) {
+		t.Error("tap after reopening the overlay didn't just select the PR")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := fake.Commands(); len(got) != 0 {
+		t.Errorf("ran %q, want nothing opened", got)
+	}
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxCachedResponses bounds the ETag cache. Status URLs change with every
+// pushed commit, so old entries are evicted least recently used first.
+const maxCachedResponses = 256
+
+// cachedResponse is a response body kept for conditional requests.
+type cachedResponse struct {
+	etag string
+	body []byte
+	used time.Time
+}
+
+// responseCache holds the ETag and body of recent GET responses by URL.
+// GitHub answers a request carrying a matching If-None-Match with 304 Not
+// Modified, which doesn't count against the rate limit.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// lookup returns the cached response for url, if any.
+func (rc *responseCache) lookup(url string) (*cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[url]
+	if ok {
+		entry.used = time.Now()
+	}
+	return entry, ok
+}
+
+// store caches body under url with its ETag, evicting the least recently
+// used entry when full.
+func (rc *responseCache) store(url, etag string, body []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.entries == nil {
+		rc.entries = make(map[string]*cachedResponse)
+	}
+	if _, ok := rc.entries[url]; !ok && len(rc.entries) >= maxCachedResponses {
+		var oldest string
+		for u, e := range rc.entries {
+			if oldest == "" || e.used.Before(rc.entries[oldest].used) {
+				oldest = u
+			}
+		}
+		delete(rc.entries, oldest)
+	}
+	rc.entries[url] = &cachedResponse{etag: etag, body: body, used: time.Now()}
+}
+
+// get fetches apiURL and returns the response body. When an earlier
+// response is cached, the request is conditional and a 304 returns the
+// cached body.
+func (c *Client) get(ctx context.Context, apiURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	cached, ok := c.cache.lookup(apiURL)
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	// Wait for a slot, so parallel fetches don't trip GitHub's rate limits
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		return cached.body, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.cache.store(apiURL, etag, body)
+	}
+	return body, nil
+}
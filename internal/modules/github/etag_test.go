@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// quota simulates GitHub's rate limit: full responses use up a request,
+// 304 Not Modified answers to conditional requests don't.
+type quota struct {
+	mu        sync.Mutex
+	remaining int
+}
+
+func (q *quota) left() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.remaining
+}
+
+// conditional returns a handler answering with body, tagged with an ETag,
+// or 304 Not Modified to a request carrying that ETag.
+func (q *quota) conditional(body string) http.HandlerFunc {
+	const etag = `"v1"`
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		q.mu.Lock()
+		q.remaining--
+		q.mu.Unlock()
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestNotModifiedReusesCachedPRs(t *testing.T) {
+	client, srv := newStubClient(t)
+	q := &quota{remaining: 100}
+	srv.Handle("GET /search/issues", q.conditional(`{"items": [{
+		"title": "Fix the widget",
+		"number": 7,
+		"html_url": "https://github.com/octo/repo/pull/7",
+		"repository_url": "https://api.github.com/repos/octo/repo",
+		"labels": [{"name": "bug", "color": "d73a4a"}]
+	}]}`))
+	srv.Handle("GET /repos/octo/repo/pulls/7", q.conditional(`{"head": {"sha": "abc123"}}`))
+	srv.Handle("GET /repos/octo/repo/commits/abc123/status", q.conditional(`{"state": "success"}`))
+
+	first, err := client.GetMyPRList(context.Background())
+	if err != nil {
+		t.Fatalf("first GetMyPRList: %v", err)
+	}
+	if len(first) != 1 || first[0].HeadSHA != "abc123" || first[0].CI != CIStatusPassed {
+		t.Fatalf("first list = %+v, want PR 7 at abc123, passing", first)
+	}
+	spent := q.left()
+
+	second, err := client.GetMyPRList(context.Background())
+	if err != nil {
+		t.Fatalf("second GetMyPRList: %v", err)
+	}
+	if !reflect.DeepEqual(second, first) {
+		t.Errorf("list from 304s = %+v, want the cached %+v", second, first)
+	}
+	if got := q.left(); got != spent {
+		t.Errorf("quota went from %d to %d on an unchanged refresh, want 304s to cost nothing", spent, got)
+	}
+}
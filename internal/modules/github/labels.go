@@ -0,0 +1,76 @@
+package github
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+// colorLabelDefault is used for labels whose color GitHub didn't report or
+// that can't be parsed.
+var colorLabelDefault = color.RGBA{110, 110, 110, 255}
+
+// Label is a label on a PR, with its color as GitHub reports it, e.g.
+// "d73a4a".
+type Label struct {
+	Name  string
+	Color string
+}
+
+// RGBA returns the label's color, or a neutral gray if it's unknown.
+func (l Label) RGBA() color.RGBA {
+	c, err := render.ParseHexColor(l.Color)
+	if err != nil {
+		return colorLabelDefault
+	}
+	return c
+}
+
+// parseShownLabels parses GITHUB_LABELS, a comma-separated list of label
+// names to show on PRs, e.g. "blocked,needs-review". Names are matched
+// case-insensitively, as GitHub does.
+func parseShownLabels(v string) map[string]bool {
+	shown := make(map[string]bool)
+	for _, name := range strings.Split(v, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			shown[name] = true
+		}
+	}
+	return shown
+}
+
+// shownLabel returns the first of the PR's labels that is configured to be
+// shown.
+func shownLabel(pr PRInfo, shown map[string]bool) (Label, bool) {
+	for _, l := range pr.Labels {
+		if shown[strings.ToLower(l.Name)] {
+			return l, true
+		}
+	}
+	return Label{}, false
+}
+
+// labelTextColor picks black or white text, whichever reads better on bg.
+func labelTextColor(bg color.RGBA) color.Color {
+	// Perceived brightness (ITU-R BT.601 luma)
+	if 299*int(bg.R)+587*int(bg.G)+114*int(bg.B) > 128*1000 {
+		return color.Black
+	}
+	return color.White
+}
+
+// drawLabelChip draws a label as a pill in its color, with its left edge at
+// x and centered vertically on y. The name is shortened to fit maxWidth.
+func drawLabelChip(c *render.Canvas, l Label, x, y, maxWidth int, face font.Face) {
+	bg := l.RGBA()
+	name := l.Name
+	for render.BadgeSize(face, name).X > maxWidth && len([]rune(name)) > 1 {
+		runes := []rune(strings.TrimSuffix(name, "."))
+		name = string(runes[:len(runes)-1]) + "."
+	}
+	size := render.BadgeSize(face, name)
+	render.DrawBadge(c, name, image.Pt(x+size.X/2, y), face, labelTextColor(bg), bg)
+}
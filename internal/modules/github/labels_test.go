@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+func TestSearchPRsParsesLabels(t *testing.T) {
+	client, srv := newStubClient(t)
+	srv.JSON("GET /search/issues", map[string]any{
+		"items": []map[string]any{{
+			"title":          "Fix the thing",
+			"number":         12,
+			"repository_url": "https://api.github.com/repos/phinze/belowdeck",
+			"labels": []map[string]string{
+				{"name": "bug", "color": "d73a4a"},
+				{"name": "Blocked", "color": "fbca04"},
+			},
+		}},
+	})
+
+	prs, err := client.searchPRs(context.Background(), "is:pr", PRStatusWaiting)
+	if err != nil {
+		t.Fatalf("searchPRs: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d PRs, want 1", len(prs))
+	}
+	want := []Label{{Name: "bug", Color: "d73a4a"}, {Name: "Blocked", Color: "fbca04"}}
+	if !slices.Equal(prs[0].Labels, want) {
+		t.Errorf("labels = %v, want %v", prs[0].Labels, want)
+	}
+}
+
+func TestShownLabel(t *testing.T) {
+	pr := PRInfo{Labels: []Label{{Name: "bug"}, {Name: "Blocked"}, {Name: "needs-review"}}}
+
+	l, ok := shownLabel(pr, parseShownLabels(" needs-review, blocked ,"))
+	if !ok || l.Name != "Blocked" {
+		t.Errorf("shownLabel = %v, %v; want the first configured label, Blocked", l, ok)
+	}
+	if _, ok := shownLabel(pr, parseShownLabels("")); ok {
+		t.Error("shownLabel found a label with none configured")
+	}
+}
+
+func TestLabelRGBA(t *testing.T) {
+	if got, want := (Label{Color: "fbca04"}).RGBA(), (color.RGBA{0xfb, 0xca, 0x04, 0xff}); got != want {
+		t.Errorf("RGBA() = %v, want %v", got, want)
+	}
+	if got := (Label{Color: "nope"}).RGBA(); got != colorLabelDefault {
+		t.Errorf("RGBA() of a bad color = %v, want the default", got)
+	}
+}
+
+func TestPRKeyAccentsShownLabel(t *testing.T) {
+	m := New(device.NewFake())
+	m.initFonts()
+	m.labels = parseShownLabels("blocked")
+	pr := PRInfo{Repo: "phinze/belowdeck", Number: 12, Labels: []Label{{Name: "Blocked", Color: "fbca04"}}}
+
+	img := m.renderPRKey(pr)
+	if got, want := rgbaAt(img, keySize/2, keySize-1), (color.RGBA{0xfb, 0xca, 0x04, 0xff}); got != want {
+		t.Errorf("bottom edge = %v, want the label color %v", got, want)
+	}
+
+	m.labels = parseShownLabels("needs-review")
+	if got := rgbaAt(m.renderPRKey(pr), keySize/2, keySize-1); got == (color.RGBA{0xfb, 0xca, 0x04, 0xff}) {
+		t.Error("bottom edge accented for a label that isn't configured")
+	}
+}
+
+func TestDrawLabelChip(t *testing.T) {
+	m := New(device.NewFake())
+	m.initFonts()
+	c := render.NewCanvas(200, 40, 1)
+	l := Label{Name: "blocked", Color: "fbca04"}
+	drawLabelChip(c, l, 10, 20, 180, m.labelFace)
+
+	if got := rgbaAt(c.Image(), 12, 20); got != l.RGBA() {
+		t.Errorf("chip background = %v, want the label color %v", got, l.RGBA())
+	}
+}
+
+// rgbaAt returns the color of img at (x, y).
+func rgbaAt(img image.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
@@ -0,0 +1,109 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MergeState describes whether a PR is already on its way to landing.
+type MergeState string
+
+const (
+	MergeStateNone      MergeState = ""
+	MergeStateAutoMerge MergeState = "auto_merge" // merges itself once requirements pass
+	MergeStateQueued    MergeState = "queued"     // in the repo's merge queue
+)
+
+// mergeStateFrom maps the GraphQL merge fields of a PR to a MergeState.
+// Queue membership wins, since a queued PR may also have auto-merge on.
+func mergeStateFrom(inMergeQueue, autoMergeEnabled bool) MergeState {
+	switch {
+	case inMergeQueue:
+		return MergeStateQueued
+	case autoMergeEnabled:
+		return MergeStateAutoMerge
+	default:
+		return MergeStateNone
+	}
+}
+
+// graphqlURL returns the GraphQL endpoint matching the REST base URL.
+// GitHub Enterprise serves REST at /api/v3 and GraphQL at /api/graphql.
+func (c *Client) graphqlURL() string {
+	if base, ok := strings.CutSuffix(c.baseURL, "/api/v3"); ok {
+		return base + "/api/graphql"
+	}
+	return c.baseURL + "/graphql"
+}
+
+// fetchMergeStates sets MergeState for each PR using a single GraphQL
+// query. Failures leave the PRs at MergeStateNone; merge state is a hint,
+// not worth failing the whole refresh over.
+func (c *Client) fetchMergeStates(ctx context.Context, prs []PRInfo) {
+	if len(prs) == 0 {
+		return
+	}
+
+	var query strings.Builder
+	query.WriteString("query {")
+	for i, pr := range prs {
+		owner, name, ok := strings.Cut(pr.Repo, "/")
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&query, " pr%d: repository(owner: %q, name: %q) { pullRequest(number: %d) { isInMergeQueue autoMergeRequest { enabledAt } } }",
+			i, owner, name, pr.Number)
+	}
+	query.WriteString(" }")
+
+	body, err := json.Marshal(map[string]string{"query": query.String()})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.graphqlURL(), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	// Partial errors (e.g. an inaccessible repo) still return data for the
+	// other aliases, so errors in the response are ignored
+	var result struct {
+		Data map[string]*struct {
+			PullRequest *struct {
+				IsInMergeQueue   bool `json:"isInMergeQueue"`
+				AutoMergeRequest *struct {
+					EnabledAt string `json:"enabledAt"`
+				} `json:"autoMergeRequest"`
+			} `json:"pullRequest"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return
+	}
+
+	for i := range prs {
+		repo := result.Data[fmt.Sprintf("pr%d", i)]
+		if repo == nil || repo.PullRequest == nil {
+			continue
+		}
+		pr := repo.PullRequest
+		prs[i].MergeState = mergeStateFrom(pr.IsInMergeQueue, pr.AutoMergeRequest != nil)
+	}
+}
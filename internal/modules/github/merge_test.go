@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMergeStateFrom(t *testing.T) {
+	for _, tt := range []struct {
+		queued, autoMerge bool
+		want              MergeState
+	}{
+		{false, false, MergeStateNone},
+		{false, true, MergeStateAutoMerge},
+		{true, false, MergeStateQueued},
+		{true, true, MergeStateQueued},
+	} {
+		if got := mergeStateFrom(tt.queued, tt.autoMerge); got != tt.want {
+			t.Errorf("mergeStateFrom(%v, %v) = %q, want %q", tt.queued, tt.autoMerge, got, tt.want)
+		}
+	}
+}
+
+func TestFetchMergeStates(t *testing.T) {
+	client, srv := newStubClient(t)
+	srv.JSON("POST /graphql", map[string]any{
+		"data": map[string]any{
+			"pr0": map[string]any{"pullRequest": map[string]any{"isInMergeQueue": true}},
+			"pr1": map[string]any{"pullRequest": map[string]any{"autoMergeRequest": map[string]string{"enabledAt": "2026-01-01T00:00:00Z"}}},
+			"pr2": nil, // inaccessible repo
+		},
+	})
+
+	prs := []PRInfo{
+		{Repo: "o/a", Number: 1},
+		{Repo: "o/b", Number: 2},
+		{Repo: "o/c", Number: 3},
+	}
+	client.fetchMergeStates(context.Background(), prs)
+
+	want := []MergeState{MergeStateQueued, MergeStateAutoMerge, MergeStateNone}
+	for i, pr := range prs {
+		if pr.MergeState != want[i] {
+			t.Errorf("%s#%d merge state = %q, want %q", pr.Repo, pr.Number, pr.MergeState, want[i])
+		}
+	}
+}
+
+func TestGraphqlURL(t *testing.T) {
+	for base, want := range map[string]string{
+		"https://api.github.com":            "https://api.github.com/graphql",
+		"https://github.example.com/api/v3": "https://github.example.com/api/graphql",
+	} {
+		c := newClient(base, "token", nil)
+		if got := c.graphqlURL(); got != want {
+			t.Errorf("graphqlURL for %s = %q, want %q", base, got, want)
+		}
+	}
+}
@@ -3,14 +3,19 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"image"
 	"log"
-	"os/exec"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/httpclient"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
 	"golang.org/x/image/font"
 )
 
@@ -23,26 +28,72 @@ const (
 	OverlayReviewRequested
 )
 
+// StatsMode selects how the PR stats key is drawn.
+type StatsMode int
+
+const (
+	// StatsDetailed shows the icon (or failures) plus a row per status.
+	StatsDetailed StatsMode = iota
+	// StatsCompact shows only the most urgent count, large and centered.
+	StatsCompact
+)
+
+// parseStatsMode parses GITHUB_STATS_MODE, defaulting to detailed.
+func parseStatsMode(v string) (StatsMode, error) {
+	switch v {
+	case "", "detailed":
+		return StatsDetailed, nil
+	case "compact":
+		return StatsCompact, nil
+	default:
+		return StatsDetailed, fmt.Errorf("invalid GITHUB_STATS_MODE %q (want \"detailed\" or \"compact\")", v)
+	}
+}
+
+// overlayGrid is the key grid the PR list overlay is laid out on: a PR per
+// key in reading order, with the bottom right key going back.
+var overlayGrid = module.PlusKeyGrid
+
+// refreshCooldown is the minimum time between on-demand refreshes, to stay
+// well within the search API's rate limit.
+const refreshCooldown = 15 * time.Second
+
 // Module implements the GitHub PR stats module.
 type Module struct {
 	module.BaseModule
 
-	device  device.Device
-	client  *Client
-	enabled bool
+	device    device.Device
+	client    *Client
+	runner    runner.Runner
+	enabled   bool
+	statsMode StatsMode
+	pinned    map[string]bool
+	labels    map[string]bool
 
-	// State for my PRs (Key3)
+	// includeTeams counts review requests made through my teams, not just
+	// those made to me directly
+	includeTeams bool
+
+	// State for my PRs (Key3). loaded is false until the first successful
+	// fetch, so the keys show a loading state rather than zeros.
 	mu     sync.RWMutex
+	loaded bool
 	stats  PRStats
 	prList []PRInfo
 
+	// Debounced CI failure alert for the stats key
+	ciAlert ciAlert
+
 	// State for review-requested PRs (Key4)
 	reviewStats  ReviewStats
 	reviewPRList []PRInfo
 
-	// Overlay state
+	// Overlay state. selectedURL is the PR waiting for a second tap to
+	// open, with confirmOpen set.
 	overlayType   OverlayType
 	overlayExpiry time.Time
+	confirmOpen   bool
+	selectedURL   string
 
 	// Fonts
 	labelFace      font.Face
@@ -50,12 +101,16 @@ type Module struct {
 	overlayFace    font.Face
 	stripTitleFace font.Face
 	stripLabelFace font.Face
+	bigNumberFace  font.Face
 
 	// Resources
 	resources module.Resources
 
 	// Context for fetching
 	ctx context.Context
+
+	// On-demand refreshes from a long key hold
+	refresher module.Refresher
 }
 
 // New creates a new GitHub module.
@@ -63,6 +118,7 @@ func New(dev device.Device) *Module {
 	return &Module{
 		BaseModule: module.NewBaseModule("github"),
 		device:     dev,
+		runner:     runner.Default,
 	}
 }
 
@@ -79,9 +135,10 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 
 	m.resources = res
 	m.ctx = ctx
+	m.refresher = module.Refresher{Fetch: m.fetchStats, Cooldown: refreshCooldown}
 
 	// Create API client (uses gh CLI token)
-	client, err := NewClient()
+	client, err := NewClient(os.Getenv("GITHUB_API_URL"), httpclient.New())
 	if err != nil {
 		log.Printf("GitHub module disabled: %v", err)
 		m.enabled = false
@@ -90,13 +147,55 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	m.client = client
 	m.enabled = true
 
-	// Initialize fonts
-	if err := m.initFonts(); err != nil {
-		return err
+	maxConcurrent, err := parseMaxConcurrent(os.Getenv("GITHUB_MAX_CONCURRENT"))
+	if err != nil {
+		log.Printf("GitHub: %v, using %d", err, maxConcurrent)
+	}
+	m.client.setMaxConcurrent(maxConcurrent)
+
+	statsMode, err := parseStatsMode(os.Getenv("GITHUB_STATS_MODE"))
+	if err != nil {
+		log.Printf("GitHub: %v, using detailed", err)
 	}
+	m.statsMode = statsMode
+
+	pinned, err := parsePinned(os.Getenv("GITHUB_PINNED"))
+	if err != nil {
+		log.Printf("GitHub: %v, pinning nothing", err)
+	}
+	m.pinned = pinned
+	m.labels = parseShownLabels(os.Getenv("GITHUB_LABELS"))
+
+	stablePolls, err := parseCIStablePolls(os.Getenv("GITHUB_CI_STABLE_POLLS"))
+	if err != nil {
+		log.Printf("GitHub: %v, using %d", err, stablePolls)
+	}
+	m.ciAlert = ciAlert{stablePolls: stablePolls}
+
+	m.includeTeams = true
+	if v := os.Getenv("GITHUB_TEAM_REVIEWS"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Printf("GitHub: invalid GITHUB_TEAM_REVIEWS %q, including team reviews", v)
+		} else {
+			m.includeTeams = include
+		}
+	}
+
+	if v := os.Getenv("GITHUB_CONFIRM_OPEN"); v != "" {
+		confirm, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Printf("GitHub: invalid GITHUB_CONFIRM_OPEN %q, opening on the first tap", v)
+		} else {
+			m.confirmOpen = confirm
+		}
+	}
+
+	// Initialize fonts
+	m.initFonts()
 
 	// Start polling
-	go m.pollStats(ctx)
+	module.Supervise(ctx, "github poll", m.pollStats)
 
 	log.Println("GitHub module initialized")
 	return nil
@@ -121,6 +220,10 @@ func (m *Module) pollStats(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			// Skip fetches while snoozed
+			if m.IsSnoozed() {
+				continue
+			}
 			m.fetchStats(ctx)
 		}
 	}
@@ -148,22 +251,34 @@ func (m *Module) fetchStats(ctx context.Context) {
 			stats.CIFailed++
 		}
 	}
+	listFetched := err == nil
 
 	// Fetch review-requested stats
-	reviewStats, err := m.client.GetReviewRequestedStats(ctx)
+	reviewStats, err := m.client.GetReviewRequestedStats(ctx, m.includeTeams)
 	if err != nil {
 		log.Printf("Failed to fetch review-requested stats: %v", err)
 		// Continue with partial data
 	}
 
 	// Fetch review-requested PR list
-	reviewPRList, err := m.client.GetReviewRequestedPRList(ctx)
+	reviewPRList, err := m.client.GetReviewRequestedPRList(ctx, m.includeTeams)
 	if err != nil {
 		log.Printf("Failed to fetch review-requested PR list: %v", err)
 		// Continue with partial data
 	}
 
+	// Keep pinned PRs first
+	prList = pinFirst(prList, m.pinned)
+	reviewPRList = pinFirst(reviewPRList, m.pinned)
+
 	m.mu.Lock()
+	m.loaded = true
+	// Debounce the failure alert; without a PR list there's no new count
+	if listFetched {
+		stats.CIFailed = m.ciAlert.update(stats.CIFailed)
+	} else {
+		stats.CIFailed = m.ciAlert.count()
+	}
 	m.stats = stats
 	if prList != nil {
 		m.prList = prList
@@ -175,6 +290,13 @@ func (m *Module) fetchStats(ctx context.Context) {
 	m.mu.Unlock()
 }
 
+// isLoaded reports whether stats have been fetched at least once.
+func (m *Module) isLoaded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.loaded
+}
+
 // getStats returns the current PR stats.
 func (m *Module) getStats() PRStats {
 	m.mu.RLock()
@@ -211,9 +333,25 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 
 	keys := make(map[module.KeyID]image.Image)
 
+	// Until the first fetch completes, and while refreshing, show
+	// placeholders rather than zeros or stale counts
+	if !m.isLoaded() || m.refresher.Refreshing() {
+		if len(m.resources.Keys) > 0 {
+			keys[m.resources.Keys[0]] = m.renderLoadingButton(iconSendSVG, "PRs")
+		}
+		if len(m.resources.Keys) > 1 {
+			keys[m.resources.Keys[1]] = m.renderLoadingButton(iconInboxSVG, "Review")
+		}
+		return keys
+	}
+
 	// Key 0 (Key3): My PR stats overview (outbox)
 	if len(m.resources.Keys) > 0 {
-		keys[m.resources.Keys[0]] = m.renderPRStatsButton()
+		if m.statsMode == StatsCompact {
+			keys[m.resources.Keys[0]] = m.renderCompactPRStatsButton()
+		} else {
+			keys[m.resources.Keys[0]] = m.renderPRStatsButton()
+		}
 	}
 
 	// Key 1 (Key4): Review-requested PRs (inbox)
@@ -231,26 +369,57 @@ func (m *Module) RenderStrip() image.Image {
 
 // HandleKey processes key events.
 func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
-	// Only trigger on press (not release)
-	if !event.Pressed {
+	// Trigger on release, so a long hold can refresh instead
+	if event.Pressed || event.Duration >= module.RefreshHold {
 		return nil
 	}
 
 	// Determine which overlay to show based on which key was pressed
-	m.mu.Lock()
 	if len(m.resources.Keys) > 1 && id == m.resources.Keys[1] {
 		// Key4 pressed - show review-requested overlay
-		m.overlayType = OverlayReviewRequested
+		m.showOverlay(OverlayReviewRequested)
 	} else {
 		// Key3 pressed - show my PRs overlay
-		m.overlayType = OverlayMyPRs
+		m.showOverlay(OverlayMyPRs)
 	}
-	m.overlayExpiry = time.Now().Add(5 * time.Second)
-	m.mu.Unlock()
 
 	return nil
 }
 
+// Refresh fetches PR stats now rather than at the next poll. Refreshes are
+// limited to one per refreshCooldown.
+func (m *Module) Refresh() {
+	if !m.enabled {
+		return
+	}
+	if !m.refresher.Trigger(m.ctx) {
+		log.Println("GitHub refresh skipped: refreshed too recently")
+	}
+}
+
+// HandleAction runs an external action: "prs" shows my PRs, "reviews" shows
+// PRs awaiting my review.
+func (m *Module) HandleAction(action string) error {
+	switch action {
+	case "prs":
+		m.showOverlay(OverlayMyPRs)
+	case "reviews":
+		m.showOverlay(OverlayReviewRequested)
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+// showOverlay shows the given PR overlay for a few seconds.
+func (m *Module) showOverlay(overlayType OverlayType) {
+	m.mu.Lock()
+	m.overlayType = overlayType
+	m.overlayExpiry = time.Now().Add(overlayDuration)
+	m.selectedURL = ""
+	m.mu.Unlock()
+}
+
 // HandleDial processes dial events.
 func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 	return nil
@@ -268,8 +437,8 @@ func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error
 		return nil
 	}
 
-	// Key8 (bottom right) dismisses overlay
-	if id == module.Key8 {
+	// The bottom right key dismisses overlay
+	if id == overlayGrid.Last() {
 		m.mu.Lock()
 		m.overlayType = OverlayNone
 		m.mu.Unlock()
@@ -288,13 +457,10 @@ func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error
 		prList = m.getPRList()
 	}
 
-	// Map key to PR index (Key1-Key7 map to PRs 0-6)
-	keyIndex := int(id) - 1 // Key1=1, so subtract 1 for 0-indexed
-	if keyIndex >= 0 && keyIndex < len(prList) {
-		pr := prList[keyIndex]
-		if pr.URL != "" {
-			m.openURL(pr.URL)
-		}
+	// Map key to PR index, in reading order
+	keyIndex, ok := overlayGrid.Index(id)
+	if ok && keyIndex < len(prList) {
+		m.openPR(prList[keyIndex])
 	}
 
 	return nil
@@ -323,24 +489,27 @@ func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
 		return nil
 	}
 
-	// Strip is 800px wide, divided into 4 sections of 200px each
-	const prWidth = 200
-	prIndex := event.Point.X / prWidth
-	if prIndex >= 0 && prIndex < len(prList) && prIndex < 4 {
-		pr := prList[prIndex]
-		if pr.URL != "" {
-			m.openURL(pr.URL)
-		}
+	// The strip is divided evenly into stripSections sections
+	prIndex := stripSectionAt(event.Pos.X)
+	if prIndex >= 0 && prIndex < len(prList) && prIndex < stripSections {
+		m.openPR(prList[prIndex])
 	}
 
 	return nil
 }
 
-// openURL opens a URL in the default browser.
+// openURL opens a URL in the default browser, in the background so the
+// overlay doesn't wait on the browser.
 func (m *Module) openURL(url string) {
-	if err := exec.Command("open", url).Start(); err != nil {
-		log.Printf("Failed to open URL %s: %v", url, err)
-	}
+	go func() {
+		err := runner.OpenURL(m.Context(), m.runner, url)
+		switch {
+		case errors.Is(err, runner.ErrThrottled):
+			log.Printf("Not opening %s: %v", url, err)
+		case err != nil:
+			log.Printf("Failed to open URL %s: %v", url, err)
+		}
+	}()
 }
 
 // IsOverlayActive returns true if the PR list overlay is visible.
@@ -366,7 +535,7 @@ func (m *Module) IsOverlayActive() bool {
 	return true
 }
 
-// RenderOverlayKeys returns images for all 8 keys showing PR list.
+// RenderOverlayKeys returns images for every overlay key showing PR list.
 func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
 	keys := make(map[module.KeyID]image.Image)
 
@@ -382,13 +551,12 @@ func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
 		prList = m.getPRList()
 	}
 
-	// Render up to 7 PRs on Keys 1-7, Key8 is the back button
-	prKeys := []module.KeyID{
-		module.Key1, module.Key2, module.Key3, module.Key4,
-		module.Key5, module.Key6, module.Key7,
-	}
-
-	for i, keyID := range prKeys {
+	// Render a PR on every key but the last, in reading order
+	back := overlayGrid.Last()
+	for i, keyID := range overlayGrid.Keys() {
+		if keyID == back {
+			continue
+		}
 		if i < len(prList) {
 			keys[keyID] = m.renderPRKey(prList[i])
 		} else {
@@ -396,8 +564,8 @@ func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
 		}
 	}
 
-	// Key8 is the back button
-	keys[module.Key8] = m.renderBackKey()
+	// The bottom right key is the back button
+	keys[back] = m.renderBackKey()
 
 	return keys
 }
@@ -416,5 +584,5 @@ func (m *Module) RenderOverlayStrip() image.Image {
 		prList = m.getPRList()
 	}
 
-	return m.renderOverlayStripWithPRs(prList)
+	return m.renderOverlayStripWithPRs(m.overlayStripRect(), prList)
 }
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// parsePinned parses GITHUB_PINNED, a comma-separated list of
+// "owner/repo#number" PRs to keep at the front of the PR lists. The
+// returned set is keyed by pinKey.
+func parsePinned(v string) (map[string]bool, error) {
+	pinned := make(map[string]bool)
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		repo, numStr, ok := strings.Cut(part, "#")
+		num, err := strconv.Atoi(numStr)
+		if !ok || err != nil || num <= 0 || !strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid GITHUB_PINNED entry %q (want \"owner/repo#123\")", part)
+		}
+		pinned[pinKey(repo, num)] = true
+	}
+	return pinned, nil
+}
+
+// pinKey identifies a PR for pinning. Repos are compared case-insensitively,
+// as GitHub does.
+func pinKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(strings.TrimSpace(repo)), number)
+}
+
+// pinFirst marks the pinned PRs in prs and moves them to the front, keeping
+// the search order otherwise.
+func pinFirst(prs []PRInfo, pinned map[string]bool) []PRInfo {
+	if len(pinned) == 0 {
+		return prs
+	}
+	for i := range prs {
+		prs[i].Pinned = pinned[pinKey(prs[i].Repo, prs[i].Number)]
+	}
+	slices.SortStableFunc(prs, func(a, b PRInfo) int {
+		switch {
+		case a.Pinned && !b.Pinned:
+			return -1
+		case b.Pinned && !a.Pinned:
+			return 1
+		}
+		return 0
+	})
+	return prs
+}
+
+// drawPinMarker draws the pinned indicator, a small triangle filling the
+// top-right corner of the given rectangle.
+func drawPinMarker(c *render.Canvas, r image.Rectangle, col color.Color) {
+	size := c.Px(min(r.Dx(), r.Dy()))
+	right, top := c.Px(r.Max.X), c.Px(r.Min.Y)
+	for y := 0; y < size; y++ {
+		for x := y; x < size; x++ {
+			c.Img.Set(right-size+x, top+y, col)
+		}
+	}
+}
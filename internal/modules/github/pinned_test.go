@@ -0,0 +1,45 @@
+package github
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePinned(t *testing.T) {
+	pinned, err := parsePinned(" phinze/belowdeck#12, Other/Repo#3 ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{pinKey("phinze/belowdeck", 12), pinKey("other/repo", 3)} {
+		if !pinned[key] {
+			t.Errorf("%s not pinned", key)
+		}
+	}
+
+	for _, bad := range []string{"belowdeck#12", "phinze/belowdeck", "phinze/belowdeck#0", "phinze/belowdeck#x"} {
+		if _, err := parsePinned(bad); err == nil {
+			t.Errorf("parsePinned(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestPinFirstKeepsOrderOtherwise(t *testing.T) {
+	prs := []PRInfo{
+		{Repo: "o/a", Number: 1},
+		{Repo: "o/b", Number: 2},
+		{Repo: "O/C", Number: 3},
+		{Repo: "o/d", Number: 4},
+	}
+	pinned := map[string]bool{pinKey("o/c", 3): true, pinKey("o/b", 2): true}
+
+	var got []int
+	for _, pr := range pinFirst(prs, pinned) {
+		got = append(got, pr.Number)
+		if pr.Pinned != (pr.Number == 2 || pr.Number == 3) {
+			t.Errorf("#%d Pinned = %v", pr.Number, pr.Pinned)
+		}
+	}
+	if want := []int{2, 3, 1, 4}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
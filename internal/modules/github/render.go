@@ -7,12 +7,13 @@ import (
 	"image/color"
 	"log"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/phinze/belowdeck/internal/render"
 	"github.com/srwiley/oksvg"
 	"github.com/srwiley/rasterx"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -28,167 +29,157 @@ var iconSendSVG string
 //go:embed icons/inbox.svg
 var iconInboxSVG string
 
+//go:embed icons/git-merge.svg
+var iconMergeSVG string
+
 // Common colors
 var (
 	colorKeyBg   = color.RGBA{40, 40, 40, 255}
 	colorWhite   = color.RGBA{255, 255, 255, 255}
-	colorGreen   = color.RGBA{63, 185, 80, 255}  // GitHub green
-	colorYellow  = color.RGBA{210, 153, 34, 255} // GitHub yellow
-	colorOrange  = color.RGBA{219, 109, 40, 255} // GitHub orange
-	colorRed     = color.RGBA{248, 81, 73, 255}  // GitHub red for CI failures
+	colorGreen   = color.RGBA{63, 185, 80, 255}   // GitHub green
+	colorYellow  = color.RGBA{210, 153, 34, 255}  // GitHub yellow
+	colorOrange  = color.RGBA{219, 109, 40, 255}  // GitHub orange
+	colorRed     = color.RGBA{248, 81, 73, 255}   // GitHub red for CI failures
+	colorPurple  = color.RGBA{163, 113, 247, 255} // GitHub purple for merging PRs
 	colorDimGray = color.RGBA{110, 110, 110, 255}
 )
 
 const keySize = 72
 
 // initFonts initializes the font faces for rendering.
-func (m *Module) initFonts() error {
-	ttBold, err := opentype.Parse(fontBold)
-	if err != nil {
-		return fmt.Errorf("failed to parse bold font: %w", err)
-	}
-
-	m.labelFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    9,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create label face: %w", err)
-	}
-
-	m.numberFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    11,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create number face: %w", err)
-	}
-
-	m.overlayFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    10,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create overlay face: %w", err)
-	}
-
-	m.stripTitleFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    18,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create strip title face: %w", err)
-	}
-
-	m.stripLabelFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    14,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create strip label face: %w", err)
-	}
-
-	return nil
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("github bold", render.BoldFont, fontBold)
+	m.labelFace = render.NewFace(ttBold, 9)
+	m.numberFace = render.NewFace(ttBold, 11)
+	m.overlayFace = render.NewFace(ttBold, 10)
+	m.stripTitleFace = render.NewFace(ttBold, 18)
+	m.stripLabelFace = render.NewFace(ttBold, 14)
+	m.bigNumberFace = render.NewFace(ttBold, 32)
 }
 
 // renderPRStatsButton renders the PR stats button (my PRs - outbox).
 func (m *Module) renderPRStatsButton() image.Image {
 	stats := m.getStats()
 
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	c := render.NewKeyCanvas(keySize)
 
 	// Background
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	c.Fill(c.Bounds(), colorKeyBg)
 
 	var rowY int
 	if stats.CIFailed > 0 {
 		// Show fail row at top instead of icon
-		m.drawStatRow(img, 14, "Fail", stats.CIFailed, colorRed)
+		m.drawStatRow(c, 14, "Fail", stats.CIFailed, colorRed)
 		rowY = 28
 	} else {
 		// Draw send icon (outbox) at top
-		iconImg := renderSVGIcon(iconSendSVG, 20, colorWhite)
+		iconImg := renderSVGIcon(iconSendSVG, c.Px(20), colorWhite)
 		iconX := (keySize - 20) / 2
-		draw.Draw(img, image.Rect(iconX, 4, iconX+20, 24), iconImg, image.Point{}, draw.Over)
+		c.DrawImage(image.Rect(iconX, 4, iconX+20, 24), iconImg)
 		rowY = 28
 	}
 
 	// Draw stats as colored rows
 	// Waiting (yellow)
-	m.drawStatRow(img, rowY, "Wait", stats.WaitingForReview, colorYellow)
+	m.drawStatRow(c, rowY, "Wait", stats.WaitingForReview, colorYellow)
 	// Approved (green)
-	m.drawStatRow(img, rowY+14, "OK", stats.Approved, colorGreen)
+	m.drawStatRow(c, rowY+14, "OK", stats.Approved, colorGreen)
 	// Changes requested (orange)
-	m.drawStatRow(img, rowY+28, "Chg", stats.ChangesRequested, colorOrange)
+	m.drawStatRow(c, rowY+28, "Chg", stats.ChangesRequested, colorOrange)
 
-	return img
+	return c.Image()
+}
+
+// renderCompactPRStatsButton renders the PR stats button as a single large
+// count: CI failures first, then changes requested, then PRs waiting on
+// review, falling back to the approved count when nothing needs attention.
+func (m *Module) renderCompactPRStatsButton() image.Image {
+	stats := m.getStats()
+
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	label, count, col := "OK", stats.Approved, colorGreen
+	switch {
+	case stats.CIFailed > 0:
+		label, count, col = "Fail", stats.CIFailed, colorRed
+	case stats.ChangesRequested > 0:
+		label, count, col = "Chg", stats.ChangesRequested, colorOrange
+	case stats.WaitingForReview > 0:
+		label, count, col = "Wait", stats.WaitingForReview, colorYellow
+	}
+
+	// Draw count large and centered, label underneath
+	c.DrawStringCentered(fmt.Sprintf("%d", count), keySize/2, 44, m.bigNumberFace, col)
+	c.DrawStringCentered(label, keySize/2, 62, m.labelFace, colorDimGray)
+
+	return c.Image()
 }
 
 // renderReviewRequestedButton renders the review-requested PRs button (inbox).
 func (m *Module) renderReviewRequestedButton() image.Image {
 	stats := m.getReviewStats()
 
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	c := render.NewKeyCanvas(keySize)
 
 	// Background
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	c.Fill(c.Bounds(), colorKeyBg)
 
 	// Draw inbox icon at top
-	iconImg := renderSVGIcon(iconInboxSVG, 24, colorWhite)
+	iconImg := renderSVGIcon(iconInboxSVG, c.Px(24), colorWhite)
 	iconX := (keySize - 24) / 2
-	draw.Draw(img, image.Rect(iconX, 8, iconX+24, 32), iconImg, image.Point{}, draw.Over)
+	c.DrawImage(image.Rect(iconX, 8, iconX+24, 32), iconImg)
 
 	// Draw "Review" label
-	m.drawTextCentered(img, "Review", keySize/2, 48, m.labelFace, colorDimGray)
+	c.DrawStringCentered("Review", keySize/2, 48, m.labelFace, colorDimGray)
 
-	// Draw count
-	countStr := fmt.Sprintf("%d", stats.Total)
-	m.drawTextCentered(img, countStr, keySize/2, 64, m.numberFace, colorYellow)
+	// Draw count of direct requests, followed by team requests dimmed
+	countStr := fmt.Sprintf("%d", stats.Direct())
+	if stats.Team == 0 {
+		c.DrawStringCentered(countStr, keySize/2, 64, m.numberFace, colorYellow)
+	} else {
+		teamStr := fmt.Sprintf(" +%d", stats.Team)
+		width := c.MeasureString(m.numberFace, countStr) + c.MeasureString(m.numberFace, teamStr)
+		x := keySize/2 - width/2
+		c.DrawString(countStr, x, 64, m.numberFace, colorYellow)
+		c.DrawString(teamStr, x+c.MeasureString(m.numberFace, countStr), 64, m.numberFace, colorDimGray)
+	}
 
-	return img
+	return c.Image()
 }
 
-// drawStatRow draws a stat row with label and count.
-func (m *Module) drawStatRow(img *image.RGBA, y int, label string, count int, col color.Color) {
-	// Draw colored indicator dot
-	dotSize := 6
-	dotX := 8
-	dotY := y + 2
-	for dy := 0; dy < dotSize; dy++ {
-		for dx := 0; dx < dotSize; dx++ {
-			img.Set(dotX+dx, dotY+dy, col)
-		}
-	}
+// renderLoadingButton renders a key whose stats haven't been fetched yet: a
+// dimmed icon and label over loading dots.
+func (m *Module) renderLoadingButton(svg, label string) image.Image {
+	c := render.NewKeyCanvas(keySize)
 
-	// Draw label
-	m.drawText(img, label, 18, y+8, m.labelFace, colorDimGray)
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
 
-	// Draw count on right
-	countStr := fmt.Sprintf("%d", count)
-	m.drawTextRight(img, countStr, keySize-8, y+8, m.numberFace, colorWhite)
-}
+	iconImg := renderSVGIcon(svg, c.Px(24), colorDimGray)
+	iconX := (keySize - 24) / 2
+	c.DrawImage(image.Rect(iconX, 8, iconX+24, 32), iconImg)
 
-// drawText draws text at the given position.
-func (m *Module) drawText(img *image.RGBA, text string, x, y int, face font.Face, col color.Color) {
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(col),
-		Face: face,
-		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
-	}
-	d.DrawString(text)
+	c.DrawStringCentered(label, keySize/2, 48, m.labelFace, colorDimGray)
+	render.DrawLoadingDots(c, image.Pt(keySize/2, 60), colorDimGray)
+
+	return c.Image()
 }
 
-// drawTextRight draws text right-aligned at the given position.
-func (m *Module) drawTextRight(img *image.RGBA, text string, rightX, y int, face font.Face, col color.Color) {
-	width := font.MeasureString(face, text).Ceil()
-	x := rightX - width
+// drawStatRow draws a stat row with label and count.
+func (m *Module) drawStatRow(c *render.Canvas, y int, label string, count int, col color.Color) {
+	render.DrawStatRow(c, image.Rect(8, y, keySize-8, y+render.StatRowHeight), label, count, col, render.StatRowStyle{
+		LabelFace:  m.labelFace,
+		CountFace:  m.numberFace,
+		LabelColor: colorDimGray,
+		CountColor: colorWhite,
+	})
+}
 
+// drawText draws text at the given position.
+func (m *Module) drawText(img *image.RGBA, text string, x, y int, face font.Face, col color.Color) {
 	d := &font.Drawer{
 		Dst:  img,
 		Src:  image.NewUniform(col),
@@ -222,7 +213,7 @@ func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Ima
 
 // renderPRKey renders a single PR on a key.
 func (m *Module) renderPRKey(pr PRInfo) image.Image {
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	c := render.NewKeyCanvas(keySize)
 
 	// Background color based on status (darken if CI failed)
 	var bgColor color.Color
@@ -236,7 +227,7 @@ func (m *Module) renderPRKey(pr PRInfo) image.Image {
 	default:
 		bgColor = color.RGBA{50, 50, 40, 255} // Dark yellow
 	}
-	draw.Draw(img, img.Bounds(), &image.Uniform{bgColor}, image.Point{}, draw.Src)
+	c.Fill(c.Bounds(), bgColor)
 
 	// Status indicator color (review status)
 	var statusColor color.Color
@@ -249,98 +240,154 @@ func (m *Module) renderPRKey(pr PRInfo) image.Image {
 		statusColor = colorYellow
 	}
 
-	// Draw status indicator bar at top (red if CI failed)
+	// Draw status indicator bar at top (red if CI failed, purple if landing,
+	// gray if only my team was asked to review)
 	barColor := statusColor
 	if pr.CI == CIStatusFailed {
 		barColor = colorRed
+	} else if pr.Landing() {
+		barColor = colorPurple
+	} else if pr.TeamRequest {
+		barColor = colorDimGray
 	}
 	barRect := image.Rect(0, 0, keySize, 4)
-	draw.Draw(img, barRect, &image.Uniform{barColor}, image.Point{}, draw.Src)
+	c.Fill(barRect, barColor)
 
 	// Draw PR number
 	prNum := fmt.Sprintf("#%d", pr.Number)
-	m.drawText(img, prNum, 4, 16, m.labelFace, statusColor)
+	c.DrawString(prNum, 4, 16, m.labelFace, statusColor)
 
-	// Draw CI indicator next to PR number
+	// Draw CI indicator next to PR number, or the merge icon once it's landing
 	if pr.CI == CIStatusFailed {
-		m.drawText(img, "X", 40, 16, m.labelFace, colorRed)
+		c.DrawString("X", 40, 16, m.labelFace, colorRed)
+	} else if pr.Landing() {
+		iconImg := renderSVGIcon(iconMergeSVG, c.Px(12), colorPurple)
+		c.DrawImage(image.Rect(40, 6, 52, 18), iconImg)
 	} else if pr.CI == CIStatusPassed {
-		m.drawText(img, "+", 40, 16, m.labelFace, colorGreen)
+		c.DrawString("+", 40, 16, m.labelFace, colorGreen)
 	}
 
-	// Draw repo name (truncated)
-	repo := pr.Repo
-	// Get just the repo part (after /)
-	if idx := strings.LastIndex(repo, "/"); idx != -1 {
-		repo = repo[idx+1:]
+	// Mark pinned PRs in the top-right corner
+	if pr.Pinned {
+		drawPinMarker(c, image.Rect(keySize-10, 4, keySize, 14), colorWhite)
 	}
-	if len(repo) > 10 {
-		repo = repo[:9] + "."
+
+	// Draw repo name (truncated), or ask for the second tap once selected
+	selected := m.isSelected(pr)
+	if selected {
+		c.DrawString("Tap again", 4, 28, m.labelFace, colorWhite)
+	} else {
+		repo := truncate(repoName(pr.Repo), 10, ".")
+		c.DrawString(repo, 4, 28, m.labelFace, colorDimGray)
 	}
-	m.drawText(img, repo, 4, 28, m.labelFace, colorDimGray)
 
 	// Draw title (wrapped across multiple lines)
-	title := pr.Title
-	lines := wrapText(title, 11) // ~11 chars per line at this font size
+	lines := wrapText(prTitle(pr), 11) // ~11 chars per line at this font size
 	y := 42
 	for i, line := range lines {
 		if i >= 3 { // Max 3 lines
 			break
 		}
-		m.drawText(img, line, 4, y, m.overlayFace, colorWhite)
+		c.DrawString(line, 4, y, m.overlayFace, colorWhite)
 		y += 11
 	}
 
-	return img
+	// Accent the bottom edge with the first configured label's color
+	if l, ok := shownLabel(pr, m.labels); ok {
+		c.Fill(image.Rect(0, keySize-3, keySize, keySize), l.RGBA())
+	}
+
+	if selected {
+		m.drawSelection(c)
+	}
+
+	return c.Image()
 }
 
 // renderEmptyKey renders an empty key for the overlay.
 func (m *Module) renderEmptyKey() image.Image {
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
-	return img
+	c := render.NewKeyCanvas(keySize)
+	c.Fill(c.Bounds(), colorKeyBg)
+	return c.Image()
 }
 
 // renderBackKey renders the back button for dismissing the overlay.
 func (m *Module) renderBackKey() image.Image {
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	c := render.NewKeyCanvas(keySize)
+	c.Fill(c.Bounds(), colorKeyBg)
 
 	// Draw "Back" label centered
-	m.drawTextCentered(img, "Back", keySize/2, keySize/2+4, m.overlayFace, colorDimGray)
+	c.DrawStringCentered("Back", keySize/2, keySize/2+4, m.overlayFace, colorDimGray)
 
-	return img
+	return c.Image()
 }
 
-// renderOverlayStripWithPRs renders the touch strip for the PR overlay with the given PR list.
-func (m *Module) renderOverlayStripWithPRs(prList []PRInfo) image.Image {
-	img := image.NewRGBA(image.Rect(0, 0, 800, 100))
+// stripSections is how many PRs the overlay strip shows, side by side.
+const stripSections = 4
+
+// defaultStripRect is the Stream Deck Plus strip, assumed if the device
+// can't report its own.
+var defaultStripRect = image.Rect(0, 0, 800, 100)
+
+// overlayStripRect returns the device's strip rectangle, which the overlay
+// strip fills.
+func (m *Module) overlayStripRect() image.Rectangle {
+	rect, err := m.device.GetTouchStripImageRectangle()
+	if err != nil || rect.Empty() {
+		return defaultStripRect
+	}
+	return rect
+}
+
+// stripSection returns the part of rect showing the i-th PR. Sections
+// share the width evenly, so they scale with the strip.
+func stripSection(rect image.Rectangle, i int) image.Rectangle {
+	w := rect.Dx()
+	return image.Rect(rect.Min.X+i*w/stripSections, rect.Min.Y, rect.Min.X+(i+1)*w/stripSections, rect.Max.Y)
+}
+
+// stripSectionAt returns the index of the section at x, a fraction of the
+// strip's width.
+func stripSectionAt(x float64) int {
+	if x < 0 {
+		return -1
+	}
+	return min(int(x*stripSections), stripSections-1)
+}
+
+// renderOverlayStripWithPRs renders the touch strip for the PR overlay with
+// the given PR list, filling rect.
+func (m *Module) renderOverlayStripWithPRs(rect image.Rectangle, prList []PRInfo) image.Image {
+	img := image.NewRGBA(rect)
 
 	// Dark background
 	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{30, 30, 30, 255}}, image.Point{}, draw.Src)
 
 	if len(prList) == 0 {
-		m.drawTextCentered(img, "No PRs", 400, 55, m.stripTitleFace, colorDimGray)
+		center := rect.Min.Add(rect.Size().Div(2))
+		m.drawTextCentered(img, "No PRs", center.X, center.Y+5, m.stripTitleFace, colorDimGray)
 		return img
 	}
 
-	// Show up to 4 PRs in a single row with larger text
-	// Each PR gets 200px width
-	const prWidth = 200
-
+	// Show up to stripSections PRs in a single row with larger text
 	for i, pr := range prList {
-		if i >= 4 {
+		if i >= stripSections {
 			break
 		}
-		x := i * prWidth
-		m.drawStripPR(img, pr, x)
+		m.drawStripPR(img, pr, stripSection(rect, i))
 	}
 
 	return img
 }
 
-// drawStripPR draws a single PR entry on the strip.
-func (m *Module) drawStripPR(img *image.RGBA, pr PRInfo, x int) {
+// drawStripPR draws a single PR entry on the strip, within section. The
+// layout is designed for 200x100 sections: positions scale with the
+// section's height, and how much text fits with its width.
+func (m *Module) drawStripPR(img *image.RGBA, pr PRInfo, section image.Rectangle) {
+	x := section.Min.X
+	y := func(v int) int { return section.Min.Y + v*section.Dy()/100 }
+	chars := func(n int) int { return max(1, n*section.Dx()/200) }
+
 	// Status color (review status)
 	var statusColor color.Color
 	switch pr.Status {
@@ -352,39 +399,85 @@ func (m *Module) drawStripPR(img *image.RGBA, pr PRInfo, x int) {
 		statusColor = colorYellow
 	}
 
-	// Draw status bar on left edge (red if CI failed)
+	// Draw status bar on left edge (red if CI failed, purple if landing,
+	// gray if only my team was asked to review)
 	barColor := statusColor
 	if pr.CI == CIStatusFailed {
 		barColor = colorRed
+	} else if pr.Landing() {
+		barColor = colorPurple
+	} else if pr.TeamRequest {
+		barColor = colorDimGray
 	}
-	barRect := image.Rect(x+4, 15, x+8, 85)
+	barRect := image.Rect(x+4, y(15), x+8, y(85))
 	draw.Draw(img, barRect, &image.Uniform{barColor}, image.Point{}, draw.Src)
 
 	// Draw repo/number (14px)
-	repo := pr.Repo
-	if idx := strings.LastIndex(repo, "/"); idx != -1 {
-		repo = repo[idx+1:]
-	}
-	if len(repo) > 10 {
-		repo = repo[:9] + "."
-	}
-	label := fmt.Sprintf("%s #%d", repo, pr.Number)
-	m.drawText(img, label, x+16, 35, m.stripLabelFace, statusColor)
+	repo := truncate(repoName(pr.Repo), chars(10), ".")
+	label := strings.TrimSpace(fmt.Sprintf("%s #%d", repo, pr.Number))
+	m.drawText(img, label, x+16, y(35), m.stripLabelFace, statusColor)
 
 	// Draw CI indicator
 	ciIndicatorX := x + 16 + font.MeasureString(m.stripLabelFace, label).Ceil() + 5
 	if pr.CI == CIStatusFailed {
-		m.drawText(img, "X", ciIndicatorX, 35, m.stripLabelFace, colorRed)
+		m.drawText(img, "X", ciIndicatorX, y(35), m.stripLabelFace, colorRed)
+	} else if pr.Landing() {
+		iconImg := renderSVGIcon(iconMergeSVG, 16, colorPurple)
+		draw.Draw(img, image.Rect(ciIndicatorX, y(35)-13, ciIndicatorX+16, y(35)+3), iconImg, image.Point{}, draw.Over)
 	} else if pr.CI == CIStatusPassed {
-		m.drawText(img, "+", ciIndicatorX, 35, m.stripLabelFace, colorGreen)
+		m.drawText(img, "+", ciIndicatorX, y(35), m.stripLabelFace, colorGreen)
+	}
+
+	// Mark pinned PRs in the top-right corner of their slot
+	if pr.Pinned {
+		right := section.Max.X
+		drawPinMarker(render.CanvasOf(img), image.Rect(right-16, y(15), right-4, y(15)+12), colorWhite)
 	}
 
 	// Draw title (18px, truncated)
-	title := pr.Title
-	if len(title) > 18 {
-		title = title[:17] + "..."
+	title := truncate(prTitle(pr), chars(18), "...")
+	m.drawText(img, title, x+16, y(60), m.stripTitleFace, colorWhite)
+
+	// Draw the first configured label as a chip under the title
+	if l, ok := shownLabel(pr, m.labels); ok {
+		drawLabelChip(render.CanvasOf(img), l, x+16, y(77), section.Dx()-24, m.labelFace)
+	}
+}
+
+// prTitle returns the PR's title for display, or "#number" if it's empty.
+func prTitle(pr PRInfo) string {
+	if title := strings.Join(strings.Fields(pr.Title), " "); title != "" {
+		return title
+	}
+	return fmt.Sprintf("#%d", pr.Number)
+}
+
+// repoName returns the name part of an "owner/name" repo. A repo that
+// doesn't split cleanly, e.g. with no slash or nothing after it, is
+// returned whole.
+func repoName(repo string) string {
+	repo = strings.TrimSpace(repo)
+	if idx := strings.LastIndex(repo, "/"); idx != -1 {
+		if name := strings.TrimSpace(repo[idx+1:]); name != "" {
+			return name
+		}
+	}
+	return repo
+}
+
+// truncate shortens s to at most max characters, ending it with ellipsis
+// when anything was cut. It counts runes so multi-byte characters aren't
+// split.
+func truncate(s string, max int, ellipsis string) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	keep := max - len([]rune(ellipsis))
+	if keep < 1 {
+		keep = 1
 	}
-	m.drawText(img, title, x+16, 60, m.stripTitleFace, colorWhite)
+	return string(runes[:keep]) + ellipsis
 }
 
 // drawTextCentered draws text horizontally centered at the given position.
@@ -403,7 +496,7 @@ func (m *Module) drawTextCentered(img *image.RGBA, text string, centerX, y int,
 
 // wrapText wraps text to fit within a given character width.
 func wrapText(text string, maxChars int) []string {
-	if len(text) <= maxChars {
+	if utf8.RuneCountInString(text) <= maxChars {
 		return []string{text}
 	}
 
@@ -413,18 +506,18 @@ func wrapText(text string, maxChars int) []string {
 
 	for _, word := range words {
 		if len(currentLine) == 0 {
-			if len(word) > maxChars {
+			if utf8.RuneCountInString(word) > maxChars {
 				// Word too long, truncate
-				lines = append(lines, word[:maxChars-1]+".")
+				lines = append(lines, truncate(word, maxChars, "."))
 				continue
 			}
 			currentLine = word
-		} else if len(currentLine)+1+len(word) <= maxChars {
+		} else if utf8.RuneCountInString(currentLine)+1+utf8.RuneCountInString(word) <= maxChars {
 			currentLine += " " + word
 		} else {
 			lines = append(lines, currentLine)
-			if len(word) > maxChars {
-				currentLine = word[:maxChars-1] + "."
+			if utf8.RuneCountInString(word) > maxChars {
+				currentLine = truncate(word, maxChars, ".")
 			} else {
 				currentLine = word
 			}
@@ -0,0 +1,222 @@
+package github
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestPRTitle(t *testing.T) {
+	for _, tt := range []struct {
+		title string
+		want  string
+	}{
+		{"Fix the thing", "Fix the thing"},
+		{"  Fix\tthe\n thing ", "Fix the thing"},
+		{"", "#42"},
+		{" \n\t", "#42"},
+	} {
+		if got := prTitle(PRInfo{Title: tt.title, Number: 42}); got != tt.want {
+			t.Errorf("prTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestRepoName(t *testing.T) {
+	for repo, want := range map[string]string{
+		"phinze/belowdeck": "belowdeck",
+		"belowdeck":        "belowdeck",
+		"phinze/":          "phinze/",
+		" phinze/ deck ":   "deck",
+		"":                 "",
+	} {
+		if got := repoName(repo); got != want {
+			t.Errorf("repoName(%q) = %q, want %q", repo, got, want)
+		}
+	}
+}
+
+func TestTruncateCountsRunes(t *testing.T) {
+	for _, tt := range []struct {
+		s        string
+		max      int
+		ellipsis string
+		want     string
+	}{
+		{"short", 10, ".", "short"},
+		{"belowdeck-extra", 10, ".", "belowdeck."},
+		{"héllo wörld", 10, "...", "héllo w..."},
+	} {
+		if got := truncate(tt.s, tt.max, tt.ellipsis); got != tt.want {
+			t.Errorf("truncate(%q, %d, %q) = %q, want %q", tt.s, tt.max, tt.ellipsis, got, tt.want)
+		}
+	}
+}
+
+func TestKeysLoadingUntilFirstFetch(t *testing.T) {
+	m := New(device.NewFake())
+	m.resources = module.Resources{Keys: []module.KeyID{3, 4}}
+	m.enabled = true
+	m.initFonts()
+	loading := m.renderLoadingButton(iconSendSVG, "PRs").(*image.RGBA)
+
+	if got := m.RenderKeys()[3].(*image.RGBA); !reflect.DeepEqual(got.Pix, loading.Pix) {
+		t.Error("PR key doesn't show the loading state before the first fetch")
+	}
+
+	// Zero counts once loaded are real data
+	m.mu.Lock()
+	m.loaded = true
+	m.mu.Unlock()
+	if got := m.RenderKeys()[3].(*image.RGBA); reflect.DeepEqual(got.Pix, loading.Pix) {
+		t.Error("PR key still shows the loading state once loaded")
+	}
+}
+
+func TestOverlayStripScalesToStrip(t *testing.T) {
+	m := New(device.NewFake())
+	m.initFonts()
+
+	var prs []PRInfo
+	for i := range stripSections {
+		prs = append(prs, PRInfo{Repo: "phinze/belowdeck", Number: i + 1, Title: "Fix", Status: PRStatusApproved})
+	}
+	rect := image.Rect(0, 0, 1200, 120)
+	img := m.renderOverlayStripWithPRs(rect, prs)
+	if got := img.Bounds(); got != rect {
+		t.Fatalf("overlay strip bounds = %v, want %v", got, rect)
+	}
+
+	// Each PR's status bar sits at the start of its quarter, spanning 15%
+	// to 85% of the height
+	background := rgbaAt(img, 2, 60)
+	for i := range stripSections {
+		x := i*300 + 5
+		if got := rgbaAt(img, x, 60); got != colorGreen {
+			t.Errorf("section %d bar at x=%d is %v, want %v", i, x, got, colorGreen)
+		}
+		if got := rgbaAt(img, x, 17); got != background {
+			t.Errorf("section %d bar starts above 15%% of the height", i)
+		}
+		if got := rgbaAt(img, x, 101); got != colorGreen {
+			t.Errorf("section %d bar ends above 85%% of the height", i)
+		}
+		if got := rgbaAt(img, x+150, 100); got != background {
+			t.Errorf("section %d has a bar mid-section", i)
+		}
+	}
+}
+
+func TestStripSectionAt(t *testing.T) {
+	for _, tt := range []struct {
+		x    float64
+		want int
+	}{
+		{0, 0},
+		{0.249, 0},
+		{0.25, 1},
+		{0.375, 1},
+		{0.99, 3},
+		{1, 3},
+		{-0.1, -1},
+	} {
+		if got := stripSectionAt(tt.x); got != tt.want {
+			t.Errorf("stripSectionAt(%v) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestStripSection(t *testing.T) {
+	rect := image.Rect(100, 0, 700, 80)
+	for i, want := range []image.Rectangle{
+		image.Rect(100, 0, 250, 80),
+		image.Rect(250, 0, 400, 80),
+		image.Rect(400, 0, 550, 80),
+		image.Rect(550, 0, 700, 80),
+	} {
+		if got := stripSection(rect, i); got != want {
+			t.Errorf("stripSection(%v, %d) = %v, want %v", rect, i, got, want)
+		}
+	}
+}
+
+// colorBounds returns how many of img's pixels are exactly col, and the
+// bounds they span.
+func colorBounds(img image.Image, col color.RGBA) (n int, bounds image.Rectangle) {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if rgbaAt(img, x, y) == col {
+				n++
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return n, bounds
+}
+
+func TestPRStatsModes(t *testing.T) {
+	m := New(device.NewFake())
+	m.resources = module.Resources{Keys: []module.KeyID{3, 4}}
+	m.enabled = true
+	m.initFonts()
+	m.mu.Lock()
+	m.loaded = true
+	m.stats = PRStats{WaitingForReview: 2, Approved: 1, ChangesRequested: 3}
+	m.mu.Unlock()
+
+	// Detailed shows a row, with its colored dot, for each status
+	detailed := m.RenderKeys()[3]
+	for name, col := range map[string]color.RGBA{"waiting": colorYellow, "approved": colorGreen, "changes requested": colorOrange} {
+		if n, bounds := colorBounds(detailed, col); n == 0 || bounds.Dy() > 8 {
+			t.Errorf("detailed %s dot covers %d pixels in %v, want a small dot", name, n, bounds)
+		}
+	}
+
+	// Compact shows only the most urgent count, large
+	m.statsMode = StatsCompact
+	compact := m.RenderKeys()[3]
+	if n, _ := colorBounds(compact, colorYellow); n != 0 {
+		t.Error("compact key shows the waiting count as well as the most urgent")
+	}
+	if n, _ := colorBounds(compact, colorGreen); n != 0 {
+		t.Error("compact key shows the approved count as well as the most urgent")
+	}
+	if _, bounds := colorBounds(compact, colorOrange); bounds.Dy() < 16 || bounds.Dx() > keySize/2 {
+		t.Errorf("compact count spans %v, want a single large number", bounds)
+	}
+
+	// CI failures are the most urgent of all
+	m.mu.Lock()
+	m.stats.CIFailed = 1
+	m.mu.Unlock()
+	compact = m.RenderKeys()[3]
+	if n, _ := colorBounds(compact, colorOrange); n != 0 {
+		t.Error("compact key shows changes requested over CI failures")
+	}
+	if _, bounds := colorBounds(compact, colorRed); bounds.Dy() < 16 {
+		t.Errorf("compact failure count spans %v, want a single large number", bounds)
+	}
+}
+
+func TestParseStatsMode(t *testing.T) {
+	for _, tt := range []struct {
+		v       string
+		want    StatsMode
+		wantErr bool
+	}{
+		{"", StatsDetailed, false},
+		{"detailed", StatsDetailed, false},
+		{"compact", StatsCompact, false},
+		{"tiny", StatsDetailed, true},
+	} {
+		got, err := parseStatsMode(tt.v)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseStatsMode(%q) = %v, %v; want %v, error %v", tt.v, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package homeassistant
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+//go:embed icons/power.svg
+var iconPowerSVG string
+
+// colorAllOffArmed is the all-off key while it waits for a confirming press.
+var colorAllOffArmed = color.RGBA{220, 80, 70, 255}
+
+// allOffConfirmWindow is how long a confirming press of the all-off key
+// has after the first.
+const allOffConfirmWindow = 3 * time.Second
+
+// loadAllOffConfig reads the all-off key settings into config: whether the
+// key is shown, whether it asks for confirmation, and the entities it turns
+// off, which are the configured lights followed by HASS_ALL_OFF_ENTITIES.
+func loadAllOffConfig(config *Config) error {
+	for _, b := range []struct {
+		name string
+		dst  *bool
+	}{
+		{"HASS_ALL_OFF_KEY", &config.AllOffKey},
+		{"HASS_ALL_OFF_CONFIRM", &config.AllOffConfirm},
+	} {
+		if v := os.Getenv(b.name); v != "" {
+			on, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %q", b.name, v)
+			}
+			*b.dst = on
+		}
+	}
+
+	seen := make(map[string]bool)
+	add := func(entity string) {
+		if entity != "" && !seen[entity] {
+			seen[entity] = true
+			config.AllOffEntities = append(config.AllOffEntities, entity)
+		}
+	}
+	add(config.RingLightEntity)
+	add(config.OfficeLightEntity)
+	add(config.BusyLightEntity)
+
+	if v := os.Getenv("HASS_ALL_OFF_ENTITIES"); v != "" {
+		for _, entity := range strings.Split(v, ",") {
+			entity = strings.TrimSpace(entity)
+			if !strings.Contains(entity, ".") {
+				return fmt.Errorf("invalid HASS_ALL_OFF_ENTITIES: %q is not an entity ID", entity)
+			}
+			add(entity)
+		}
+	}
+	return nil
+}
+
+// offService returns the service that turns entity off. Scenes and scripts
+// can't be turned off, so they're turned on instead, e.g. a "lights out"
+// scene.
+func offService(entity string) (domain, service string) {
+	domain, _, _ = strings.Cut(entity, ".")
+	switch domain {
+	case "scene", "script":
+		return domain, "turn_on"
+	}
+	return domain, "turn_off"
+}
+
+// allOff turns off every entity in AllOffEntities, carrying on past
+// failures and returning them together.
+func (m *Module) allOff() error {
+	log.Printf("Turning off %d entities...", len(m.config.AllOffEntities))
+
+	var errs []error
+	for _, entity := range m.config.AllOffEntities {
+		domain, service := offService(entity)
+		err := m.client.CallService(m.Context(), domain, service, map[string]any{
+			"entity_id": entity,
+		})
+		if err != nil {
+			log.Printf("Failed to call %s.%s for %s: %v", domain, service, entity, err)
+			errs = append(errs, fmt.Errorf("%s: %w", entity, err))
+		}
+	}
+
+	m.booster.Boost(m.Context())
+	return errors.Join(errs...)
+}
+
+// allOffKey returns the all-off key, the first after the sensor keys,
+// reporting false if it's not enabled or the module has no key for it.
+func (m *Module) allOffKey() (module.KeyID, bool) {
+	i := 2 + len(m.config.Sensors)
+	if !m.config.AllOffKey || i >= len(m.resources.Keys) {
+		return 0, false
+	}
+	return m.resources.Keys[i], true
+}
+
+// handleAllOffKey runs the all-off key on press. With confirmation on, the
+// first press arms the key and a second within allOffConfirmWindow turns
+// everything off.
+func (m *Module) handleAllOffKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+
+	if m.config.AllOffConfirm && !m.disarmAllOff() {
+		m.mu.Lock()
+		m.allOffArmedUntil = time.Now().Add(allOffConfirmWindow)
+		m.mu.Unlock()
+		log.Println("All off: press again to confirm")
+		return nil
+	}
+
+	err := m.allOff()
+	m.FlashResult(id, err)
+	return err
+}
+
+// disarmAllOff disarms the all-off key, reporting whether it was armed.
+func (m *Module) disarmAllOff() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	armed := time.Now().Before(m.allOffArmedUntil)
+	m.allOffArmedUntil = time.Time{}
+	return armed
+}
+
+// allOffArmed reports whether the all-off key is waiting for a confirming
+// press.
+func (m *Module) allOffArmed() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Now().Before(m.allOffArmedUntil)
+}
+
+// renderAllOffButton renders the all-off key, red and asking for
+// confirmation while armed.
+func (m *Module) renderAllOffButton() image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconColor, labelText := colorGray, "All Off"
+	if m.allOffArmed() {
+		iconColor, labelText = colorAllOffArmed, "Confirm?"
+	}
+
+	// Draw icon in upper portion
+	iconImg := renderSVGIcon(iconPowerSVG, c.Px(40), iconColor)
+	iconX := (keySize - 40) / 2
+	iconY := 8
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
+
+	// Draw label at bottom
+	c.DrawStringCentered(labelText, keySize/2, 62, m.labelFace, colorWhite)
+
+	return c.Image()
+}
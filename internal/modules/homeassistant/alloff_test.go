@@ -0,0 +1,88 @@
+package homeassistant
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestOffService(t *testing.T) {
+	for entity, want := range map[string]string{
+		"light.office":      "light.turn_off",
+		"switch.fan":        "switch.turn_off",
+		"scene.lights_out":  "scene.turn_on",
+		"script.end_of_day": "script.turn_on",
+	} {
+		if domain, service := offService(entity); domain+"."+service != want {
+			t.Errorf("offService(%q) = %s.%s, want %s", entity, domain, service, want)
+		}
+	}
+}
+
+func TestLoadAllOffConfig(t *testing.T) {
+	t.Setenv("HASS_ALL_OFF_KEY", "true")
+	t.Setenv("HASS_ALL_OFF_ENTITIES", "switch.fan, light.office ,scene.lights_out")
+	config := Config{RingLightEntity: "light.ring", OfficeLightEntity: "light.office"}
+	if err := loadAllOffConfig(&config); err != nil {
+		t.Fatalf("loadAllOffConfig: %v", err)
+	}
+	if !config.AllOffKey || config.AllOffConfirm {
+		t.Errorf("AllOffKey, AllOffConfirm = %v, %v; want true, false", config.AllOffKey, config.AllOffConfirm)
+	}
+	want := []string{"light.ring", "light.office", "switch.fan", "scene.lights_out"}
+	if !slices.Equal(config.AllOffEntities, want) {
+		t.Errorf("AllOffEntities = %q, want %q", config.AllOffEntities, want)
+	}
+
+	t.Setenv("HASS_ALL_OFF_ENTITIES", "fan")
+	if err := loadAllOffConfig(&Config{}); err == nil {
+		t.Error("entity without a domain accepted")
+	}
+}
+
+// newAllOffModule returns a module with the all-off key on key 3, turning
+// off entities.
+func newAllOffModule(t *testing.T, confirm bool, entities ...string) (*Module, func() []string) {
+	t.Helper()
+	m, srv := newKeyModule(t, Config{AllOffKey: true, AllOffConfirm: confirm, AllOffEntities: entities})
+	m.resources = module.Resources{Keys: []module.KeyID{1, 2, 3}}
+	m.BaseModule.Init(context.Background(), m.resources)
+	if key, ok := m.allOffKey(); !ok || key != 3 {
+		t.Fatalf("allOffKey() = %d, %v; want key 3", key, ok)
+	}
+	return m, func() []string { return servicesCalled(srv) }
+}
+
+func TestAllOffKeyCallsEachEntity(t *testing.T) {
+	m, called := newAllOffModule(t, false, "light.ring", "switch.fan", "scene.lights_out")
+
+	if err := m.HandleKey(3, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatalf("HandleKey: %v", err)
+	}
+	want := []string{"/api/services/light/turn_off", "/api/services/switch/turn_off", "/api/services/scene/turn_on"}
+	if got := called(); !slices.Equal(got, want) {
+		t.Errorf("called %q, want %q", got, want)
+	}
+}
+
+func TestAllOffKeyConfirm(t *testing.T) {
+	m, called := newAllOffModule(t, true, "light.ring")
+
+	m.HandleKey(3, module.KeyEvent{Pressed: true})
+	if got := called(); len(got) != 0 {
+		t.Fatalf("first press called %q, want nothing until confirmed", got)
+	}
+	if !m.allOffArmed() {
+		t.Fatal("key not armed after the first press")
+	}
+
+	m.HandleKey(3, module.KeyEvent{Pressed: true})
+	if got, want := called(), []string{"/api/services/light/turn_off"}; !slices.Equal(got, want) {
+		t.Errorf("confirming press called %q, want %q", got, want)
+	}
+	if m.allOffArmed() {
+		t.Error("key still armed after confirming")
+	}
+}
@@ -4,18 +4,58 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
-	"time"
+
+	"github.com/phinze/belowdeck/internal/httpclient"
 )
 
+// ErrEntityNotFound is returned for an entity Home Assistant doesn't have,
+// usually a mistyped entity ID.
+var ErrEntityNotFound = errors.New("homeassistant: entity not found")
+
 // LightState represents the state of a light entity.
 type LightState struct {
 	On         bool
 	Brightness uint8 // 0-255
 }
 
+// EntityState is the state of any entity, as returned by the states API.
+type EntityState struct {
+	EntityID   string         `json:"entity_id"`
+	State      string         `json:"state"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// Unit returns the entity's unit of measurement, e.g. "°C" or "ppm", or ""
+// if it has none.
+func (s EntityState) Unit() string {
+	unit, _ := s.Attributes["unit_of_measurement"].(string)
+	return unit
+}
+
+// FriendlyName returns the entity's display name, falling back to its ID.
+func (s EntityState) FriendlyName() string {
+	if name, ok := s.Attributes["friendly_name"].(string); ok && name != "" {
+		return name
+	}
+	return s.EntityID
+}
+
+// Number returns the state as a number, for numeric sensors. It reports
+// false for non-numeric states such as "on" or "unavailable".
+func (s EntityState) Number() (float64, bool) {
+	n, err := strconv.ParseFloat(s.State, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
 // Client is a Home Assistant API client.
 type Client struct {
 	baseURL    string
@@ -24,16 +64,19 @@ type Client struct {
 }
 
 // NewClient creates a new Home Assistant API client.
-func NewClient(baseURL, token string) *Client {
+// If httpClient is nil, a client using the shared transport is created.
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
 	// Ensure baseURL doesn't have trailing slash
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
+	if httpClient == nil {
+		httpClient = httpclient.New()
+	}
+
 	return &Client{
-		baseURL: baseURL,
-		token:   token,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: httpClient,
 	}
 }
 
@@ -71,44 +114,51 @@ func (c *Client) CallService(ctx context.Context, domain, service string, data m
 	return nil
 }
 
-// GetLightState fetches the current state of a light entity.
-func (c *Client) GetLightState(ctx context.Context, entityID string) (LightState, error) {
+// GetState fetches the current state of any entity.
+func (c *Client) GetState(ctx context.Context, entityID string) (EntityState, error) {
 	url := fmt.Sprintf("%s/api/states/%s", c.baseURL, entityID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return LightState{}, fmt.Errorf("failed to create request: %w", err)
+		return EntityState{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return LightState{}, fmt.Errorf("request failed: %w", err)
+		return EntityState{}, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return EntityState{}, fmt.Errorf("%s: %w", entityID, ErrEntityNotFound)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return LightState{}, fmt.Errorf("API error: %s", resp.Status)
+		return EntityState{}, fmt.Errorf("API error: %s", resp.Status)
 	}
 
-	var data struct {
-		State      string `json:"state"`
-		Attributes struct {
-			Brightness *int `json:"brightness"`
-		} `json:"attributes"`
+	var state EntityState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return EntityState{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return LightState{}, fmt.Errorf("failed to decode response: %w", err)
+	return state, nil
+}
+
+// GetLightState fetches the current state of a light entity.
+func (c *Client) GetLightState(ctx context.Context, entityID string) (LightState, error) {
+	entity, err := c.GetState(ctx, entityID)
+	if err != nil {
+		return LightState{}, err
 	}
 
 	state := LightState{
-		On: data.State == "on",
+		On: entity.State == "on",
 	}
 
-	if data.Attributes.Brightness != nil {
-		state.Brightness = uint8(*data.Attributes.Brightness)
+	if brightness, ok := entity.Attributes["brightness"].(float64); ok {
+		state.Brightness = uint8(brightness)
 	}
 
 	return state, nil
@@ -0,0 +1,30 @@
+package homeassistant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+func TestGetLightState(t *testing.T) {
+	srv := stubserver.New(t)
+	srv.JSON("GET /api/states/light.desk", map[string]any{
+		"entity_id":  "light.desk",
+		"state":      "on",
+		"attributes": map[string]any{"brightness": 128},
+	})
+
+	client := NewClient(srv.URL+"/", "token", nil)
+	state, err := client.GetLightState(context.Background(), "light.desk")
+	if err != nil {
+		t.Fatalf("GetLightState: %v", err)
+	}
+	if !state.On || state.Brightness != 128 {
+		t.Errorf("state = %+v, want on at 128", state)
+	}
+
+	if got := srv.Requests()[0].Header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+	}
+}
@@ -0,0 +1,37 @@
+package homeassistant
+
+import (
+	"log"
+
+	"github.com/phinze/belowdeck/internal/bus"
+)
+
+// subscribeAway turns the busy light on while the deck is away and off when
+// it's back. Does nothing unless BusyLightEntity is set.
+func (m *Module) subscribeAway() {
+	b := m.Bus()
+	if b == nil || m.config.BusyLightEntity == "" {
+		return
+	}
+	b.SubscribeContext(m.Context(), bus.TopicAway, func(e bus.Event) {
+		if away, ok := e.Data.(bus.Away); ok {
+			go m.setBusyLight(away.On)
+		}
+	})
+}
+
+// setBusyLight turns the busy light on or off.
+func (m *Module) setBusyLight(on bool) {
+	service := "turn_off"
+	if on {
+		service = "turn_on"
+	}
+	log.Printf("Busy light %s...", service)
+
+	err := m.client.CallService(m.Context(), "light", service, map[string]any{
+		"entity_id": m.config.BusyLightEntity,
+	})
+	if err != nil {
+		log.Printf("Failed to %s busy light: %v", service, err)
+	}
+}
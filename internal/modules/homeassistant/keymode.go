@@ -0,0 +1,66 @@
+package homeassistant
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// KeyMode is how a key binding acts on its entity.
+type KeyMode string
+
+const (
+	// KeyToggle flips the entity on each press.
+	KeyToggle KeyMode = "toggle"
+	// KeyMomentary turns the entity on while the key is held and off on
+	// release, e.g. for push-to-talk.
+	KeyMomentary KeyMode = "momentary"
+)
+
+// keyModeEnv reads a key mode from the named environment variable,
+// defaulting to toggle.
+func keyModeEnv(name string) (KeyMode, error) {
+	switch v := os.Getenv(name); KeyMode(v) {
+	case "", KeyToggle:
+		return KeyToggle, nil
+	case KeyMomentary:
+		return KeyMomentary, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q (want \"toggle\" or \"momentary\")", name, v)
+	}
+}
+
+// handleBinding runs a key event for a binding in the given mode: toggle
+// acts on press only, momentary sets the entity on at press and off at
+// release. The key flashes with each action's result, and states are
+// fetched a few extra times after one succeeds.
+func (m *Module) handleBinding(id module.KeyID, event module.KeyEvent, mode KeyMode, toggle func() error, set func(on bool) error) error {
+	var err error
+	switch {
+	case mode == KeyMomentary:
+		err = set(event.Pressed)
+	case event.Pressed:
+		err = toggle()
+	default:
+		return nil
+	}
+	m.FlashResult(id, err)
+	if err == nil {
+		m.booster.Boost(m.Context())
+	}
+	return err
+}
+
+// HoldsKey reports whether the given key is bound in momentary mode, so it
+// gets its press as it happens rather than at release.
+func (m *Module) HoldsKey(id module.KeyID) bool {
+	keys := m.resources.Keys
+	switch {
+	case len(keys) > 0 && id == keys[0]:
+		return m.config.OfficeMode == KeyMomentary
+	case len(keys) > 1 && id == keys[1]:
+		return m.config.RingLightMode == KeyMomentary
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package homeassistant
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+// newKeyModule returns an enabled module on keys 1 and 2 whose service
+// calls go to a stub server.
+func newKeyModule(t *testing.T, config Config) (*Module, *stubserver.Server) {
+	t.Helper()
+	srv := stubserver.New(t)
+	srv.JSON("POST /api/services/{domain}/{service}", []any{})
+
+	m := New(device.NewFake())
+	m.config = config
+	m.client = NewClient(srv.URL, "token", nil)
+	m.resources = module.Resources{Keys: []module.KeyID{1, 2}}
+	m.enabled = true
+	return m, srv
+}
+
+// servicesCalled returns the paths of the service calls srv received.
+func servicesCalled(srv *stubserver.Server) []string {
+	var paths []string
+	for _, req := range srv.Requests() {
+		paths = append(paths, req.URL.Path)
+	}
+	return paths
+}
+
+func TestMomentaryKeyHoldsEntityOn(t *testing.T) {
+	m, srv := newKeyModule(t, Config{RingLightEntity: "light.ring", RingLightMode: KeyMomentary})
+
+	m.HandleKey(2, module.KeyEvent{Pressed: true})
+	m.HandleKey(2, module.KeyEvent{Pressed: false, Duration: 3 * time.Second})
+
+	want := []string{"/api/services/light/turn_on", "/api/services/light/turn_off"}
+	if got := servicesCalled(srv); !slices.Equal(got, want) {
+		t.Errorf("called %q, want %q", got, want)
+	}
+	if !m.HoldsKey(2) {
+		t.Error("momentary key not reported as held")
+	}
+}
+
+func TestToggleKeyActsOnPress(t *testing.T) {
+	m, srv := newKeyModule(t, Config{RingLightEntity: "light.ring", RingLightMode: KeyToggle})
+
+	m.HandleKey(2, module.KeyEvent{Pressed: true})
+	m.HandleKey(2, module.KeyEvent{Pressed: false})
+
+	want := []string{"/api/services/light/toggle"}
+	if got := servicesCalled(srv); !slices.Equal(got, want) {
+		t.Errorf("called %q, want %q", got, want)
+	}
+	if m.HoldsKey(2) {
+		t.Error("toggle key reported as held")
+	}
+}
+
+func TestKeyModeEnv(t *testing.T) {
+	for v, want := range map[string]KeyMode{"": KeyToggle, "toggle": KeyToggle, "momentary": KeyMomentary} {
+		t.Setenv("HA_TEST_MODE", v)
+		if got, err := keyModeEnv("HA_TEST_MODE"); err != nil || got != want {
+			t.Errorf("keyModeEnv(%q) = %q, %v; want %q", v, got, err, want)
+		}
+	}
+	t.Setenv("HA_TEST_MODE", "hold")
+	if _, err := keyModeEnv("HA_TEST_MODE"); err == nil {
+		t.Error("keyModeEnv accepted \"hold\"")
+	}
+}
+
+func TestKeyActionBoostsPolling(t *testing.T) {
+	m, _ := newKeyModule(t, Config{RingLightEntity: "light.ring", RingLightMode: KeyToggle})
+	m.BaseModule.Init(context.Background(), m.resources)
+	fetches := make(chan struct{}, 10)
+	m.booster = module.Booster{
+		Fetch:    func(ctx context.Context) { fetches <- struct{}{} },
+		Polls:    2,
+		Interval: time.Millisecond,
+	}
+
+	m.HandleKey(2, module.KeyEvent{Pressed: true})
+	for i := range 2 {
+		select {
+		case <-fetches:
+		case <-time.After(time.Second):
+			t.Fatalf("got %d boosted fetches after a toggle, want 2", i)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package homeassistant
+
+import (
+	"context"
+	"image"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+func TestLightKeyLoadingUntilFirstFetch(t *testing.T) {
+	m, srv := newKeyModule(t, Config{OfficeLightEntity: "light.office"})
+	m.initFonts()
+	captureLog(t)
+	loading := m.renderLoadingButton(iconLampDeskSVG).(*image.RGBA)
+
+	if got := m.renderOfficeTimeButton().(*image.RGBA); !reflect.DeepEqual(got.Pix, loading.Pix) {
+		t.Error("office key doesn't show the loading state before the first fetch")
+	}
+
+	// A failed fetch isn't a first fetch
+	var up atomic.Bool
+	srv.Handle("GET /api/states/{entity}", func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		stubserver.WriteJSON(w, map[string]any{"state": "off"})
+	})
+	m.fetchOfficeLightState(context.Background())
+	if got := m.renderOfficeTimeButton().(*image.RGBA); !reflect.DeepEqual(got.Pix, loading.Pix) {
+		t.Error("office key left the loading state after a failed fetch")
+	}
+
+	up.Store(true)
+	m.fetchOfficeLightState(context.Background())
+	if got := m.renderOfficeTimeButton().(*image.RGBA); reflect.DeepEqual(got.Pix, loading.Pix) {
+		t.Error("office key still shows the loading state after a fetch")
+	}
+}
@@ -0,0 +1,67 @@
+package homeassistant
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"log"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// colorMissing labels keys whose entity Home Assistant doesn't have.
+var colorMissing = color.RGBA{220, 80, 70, 255}
+
+// recordFetch records the result of fetching the state of entity, a name
+// such as "ring light", reporting whether it succeeded. An entity Home
+// Assistant doesn't have is marked missing, so its key says so rather than
+// showing a stale or "off" state, and logged only once rather than every
+// poll. Other errors are logged each time.
+func (m *Module) recordFetch(name, entity string, err error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if errors.Is(err, ErrEntityNotFound) {
+		if !m.missing[entity] {
+			log.Printf("Home Assistant has no %s entity %s; check the configured entity ID", name, entity)
+			m.missing[entity] = true
+		}
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to fetch %s %s state: %v", name, entity, err)
+		return false
+	}
+
+	if m.missing[entity] {
+		log.Printf("Home Assistant %s entity %s found", name, entity)
+		delete(m.missing, entity)
+	}
+	return true
+}
+
+// isMissing reports whether Home Assistant didn't have entity when last
+// fetched.
+func (m *Module) isMissing(entity string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.missing[entity]
+}
+
+// renderMissingButton renders the key of an entity Home Assistant doesn't
+// have: the icon dimmed, labeled as missing.
+func (m *Module) renderMissingButton(svg string) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconImg := renderSVGIcon(svg, c.Px(40), colorDimGray)
+	iconX := (keySize - 40) / 2
+	iconY := 8
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
+
+	c.DrawStringCentered("Missing", keySize/2, 62, m.labelFace, colorMissing)
+
+	return c.Image()
+}
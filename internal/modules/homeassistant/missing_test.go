@@ -0,0 +1,59 @@
+package homeassistant
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+// captureLog sends the log to a buffer until the test ends.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(prev) })
+	return &buf
+}
+
+func TestGetStateNotFound(t *testing.T) {
+	srv := stubserver.New(t)
+
+	client := NewClient(srv.URL, "token", nil)
+	if _, err := client.GetState(context.Background(), "light.rign"); !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("err = %v, want ErrEntityNotFound", err)
+	}
+}
+
+func TestMissingEntityRendersMissingAndLogsOnce(t *testing.T) {
+	srv := stubserver.New(t)
+	m := New(device.NewFake())
+	m.config = Config{RingLightEntity: "light.rign", OfficeLightEntity: "light.office"}
+	m.client = NewClient(srv.URL, "token", nil)
+	m.initFonts()
+	logs := captureLog(t)
+
+	for range 3 {
+		m.fetchRingLightState(context.Background())
+	}
+
+	if got := strings.Count(logs.String(), "light.rign"); got != 1 {
+		t.Errorf("missing entity logged %d times, want once:\n%s", got, logs)
+	}
+	if !m.isMissing("light.rign") {
+		t.Fatal("entity not marked missing after a 404")
+	}
+	got := m.renderRingLightButton().(*image.RGBA)
+	want := m.renderMissingButton(iconCircleSVG).(*image.RGBA)
+	if !reflect.DeepEqual(got.Pix, want.Pix) {
+		t.Error("ring light key doesn't show the missing state")
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"image"
 	"log"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/httpclient"
 	"github.com/phinze/belowdeck/internal/module"
 	"golang.org/x/image/font"
 )
@@ -21,8 +23,40 @@ type Config struct {
 	Token             string
 	RingLightEntity   string
 	OfficeLightEntity string
+
+	// BusyLightEntity is an optional light turned on while the deck is
+	// away, e.g. a lamp outside the office door.
+	BusyLightEntity string
+
+	// RingLightMode and OfficeMode set whether each key toggles on press or
+	// is momentary, on only while held.
+	RingLightMode KeyMode
+	OfficeMode    KeyMode
+
+	// Sensors are shown on the keys after the office and ring light keys.
+	Sensors []SensorBinding
+
+	// BoostPolls is how many times states are fetched after an action,
+	// BoostInterval after it and then at doubling intervals, so the keys
+	// catch up before the next regular poll. Zero disables boosting.
+	BoostPolls    int
+	BoostInterval time.Duration
+
+	// BrightnessAccel speeds up the brightness dial when it's spun quickly.
+	// Nil leaves every tick at the same step.
+	BrightnessAccel *module.Acceleration
+
+	// AllOffKey shows a key after the sensors that turns off
+	// AllOffEntities, asking for a second press first if AllOffConfirm is
+	// set.
+	AllOffKey      bool
+	AllOffConfirm  bool
+	AllOffEntities []string
 }
 
+// refreshCooldown is the minimum time between on-demand refreshes.
+const refreshCooldown = 2 * time.Second
+
 // Module implements the Home Assistant control module.
 type Module struct {
 	module.BaseModule
@@ -32,23 +66,44 @@ type Module struct {
 	client  *Client
 	enabled bool
 
-	// State
-	mu               sync.RWMutex
-	ringLightState   LightState
-	officeLightState LightState
+	// State. The loaded flags are false until each light's first
+	// successful fetch, so its key shows a loading state rather than "off".
+	mu                sync.RWMutex
+	ringLightState    LightState
+	officeLightState  LightState
+	ringLightLoaded   bool
+	officeLightLoaded bool
+
+	// Sensor states by entity ID, present once fetched
+	sensorStates map[string]EntityState
+
+	// Entities Home Assistant didn't have when last fetched
+	missing map[string]bool
+
+	// Until when the all-off key waits for a confirming press
+	allOffArmedUntil time.Time
 
 	// Fonts
 	labelFace font.Face
+	valueFace font.Face
 
 	// Resources
 	resources module.Resources
+
+	// On-demand refreshes from a long key hold
+	refresher module.Refresher
+
+	// Extra fetches after actions
+	booster module.Booster
 }
 
 // New creates a new Home Assistant module.
 func New(dev device.Device) *Module {
 	return &Module{
-		BaseModule: module.NewBaseModule("homeassistant"),
-		device:     dev,
+		BaseModule:   module.NewBaseModule("homeassistant"),
+		device:       dev,
+		sensorStates: make(map[string]EntityState),
+		missing:      make(map[string]bool),
 	}
 }
 
@@ -76,16 +131,32 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	m.config = config
 	m.enabled = true
 
+	if extra := len(m.config.Sensors) - max(len(res.Keys)-2, 0); extra > 0 {
+		log.Printf("Home Assistant: %d sensors configured without a key, extra sensors hidden", extra)
+	}
+	if _, ok := m.allOffKey(); m.config.AllOffKey && !ok {
+		log.Printf("Home Assistant: no key left for all off after the sensors, key hidden")
+	}
+
 	// Create API client
-	m.client = NewClient(m.config.URL, m.config.Token)
+	m.client = NewClient(m.config.URL, m.config.Token, httpclient.New())
 
 	// Initialize fonts
-	if err := m.initFonts(); err != nil {
-		return err
+	m.initFonts()
+
+	// Start state polling, with refreshes on demand
+	module.Supervise(ctx, "homeassistant poll", m.pollState)
+	m.refresher = module.Refresher{Fetch: m.fetchStates, Cooldown: refreshCooldown}
+	m.booster = module.Booster{
+		Fetch:    m.fetchStates,
+		Polls:    m.config.BoostPolls,
+		Interval: m.config.BoostInterval,
 	}
 
-	// Start state polling
-	go m.pollState(ctx)
+	// Follow the away state with the busy light if configured, and call
+	// services for other modules
+	m.subscribeAway()
+	m.subscribeServiceCalls()
 
 	log.Printf("Home Assistant module initialized (url=%s)", m.config.URL)
 	return nil
@@ -94,8 +165,7 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 // pollState periodically fetches entity states from Home Assistant.
 func (m *Module) pollState(ctx context.Context) {
 	// Initial fetch
-	m.fetchRingLightState(ctx)
-	m.fetchOfficeLightState(ctx)
+	m.fetchStates(ctx)
 
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
@@ -105,22 +175,42 @@ func (m *Module) pollState(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			m.fetchRingLightState(ctx)
-			m.fetchOfficeLightState(ctx)
+			// Skip fetches while snoozed
+			if m.IsSnoozed() {
+				continue
+			}
+			m.fetchStates(ctx)
 		}
 	}
 }
 
+// fetchStates fetches the state of every light and sensor shown on the
+// deck.
+func (m *Module) fetchStates(ctx context.Context) {
+	m.fetchRingLightState(ctx)
+	m.fetchOfficeLightState(ctx)
+	m.fetchSensorStates(ctx)
+}
+
+// Refresh fetches light states now rather than at the next poll. Refreshes
+// are limited to one per refreshCooldown.
+func (m *Module) Refresh() {
+	if !m.enabled {
+		return
+	}
+	m.refresher.Trigger(m.Context())
+}
+
 // fetchRingLightState fetches the current ring light state.
 func (m *Module) fetchRingLightState(ctx context.Context) {
 	state, err := m.client.GetLightState(ctx, m.config.RingLightEntity)
-	if err != nil {
-		log.Printf("Failed to fetch ring light state: %v", err)
+	if !m.recordFetch("ring light", m.config.RingLightEntity, err) {
 		return
 	}
 
 	m.mu.Lock()
 	m.ringLightState = state
+	m.ringLightLoaded = true
 	m.mu.Unlock()
 }
 
@@ -134,13 +224,13 @@ func (m *Module) getRingLightState() LightState {
 // fetchOfficeLightState fetches the current office light state.
 func (m *Module) fetchOfficeLightState(ctx context.Context) {
 	state, err := m.client.GetLightState(ctx, m.config.OfficeLightEntity)
-	if err != nil {
-		log.Printf("Failed to fetch office light state: %v", err)
+	if !m.recordFetch("office light", m.config.OfficeLightEntity, err) {
 		return
 	}
 
 	m.mu.Lock()
 	m.officeLightState = state
+	m.officeLightLoaded = true
 	m.mu.Unlock()
 }
 
@@ -151,6 +241,14 @@ func (m *Module) getOfficeLightState() LightState {
 	return m.officeLightState
 }
 
+// lightsLoaded reports whether the ring and office light states have each
+// been fetched at least once.
+func (m *Module) lightsLoaded() (ring, office bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ringLightLoaded, m.officeLightLoaded
+}
+
 // Stop shuts down the module.
 func (m *Module) Stop() error {
 	return m.BaseModule.Stop()
@@ -179,12 +277,58 @@ func loadConfig() (Config, error) {
 		officeLightEntity = "light.signe_gradient_floor_1"
 	}
 
-	return Config{
+	ringLightMode, err := keyModeEnv("HASS_RING_LIGHT_MODE")
+	if err != nil {
+		return Config{}, err
+	}
+	officeMode, err := keyModeEnv("HASS_OFFICE_MODE")
+	if err != nil {
+		return Config{}, err
+	}
+
+	sensors, err := parseSensors(os.Getenv("HASS_SENSORS"))
+	if err != nil {
+		return Config{}, err
+	}
+
+	boostPolls := 3
+	if v := os.Getenv("HASS_BOOST_POLLS"); v != "" {
+		boostPolls, err = strconv.Atoi(v)
+		if err != nil || boostPolls < 0 {
+			return Config{}, fmt.Errorf("invalid HASS_BOOST_POLLS: %q", v)
+		}
+	}
+
+	boostInterval := 250 * time.Millisecond
+	if v := os.Getenv("HASS_BOOST_INTERVAL"); v != "" {
+		boostInterval, err = time.ParseDuration(v)
+		if err != nil || boostInterval <= 0 {
+			return Config{}, fmt.Errorf("invalid HASS_BOOST_INTERVAL: %q", v)
+		}
+	}
+
+	brightnessAccel, err := module.ParseAcceleration(os.Getenv("HASS_BRIGHTNESS_ACCEL"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid HASS_BRIGHTNESS_ACCEL: %w", err)
+	}
+
+	config := Config{
 		URL:               url,
 		Token:             token,
 		RingLightEntity:   ringLightEntity,
 		OfficeLightEntity: officeLightEntity,
-	}, nil
+		BusyLightEntity:   os.Getenv("HASS_BUSY_LIGHT_ENTITY"),
+		RingLightMode:     ringLightMode,
+		OfficeMode:        officeMode,
+		Sensors:           sensors,
+		BoostPolls:        boostPolls,
+		BoostInterval:     boostInterval,
+		BrightnessAccel:   brightnessAccel,
+	}
+	if err := loadAllOffConfig(&config); err != nil {
+		return Config{}, err
+	}
+	return config, nil
 }
 
 // RenderKeys returns images for the module's keys.
@@ -205,6 +349,16 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 		keys[m.resources.Keys[1]] = m.renderRingLightButton()
 	}
 
+	// Remaining keys: sensors
+	for i, key := range m.sensorKeys() {
+		keys[key] = m.renderSensorButton(m.config.Sensors[i])
+	}
+
+	// Then the all-off key, if enabled
+	if key, ok := m.allOffKey(); ok {
+		keys[key] = m.renderAllOffButton()
+	}
+
 	return keys
 }
 
@@ -219,50 +373,64 @@ func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
 		return nil
 	}
 
-	// Only trigger on key press, not release
-	if !event.Pressed {
-		return nil
-	}
-
-	// Key 0: Office toggle button
+	// Key 0: Office button
 	if len(m.resources.Keys) > 0 && id == m.resources.Keys[0] {
-		return m.toggleOfficeMode()
+		return m.handleBinding(id, event, m.config.OfficeMode, m.toggleOfficeMode, m.setOfficeMode)
 	}
 
-	// Key 1: Ring Light toggle
+	// Key 1: Ring Light button
 	if len(m.resources.Keys) > 1 && id == m.resources.Keys[1] {
-		return m.toggleRingLight()
+		return m.handleBinding(id, event, m.config.RingLightMode, m.toggleRingLight, m.setRingLight)
+	}
+
+	if key, ok := m.allOffKey(); ok && id == key {
+		return m.handleAllOffKey(id, event)
 	}
 
 	return nil
 }
 
+// HandleAction runs an external action: "all-off" turns off every all-off
+// entity, without asking for confirmation.
+func (m *Module) HandleAction(action string) error {
+	if !m.enabled {
+		return fmt.Errorf("not configured")
+	}
+	switch action {
+	case "all-off":
+		return m.allOff()
+	}
+	return fmt.Errorf("unknown action %q (want \"all-off\")", action)
+}
+
 // toggleOfficeMode toggles between office time and quittin time based on office light state.
 func (m *Module) toggleOfficeMode() error {
-	state := m.getOfficeLightState()
+	return m.setOfficeMode(!m.getOfficeLightState().On)
+}
 
-	if state.On {
-		// Light is on, run quittin time to turn off
-		log.Println("Executing Quittin Time script...")
+// setOfficeMode runs office time to turn the office on, or quittin time to
+// turn it off.
+func (m *Module) setOfficeMode(on bool) error {
+	if on {
+		log.Println("Executing Office Time script...")
 		err := m.client.CallService(context.Background(), "script", "turn_on", map[string]any{
-			"entity_id": "script.quittin_time",
+			"entity_id": "script.office_time",
 		})
 		if err != nil {
-			log.Printf("Failed to execute Quittin Time: %v", err)
+			log.Printf("Failed to execute Office Time: %v", err)
 			return err
 		}
-		log.Println("Quittin Time script executed successfully")
+		log.Println("Office Time script executed successfully")
 	} else {
-		// Light is off, run office time to turn on
-		log.Println("Executing Office Time script...")
+		log.Println("Executing Quittin Time script...")
 		err := m.client.CallService(context.Background(), "script", "turn_on", map[string]any{
-			"entity_id": "script.office_time",
+			"entity_id": "script.quittin_time",
 		})
 		if err != nil {
-			log.Printf("Failed to execute Office Time: %v", err)
+			log.Printf("Failed to execute Quittin Time: %v", err)
 			return err
 		}
-		log.Println("Office Time script executed successfully")
+		log.Println("Quittin Time script executed successfully")
 	}
 
 	return nil
@@ -284,10 +452,29 @@ func (m *Module) toggleRingLight() error {
 	return nil
 }
 
+// setRingLight turns the ring light on or off.
+func (m *Module) setRingLight(on bool) error {
+	service := "turn_off"
+	if on {
+		service = "turn_on"
+	}
+	log.Printf("Ring light %s...", service)
+
+	err := m.client.CallService(context.Background(), "light", service, map[string]any{
+		"entity_id": m.config.RingLightEntity,
+	})
+	if err != nil {
+		log.Printf("Failed to %s ring light: %v", service, err)
+		return err
+	}
+	return nil
+}
+
 // adjustRingLightBrightness adjusts the ring light brightness by a delta.
-func (m *Module) adjustRingLightBrightness(delta int8) error {
-	// Each dial tick adjusts brightness by ~10% (25 out of 255)
-	step := int(delta) * 25
+func (m *Module) adjustRingLightBrightness(delta int) error {
+	// Each dial tick adjusts brightness by ~10% (25 out of 255); an
+	// accelerated spin can cover the whole range in one turn
+	step := max(-255, min(255, delta*25))
 
 	log.Printf("Adjusting ring light brightness by %d", step)
 
@@ -300,6 +487,7 @@ func (m *Module) adjustRingLightBrightness(delta int8) error {
 		return err
 	}
 
+	m.booster.Boost(m.Context())
 	return nil
 }
 
@@ -316,7 +504,7 @@ func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 
 	// Dial 0: Ring Light brightness
 	if len(m.resources.Dials) > 0 && id == m.resources.Dials[0] {
-		return m.adjustRingLightBrightness(event.Delta)
+		return m.adjustRingLightBrightness(m.config.BrightnessAccel.Apply(event.Delta))
 	}
 
 	return nil
@@ -8,12 +8,9 @@ import (
 	"log"
 	"strings"
 
+	"github.com/phinze/belowdeck/internal/render"
 	"github.com/srwiley/oksvg"
 	"github.com/srwiley/rasterx"
-	"golang.org/x/image/draw"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
-	"golang.org/x/image/math/fixed"
 )
 
 //go:embed fonts/PublicSans-Bold.ttf
@@ -32,37 +29,32 @@ var (
 	colorAmber    = color.RGBA{255, 191, 0, 255}
 	colorLightRay = color.RGBA{255, 245, 180, 255}
 	colorDimGray  = color.RGBA{80, 80, 80, 255}
+	colorGray     = color.RGBA{160, 160, 160, 255}
 )
 
 const keySize = 72
 
 // initFonts initializes the font faces for rendering.
-func (m *Module) initFonts() error {
-	ttBold, err := opentype.Parse(fontBold)
-	if err != nil {
-		return fmt.Errorf("failed to parse bold font: %w", err)
-	}
-
-	m.labelFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    11,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create label face: %w", err)
-	}
-
-	return nil
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("homeassistant bold", render.BoldFont, fontBold)
+	m.labelFace = render.NewFace(ttBold, 11)
+	m.valueFace = render.NewFace(ttBold, 18)
 }
 
 // renderOfficeTimeButton renders the Office toggle button.
 func (m *Module) renderOfficeTimeButton() image.Image {
+	if m.isMissing(m.config.OfficeLightEntity) {
+		return m.renderMissingButton(iconLampDeskSVG)
+	}
 	state := m.getOfficeLightState()
+	if _, loaded := m.lightsLoaded(); !loaded {
+		return m.renderLoadingButton(iconLampDeskSVG)
+	}
 
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	c := render.NewKeyCanvas(keySize)
 
 	// Background
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	c.Fill(c.Bounds(), colorKeyBg)
 
 	// Choose icon color and label based on state
 	var iconColor color.Color
@@ -77,24 +69,24 @@ func (m *Module) renderOfficeTimeButton() image.Image {
 	}
 
 	// Draw icon in upper portion
-	iconImg := renderSVGIcon(iconLampDeskSVG, 40, iconColor)
+	iconImg := renderSVGIcon(iconLampDeskSVG, c.Px(40), iconColor)
 	iconX := (keySize - 40) / 2
 	iconY := 8
-	draw.Draw(img, image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg, image.Point{}, draw.Over)
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
 
 	// Draw light rays when on
 	if state.On {
-		drawLightRays(img, colorLightRay)
+		drawLightRays(c, colorLightRay)
 	}
 
 	// Draw label at bottom
-	m.drawTextCentered(img, labelText, keySize/2, 62, m.labelFace, colorWhite)
+	c.DrawStringCentered(labelText, keySize/2, 62, m.labelFace, colorWhite)
 
-	return img
+	return c.Image()
 }
 
 // drawLightRays draws light rays emanating from the lamp's 45° shade surface.
-func drawLightRays(img *image.RGBA, col color.Color) {
+func drawLightRays(c *render.Canvas, col color.Color) {
 	// The lamp shade is a 45° diagonal line in the upper right of the icon
 	// Icon is 40x40 at position (16,8), so lamp shade runs roughly from (44,12) to (52,20)
 	// Rays emanate perpendicular to this surface (also at 45°, pointing upper-right)
@@ -111,8 +103,11 @@ func drawLightRays(img *image.RGBA, col color.Color) {
 		{53, 23, 58, 28},  // furthest ray
 	}
 
+	// On a supersampled canvas, thicken each ray to one logical pixel
 	for _, r := range rays {
-		drawLine(img, r.x1, r.y1, r.x2, r.y2, col)
+		for o := 0; o < c.Scale; o++ {
+			drawLine(c.Img, c.Px(r.x1)+o, c.Px(r.y1), c.Px(r.x2)+o, c.Px(r.y2), col)
+		}
 	}
 }
 
@@ -159,12 +154,18 @@ func abs(x int) int {
 
 // renderRingLightButton renders the Ring Light toggle button.
 func (m *Module) renderRingLightButton() image.Image {
+	if m.isMissing(m.config.RingLightEntity) {
+		return m.renderMissingButton(iconCircleSVG)
+	}
 	state := m.getRingLightState()
+	if loaded, _ := m.lightsLoaded(); !loaded {
+		return m.renderLoadingButton(iconCircleSVG)
+	}
 
-	img := image.NewRGBA(image.Rect(0, 0, keySize, keySize))
+	c := render.NewKeyCanvas(keySize)
 
 	// Background
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	c.Fill(c.Bounds(), colorKeyBg)
 
 	// Choose icon color based on state
 	var iconColor color.Color
@@ -187,15 +188,33 @@ func (m *Module) renderRingLightButton() image.Image {
 	}
 
 	// Draw icon in upper portion
-	iconImg := renderSVGIcon(iconCircleSVG, 40, iconColor)
+	iconImg := renderSVGIcon(iconCircleSVG, c.Px(40), iconColor)
 	iconX := (keySize - 40) / 2
 	iconY := 8
-	draw.Draw(img, image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg, image.Point{}, draw.Over)
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
 
 	// Draw label at bottom
-	m.drawTextCentered(img, labelText, keySize/2, 62, m.labelFace, colorWhite)
+	c.DrawStringCentered(labelText, keySize/2, 62, m.labelFace, colorWhite)
 
-	return img
+	return c.Image()
+}
+
+// renderLoadingButton renders a light's key before its state has been
+// fetched: the icon dimmed, with loading dots in place of the label.
+func (m *Module) renderLoadingButton(svg string) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconImg := renderSVGIcon(svg, c.Px(40), colorDimGray)
+	iconX := (keySize - 40) / 2
+	iconY := 8
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
+
+	render.DrawLoadingDots(c, image.Pt(keySize/2, 58), colorDimGray)
+
+	return c.Image()
 }
 
 // renderSVGIcon renders an SVG string to an image with the given size and color.
@@ -225,17 +244,3 @@ func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Ima
 
 	return img
 }
-
-// drawTextCentered draws text centered horizontally at the given position.
-func (m *Module) drawTextCentered(img *image.RGBA, text string, centerX, y int, face font.Face, col color.Color) {
-	width := font.MeasureString(face, text).Ceil()
-	x := centerX - width/2
-
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(col),
-		Face: face,
-		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
-	}
-	d.DrawString(text)
-}
@@ -0,0 +1,224 @@
+package homeassistant
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+//go:embed icons/thermometer.svg
+var iconThermometerSVG string
+
+//go:embed icons/droplet.svg
+var iconDropletSVG string
+
+//go:embed icons/zap.svg
+var iconZapSVG string
+
+//go:embed icons/wind.svg
+var iconWindSVG string
+
+//go:embed icons/gauge.svg
+var iconGaugeSVG string
+
+// sensorIcons are the built-in icons sensor keys can use by name.
+var sensorIcons = map[string]string{
+	"thermometer": iconThermometerSVG,
+	"droplet":     iconDropletSVG,
+	"zap":         iconZapSVG,
+	"wind":        iconWindSVG,
+	"gauge":       iconGaugeSVG,
+}
+
+// defaultSensorIcon is the icon for sensors configured without one.
+const defaultSensorIcon = "gauge"
+
+// SensorBinding shows an entity's state on a key, such as an indoor
+// temperature or power usage.
+type SensorBinding struct {
+	Entity string
+
+	// Icon is a built-in icon name (see sensorIcons) or the path of an
+	// SVG file drawn in place of currentColor.
+	Icon string
+
+	// Label is shown under the value. When empty, the entity's friendly
+	// name is used.
+	Label string
+
+	// iconSVG is the SVG source Icon resolves to.
+	iconSVG string
+}
+
+// parseSensors parses HASS_SENSORS: semicolon-separated entries of the form
+// "entity", "entity|icon" or "entity|icon|label", one per key.
+func parseSensors(v string) ([]SensorBinding, error) {
+	var sensors []SensorBinding
+	for _, entry := range strings.Split(v, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid HASS_SENSORS entry %q: want entity[|icon[|label]]", entry)
+		}
+
+		b := SensorBinding{Entity: strings.TrimSpace(parts[0]), Icon: defaultSensorIcon}
+		if b.Entity == "" {
+			return nil, fmt.Errorf("invalid HASS_SENSORS entry %q: entity is required", entry)
+		}
+		if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+			b.Icon = strings.TrimSpace(parts[1])
+		}
+		if len(parts) > 2 {
+			b.Label = strings.TrimSpace(parts[2])
+		}
+
+		svg, err := loadSensorIcon(b.Icon)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HASS_SENSORS icon for %s: %w", b.Entity, err)
+		}
+		b.iconSVG = svg
+
+		sensors = append(sensors, b)
+	}
+	return sensors, nil
+}
+
+// loadSensorIcon returns the SVG for a built-in icon name or an SVG file.
+func loadSensorIcon(icon string) (string, error) {
+	if svg, ok := sensorIcons[icon]; ok {
+		return svg, nil
+	}
+	if !strings.HasSuffix(strings.ToLower(icon), ".svg") {
+		return "", fmt.Errorf("unknown icon %q (want thermometer, droplet, zap, wind, gauge or an .svg path)", icon)
+	}
+	data, err := os.ReadFile(icon)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// sensorKeys returns the keys showing sensors, in order: those after the
+// office and ring light keys.
+func (m *Module) sensorKeys() []module.KeyID {
+	if len(m.resources.Keys) <= 2 {
+		return nil
+	}
+	keys := m.resources.Keys[2:]
+	if len(keys) > len(m.config.Sensors) {
+		keys = keys[:len(m.config.Sensors)]
+	}
+	return keys
+}
+
+// fetchSensorStates fetches the state of every sensor shown on a key.
+func (m *Module) fetchSensorStates(ctx context.Context) {
+	for i := range m.sensorKeys() {
+		entity := m.config.Sensors[i].Entity
+		state, err := m.client.GetState(ctx, entity)
+		if !m.recordFetch("sensor", entity, err) {
+			continue
+		}
+
+		m.mu.Lock()
+		m.sensorStates[entity] = state
+		m.mu.Unlock()
+	}
+}
+
+// getSensorState returns a sensor's last fetched state, reporting false if
+// it hasn't been fetched yet.
+func (m *Module) getSensorState(entity string) (EntityState, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.sensorStates[entity]
+	return state, ok
+}
+
+// formatSensorValue formats a sensor's state for a key: numbers to at most
+// one decimal, "--" for unavailable sensors, and other states as they are.
+func formatSensorValue(state EntityState) string {
+	if n, ok := state.Number(); ok {
+		return strconv.FormatFloat(math.Round(n*10)/10, 'f', -1, 64)
+	}
+	switch state.State {
+	case "", "unavailable", "unknown":
+		return "--"
+	}
+	return state.State
+}
+
+// renderSensorButton renders a sensor key: its icon, then the value with
+// its unit, then the label.
+func (m *Module) renderSensorButton(b SensorBinding) image.Image {
+	if m.isMissing(b.Entity) {
+		return m.renderMissingButton(b.iconSVG)
+	}
+	state, ok := m.getSensorState(b.Entity)
+	if !ok {
+		return m.renderLoadingButton(b.iconSVG)
+	}
+
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	// Icon at the top
+	iconImg := renderSVGIcon(b.iconSVG, c.Px(24), colorWhite)
+	iconX := (keySize - 24) / 2
+	c.DrawImage(image.Rect(iconX, 6, iconX+24, 30), iconImg)
+
+	// Value and unit, centered together
+	value := formatSensorValue(state)
+	unit := state.Unit()
+	if _, numeric := state.Number(); !numeric {
+		unit = ""
+	}
+	valueWidth := c.MeasureString(m.valueFace, value)
+	width := valueWidth
+	if unit != "" {
+		width += 2 + c.MeasureString(m.labelFace, unit)
+	}
+	x := (keySize - width) / 2
+	c.DrawString(value, x, 48, m.valueFace, colorWhite)
+	if unit != "" {
+		c.DrawString(unit, x+valueWidth+2, 48, m.labelFace, colorWhite)
+	}
+
+	// Label at the bottom
+	label := b.Label
+	if label == "" {
+		label = state.FriendlyName()
+	}
+	c.DrawStringCentered(fitLabel(m.labelFace, label, keySize-6), keySize/2, 64, m.labelFace, colorGray)
+
+	return c.Image()
+}
+
+// fitLabel shortens text with an ellipsis to fit within maxWidth.
+func fitLabel(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		if s := string(runes[:n]) + "..."; font.MeasureString(face, s).Ceil() <= maxWidth {
+			return s
+		}
+	}
+	return "..."
+}
@@ -0,0 +1,110 @@
+package homeassistant
+
+import (
+	"context"
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+// sensorState is a states API response for a temperature sensor.
+func sensorState(state string) map[string]any {
+	return map[string]any{
+		"entity_id": "sensor.office_temperature",
+		"state":     state,
+		"attributes": map[string]any{
+			"unit_of_measurement": "°C",
+			"friendly_name":       "Office Temperature",
+		},
+	}
+}
+
+func TestGetStateNumericSensor(t *testing.T) {
+	srv := stubserver.New(t)
+	srv.JSON("GET /api/states/sensor.office_temperature", sensorState("21.46"))
+
+	client := NewClient(srv.URL, "token", nil)
+	state, err := client.GetState(context.Background(), "sensor.office_temperature")
+	if err != nil {
+		t.Fatalf("GetState: %v", err)
+	}
+	if n, ok := state.Number(); !ok || n != 21.46 {
+		t.Errorf("Number() = %v, %v; want 21.46", n, ok)
+	}
+	if got := state.Unit(); got != "°C" {
+		t.Errorf("Unit() = %q, want %q", got, "°C")
+	}
+	if got := state.FriendlyName(); got != "Office Temperature" {
+		t.Errorf("FriendlyName() = %q, want %q", got, "Office Temperature")
+	}
+}
+
+func TestFormatSensorValue(t *testing.T) {
+	for _, tt := range []struct {
+		state string
+		want  string
+	}{
+		{"21.46", "21.5"},
+		{"415", "415"},
+		{"-3.04", "-3"},
+		{"unavailable", "--"},
+		{"unknown", "--"},
+		{"", "--"},
+		{"heating", "heating"},
+		{"NaN", "NaN"},
+	} {
+		if got := formatSensorValue(EntityState{State: tt.state}); got != tt.want {
+			t.Errorf("formatSensorValue(%q) = %q, want %q", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestParseSensors(t *testing.T) {
+	sensors, err := parseSensors("sensor.temp|thermometer|Office; sensor.co2 ;")
+	if err != nil {
+		t.Fatalf("parseSensors: %v", err)
+	}
+	if len(sensors) != 2 {
+		t.Fatalf("got %d sensors, want 2", len(sensors))
+	}
+	if s := sensors[0]; s.Entity != "sensor.temp" || s.Icon != "thermometer" || s.Label != "Office" || s.iconSVG != iconThermometerSVG {
+		t.Errorf("first sensor = %+v", s)
+	}
+	if s := sensors[1]; s.Entity != "sensor.co2" || s.Icon != defaultSensorIcon || s.Label != "" {
+		t.Errorf("second sensor = %+v, want the default icon and no label", s)
+	}
+
+	for _, bad := range []string{"|gauge", "sensor.temp|rocket", "a|b|c|d"} {
+		if _, err := parseSensors(bad); err == nil {
+			t.Errorf("parseSensors(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestSensorKeyShowsFetchedValue(t *testing.T) {
+	sensors, err := parseSensors("sensor.office_temperature|thermometer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, srv := newKeyModule(t, Config{Sensors: sensors})
+	m.resources = module.Resources{Keys: []module.KeyID{1, 2, 3}}
+	m.initFonts()
+	srv.JSON("GET /api/states/sensor.office_temperature", sensorState("21.46"))
+
+	loading := m.renderSensorButton(sensors[0]).(*image.RGBA)
+	m.fetchSensorStates(context.Background())
+	if _, ok := m.getSensorState("sensor.office_temperature"); !ok {
+		t.Fatal("sensor state not recorded after a fetch")
+	}
+
+	got := m.renderSensorButton(sensors[0]).(*image.RGBA)
+	if reflect.DeepEqual(got.Pix, loading.Pix) {
+		t.Error("sensor key still shows the loading state after a fetch")
+	}
+	if keys := m.sensorKeys(); len(keys) != 1 || keys[0] != 3 {
+		t.Errorf("sensorKeys() = %v, want [3]", keys)
+	}
+}
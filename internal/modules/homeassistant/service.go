@@ -0,0 +1,33 @@
+package homeassistant
+
+import (
+	"log"
+
+	"github.com/phinze/belowdeck/internal/bus"
+)
+
+// subscribeServiceCalls calls the Home Assistant services other modules ask
+// for on the bus, such as the macro module's ha-service steps.
+func (m *Module) subscribeServiceCalls() {
+	b := m.Bus()
+	if b == nil {
+		return
+	}
+	b.SubscribeContext(m.Context(), bus.TopicHAService, func(e bus.Event) {
+		call, ok := e.Data.(bus.HAServiceCall)
+		if !ok {
+			return
+		}
+		go func() {
+			err := m.client.CallService(m.Context(), call.Domain, call.Service, call.Data)
+			if err != nil {
+				log.Printf("Failed to call %s.%s: %v", call.Domain, call.Service, err)
+			} else {
+				m.booster.Boost(m.Context())
+			}
+			if call.Done != nil {
+				call.Done <- err
+			}
+		}()
+	})
+}
@@ -0,0 +1,41 @@
+package homeassistant
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+)
+
+func TestServiceCallsFromBus(t *testing.T) {
+	m, srv := newKeyModule(t, Config{})
+	m.SetBus(bus.New())
+	m.BaseModule.Init(context.Background(), m.resources)
+	m.subscribeServiceCalls()
+
+	if !m.Bus().HasSubscribers(bus.TopicHAService) {
+		t.Fatal("no subscriber for service calls")
+	}
+
+	done := make(chan error, 1)
+	m.Bus().Publish(bus.TopicHAService, bus.HAServiceCall{
+		Domain:  "light",
+		Service: "toggle",
+		Data:    map[string]any{"entity_id": "light.office"},
+		Done:    done,
+	})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("service call failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reply to the service call")
+	}
+
+	if got, want := servicesCalled(srv), []string{"/api/services/light/toggle"}; !slices.Equal(got, want) {
+		t.Errorf("called %q, want %q", got, want)
+	}
+}
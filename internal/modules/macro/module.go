@@ -0,0 +1,237 @@
+// Package macro provides a Stream Deck module whose keys each run a
+// configured sequence of steps, such as opening a URL, then toggling a
+// light, then running a command.
+package macro
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+	"golang.org/x/image/font"
+)
+
+// Macro is a sequence of steps run by one key.
+type Macro struct {
+	Label string
+	Steps []Step
+
+	// ContinueOnError runs the remaining steps after one fails, rather
+	// than stopping there.
+	ContinueOnError bool
+}
+
+// Config holds the macro module configuration.
+type Config struct {
+	Macros []Macro
+}
+
+// Module implements the macro module.
+type Module struct {
+	module.BaseModule
+
+	device  device.Device
+	config  Config
+	runner  runner.Runner
+	enabled bool
+
+	// Indexes of the macros currently running
+	mu      sync.RWMutex
+	running map[int]bool
+
+	// Fonts
+	labelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new macro module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("macro"),
+		device:     dev,
+		runner:     runner.Default,
+		running:    make(map[int]bool),
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "macro"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	// Load config from environment (optional - module disabled if not configured)
+	config, err := loadConfig()
+	if err != nil {
+		log.Printf("Macro module disabled: %v", err)
+		m.enabled = false
+		return nil
+	}
+	m.config = config
+	m.enabled = true
+
+	if len(m.config.Macros) > len(res.Keys) {
+		log.Printf("Macros: %d configured but only %d keys allocated, extra macros run only as actions",
+			len(m.config.Macros), len(res.Keys))
+	}
+
+	// Initialize fonts
+	m.initFonts()
+
+	log.Printf("Macro module initialized (%d macros)", len(m.config.Macros))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+//
+// Macros are numbered from 1 with no gaps. MACRO_<n> holds a macro's steps,
+// one per line; MACRO_<n>_LABEL names its key (default "Macro <n>") and
+// MACRO_<n>_ON_ERROR is "stop" (default) or "continue".
+func loadConfig() (Config, error) {
+	var config Config
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("MACRO_%d", n)
+		v := os.Getenv(name)
+		if v == "" {
+			break
+		}
+
+		steps, err := parseSteps(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		macro := Macro{
+			Label: os.Getenv(name + "_LABEL"),
+			Steps: steps,
+		}
+		if macro.Label == "" {
+			macro.Label = "Macro " + strconv.Itoa(n)
+		}
+
+		switch onError := os.Getenv(name + "_ON_ERROR"); onError {
+		case "", "stop":
+		case "continue":
+			macro.ContinueOnError = true
+		default:
+			return Config{}, fmt.Errorf("invalid %s_ON_ERROR: %q (want \"stop\" or \"continue\")", name, onError)
+		}
+
+		config.Macros = append(config.Macros, macro)
+	}
+
+	if len(config.Macros) == 0 {
+		return Config{}, fmt.Errorf("MACRO_1 environment variable not set")
+	}
+	return config, nil
+}
+
+// startMacro marks macro i as running, reporting false if it already was.
+func (m *Module) startMacro(i int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.running[i] {
+		return false
+	}
+	m.running[i] = true
+	return true
+}
+
+// isRunning reports whether macro i is running.
+func (m *Module) isRunning(i int) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.running[i]
+}
+
+// run runs macro i, flashing key with the result unless it's zero.
+func (m *Module) run(i int, key module.KeyID) {
+	defer func() {
+		m.mu.Lock()
+		delete(m.running, i)
+		m.mu.Unlock()
+	}()
+
+	macro := m.config.Macros[i]
+	log.Printf("Macro %s: running %d steps", macro.Label, len(macro.Steps))
+	err := m.runMacro(m.Context(), macro)
+	if err != nil {
+		log.Printf("Macro %s failed: %v", macro.Label, err)
+	} else {
+		log.Printf("Macro %s: done", macro.Label)
+	}
+	if key != 0 {
+		m.FlashResult(key, err)
+	}
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled {
+		return nil
+	}
+
+	keys := make(map[module.KeyID]image.Image)
+	for i, keyID := range m.resources.Keys {
+		if i >= len(m.config.Macros) {
+			break
+		}
+		keys[keyID] = m.renderMacroKey(m.config.Macros[i].Label, m.isRunning(i))
+	}
+	return keys
+}
+
+// HandleAction runs an external action: a macro's number, e.g. "2", runs
+// that macro.
+func (m *Module) HandleAction(action string) error {
+	if !m.enabled {
+		return fmt.Errorf("not configured")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(action))
+	if err != nil || n < 1 || n > len(m.config.Macros) {
+		return fmt.Errorf("unknown action %q (want a macro number from 1 to %d)", action, len(m.config.Macros))
+	}
+	if m.startMacro(n - 1) {
+		go m.run(n-1, 0)
+	}
+	return nil
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	// Steps may wait on delays and commands, so macros run in the
+	// background; pressing a running macro's key again does nothing
+	for i, keyID := range m.resources.Keys {
+		if keyID == id && i < len(m.config.Macros) && m.startMacro(i) {
+			go m.run(i, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package macro
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+//go:embed icons/workflow.svg
+var iconWorkflowSVG string
+
+// Common colors
+var (
+	colorKeyBg   = color.RGBA{40, 40, 40, 255}
+	colorWhite   = color.RGBA{255, 255, 255, 255}
+	colorPurple  = color.RGBA{138, 110, 255, 255}
+	colorDimGray = color.RGBA{120, 120, 120, 255}
+)
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("macro bold", render.BoldFont, fontBold)
+	m.labelFace = render.NewFace(ttBold, 11)
+}
+
+// renderMacroKey renders a macro's key, dimmed while it runs.
+func (m *Module) renderMacroKey(label string, running bool) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconColor := colorPurple
+	if running {
+		iconColor = colorDimGray
+		label = "Running..."
+	}
+
+	// Draw icon in upper portion
+	iconImg := renderSVGIcon(iconWorkflowSVG, c.Px(40), iconColor)
+	iconX := (keySize - 40) / 2
+	iconY := 8
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
+
+	// Draw label at bottom
+	c.DrawStringCentered(label, keySize/2, 62, m.labelFace, colorWhite)
+
+	return c.Image()
+}
+
+// renderSVGIcon renders an SVG string to an image with the given size and color.
+func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
+	r, g, b, _ := iconColor.RGBA()
+	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+
+	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
+	if err != nil {
+		log.Printf("Failed to parse SVG: %v", err)
+		return image.NewRGBA(image.Rect(0, 0, size, size))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.SetTarget(0, 0, float64(size), float64(size))
+
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	raster := rasterx.NewDasher(size, size, scanner)
+	icon.Draw(raster, 1.0)
+
+	return img
+}
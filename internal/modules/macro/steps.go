@@ -0,0 +1,187 @@
+package macro
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// Step kinds, each written at the start of a step's line.
+const (
+	// StepCommand runs the rest of the line as a shell command.
+	StepCommand = "command"
+
+	// StepOpenURL opens a URL in the default browser.
+	StepOpenURL = "open-url"
+
+	// StepHAService calls a Home Assistant service through the Home
+	// Assistant module, e.g. "ha-service light.toggle light.office". The
+	// service may be followed by an entity ID or a JSON object of service
+	// data.
+	StepHAService = "ha-service"
+
+	// StepDelay waits for a duration, e.g. "delay 2s".
+	StepDelay = "delay"
+)
+
+// Step is one action in a macro.
+type Step struct {
+	Kind string
+
+	// Arg is the command or URL for command and open-url steps.
+	Arg string
+
+	// Domain, Service and Data are the service call for ha-service steps.
+	Domain  string
+	Service string
+	Data    map[string]any
+
+	// Delay is how long a delay step waits.
+	Delay time.Duration
+}
+
+// String returns the step as it's written in the config.
+func (s Step) String() string {
+	switch s.Kind {
+	case StepHAService:
+		return fmt.Sprintf("%s %s.%s", s.Kind, s.Domain, s.Service)
+	case StepDelay:
+		return fmt.Sprintf("%s %s", s.Kind, s.Delay)
+	}
+	return s.Kind + " " + s.Arg
+}
+
+// parseSteps parses a macro's steps, one per line as "<kind> <argument>".
+// Blank lines are skipped.
+func parseSteps(v string) ([]Step, error) {
+	var steps []Step
+	for _, line := range strings.Split(v, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		step, err := parseStep(line)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no steps")
+	}
+	return steps, nil
+}
+
+// parseStep parses a single step line.
+func parseStep(line string) (Step, error) {
+	kind, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return Step{}, fmt.Errorf("step %q: missing argument", line)
+	}
+
+	step := Step{Kind: kind}
+	switch kind {
+	case StepCommand, StepOpenURL:
+		step.Arg = arg
+
+	case StepHAService:
+		name, data, _ := strings.Cut(arg, " ")
+		domain, service, ok := strings.Cut(name, ".")
+		if !ok || domain == "" || service == "" {
+			return Step{}, fmt.Errorf("step %q: want a service like \"light.toggle\"", line)
+		}
+		step.Domain, step.Service = domain, service
+
+		data = strings.TrimSpace(data)
+		switch {
+		case strings.HasPrefix(data, "{"):
+			if err := json.Unmarshal([]byte(data), &step.Data); err != nil {
+				return Step{}, fmt.Errorf("step %q: invalid service data: %w", line, err)
+			}
+		case data != "":
+			step.Data = map[string]any{"entity_id": data}
+		}
+
+	case StepDelay:
+		delay, err := time.ParseDuration(arg)
+		if err != nil || delay < 0 {
+			return Step{}, fmt.Errorf("step %q: invalid delay", line)
+		}
+		step.Delay = delay
+
+	default:
+		return Step{}, fmt.Errorf("step %q: unknown kind %q (want %s, %s, %s or %s)",
+			line, kind, StepCommand, StepOpenURL, StepHAService, StepDelay)
+	}
+	return step, nil
+}
+
+// runMacro runs a macro's steps in order. A failed step stops the macro
+// unless it continues on errors, in which case every failure is returned
+// together once the last step has run.
+func (m *Module) runMacro(ctx context.Context, macro Macro) error {
+	var errs []error
+	for i, step := range macro.Steps {
+		if err := m.runStep(ctx, step); err != nil {
+			err = fmt.Errorf("step %d (%s): %w", i+1, step, err)
+			if !macro.ContinueOnError {
+				return err
+			}
+			log.Printf("Macro %s: %v", macro.Label, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runStep runs a single step.
+func (m *Module) runStep(ctx context.Context, step Step) error {
+	switch step.Kind {
+	case StepCommand:
+		return runner.Shell(ctx, m.runner, step.Arg)
+	case StepOpenURL:
+		return runner.OpenURL(ctx, m.runner, step.Arg)
+	case StepHAService:
+		return m.callService(ctx, step)
+	case StepDelay:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(step.Delay):
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown step kind %q", step.Kind)
+}
+
+// callService asks the Home Assistant module to call a step's service over
+// the bus, waiting for the result.
+func (m *Module) callService(ctx context.Context, step Step) error {
+	b := m.Bus()
+	if b == nil || !b.HasSubscribers(bus.TopicHAService) {
+		return fmt.Errorf("the Home Assistant module isn't running")
+	}
+
+	done := make(chan error, 1)
+	b.Publish(bus.TopicHAService, bus.HAServiceCall{
+		Domain:  step.Domain,
+		Service: step.Service,
+		Data:    step.Data,
+		Done:    done,
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
@@ -0,0 +1,147 @@
+package macro
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+func TestParseSteps(t *testing.T) {
+	got, err := parseSteps(`
+		open-url https://example.com/standup
+		ha-service light.turn_on {"entity_id": "light.office", "brightness": 128}
+
+		ha-service scene.turn_on scene.focus
+		delay 2s
+		command say "hello there"
+	`)
+	if err != nil {
+		t.Fatalf("parseSteps: %v", err)
+	}
+	want := []Step{
+		{Kind: StepOpenURL, Arg: "https://example.com/standup"},
+		{Kind: StepHAService, Domain: "light", Service: "turn_on", Data: map[string]any{"entity_id": "light.office", "brightness": float64(128)}},
+		{Kind: StepHAService, Domain: "scene", Service: "turn_on", Data: map[string]any{"entity_id": "scene.focus"}},
+		{Kind: StepDelay, Delay: 2 * time.Second},
+		{Kind: StepCommand, Arg: `say "hello there"`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSteps = %+v, want %+v", got, want)
+	}
+
+	for _, v := range []string{
+		"",
+		"command",
+		"launch rockets",
+		"ha-service toggle light.office",
+		"ha-service light.turn_on {bad json",
+		"delay soon",
+		"delay -1s",
+	} {
+		if _, err := parseSteps(v); err == nil {
+			t.Errorf("parseSteps(%q) accepted", v)
+		}
+	}
+}
+
+// newTestModule returns an enabled module running commands with a fake
+// runner, on a bus with no Home Assistant module.
+func newTestModule(t *testing.T) (*Module, *runner.Fake) {
+	t.Helper()
+	fake := &runner.Fake{}
+	m := New(device.NewFake())
+	m.runner = fake
+	m.enabled = true
+	m.SetBus(bus.New())
+	if err := m.BaseModule.Init(context.Background(), module.Resources{}); err != nil {
+		t.Fatal(err)
+	}
+	return m, fake
+}
+
+func TestRunMacroInOrder(t *testing.T) {
+	m, fake := newTestModule(t)
+
+	// The service call records how many commands ran before it
+	var ranBefore int
+	var calls []bus.HAServiceCall
+	m.Bus().Subscribe(bus.TopicHAService, func(e bus.Event) {
+		call := e.Data.(bus.HAServiceCall)
+		calls = append(calls, call)
+		ranBefore = len(fake.Commands())
+		call.Done <- nil
+	})
+
+	steps, err := parseSteps("command first\nopen-url https://example.com/in-order\nha-service light.toggle light.office\ndelay 1ms\ncommand last")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.runMacro(context.Background(), Macro{Label: "test", Steps: steps}); err != nil {
+		t.Fatalf("runMacro: %v", err)
+	}
+
+	name, args := platform.OpenURL("https://example.com/in-order")
+	want := []string{"sh -c first", strings.Join(append([]string{name}, args...), " "), "sh -c last"}
+	if got := fake.Commands(); !slices.Equal(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+	if len(calls) != 1 || calls[0].Domain != "light" || calls[0].Service != "toggle" {
+		t.Fatalf("called services %+v, want light.toggle", calls)
+	}
+	if ranBefore != 2 {
+		t.Errorf("service called after %d commands, want 2", ranBefore)
+	}
+}
+
+func TestRunMacroOnError(t *testing.T) {
+	// Without the Home Assistant module, the service step fails
+	steps, err := parseSteps("command first\nha-service light.toggle light.office\ncommand last")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		continueOnError bool
+		want            []string
+	}{
+		{"stop", false, []string{"sh -c first"}},
+		{"continue", true, []string{"sh -c first", "sh -c last"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, fake := newTestModule(t)
+			err := m.runMacro(context.Background(), Macro{Label: "test", Steps: steps, ContinueOnError: tt.continueOnError})
+			if err == nil || !strings.Contains(err.Error(), "step 2") {
+				t.Errorf("runMacro() error = %v, want step 2's failure", err)
+			}
+			if got := fake.Commands(); !slices.Equal(got, tt.want) {
+				t.Errorf("ran %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunMacroCancelledDelay(t *testing.T) {
+	m, fake := newTestModule(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := m.runMacro(ctx, Macro{Steps: []Step{{Kind: StepDelay, Delay: time.Hour}, {Kind: StepCommand, Arg: "after"}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("runMacro() error = %v, want cancelled", err)
+	}
+	if got := fake.Commands(); len(got) != 0 {
+		t.Errorf("ran %q after a cancelled delay, want nothing", got)
+	}
+}
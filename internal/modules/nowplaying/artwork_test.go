@@ -0,0 +1,86 @@
+package nowplaying
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"sync"
+	"testing"
+)
+
+// solidArtwork returns base64 PNG artwork filled with col.
+func solidArtwork(t *testing.T, col color.RGBA) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = col.R, col.G, col.B, col.A
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestArtworkMatchesSnapshot(t *testing.T) {
+	m := newStripModule(t)
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	tracks := []NowPlaying{
+		{Title: "Red", Artist: "A", ArtworkData: solidArtwork(t, red), Playing: true},
+		{Title: "Blue", Artist: "A", ArtworkData: solidArtwork(t, blue), Playing: true},
+	}
+	want := map[string]color.RGBA{"Red": red, "Blue": blue}
+	m.SetNowPlaying(tracks[0])
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			m.SetNowPlaying(tracks[i%2])
+		}
+		close(stop)
+	}()
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				np := m.liveState.get()
+				art, _ := m.artworkFor(&np)
+				if art == nil {
+					t.Errorf("no art for %s", np.Title)
+					return
+				}
+				if got := color.RGBAModel.Convert(art.At(4, 4)); got != want[np.Title] {
+					t.Errorf("art for %s is %v, want %v", np.Title, got, want[np.Title])
+					return
+				}
+				m.RenderStrip()
+				m.RenderOverlayStrip()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestUndecodableArtworkClearsPrevious(t *testing.T) {
+	m := newStripModule(t)
+	np := NowPlaying{Title: "Red", ArtworkData: solidArtwork(t, color.RGBA{255, 0, 0, 255})}
+	if art, _ := m.artworkFor(&np); art == nil {
+		t.Fatal("no art for a valid PNG")
+	}
+
+	np = NowPlaying{Title: "Broken", ArtworkData: base64.StdEncoding.EncodeToString([]byte("not an image"))}
+	if art, _ := m.artworkFor(&np); art != nil {
+		t.Error("previous track's art kept for undecodable artwork")
+	}
+}
@@ -0,0 +1,39 @@
+package nowplaying
+
+import (
+	"log"
+	"sync/atomic"
+
+	"github.com/phinze/belowdeck/internal/bus"
+)
+
+// subscribeAway pauses playback when the deck goes away. Changes of reason
+// while already away, e.g. Focus giving way to a meeting, don't pause again.
+// Does nothing unless PauseWhenAway is set.
+func (m *Module) subscribeAway() {
+	b := m.Bus()
+	if b == nil || !m.config.PauseWhenAway {
+		return
+	}
+	var wasAway atomic.Bool
+	b.SubscribeContext(m.Context(), bus.TopicAway, func(e bus.Event) {
+		away, ok := e.Data.(bus.Away)
+		if !ok {
+			return
+		}
+		if !wasAway.Swap(away.On) && away.On {
+			go m.pauseForAway(away.Reason)
+		}
+	})
+}
+
+// pauseForAway pauses playback if anything is playing.
+func (m *Module) pauseForAway(reason string) {
+	if !m.liveState.get().Playing {
+		return
+	}
+	log.Printf("Pausing media: away (%s)", reason)
+	if err := m.runner.Run(m.Context(), "media-control", "pause"); err != nil {
+		log.Printf("Failed to pause media: %v", err)
+	}
+}
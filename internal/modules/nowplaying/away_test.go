@@ -0,0 +1,56 @@
+package nowplaying
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// pauses returns how many times fake was told to pause.
+func pauses(fake *runner.Fake) int {
+	n := 0
+	for _, cmd := range fake.Commands() {
+		if cmd == "media-control pause" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPauseWhenAway(t *testing.T) {
+	m := newStripModule(t)
+	m.config.PauseWhenAway = true
+	fake := &runner.Fake{}
+	m.runner = fake
+	b := bus.New()
+	m.SetBus(b)
+	m.subscribeAway()
+	m.SetNowPlaying(NowPlaying{Title: "T", Playing: true})
+
+	waitPauses := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for pauses(fake) < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("ran %q, want %d pauses", fake.Commands(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	b.Publish(bus.TopicAway, bus.Away{On: true, Reason: "Focus"})
+	waitPauses(1)
+
+	// A new reason while still away doesn't pause again, but coming back
+	// and leaving again does
+	b.Publish(bus.TopicAway, bus.Away{On: true, Reason: "Standup"})
+	b.Publish(bus.TopicAway, bus.Away{})
+	b.Publish(bus.TopicAway, bus.Away{On: true, Reason: "Away"})
+	waitPauses(2)
+	time.Sleep(20 * time.Millisecond)
+	if got := pauses(fake); got != 2 {
+		t.Errorf("paused %d times, want 2", got)
+	}
+}
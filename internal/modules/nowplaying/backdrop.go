@@ -0,0 +1,74 @@
+package nowplaying
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+)
+
+// backdropScale is how many times smaller than the strip the backdrop is
+// blurred at. Blurring a small copy and scaling it back up is far cheaper
+// than blurring at full size, and the result is just as soft.
+const backdropScale = 4
+
+// backdropBlurRadius is the box blur radius, in downscaled pixels, of each
+// of the backdrop's three blur passes.
+const backdropBlurRadius = 3
+
+// colorScrim darkens the backdrop so the track text and progress bar stay
+// readable over bright art.
+var colorScrim = color.NRGBA{0, 0, 0, 140}
+
+// backdropKey identifies a rendered backdrop.
+type backdropKey struct {
+	artwork image.Image
+	size    image.Point
+}
+
+// artworkBackdrop returns artwork scaled to cover an area of the given size,
+// blurred and darkened by the scrim. Like thumbnails, the backdrop is cached
+// until the artwork or size changes, since blurring every frame would be too
+// slow.
+func (m *Module) artworkBackdrop(artwork image.Image, size image.Point) image.Image {
+	m.thumbMu.Lock()
+	defer m.thumbMu.Unlock()
+
+	key := backdropKey{artwork: artwork, size: size}
+	if m.backdrop != nil && m.backdropKey == key {
+		return m.backdrop
+	}
+
+	// Crop the art to the area's aspect ratio, then blur a small copy
+	small := image.NewRGBA(image.Rect(0, 0, max(1, size.X/backdropScale), max(1, size.Y/backdropScale)))
+	draw.BiLinear.Scale(small, small.Bounds(), artwork, coverCrop(artwork.Bounds(), size), draw.Src, nil)
+	blurred := render.Blur(small, backdropBlurRadius, 3)
+
+	backdrop := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.BiLinear.Scale(backdrop, backdrop.Bounds(), blurred, blurred.Bounds(), draw.Src, nil)
+	draw.Draw(backdrop, backdrop.Bounds(), &image.Uniform{colorScrim}, image.Point{}, draw.Over)
+
+	m.backdrop, m.backdropKey = backdrop, key
+	return backdrop
+}
+
+// coverCrop returns the centered part of bounds with the aspect ratio of
+// size, so scaling it to size fills the area without distorting it.
+func coverCrop(bounds image.Rectangle, size image.Point) image.Rectangle {
+	w, h := bounds.Dx(), bounds.Dy()
+	if size.X <= 0 || size.Y <= 0 || w <= 0 || h <= 0 {
+		return bounds
+	}
+
+	if w*size.Y > h*size.X {
+		// Wider than the area: trim the sides
+		cw := max(1, h*size.X/size.Y)
+		x := bounds.Min.X + (w-cw)/2
+		return image.Rect(x, bounds.Min.Y, x+cw, bounds.Max.Y)
+	}
+	// Taller than the area: trim the top and bottom
+	ch := max(1, w*size.Y/size.X)
+	y := bounds.Min.Y + (h-ch)/2
+	return image.Rect(bounds.Min.X, y, bounds.Max.X, y+ch)
+}
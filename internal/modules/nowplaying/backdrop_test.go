@@ -0,0 +1,43 @@
+package nowplaying
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCoverCrop(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		size   image.Point
+		want   image.Rectangle
+	}{
+		{"square art on a wide strip", image.Rect(0, 0, 600, 600), image.Pt(400, 100), image.Rect(0, 225, 600, 375)},
+		{"wide art on a square", image.Rect(0, 0, 400, 100), image.Pt(100, 100), image.Rect(150, 0, 250, 100)},
+		{"same ratio", image.Rect(10, 10, 410, 110), image.Pt(200, 50), image.Rect(10, 10, 410, 110)},
+		{"empty area", image.Rect(0, 0, 600, 600), image.Pt(0, 100), image.Rect(0, 0, 600, 600)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coverCrop(tt.bounds, tt.size); got != tt.want {
+				t.Errorf("coverCrop() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArtworkBackdropCached(t *testing.T) {
+	m := newStripModule(t)
+	art := image.NewRGBA(image.Rect(0, 0, 64, 64))
+
+	first := m.artworkBackdrop(art, image.Pt(400, 100))
+	if got := first.Bounds(); got != image.Rect(0, 0, 400, 100) {
+		t.Errorf("backdrop bounds = %v, want the strip area", got)
+	}
+	if m.artworkBackdrop(art, image.Pt(400, 100)) != first {
+		t.Error("backdrop re-blurred for the same artwork")
+	}
+	if m.artworkBackdrop(art, image.Pt(200, 100)) == first {
+		t.Error("backdrop reused for a different size")
+	}
+}
@@ -0,0 +1,70 @@
+package nowplaying
+
+// MediaRemote command IDs for rating the playing track, sent with
+// "media-control send".
+const (
+	mediaRemoteLikeTrack    = "21"
+	mediaRemoteDislikeTrack = "22"
+)
+
+// capabilities are the controls an app responds to beyond play/pause and
+// stop, which every app handles.
+type capabilities struct {
+	skip    bool // next and previous track
+	seek    bool // jumps
+	shuffle bool
+	repeat  bool
+	like    bool // like and dislike
+}
+
+// appCapabilities are the capabilities of known media apps, by bundle ID.
+var appCapabilities = map[string]capabilities{
+	"com.apple.Music":            {skip: true, seek: true, shuffle: true, repeat: true, like: true},
+	"com.apple.podcasts":         {skip: true, seek: true},
+	"com.spotify.client":         {skip: true, seek: true, shuffle: true, repeat: true},
+	"com.apple.TV":               {seek: true},
+	"com.apple.QuickTimePlayerX": {seek: true},
+
+	// Browsers play web media, which can't shuffle, repeat or be liked
+	"com.apple.Safari":           {skip: true, seek: true},
+	"com.google.Chrome":          {skip: true, seek: true},
+	"org.mozilla.firefox":        {skip: true, seek: true},
+	"com.microsoft.edgemac":      {skip: true, seek: true},
+	"com.brave.Browser":          {skip: true, seek: true},
+	"company.thebrowser.Browser": {skip: true, seek: true},
+}
+
+// capabilitiesOf returns what the app playing np responds to. Apps that
+// aren't known get skipping and seeking, shuffle and repeat once
+// media-control has reported a mode for them, and no like or dislike.
+func capabilitiesOf(np *NowPlaying) capabilities {
+	if caps, ok := appCapabilities[np.BundleID]; ok {
+		return caps
+	}
+	return capabilities{
+		skip:    true,
+		seek:    true,
+		shuffle: np.Shuffle != "",
+		repeat:  np.Repeat != "",
+	}
+}
+
+// supports reports whether the app playing np responds to a key binding.
+func supports(np *NowPlaying, b KeyBinding) bool {
+	caps := capabilitiesOf(np)
+	if b.Jump != 0 {
+		return caps.seek
+	}
+
+	switch b.Action {
+	case KeyPrevious, KeyNext:
+		return caps.skip
+	case KeyShuffle:
+		return caps.shuffle
+	case KeyRepeat:
+		return caps.repeat
+	case KeyLike, KeyDislike:
+		return caps.like
+	}
+	return true
+}
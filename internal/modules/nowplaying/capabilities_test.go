@@ -0,0 +1,95 @@
+package nowplaying
+
+import (
+	"context"
+	"image"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// allBindings is every key action plus a jump, on keys 1 to 10.
+var allBindings = []KeyBinding{
+	{Action: KeyPlayPause}, {Action: KeyPrevious}, {Action: KeyNext}, {Action: KeyStop}, {Action: KeyInfo},
+	{Action: KeyShuffle}, {Action: KeyRepeat}, {Action: KeyLike}, {Action: KeyDislike}, {Jump: 15 * time.Second},
+}
+
+// isBlank reports whether img is entirely black.
+func isBlank(img image.Image) bool {
+	rgba := img.(*image.RGBA)
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		if rgba.Pix[i] != 0 || rgba.Pix[i+1] != 0 || rgba.Pix[i+2] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRenderedKeysFollowCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		np   NowPlaying
+		want []string // the actions of keys drawn, "jump" for the jump
+	}{
+		{"music", NowPlaying{BundleID: "com.apple.Music"},
+			[]string{KeyPlayPause, KeyPrevious, KeyNext, KeyStop, KeyInfo, KeyShuffle, KeyRepeat, KeyLike, KeyDislike, "jump"}},
+		{"browser", NowPlaying{BundleID: "com.google.Chrome"},
+			[]string{KeyPlayPause, KeyPrevious, KeyNext, KeyStop, KeyInfo, "jump"}},
+		{"tv", NowPlaying{BundleID: "com.apple.TV"},
+			[]string{KeyPlayPause, KeyStop, KeyInfo, "jump"}},
+		{"unknown app", NowPlaying{BundleID: "com.example.player"},
+			[]string{KeyPlayPause, KeyPrevious, KeyNext, KeyStop, KeyInfo, "jump"}},
+		{"unknown app reporting shuffle", NowPlaying{BundleID: "com.example.player", Shuffle: ShuffleOn},
+			[]string{KeyPlayPause, KeyPrevious, KeyNext, KeyStop, KeyInfo, KeyShuffle, "jump"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newStripModule(t)
+			m.config.Keys = allBindings
+			var keys []module.KeyID
+			for i := range allBindings {
+				keys = append(keys, module.KeyID(i+1))
+			}
+			m.BaseModule.Init(context.Background(), module.Resources{Keys: keys})
+			np := tt.np
+			np.Title, np.Playing = "T", true
+			m.SetNowPlaying(np)
+
+			images := m.RenderKeys()
+			var drawn []string
+			for i, b := range allBindings {
+				if isBlank(images[module.KeyID(i+1)]) {
+					continue
+				}
+				if b.Jump != 0 {
+					drawn = append(drawn, "jump")
+				} else {
+					drawn = append(drawn, b.Action)
+				}
+			}
+			if !slices.Equal(drawn, tt.want) {
+				t.Errorf("drew %q, want %q", drawn, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsupportedKeyDoesNothing(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+	m.config.Keys = []KeyBinding{{Action: KeyLike}}
+	m.BaseModule.Init(context.Background(), module.Resources{Keys: []module.KeyID{1}})
+	m.SetNowPlaying(NowPlaying{Title: "T", BundleID: "com.spotify.client", Playing: true})
+
+	if err := m.HandleKey(1, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatalf("HandleKey: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := fake.Commands(); len(got) != 0 {
+		t.Errorf("like on Spotify ran %q, want nothing", got)
+	}
+}
@@ -0,0 +1,107 @@
+package nowplaying
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// debugMaxString is the longest string value shown verbatim in the debug
+// overlay. Longer values (artwork data, mostly) are replaced by their size.
+const debugMaxString = 64
+
+var colorDebugText = color.RGBA{170, 220, 170, 255}
+
+// renderDebugStrip renders the most recent raw media-control line across the
+// full strip.
+func renderDebugStrip(rect image.Rectangle, raw string) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	drawDebugText(img, raw, image.Rect(8, 4, rect.Dx()-8, rect.Dy()-4))
+	return img
+}
+
+// drawDebugText draws the raw payload wrapped to fit area, truncating with an
+// ellipsis when it runs out of lines.
+func drawDebugText(img *image.RGBA, raw string, area image.Rectangle) {
+	face := basicfont.Face7x13
+	lineHeight := render.LineHeight(face)
+	lines := debugLines(raw, face, area.Dx(), area.Dy()/lineHeight)
+
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(colorDebugText),
+		Face: face,
+	}
+	for i, line := range lines {
+		y := area.Min.Y + i*lineHeight + face.Metrics().Ascent.Ceil()
+		d.Dot = fixed.Point26_6{X: fixed.I(area.Min.X), Y: fixed.I(y)}
+		d.DrawString(line)
+	}
+}
+
+// debugLines wraps the raw payload to width, keeping at most maxLines lines
+// and ending the last with an ellipsis if any were dropped.
+func debugLines(raw string, face font.Face, width, maxLines int) []string {
+	if maxLines < 1 {
+		return nil
+	}
+	lines := render.WrapText(face, debugText(raw), width)
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncateText(lines[maxLines-1]+" ...", face, width)
+	}
+	return lines
+}
+
+// debugText formats a raw stream line for display: long string values are
+// elided and spaces are added between fields so the line can wrap.
+func debugText(raw string) string {
+	if raw == "" {
+		return "(no payload received yet)"
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return raw
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(elideLongStrings(v)); err != nil {
+		return raw
+	}
+
+	text := strings.ReplaceAll(strings.TrimSpace(buf.String()), `,"`, `, "`)
+	return strings.ReplaceAll(text, `":`, `": `)
+}
+
+// elideLongStrings replaces string values longer than debugMaxString with a
+// placeholder noting their length.
+func elideLongStrings(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			val[k] = elideLongStrings(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = elideLongStrings(item)
+		}
+	case string:
+		if len(val) > debugMaxString {
+			return fmt.Sprintf("<%d bytes>", len(val))
+		}
+	}
+	return v
+}
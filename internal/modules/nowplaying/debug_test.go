@@ -0,0 +1,103 @@
+package nowplaying
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestStreamCapturesLastRawLine(t *testing.T) {
+	m := New(nil)
+	last := `{"diff":true,"payload":{"artist":"Someone"}}`
+	m.streamCommand = []string{"sh", "-c", `
+echo '{"diff":false,"payload":{"title":"T"}}'
+echo '` + last + `'
+echo 'not json'`}
+
+	m.startMediaStream(context.Background())
+
+	if got := m.liveState.getRaw(); got != last {
+		t.Errorf("raw = %q, want the last valid line %q", got, last)
+	}
+	if np := m.liveState.get(); np.Title != "T" || np.Artist != "Someone" {
+		t.Errorf("state = %q by %q, want both lines merged", np.Title, np.Artist)
+	}
+}
+
+func TestDebugText(t *testing.T) {
+	art := strings.Repeat("A", 5000)
+	tests := []struct {
+		name, raw, want string
+	}{
+		{"nothing yet", "", "(no payload received yet)"},
+		{"not json", "garbled{", "garbled{"},
+		{
+			"artwork elided",
+			`{"diff":false,"payload":{"artworkData":"` + art + `","title":"T"}}`,
+			`{"diff": false, "payload": {"artworkData": "<5000 bytes>", "title": "T"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := debugText(tt.raw); got != tt.want {
+				t.Errorf("debugText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugLinesTruncated(t *testing.T) {
+	face := basicfont.Face7x13
+	var fields []string
+	for i := range 60 {
+		fields = append(fields, fmt.Sprintf(`"field%d":"some value"`, i))
+	}
+	raw := `{"diff":false,"payload":{` + strings.Join(fields, ",") + `}}`
+
+	lines := debugLines(raw, face, 300, 4)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want the 4 that fit", len(lines))
+	}
+	for _, line := range lines {
+		if w := font.MeasureString(face, line).Ceil(); w > 300 {
+			t.Errorf("line %q is %dpx wide, more than 300", line, w)
+		}
+	}
+	if last := lines[3]; !strings.HasSuffix(last, "...") {
+		t.Errorf("last line %q doesn't end with an ellipsis", last)
+	}
+
+	// A payload that fits isn't marked as truncated
+	short := debugLines(`{"diff":true,"payload":{}}`, face, 300, 4)
+	if len(short) != 1 || strings.HasSuffix(short[0], "...") {
+		t.Errorf("short payload laid out as %q, want one untruncated line", short)
+	}
+}
+
+func TestDebugStripRendersPayload(t *testing.T) {
+	rect := image.Rect(0, 0, 800, 100)
+	raw := `{"diff":false,"payload":{"title":"` + strings.Repeat("Long Title ", 80) + `"}}`
+	img := renderDebugStrip(rect, raw).(*image.RGBA)
+
+	// Text is drawn, and only within the inset area
+	drawn := false
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			if img.RGBAAt(x, y) == colorBackground {
+				continue
+			}
+			drawn = true
+			if !image.Pt(x, y).In(image.Rect(8, 4, 792, 96)) {
+				t.Fatalf("text at (%d, %d), outside the strip's margins", x, y)
+			}
+		}
+	}
+	if !drawn {
+		t.Error("debug strip shows no text")
+	}
+}
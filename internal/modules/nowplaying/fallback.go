@@ -0,0 +1,104 @@
+package nowplaying
+
+import (
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// fallbackArtExts are the image files used from the fallback art directory.
+var fallbackArtExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true}
+
+// artKey normalizes a name for matching fallback art files: lowercased,
+// with each run of anything but letters and digits turned into a dash, e.g.
+// "AC/DC" becomes "ac-dc".
+func artKey(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// indexFallbackArt returns the image files in dir by the artKey of their
+// names without the extension.
+func indexFallbackArt(dir string) map[string]string {
+	files := make(map[string]string)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("NowPlaying: can't read fallback art: %v", err)
+		return files
+	}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || !fallbackArtExts[strings.ToLower(ext)] {
+			continue
+		}
+		files[artKey(strings.TrimSuffix(e.Name(), ext))] = filepath.Join(dir, e.Name())
+	}
+	return files
+}
+
+// fallbackArtPath returns the file in ArtFallbackDir to show for np, which
+// has no art of its own, or "" if there's none. Files are matched by name
+// as "<artist> - <album>", then the album, then the artist, compared with
+// artKey so case and punctuation don't matter. The directory is listed once
+// and each track's lookup is cached.
+func (m *Module) fallbackArtPath(np *NowPlaying) string {
+	if m.config.ArtFallbackDir == "" {
+		return ""
+	}
+
+	m.fallbackMu.Lock()
+	defer m.fallbackMu.Unlock()
+	if m.fallbackFiles == nil {
+		m.fallbackFiles = indexFallbackArt(m.config.ArtFallbackDir)
+		m.fallbackPaths = make(map[string]string)
+	}
+
+	track := np.Artist + "\x00" + np.Album
+	if path, ok := m.fallbackPaths[track]; ok {
+		return path
+	}
+
+	var path string
+	for _, name := range []string{np.Artist + " - " + np.Album, np.Album, np.Artist} {
+		key := artKey(name)
+		if p, ok := m.fallbackFiles[key]; ok && key != "" {
+			path = p
+			break
+		}
+	}
+	m.fallbackPaths[track] = path
+	return path
+}
+
+// loadArtworkFile reads an artwork image file, scaled down like decoded
+// artwork. It returns nil if the file can't be read.
+func loadArtworkFile(path string, maxSize int) image.Image {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("NowPlaying: can't open fallback art: %v", err)
+		return nil
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		log.Printf("NowPlaying: can't decode fallback art %s: %v", path, err)
+		return nil
+	}
+	return limitImageSize(img, maxSize)
+}
@@ -0,0 +1,73 @@
+package nowplaying
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeArt writes an 8px PNG filled with col to dir/name.
+func writeArt(t *testing.T, dir, name string, col color.RGBA) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = col.R, col.G, col.B, col.A
+	}
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArtKey(t *testing.T) {
+	for name, want := range map[string]string{
+		"Daft Punk":             "daft-punk",
+		"AC/DC":                 "ac-dc",
+		"AC_DC":                 "ac-dc",
+		"  Sigur Rós!  ":        "sigur-rós",
+		"Daft Punk - Discovery": "daft-punk-discovery",
+		"...":                   "",
+	} {
+		if got := artKey(name); got != want {
+			t.Errorf("artKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFallbackArtRendered(t *testing.T) {
+	dir := t.TempDir()
+	green, blue := color.RGBA{0, 200, 0, 255}, color.RGBA{0, 0, 200, 255}
+	writeArt(t, dir, "Daft Punk.PNG", green)
+	writeArt(t, dir, "daft_punk - discovery.png", blue)
+	os.WriteFile(filepath.Join(dir, "Homework.txt"), []byte("not art"), 0o644)
+
+	m := newStripModule(t)
+	m.config.ArtFallbackDir = dir
+	artCenter := func(np NowPlaying) color.Color {
+		m.SetNowPlaying(np)
+		return color.RGBAModel.Convert(m.RenderStrip().At(50, 50))
+	}
+
+	if got := artCenter(NowPlaying{Title: "One More Time", Artist: "Daft Punk", Album: "Discovery"}); got != blue {
+		t.Errorf("art for an artist and album match = %v, want %v", got, blue)
+	}
+	if got := artCenter(NowPlaying{Title: "Da Funk", Artist: "Daft Punk", Album: "Homework"}); got != green {
+		t.Errorf("art for an artist match = %v, want %v", got, green)
+	}
+	if got := artCenter(NowPlaying{Title: "T", Artist: "Someone Else"}); got == green || got == blue {
+		t.Errorf("art for no match = %v, want the placeholder", got)
+	}
+
+	// The directory is only listed once
+	writeArt(t, dir, "Another.png", green)
+	if path := m.fallbackArtPath(&NowPlaying{Artist: "Another"}); path != "" {
+		t.Errorf("fallbackArtPath found %s added after the first lookup", path)
+	}
+}
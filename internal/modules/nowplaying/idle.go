@@ -0,0 +1,120 @@
+package nowplaying
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+)
+
+// Idle animations, as set with NOWPLAYING_IDLE_ANIMATION.
+const (
+	idleOff      = "off"
+	idleGradient = "gradient"
+	idleBars     = "bars"
+)
+
+const (
+	// idleHueStep is how far the gradient's hue moves each frame, in
+	// degrees, so it takes a few minutes to go all the way around.
+	idleHueStep = 1.5
+
+	// idleHueSpan is how much the hue varies across the strip, in degrees.
+	idleHueSpan = 60
+
+	// idleBarWidth and idleBarGap size the visualizer's bars.
+	idleBarWidth = 10
+	idleBarGap   = 6
+)
+
+// nothingPlaying reports whether np has no track to show, as before
+// media-control's first update or after a player quits.
+func nothingPlaying(np *NowPlaying) bool {
+	return !np.Playing && (np.Title == "" || np.Title == "?")
+}
+
+// nextIdleFrame returns the idle animation's next frame number.
+func (m *Module) nextIdleFrame() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idleFrame++
+	return m.idleFrame
+}
+
+// renderIdleStrip renders frame of the idle animation into region, with
+// "Nothing playing" over it. Each frame is cheap to draw, since it's redrawn
+// on every render tick.
+func (m *Module) renderIdleStrip(rect, region image.Rectangle, frame int) image.Image {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	switch m.config.IdleAnimation {
+	case idleGradient:
+		drawIdleGradient(img, region, frame)
+	case idleBars:
+		drawIdleBars(img, region, frame, m.config.ProgressPlaying)
+	}
+
+	text := "Nothing playing"
+	textX := region.Min.X + (region.Dx()-font.MeasureString(m.artistFace, text).Ceil())/2
+	m.drawText(img, text, textX, region.Min.Y+region.Dy()/2+6, m.artistFace, colorArtist, region.Dx())
+	return img
+}
+
+// drawIdleGradient fills region with a dim horizontal gradient whose hues
+// drift with frame. Each column is a single color, so it's filled as one
+// rectangle.
+func drawIdleGradient(img *image.RGBA, region image.Rectangle, frame int) {
+	base := float64(frame) * idleHueStep
+	for x := region.Min.X; x < region.Max.X; x++ {
+		hue := base + float64(x-region.Min.X)*idleHueSpan/float64(region.Dx())
+		col := hsvColor(hue, 0.6, 0.22)
+		draw.Draw(img, image.Rect(x, region.Min.Y, x+1, region.Max.Y), &image.Uniform{col}, image.Point{}, draw.Src)
+	}
+}
+
+// drawIdleBars draws a row of dim bars rising from the bottom of region,
+// their heights swaying with frame like a slow visualizer.
+func drawIdleBars(img *image.RGBA, region image.Rectangle, frame int, col color.Color) {
+	r, g, b, _ := col.RGBA()
+	barColor := color.RGBA{uint8(r >> 10), uint8(g >> 10), uint8(b >> 10), 255}
+
+	t := float64(frame) * 0.35
+	maxH := float64(region.Dy()) * 0.7
+	for i, x := 0, region.Min.X+idleBarGap; x+idleBarWidth <= region.Max.X; i, x = i+1, x+idleBarWidth+idleBarGap {
+		fi := float64(i)
+		level := 0.5 + 0.3*math.Sin(t+fi*0.7) + 0.2*math.Sin(t*1.7+fi*1.3)
+		h := int(maxH * (0.15 + 0.85*level))
+		bar := image.Rect(x, region.Max.Y-h, x+idleBarWidth, region.Max.Y)
+		draw.Draw(img, bar, &image.Uniform{barColor}, image.Point{}, draw.Src)
+	}
+}
+
+// hsvColor converts a hue in degrees, saturation and value to a color.
+func hsvColor(h, s, v float64) color.RGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	m := v - c
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
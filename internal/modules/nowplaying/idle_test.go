@@ -0,0 +1,69 @@
+package nowplaying
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+// newStripModule returns a module rendering into the left half of a fake
+// device's strip, configured as loadConfig would with no environment.
+func newStripModule(t *testing.T) *Module {
+	t.Helper()
+	m := New(device.NewFake())
+	m.config = loadConfig()
+	m.initFonts()
+	m.BaseModule.Init(context.Background(), module.Resources{StripRect: image.Rect(0, 0, 400, 100)})
+	return m
+}
+
+// pixels returns img's pixel data.
+func pixels(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		t.Fatalf("strip is a %T, want *image.RGBA", img)
+	}
+	return rgba.Pix
+}
+
+func TestIdleAnimationFramesDiffer(t *testing.T) {
+	for _, mode := range []string{idleGradient, idleBars} {
+		t.Run(mode, func(t *testing.T) {
+			m := newStripModule(t)
+			m.config.IdleAnimation = mode
+
+			first := pixels(t, m.RenderStrip())
+			second := pixels(t, m.RenderStrip())
+			if bytes.Equal(first, second) {
+				t.Error("successive idle renders are identical, want the animation to move")
+			}
+		})
+	}
+}
+
+func TestIdleAnimationOnlyWhileNothingPlays(t *testing.T) {
+	m := newStripModule(t)
+	m.config.IdleAnimation = idleGradient
+	m.SetNowPlaying(NowPlaying{Title: "Song", Artist: "Artist"})
+
+	first := pixels(t, m.RenderStrip())
+	second := pixels(t, m.RenderStrip())
+	if !bytes.Equal(first, second) {
+		t.Error("strip with a paused track changed between renders, want it still")
+	}
+}
+
+func TestIdleAnimationOff(t *testing.T) {
+	m := newStripModule(t)
+
+	first := pixels(t, m.RenderStrip())
+	second := pixels(t, m.RenderStrip())
+	if !bytes.Equal(first, second) {
+		t.Error("idle strip changed between renders with the animation off")
+	}
+}
@@ -0,0 +1,65 @@
+package nowplaying
+
+import (
+	"testing"
+
+	"golang.org/x/image/font"
+)
+
+// The info overlay's text area on an 800x100 strip with artwork: right of
+// the 100px thumb, inset by the margins.
+const (
+	infoWidth  = 800 - 100 - 24
+	infoHeight = 100 - 12
+)
+
+func TestInfoLayoutWrapsLongMetadata(t *testing.T) {
+	m := newStripModule(t)
+	np := &NowPlaying{
+		Title:  "A Remarkably Long Song Title That Goes On And On Well Past The Width Of The Strip",
+		Artist: "The Orchestra And Chorus Of Somewhere Quite Far Away",
+		Album:  "Collected Recordings, Volume Three: The Later Years (Remastered Deluxe Edition)",
+	}
+
+	layout := m.layoutInfo(np, infoWidth, infoHeight)
+	if layout == nil {
+		t.Fatal("no layout for long metadata")
+	}
+	if len(layout.title)+len(layout.detail) <= 2 {
+		t.Errorf("laid out %d title and %d detail lines, want the metadata wrapped", len(layout.title), len(layout.detail))
+	}
+	if h := layout.height(); h > infoHeight {
+		t.Errorf("text is %dpx tall, more than the %dpx area", h, infoHeight)
+	}
+	check := func(lines []string, face font.Face) {
+		for _, line := range lines {
+			if w := font.MeasureString(face, line).Ceil(); w > infoWidth {
+				t.Errorf("line %q is %dpx wide, more than the %dpx area", line, w, infoWidth)
+			}
+		}
+	}
+	check(layout.title, layout.titleFace)
+	check(layout.detail, layout.detailFace)
+
+	// Long metadata is set smaller than a short title
+	short := fitInfoText(m.boldFont, m.regularFont, "Hey", "Someone", infoWidth, infoHeight)
+	if layout.titleFace.Metrics().Height >= short.titleFace.Metrics().Height {
+		t.Error("long metadata set at the same size as a short title, want it shrunk to fit")
+	}
+	if len(short.title) != 1 || len(short.detail) != 1 {
+		t.Errorf("short metadata laid out on %d and %d lines, want 1 and 1", len(short.title), len(short.detail))
+	}
+}
+
+func TestInfoLayoutCached(t *testing.T) {
+	m := newStripModule(t)
+	np := &NowPlaying{Title: "Hey", Artist: "Someone"}
+
+	first := m.layoutInfo(np, infoWidth, infoHeight)
+	if again := m.layoutInfo(np, infoWidth, infoHeight); again != first {
+		t.Error("same metadata and area laid out again, want the cached layout")
+	}
+	if other := m.layoutInfo(np, infoWidth/2, infoHeight); other == first {
+		t.Error("a new area reused the layout for another area")
+	}
+}
@@ -0,0 +1,144 @@
+package nowplaying
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// Key actions that can be bound to the module's keys with NOWPLAYING_KEYS.
+const (
+	KeyPlayPause = "play-pause"
+	KeyPrevious  = "previous"
+	KeyNext      = "next"
+	KeyStop      = "stop"
+	KeyInfo      = "info"
+	KeyShuffle   = "shuffle"
+	KeyRepeat    = "repeat"
+	KeyLike      = "like"
+	KeyDislike   = "dislike"
+)
+
+// defaultKeys is the key mapping used when NOWPLAYING_KEYS is unset.
+var defaultKeys = []KeyBinding{{Action: KeyPlayPause}, {Action: KeyInfo}}
+
+// KeyBinding is what one of the module's keys does: a key action such as
+// KeyPlayPause, or a relative seek when Jump is non-zero.
+type KeyBinding struct {
+	Action string
+	Jump   time.Duration
+}
+
+// parseKeyBindings parses a comma-separated list of key actions, one per
+// key in order, e.g. "previous,play-pause,next". A signed duration such as
+// "+30s" binds a jump.
+func parseKeyBindings(v string) ([]KeyBinding, error) {
+	var keys []KeyBinding
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case KeyPlayPause, KeyPrevious, KeyNext, KeyStop, KeyInfo, KeyShuffle, KeyRepeat, KeyLike, KeyDislike:
+			keys = append(keys, KeyBinding{Action: part})
+			continue
+		}
+
+		jump, err := time.ParseDuration(part)
+		if err != nil || jump == 0 {
+			return nil, fmt.Errorf("unknown key action %q", part)
+		}
+		keys = append(keys, KeyBinding{Jump: jump})
+	}
+	return keys, nil
+}
+
+// renderKeyBinding renders the key for a binding. playing selects play or
+// pause for KeyPlayPause. Keys the playing app doesn't respond to are left
+// blank.
+func (m *Module) renderKeyBinding(b KeyBinding, size int, np *NowPlaying, playing bool) image.Image {
+	if !supports(np, b) {
+		c := render.NewKeyCanvas(size)
+		c.Fill(c.Bounds(), color.Black)
+		return c.Image()
+	}
+	if b.Jump != 0 {
+		return m.renderJumpKey(size, b.Jump)
+	}
+
+	switch b.Action {
+	case KeyPlayPause:
+		c := iconKeyCanvas(size, iconPlaySVG, colorLimeGreen)
+		if playing {
+			c = iconKeyCanvas(size, iconPauseSVG, colorOrange)
+		}
+		m.drawModeBadges(c, np)
+		return c.Image()
+	case KeyPrevious:
+		return iconKeyCanvas(size, iconSkipBackSVG, color.White).Image()
+	case KeyNext:
+		return iconKeyCanvas(size, iconSkipForwardSVG, color.White).Image()
+	case KeyStop:
+		return iconKeyCanvas(size, iconStopSVG, colorOrange).Image()
+	case KeyInfo:
+		return iconKeyCanvas(size, iconInfoSVG, colorDeepSkyBlue).Image()
+	case KeyShuffle:
+		return iconKeyCanvas(size, iconShuffleSVG, modeColor(np.Shuffle == ShuffleOn)).Image()
+	case KeyRepeat:
+		c := iconKeyCanvas(size, iconRepeatSVG, modeColor(np.Repeat == RepeatAll || np.Repeat == RepeatOne))
+		if np.Repeat == RepeatOne {
+			c.DrawStringCentered("1", size/2, size/2+5, m.modeFace, colorDeepSkyBlue)
+		}
+		return c.Image()
+	case KeyLike:
+		return iconKeyCanvas(size, iconThumbsUpSVG, colorLimeGreen).Image()
+	case KeyDislike:
+		return iconKeyCanvas(size, iconThumbsDownSVG, colorTime).Image()
+	}
+	return nil
+}
+
+// modeColor returns the icon color for a shuffle or repeat key.
+func modeColor(on bool) color.Color {
+	if on {
+		return colorDeepSkyBlue
+	}
+	return colorTime
+}
+
+// runKeyBinding performs a key's action, unless the playing app doesn't
+// respond to it.
+func (m *Module) runKeyBinding(b KeyBinding) {
+	if np := m.liveState.get(); !supports(&np, b) {
+		log.Printf("Key: %s not supported by %s", b.Action, np.BundleID)
+		return
+	}
+	if b.Jump != 0 {
+		m.jump(b.Jump)
+		return
+	}
+
+	log.Printf("Key: %s", b.Action)
+	if b.Action == KeyInfo {
+		m.runInfoAction()
+		return
+	}
+	if err := m.HandleAction(b.Action); err != nil {
+		log.Printf("Key action %s: %v", b.Action, err)
+	}
+}
+
+// runInfoAction performs the info key's configured InfoAction.
+func (m *Module) runInfoAction() {
+	switch m.config.InfoAction {
+	case "app":
+		go m.openApp()
+	case "copy":
+		go m.copyTrack()
+	default:
+		m.showOverlay(overlayInfo)
+	}
+}
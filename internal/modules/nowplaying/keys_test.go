@@ -0,0 +1,117 @@
+package nowplaying
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+func TestParseKeyBindings(t *testing.T) {
+	got, err := parseKeyBindings("previous, play-pause,next,-15s")
+	if err != nil {
+		t.Fatalf("parseKeyBindings: %v", err)
+	}
+	want := []KeyBinding{{Action: KeyPrevious}, {Action: KeyPlayPause}, {Action: KeyNext}, {Jump: -15 * time.Second}}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseKeyBindings = %v, want %v", got, want)
+	}
+
+	for _, v := range []string{"play-pause,rewind", "0s", ""} {
+		if _, err := parseKeyBindings(v); err == nil {
+			t.Errorf("parseKeyBindings(%q) succeeded, want an error", v)
+		}
+	}
+}
+
+func TestReconfiguredKeys(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+	m.config.Keys = []KeyBinding{{Action: KeyPrevious}, {Action: KeyStop}, {Action: KeyNext}}
+	m.BaseModule.Init(context.Background(), module.Resources{Keys: []module.KeyID{5, 6, 7}})
+	m.SetNowPlaying(NowPlaying{Title: "T", Playing: true})
+
+	keys := m.RenderKeys()
+	if len(keys) != 3 {
+		t.Fatalf("rendered %d keys, want 3", len(keys))
+	}
+	if bytes.Equal(keys[5].(*image.RGBA).Pix, keys[7].(*image.RGBA).Pix) {
+		t.Error("previous and next keys render the same image")
+	}
+
+	if err := m.HandleKey(7, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatalf("HandleKey: %v", err)
+	}
+	if err := m.HandleKey(6, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatalf("HandleKey: %v", err)
+	}
+
+	// Commands run in the background, in either order
+	want := []string{"media-control next-track", "media-control stop"}
+	deadline := time.Now().Add(time.Second)
+	for {
+		got := fake.Commands()
+		slices.Sort(got)
+		if slices.Equal(got, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ran %q, want %q", got, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestInfoKeyAction(t *testing.T) {
+	commandLine := func(name string, args []string) string {
+		return strings.Join(append([]string{name}, args...), " ")
+	}
+	tests := []struct {
+		action      string
+		wantOverlay bool
+		wantCommand string
+	}{
+		{"overlay", true, ""},
+		{"app", false, commandLine(platform.OpenApp("Music"))},
+		{"copy", false, commandLine(platform.Copy())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			m := newStripModule(t)
+			fake := &runner.Fake{}
+			m.runner = fake
+			m.config.Keys = []KeyBinding{{Action: KeyInfo}}
+			m.config.InfoAction = tt.action
+			m.config.App = "Music"
+			m.BaseModule.Init(context.Background(), module.Resources{Keys: []module.KeyID{6}})
+			m.SetNowPlaying(NowPlaying{Artist: "A", Title: "T", Playing: true})
+
+			if err := m.HandleKey(6, module.KeyEvent{Pressed: true}); err != nil {
+				t.Fatalf("HandleKey: %v", err)
+			}
+			if got := m.IsOverlayActive(); got != tt.wantOverlay {
+				t.Errorf("overlay active = %v, want %v", got, tt.wantOverlay)
+			}
+
+			var want []string
+			if tt.wantCommand != "" {
+				want = []string{tt.wantCommand}
+			}
+			deadline := time.Now().Add(time.Second)
+			for got := fake.Commands(); !slices.Equal(got, want); got = fake.Commands() {
+				if time.Now().After(deadline) {
+					t.Fatalf("ran %q, want %q", got, want)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package nowplaying
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lastfmAPIURL is the last.fm API endpoint.
+const lastfmAPIURL = "https://ws.audioscrobbler.com/2.0/"
+
+// LastfmCredentials authenticate the last.fm scrobbler. The API key and
+// secret come from a last.fm API account. The session key authorizes
+// scrobbling to a user's profile; without one, it's fetched once with
+// Username and Password.
+type LastfmCredentials struct {
+	APIKey     string
+	APISecret  string
+	SessionKey string
+	Username   string
+	Password   string
+}
+
+// scrobbleTrack is a track as submitted to last.fm.
+type scrobbleTrack struct {
+	Artist   string
+	Title    string
+	Album    string
+	Duration time.Duration // zero if unknown
+}
+
+// params returns the track's API parameters.
+func (t scrobbleTrack) params() url.Values {
+	params := url.Values{
+		"artist": {t.Artist},
+		"track":  {t.Title},
+	}
+	if t.Album != "" {
+		params.Set("album", t.Album)
+	}
+	if t.Duration > 0 {
+		params.Set("duration", strconv.Itoa(int(t.Duration/time.Second)))
+	}
+	return params
+}
+
+// lastfmClient is a last.fm API client for scrobbling.
+type lastfmClient struct {
+	baseURL    string
+	creds      LastfmCredentials
+	httpClient *http.Client
+}
+
+// lastfmError is an error reported by the last.fm API.
+type lastfmError struct {
+	Code    int
+	Message string
+}
+
+func (e *lastfmError) Error() string {
+	return fmt.Sprintf("last.fm error %d: %s", e.Code, e.Message)
+}
+
+// signLastfm returns the api_sig for a call with params: the MD5 of every
+// parameter name and value, sorted by name, followed by the secret.
+// "format" and "callback" aren't signed.
+func signLastfm(params url.Values, secret string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "format" && name != "callback" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(params.Get(name))
+	}
+	b.WriteString(secret)
+
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// call makes a signed POST of method with params, decoding the response
+// into out if it's non-nil.
+func (c *lastfmClient) call(ctx context.Context, method string, params url.Values, out any) error {
+	params.Set("method", method)
+	params.Set("api_key", c.creds.APIKey)
+	params.Set("api_sig", signLastfm(params, c.creds.APISecret))
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Errors come back as JSON, whatever the status
+	var result struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("API error: %s", resp.Status)
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if err := json.Unmarshal(body, &result); err == nil && result.Error != 0 {
+		return &lastfmError{Code: result.Error, Message: result.Message}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// session returns the session key, fetching one with the username and
+// password if none is configured.
+func (c *lastfmClient) session(ctx context.Context) (string, error) {
+	if c.creds.SessionKey != "" {
+		return c.creds.SessionKey, nil
+	}
+
+	var result struct {
+		Session struct {
+			Key string `json:"key"`
+		} `json:"session"`
+	}
+	params := url.Values{
+		"username": {c.creds.Username},
+		"password": {c.creds.Password},
+	}
+	if err := c.call(ctx, "auth.getMobileSession", params, &result); err != nil {
+		return "", err
+	}
+	if result.Session.Key == "" {
+		return "", fmt.Errorf("no session key in response")
+	}
+	c.creds.SessionKey = result.Session.Key
+	return c.creds.SessionKey, nil
+}
+
+// updateNowPlaying tells last.fm that track has started playing.
+func (c *lastfmClient) updateNowPlaying(ctx context.Context, track scrobbleTrack) error {
+	sk, err := c.session(ctx)
+	if err != nil {
+		return err
+	}
+	params := track.params()
+	params.Set("sk", sk)
+	return c.call(ctx, "track.updateNowPlaying", params, nil)
+}
+
+// scrobble records that track was played, having started at startedAt.
+func (c *lastfmClient) scrobble(ctx context.Context, track scrobbleTrack, startedAt time.Time) error {
+	sk, err := c.session(ctx)
+	if err != nil {
+		return err
+	}
+	params := track.params()
+	params.Set("timestamp", strconv.FormatInt(startedAt.Unix(), 10))
+	params.Set("sk", sk)
+	return c.call(ctx, "track.scrobble", params, nil)
+}
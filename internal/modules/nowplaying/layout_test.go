@@ -0,0 +1,81 @@
+package nowplaying
+
+import (
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/render"
+)
+
+// The strip text area used in these tests: the width beside the art, and
+// the band above the time and progress bar on a 100px strip.
+const (
+	stripBandWidth  = 250
+	stripBandTop    = 6
+	stripBandBottom = 62
+)
+
+const longTitle = "An Extremely Long Song Title That Cannot Possibly Fit On One Line"
+
+func TestStripTextLayout(t *testing.T) {
+	m := newStripModule(t)
+	for _, tt := range []struct {
+		name          string
+		title, artist string
+		titleLines    int
+		titleSize     float64
+	}{
+		{"short title alone", "Hey", "", 1, 36},
+		{"short title with artist", "Hey", "Someone", 1, 28},
+		{"long title with artist", longTitle, "Someone", 1, 24},
+		{"long title alone", longTitle, "", 2, 20},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := fitStripText(m.boldFont, m.artistFace, tt.title, tt.artist, stripBandWidth, stripBandTop, stripBandBottom)
+
+			titleLines, artistLines := 0, 0
+			for _, line := range layout.lines {
+				if line.artist {
+					artistLines++
+				} else {
+					titleLines++
+				}
+				if top := line.y - line.face.Metrics().Ascent.Ceil(); top < stripBandTop {
+					t.Errorf("line %q starts at %d, above the band", line.text, top)
+				}
+				if bottom := line.y - line.face.Metrics().Ascent.Ceil() + render.LineHeight(line.face); bottom > stripBandBottom {
+					t.Errorf("line %q ends at %d, below the band", line.text, bottom)
+				}
+			}
+			if titleLines != tt.titleLines {
+				t.Errorf("got %d title lines, want %d", titleLines, tt.titleLines)
+			}
+			wantArtist := 0
+			if tt.artist != "" {
+				wantArtist = 1
+			}
+			if artistLines != wantArtist {
+				t.Errorf("got %d artist lines, want %d", artistLines, wantArtist)
+			}
+
+			got := render.LineHeight(layout.lines[0].face)
+			if want := render.LineHeight(render.NewFace(m.boldFont, tt.titleSize)); got != want {
+				t.Errorf("title line height = %d, want %d (%gpx)", got, want, tt.titleSize)
+			}
+		})
+	}
+}
+
+func TestStripTextCentered(t *testing.T) {
+	m := newStripModule(t)
+	layout := fitStripText(m.boldFont, m.artistFace, "", "Someone", stripBandWidth, stripBandTop, stripBandBottom)
+	if len(layout.lines) != 1 {
+		t.Fatalf("got %d lines for an artist alone, want 1", len(layout.lines))
+	}
+
+	line := layout.lines[0]
+	top := line.y - line.face.Metrics().Ascent.Ceil()
+	above, below := top-stripBandTop, stripBandBottom-(top+render.LineHeight(line.face))
+	if diff := above - below; diff < -1 || diff > 1 {
+		t.Errorf("artist line has %dpx above and %dpx below, want it centered", above, below)
+	}
+}
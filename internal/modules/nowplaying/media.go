@@ -21,12 +21,30 @@ type NowPlaying struct {
 	Playing              bool   `json:"playing"`
 	ArtworkData          string `json:"artworkData"`
 	ArtworkMime          string `json:"artworkMimeType"`
+
+	// Shuffle and Repeat are empty until media-control reports them.
+	Shuffle ShuffleMode `json:"shuffleMode,omitempty"`
+	Repeat  RepeatMode  `json:"repeatMode,omitempty"`
+
+	// BundleID identifies the app playing, e.g. "com.spotify.client".
+	BundleID string `json:"bundleIdentifier,omitempty"`
 }
 
 // liveState wraps NowPlaying with thread-safe access.
 type liveState struct {
 	sync.RWMutex
 	NowPlaying
+
+	// raw is the most recent line received from media-control, kept for
+	// the debug overlay.
+	raw string
+
+	// updated is when the last stream update arrived.
+	updated time.Time
+
+	// seeked is when the module last asked media-control to seek. Updates
+	// soon after may step back on purpose, so they aren't smoothed over.
+	seeked time.Time
 }
 
 // newLiveState creates a new liveState.
@@ -41,15 +59,63 @@ func (s *liveState) get() NowPlaying {
 	return s.NowPlaying
 }
 
+// getRaw returns the most recent raw stream line.
+func (s *liveState) getRaw() string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.raw
+}
+
+// lastUpdate returns when the last stream update arrived.
+func (s *liveState) lastUpdate() time.Time {
+	s.RLock()
+	defer s.RUnlock()
+	return s.updated
+}
+
+// markSeek records that a seek was just requested.
+func (s *liveState) markSeek() {
+	s.Lock()
+	defer s.Unlock()
+	s.seeked = time.Now()
+}
+
+// set replaces the current state, recording raw as the latest stream line.
+func (s *liveState) set(np NowPlaying, raw string) {
+	s.Lock()
+	defer s.Unlock()
+	s.NowPlaying = np
+	s.raw = raw
+}
+
+// SetNowPlaying replaces the module's playback state as if media-control had
+// sent a full update, so rendering and input handling can be driven without
+// media-control (in tests, or on machines without it). The real stream keeps
+// running and its next update is merged on top as usual.
+func (m *Module) SetNowPlaying(np NowPlaying) {
+	// Keep the debug overlay consistent by recording an equivalent stream
+	// line. NowPlaying is plain data, so marshaling can't fail.
+	payload, _ := json.Marshal(np)
+	raw, _ := json.Marshal(StreamPayload{Payload: payload})
+	m.liveState.set(np, string(raw))
+}
+
 // StreamPayload wraps the stream JSON structure with raw payload for proper merging.
 type StreamPayload struct {
 	Diff    bool            `json:"diff"`
 	Payload json.RawMessage `json:"payload"`
 }
 
-// startMediaStream runs the media-control stream and updates state.
+// streamWaitDelay bounds how long the stream waits for media-control to
+// exit once canceled.
+const streamWaitDelay = time.Second
+
+// startMediaStream runs the media-control stream and updates state until
+// ctx is canceled. It returns promptly on cancel, even if a child of
+// media-control still holds its output open.
 func (m *Module) startMediaStream(ctx context.Context) {
-	cmd := exec.CommandContext(ctx, "media-control", "stream", "--micros")
+	cmd := exec.CommandContext(ctx, m.streamCommand[0], m.streamCommand[1:]...)
+	cmd.WaitDelay = streamWaitDelay
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		log.Printf("Failed to get stdout pipe: %v", err)
@@ -63,6 +129,11 @@ func (m *Module) startMediaStream(ctx context.Context) {
 
 	log.Println("Started media-control stream")
 
+	// Killing the process alone doesn't end the scan below while anything
+	// else holds the pipe open, so close it on cancel too
+	stop := context.AfterFunc(ctx, func() { stdout.Close() })
+	defer stop()
+
 	scanner := bufio.NewScanner(stdout)
 	// Increase buffer size for large artwork payloads
 	buf := make([]byte, 0, 1024*1024) // 1MB buffer
@@ -82,27 +153,53 @@ func (m *Module) startMediaStream(ctx context.Context) {
 		}
 
 		m.liveState.Lock()
-		if !envelope.Diff && len(payloadMap) == 0 {
-			// Reset to defaults
-			m.liveState.NowPlaying = NowPlaying{
-				Title:                "?",
-				Artist:               "?",
-				TimestampEpochMicros: time.Now().UnixMicro(),
-			}
-		} else {
-			// Merge only fields that are present in the payload
-			mergePayloadMap(&m.liveState.NowPlaying, payloadMap)
-		}
+		m.liveState.raw = string(line)
+		m.liveState.updated = time.Now()
+		seeking := time.Since(m.liveState.seeked) < seekSettle
+		m.liveState.NowPlaying = applyUpdate(m.liveState.NowPlaying, envelope.Diff, payloadMap, time.Now().UnixMicro(), seeking)
+		np := m.liveState.NowPlaying
 		m.liveState.Unlock()
+
+		if m.scrobbler != nil {
+			m.scrobbler.observe(np, time.Now())
+		}
 	}
 
-	if err := scanner.Err(); err != nil {
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
 		log.Printf("Scanner error: %v", err)
 	}
 
 	cmd.Wait()
 }
 
+// applyUpdate returns prev updated with a media-control stream payload, as
+// of nowMicros, with seeking set if the module just requested a seek. An empty full update resets to defaults. When the payload
+// names a different app than prev, the update starts from defaults too, so
+// the old app's artwork and progress aren't mixed with the new one's.
+// Otherwise only the fields present are merged, with timing that would make
+// progress jump around smoothed over.
+func applyUpdate(prev NowPlaying, diff bool, payload map[string]interface{}, nowMicros int64, seeking bool) NowPlaying {
+	reset := NowPlaying{
+		Title:                "?",
+		Artist:               "?",
+		TimestampEpochMicros: nowMicros,
+	}
+	if !diff && len(payload) == 0 {
+		return reset
+	}
+
+	if bundleID, ok := payload["bundleIdentifier"].(string); ok && prev.BundleID != "" && bundleID != prev.BundleID {
+		log.Printf("Media source changed: %s -> %s", prev.BundleID, bundleID)
+		next := reset
+		mergePayloadMap(&next, payload)
+		return next
+	}
+
+	next := prev
+	mergePayloadMap(&next, payload)
+	return reconcileTiming(prev, next, nowMicros, seeking)
+}
+
 // mergePayloadMap merges a map of fields into a NowPlaying struct.
 func mergePayloadMap(dst *NowPlaying, src map[string]interface{}) {
 	if v, ok := src["title"].(string); ok {
@@ -133,15 +230,95 @@ func mergePayloadMap(dst *NowPlaying, src map[string]interface{}) {
 	if v, ok := src["artworkMimeType"].(string); ok {
 		dst.ArtworkMime = v
 	}
+	if v, ok := parseShuffleMode(src["shuffleMode"]); ok {
+		dst.Shuffle = v
+	}
+	if v, ok := parseRepeatMode(src["repeatMode"]); ok {
+		dst.Repeat = v
+	}
+	if v, ok := src["bundleIdentifier"].(string); ok {
+		dst.BundleID = v
+	}
 }
 
 // getLiveElapsedMicros calculates the live elapsed time based on timestamp and playing state.
 func getLiveElapsedMicros(np *NowPlaying) int64 {
-	if !np.Playing {
-		return np.ElapsedTimeMicros
+	return liveElapsedAt(np, time.Now().UnixMicro())
+}
+
+// liveElapsedAt calculates the elapsed time at nowMicros, clamped to the
+// track.
+func liveElapsedAt(np *NowPlaying, nowMicros int64) int64 {
+	elapsed := np.ElapsedTimeMicros
+	if np.Playing {
+		// Calculate: elapsed + (now - timestamp)
+		elapsed += nowMicros - np.TimestampEpochMicros
+	}
+
+	if elapsed < 0 {
+		return 0
+	}
+	if np.DurationMicros > 0 && elapsed > np.DurationMicros {
+		return np.DurationMicros
+	}
+	return elapsed
+}
+
+const (
+	// maxClockSkewMicros is how far in the future a timestamp may be before
+	// it's treated as bogus and replaced with the current time.
+	maxClockSkewMicros = int64(time.Second / time.Microsecond)
+
+	// backwardJitterMicros is the largest backward step in elapsed time
+	// that's smoothed over rather than shown: about as far as reports of
+	// the same position drift apart. Anything larger is a seek, even a
+	// short rewind.
+	backwardJitterMicros = int64(300 * time.Millisecond / time.Microsecond)
+)
+
+// seekSettle is how long after the module requests a seek that updates are
+// taken as they come, since they may land just behind the old position.
+const seekSettle = 2 * time.Second
+
+// reconcileTiming sanity-checks the timing of an update merged into next
+// from prev, as of nowMicros. Within the same track it drops out-of-order
+// updates, rebases elapsed times whose timestamp didn't advance, and holds
+// progress steady across small backward steps so the bar doesn't jitter,
+// unless seeking is set because the module just requested a seek.
+func reconcileTiming(prev, next NowPlaying, nowMicros int64, seeking bool) NowPlaying {
+	if next.TimestampEpochMicros > nowMicros+maxClockSkewMicros {
+		next.TimestampEpochMicros = nowMicros
+	}
+
+	if !sameTrack(prev, next) || prev.TimestampEpochMicros == 0 {
+		return next
+	}
+
+	// An update older than what we already have is stale
+	if next.TimestampEpochMicros < prev.TimestampEpochMicros {
+		next.ElapsedTimeMicros = prev.ElapsedTimeMicros
+		next.TimestampEpochMicros = prev.TimestampEpochMicros
+		next.Playing = prev.Playing
+		return next
 	}
-	// Calculate: elapsed + (now - timestamp)
-	nowMicros := time.Now().UnixMicro()
-	timeDiff := nowMicros - np.TimestampEpochMicros
-	return np.ElapsedTimeMicros + timeDiff
+
+	// Elapsed moved but the timestamp didn't: it describes now, not then
+	if next.TimestampEpochMicros == prev.TimestampEpochMicros && next.ElapsedTimeMicros != prev.ElapsedTimeMicros {
+		next.TimestampEpochMicros = nowMicros
+	}
+
+	// Hold position over small backward steps rather than jumping back
+	prevLive := liveElapsedAt(&prev, nowMicros)
+	nextLive := liveElapsedAt(&next, nowMicros)
+	if back := prevLive - nextLive; !seeking && back > 0 && back <= backwardJitterMicros {
+		next.ElapsedTimeMicros = prevLive
+		next.TimestampEpochMicros = nowMicros
+	}
+
+	return next
+}
+
+// sameTrack reports whether two states describe the same track.
+func sameTrack(a, b NowPlaying) bool {
+	return a.Title == b.Title && a.Artist == b.Artist && a.Album == b.Album && a.DurationMicros == b.DurationMicros
 }
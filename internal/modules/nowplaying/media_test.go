@@ -0,0 +1,186 @@
+package nowplaying
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMediaStreamCancelDoesNotLeak(t *testing.T) {
+	m := New(nil)
+	// The backgrounded sleep outlives the shell killed on cancel and keeps
+	// its output open, as a child of media-control could
+	m.streamCommand = []string{"sh", "-c", `echo '{"diff":false,"payload":{"title":"T"}}'; sleep 2 & sleep 2`}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.startMediaStream(ctx)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("cycle %d: stream still running a second after cancel", i)
+		}
+	}
+
+	// Give exiting goroutines a moment to finish
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines grew from %d to %d over 20 start and cancel cycles", before, after)
+	}
+}
+
+func TestSetNowPlayingShowsOnStrip(t *testing.T) {
+	m := newStripModule(t)
+	m.SetNowPlaying(NowPlaying{Title: "First Song", Artist: "Artist", Playing: true, DurationMicros: 180e6})
+	first := bytes.Clone(pixels(t, m.RenderStrip()))
+
+	m.SetNowPlaying(NowPlaying{Title: "Another Track", Artist: "Someone Else", Playing: true, DurationMicros: 180e6})
+	second := pixels(t, m.RenderStrip())
+
+	if bytes.Equal(first, second) {
+		t.Error("strip unchanged after SetNowPlaying, want it to show the injected track")
+	}
+	if got := m.liveState.get().Title; got != "Another Track" {
+		t.Errorf("state title = %q, want %q", got, "Another Track")
+	}
+}
+
+func TestApplyUpdateMergesStream(t *testing.T) {
+	const now = int64(100_000_000)
+	var np NowPlaying
+	apply := func(line string) {
+		t.Helper()
+		var envelope StreamPayload
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+		np = applyUpdate(np, envelope.Diff, payload, now, false)
+	}
+
+	apply(`{"diff":false,"payload":{"title":"Song","artist":"Band","album":"LP","durationMicros":300000000,
+		"elapsedTimeMicros":10000000,"timestampEpochMicros":90000000,"playing":true,
+		"shuffleMode":1,"bundleIdentifier":"com.spotify.client"}}`)
+	want := NowPlaying{
+		Title: "Song", Artist: "Band", Album: "LP", DurationMicros: 300_000_000,
+		ElapsedTimeMicros: 10_000_000, TimestampEpochMicros: 90_000_000, Playing: true,
+		Shuffle: ShuffleOff, BundleID: "com.spotify.client",
+	}
+	if np != want {
+		t.Fatalf("after full update = %+v, want %+v", np, want)
+	}
+
+	// A diff changes only the fields it has
+	apply(`{"diff":true,"payload":{"artworkData":"YXJ0","artworkMimeType":"image/png","shuffleMode":"songs"}}`)
+	want.ArtworkData, want.ArtworkMime, want.Shuffle = "YXJ0", "image/png", ShuffleOn
+	if np != want {
+		t.Fatalf("after artwork diff = %+v, want %+v", np, want)
+	}
+
+	// Pausing keeps the track and position
+	apply(`{"diff":true,"payload":{"playing":false,"elapsedTimeMicros":20000000,"timestampEpochMicros":100000000}}`)
+	want.Playing, want.ElapsedTimeMicros, want.TimestampEpochMicros = false, 20_000_000, 100_000_000
+	if np != want {
+		t.Fatalf("after pause diff = %+v, want %+v", np, want)
+	}
+
+	// An empty full update means nothing is playing
+	apply(`{"diff":false,"payload":{}}`)
+	if np.Title != "?" || np.Artist != "?" || np.ArtworkData != "" || np.Playing {
+		t.Errorf("after empty update = %+v, want the reset state", np)
+	}
+}
+
+func TestReconcileTiming(t *testing.T) {
+	const s = int64(time.Second / time.Microsecond)
+	now := 100 * s
+	track := NowPlaying{Title: "T", Artist: "A", DurationMicros: 300 * s, Playing: true}
+	at := func(ts, elapsed int64) NowPlaying {
+		np := track
+		np.TimestampEpochMicros, np.ElapsedTimeMicros = ts, elapsed
+		return np
+	}
+
+	const ms = s / 1000
+	paused := func(ts, elapsed int64) NowPlaying {
+		np := at(ts, elapsed)
+		np.Playing = false
+		return np
+	}
+
+	for name, tt := range map[string]struct {
+		prev, next  NowPlaying
+		seeking     bool
+		ts, elapsed int64
+	}{
+		"stale update":          {at(90*s, 10*s), at(80*s, 5*s), false, 90 * s, 10 * s},
+		"timestamp unchanged":   {at(90*s, 10*s), at(90*s, 30*s), false, now, 30 * s},
+		"jitter back":           {at(90*s, 10*s), at(now, 20*s-200*ms), false, now, 20 * s},
+		"short rewind":          {at(90*s, 10*s), at(now, 19*s), false, now, 19 * s},
+		"short rewind paused":   {paused(90*s, 10*s), paused(now, 9*s), false, now, 9 * s},
+		"seek back":             {at(90*s, 10*s), at(now, 5*s), false, now, 5 * s},
+		"jitter after own seek": {at(90*s, 10*s), at(now, 20*s-200*ms), true, now, 20*s - 200*ms},
+		"future timestamp":      {NowPlaying{}, at(200*s, 10*s), false, now, 10 * s},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := reconcileTiming(tt.prev, tt.next, now, tt.seeking)
+			if got.TimestampEpochMicros != tt.ts || got.ElapsedTimeMicros != tt.elapsed {
+				t.Errorf("timestamp, elapsed = %dms, %dms; want %dms, %dms",
+					got.TimestampEpochMicros/ms, got.ElapsedTimeMicros/ms, tt.ts/ms, tt.elapsed/ms)
+			}
+		})
+	}
+}
+
+func TestReconcileTimingNewTrack(t *testing.T) {
+	const s = int64(time.Second / time.Microsecond)
+	prev := NowPlaying{Title: "Old", Playing: true, TimestampEpochMicros: 90 * s, ElapsedTimeMicros: 60 * s}
+	next := NowPlaying{Title: "New", Playing: true, TimestampEpochMicros: 80 * s, ElapsedTimeMicros: 0}
+	if got := reconcileTiming(prev, next, 100*s, false); got != next {
+		t.Errorf("new track's timing changed to %+v", got)
+	}
+}
+
+func TestApplyUpdateResetsOnSourceChange(t *testing.T) {
+	prev := NowPlaying{
+		Title:       "Song",
+		Artist:      "Band",
+		BundleID:    "com.spotify.client",
+		ArtworkData: "c3BvdGlmeQ==",
+		Playing:     true,
+	}
+
+	// A diff from the same app only merges what it has
+	same := applyUpdate(prev, true, map[string]interface{}{"playing": false, "bundleIdentifier": "com.spotify.client"}, 1, false)
+	if same.Title != "Song" || same.ArtworkData == "" || same.Playing {
+		t.Errorf("diff from the same app = %+v, want only playing changed", same)
+	}
+
+	// One from another app drops the old app's state
+	next := applyUpdate(prev, true, map[string]interface{}{"title": "Episode", "bundleIdentifier": "com.apple.podcasts"}, 1, false)
+	if next.Title != "Episode" || next.Artist != "?" || next.ArtworkData != "" || next.BundleID != "com.apple.podcasts" {
+		t.Errorf("diff from another app = %+v, want a fresh state with only its fields", next)
+	}
+
+	// An empty full update resets to defaults
+	if got := applyUpdate(prev, false, nil, 1, false); got.Title != "?" || got.BundleID != "" {
+		t.Errorf("empty full update = %+v, want defaults", got)
+	}
+}
@@ -0,0 +1,136 @@
+package nowplaying
+
+import (
+	"log"
+	"strings"
+)
+
+// ShuffleMode is the player's shuffle setting. The zero value means
+// media-control hasn't reported one, and no indicator is shown.
+type ShuffleMode string
+
+const (
+	ShuffleOff ShuffleMode = "off"
+	ShuffleOn  ShuffleMode = "on"
+)
+
+// RepeatMode is the player's repeat setting. The zero value means
+// media-control hasn't reported one, and no indicator is shown.
+type RepeatMode string
+
+const (
+	RepeatOff RepeatMode = "off"
+	RepeatAll RepeatMode = "all"
+	RepeatOne RepeatMode = "one"
+)
+
+// parseShuffleMode reads a shuffle mode from a media-control payload value.
+// MediaRemote reports it as a number (1 off, 2 albums, 3 tracks); names and
+// booleans are accepted too. Shuffling albums or tracks both count as on.
+func parseShuffleMode(v any) (ShuffleMode, bool) {
+	switch val := v.(type) {
+	case float64:
+		switch val {
+		case 1:
+			return ShuffleOff, true
+		case 2, 3:
+			return ShuffleOn, true
+		}
+	case bool:
+		if val {
+			return ShuffleOn, true
+		}
+		return ShuffleOff, true
+	case string:
+		switch strings.ToLower(val) {
+		case "off", "none", "false":
+			return ShuffleOff, true
+		case "on", "albums", "tracks", "songs", "true":
+			return ShuffleOn, true
+		}
+	}
+	return "", false
+}
+
+// parseRepeatMode reads a repeat mode from a media-control payload value.
+// MediaRemote reports it as a number (1 off, 2 one, 3 all); names are
+// accepted too.
+func parseRepeatMode(v any) (RepeatMode, bool) {
+	switch val := v.(type) {
+	case float64:
+		switch val {
+		case 1:
+			return RepeatOff, true
+		case 2:
+			return RepeatOne, true
+		case 3:
+			return RepeatAll, true
+		}
+	case string:
+		switch strings.ToLower(val) {
+		case "off", "none":
+			return RepeatOff, true
+		case "one", "track":
+			return RepeatOne, true
+		case "all", "playlist":
+			return RepeatAll, true
+		}
+	}
+	return "", false
+}
+
+// next returns the shuffle mode a toggle switches to. An unknown mode is
+// treated as off.
+func (s ShuffleMode) next() ShuffleMode {
+	if s == ShuffleOn {
+		return ShuffleOff
+	}
+	return ShuffleOn
+}
+
+// next returns the repeat mode a toggle switches to, cycling off, all, one.
+// An unknown mode is treated as off.
+func (r RepeatMode) next() RepeatMode {
+	switch r {
+	case RepeatAll:
+		return RepeatOne
+	case RepeatOne:
+		return RepeatOff
+	default:
+		return RepeatAll
+	}
+}
+
+// modeLabels returns the indicators to show for the current modes, e.g.
+// "SHUFFLE" and "REPEAT 1". Modes that are off or unknown are left out.
+func modeLabels(np *NowPlaying) []string {
+	var labels []string
+	if np.Shuffle == ShuffleOn {
+		labels = append(labels, "SHUFFLE")
+	}
+	switch np.Repeat {
+	case RepeatAll:
+		labels = append(labels, "REPEAT")
+	case RepeatOne:
+		labels = append(labels, "REPEAT 1")
+	}
+	return labels
+}
+
+// toggleShuffle switches shuffle on or off with "media-control shuffle".
+func (m *Module) toggleShuffle() {
+	mode := m.liveState.get().Shuffle.next()
+	log.Printf("Shuffle: %s", mode)
+	if err := m.runner.Run(m.Context(), "media-control", "shuffle", string(mode)); err != nil {
+		log.Printf("Failed to set shuffle: %v", err)
+	}
+}
+
+// toggleRepeat moves repeat to its next mode with "media-control repeat".
+func (m *Module) toggleRepeat() {
+	mode := m.liveState.get().Repeat.next()
+	log.Printf("Repeat: %s", mode)
+	if err := m.runner.Run(m.Context(), "media-control", "repeat", string(mode)); err != nil {
+		log.Printf("Failed to set repeat: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package nowplaying
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+func TestParseModes(t *testing.T) {
+	for _, tt := range []struct {
+		v    any
+		want ShuffleMode
+	}{
+		{1.0, ShuffleOff},
+		{3.0, ShuffleOn},
+		{true, ShuffleOn},
+		{"Tracks", ShuffleOn},
+		{"none", ShuffleOff},
+	} {
+		if got, ok := parseShuffleMode(tt.v); !ok || got != tt.want {
+			t.Errorf("parseShuffleMode(%v) = %q, %v; want %q", tt.v, got, ok, tt.want)
+		}
+	}
+	if _, ok := parseShuffleMode(7.0); ok {
+		t.Error("parseShuffleMode accepted 7")
+	}
+
+	for _, tt := range []struct {
+		v    any
+		want RepeatMode
+	}{
+		{1.0, RepeatOff},
+		{2.0, RepeatOne},
+		{3.0, RepeatAll},
+		{"playlist", RepeatAll},
+	} {
+		if got, ok := parseRepeatMode(tt.v); !ok || got != tt.want {
+			t.Errorf("parseRepeatMode(%v) = %q, %v; want %q", tt.v, got, ok, tt.want)
+		}
+	}
+	if _, ok := parseRepeatMode(nil); ok {
+		t.Error("parseRepeatMode accepted a missing value")
+	}
+}
+
+func TestModeLabels(t *testing.T) {
+	np := &NowPlaying{Shuffle: ShuffleOn, Repeat: RepeatOne}
+	if got, want := modeLabels(np), []string{"SHUFFLE", "REPEAT 1"}; !slices.Equal(got, want) {
+		t.Errorf("modeLabels = %q, want %q", got, want)
+	}
+	if got := modeLabels(&NowPlaying{}); len(got) != 0 {
+		t.Errorf("modeLabels with unknown modes = %q, want none", got)
+	}
+}
+
+func TestToggleModes(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+
+	m.SetNowPlaying(NowPlaying{Title: "T", Shuffle: ShuffleOff, Repeat: RepeatOne})
+	m.toggleShuffle()
+	m.toggleRepeat()
+
+	want := []string{"media-control shuffle on", "media-control repeat off"}
+	if got := fake.Commands(); !slices.Equal(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+}
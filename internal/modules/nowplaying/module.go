@@ -3,32 +3,217 @@ package nowplaying
 
 import (
 	"context"
+	"fmt"
 	"image"
+	"image/color"
 	"log"
-	"os/exec"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/phinze/belowdeck/internal/device"
 	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/phinze/belowdeck/internal/runner"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
 )
 
+// overlayDuration is how long an overlay stays up without input.
+const overlayDuration = 10 * time.Second
+
+// debugHold is how long Dial2 must be held to show the debug overlay.
+const debugHold = time.Second
+
+// copiedFeedbackDuration is how long the strip confirms a clipboard copy.
+const copiedFeedbackDuration = 2 * time.Second
+
+// overlayKind indicates which overlay is currently shown.
+type overlayKind int
+
+const (
+	overlayNone overlayKind = iota
+	overlayInfo
+	overlayDebug
+)
+
+// Config holds the NowPlaying module configuration.
+type Config struct {
+	// ArtRadius is the corner radius in pixels applied to the album art.
+	// Zero keeps the art square.
+	ArtRadius int
+
+	// ArtBorder is the width in pixels of the subtle border drawn around the
+	// album art. Zero disables the border.
+	ArtBorder int
+
+	// ArtMaxSize caps the width and height, in pixels, that decoded album
+	// art is kept at. Larger art is scaled down as soon as it's decoded.
+	// Zero keeps art at full size.
+	ArtMaxSize int
+
+	// ArtPlaceholder is drawn in place of album art for tracks that have
+	// none. Nil leaves the space empty. Art that fails to decode is never
+	// replaced, so a broken image stays distinguishable from a missing one.
+	ArtPlaceholder image.Image
+
+	// ArtFallbackDir is a directory of images named by artist or album,
+	// shown for tracks without art before falling back to ArtPlaceholder.
+	ArtFallbackDir string
+
+	// ArtBackground fills the module's strip area with the album art,
+	// blurred and darkened, behind the track text and progress bar.
+	ArtBackground bool
+
+	// IdleAnimation animates the strip while nothing is playing: "gradient"
+	// slowly shifts a dim gradient, "bars" runs a simple bar visualizer,
+	// and "off" leaves it still.
+	IdleAnimation string
+
+	// Debug enables the raw payload overlay, shown by holding Dial2.
+	Debug bool
+
+	// CopyFormat formats the current track for the clipboard when Dial2 is
+	// pressed. {artist}, {title} and {album} are replaced with the track's
+	// metadata.
+	CopyFormat string
+
+	// LongTouch is the action for a long press on the strip: "info" shows
+	// the info overlay, "app" opens App.
+	LongTouch string
+
+	// InfoAction is what the info key does: "overlay" shows the info
+	// overlay, "app" opens App and "copy" copies the track like a Dial2
+	// press.
+	InfoAction string
+
+	// App is the media application opened by the "app" long touch and
+	// info key actions.
+	App string
+
+	// ProgressPlaying and ProgressPaused fill the progress bar while playing
+	// and paused; ProgressBackground is the unfilled track.
+	ProgressPlaying    color.Color
+	ProgressPaused     color.Color
+	ProgressBackground color.Color
+
+	// SeekStep is how far each Dial1 tick seeks in fixed mode.
+	SeekStep time.Duration
+
+	// SeekPercent, when non-zero, makes each Dial1 tick seek this percentage
+	// of the track's duration instead of SeekStep.
+	SeekPercent float64
+
+	// SeekAccel speeds up the seek dial when it's spun quickly. Nil leaves
+	// every tick at the same step.
+	SeekAccel *module.Acceleration
+
+	// Keys maps the module's keys, in order, to what they do. Defaults to
+	// play/pause then info, followed by any jumps from NOWPLAYING_JUMPS.
+	// Keys beyond the module's allocation are ignored.
+	Keys []KeyBinding
+
+	// ProgressFromArt fills the progress bar with the album art's accent
+	// color while playing, falling back to ProgressPlaying for grayscale art.
+	ProgressFromArt bool
+
+	// PauseOnSleep pauses playback when the system goes to sleep.
+	PauseOnSleep bool
+
+	// ResumeOnWake starts playback again on wake if PauseOnSleep paused
+	// it.
+	ResumeOnWake bool
+
+	// ReconcileInterval is how long the stream may go without updates
+	// before the position is polled with "media-control get" to correct
+	// drift. Zero disables polling.
+	ReconcileInterval time.Duration
+
+	// PauseWhenAway pauses playback when the deck goes away, e.g. when a
+	// meeting starts.
+	PauseWhenAway bool
+
+	// Lastfm enables scrobbling to last.fm when its API key is set.
+	Lastfm LastfmCredentials
+
+	// BoostPolls is how many times the state is polled after a media
+	// command, BoostInterval after it and then at doubling intervals, so
+	// the display catches up without waiting for the stream. Zero
+	// disables boosting.
+	BoostPolls    int
+	BoostInterval time.Duration
+}
+
 // Module implements the nowplaying media control module.
 type Module struct {
 	module.BaseModule
 
 	device device.Device
+	config Config
+	runner runner.Runner
+
+	// streamCommand is the command streaming playback state, one JSON
+	// update per line
+	streamCommand []string
 
 	// State
 	liveState     *liveState
 	cachedArtwork image.Image
 	artworkHash   string
+	artworkAccent color.Color // nil unless ProgressFromArt and art has one
 	lastPlaying   bool
 	mu            sync.RWMutex
 
+	// Overlay state
+	overlay       overlayKind
+	overlayExpiry time.Time
+	infoCache     *infoLayout
+
+	// Strip title and artist layout, reused until the track changes
+	stripTextCache *stripTextLayout
+
+	// When the "copied" confirmation stops showing on the strip
+	copiedUntil time.Time
+
+	// Whether playback was paused for sleep, to be resumed on wake
+	pausedForSleep bool
+
+	// idleFrame counts strip renders of the idle animation, advancing it
+	idleFrame int
+
+	// drawMu serializes rendering, since a late strip render can overlap the
+	// overlay's and font faces aren't safe for concurrent use.
+	drawMu sync.Mutex
+
+	// Scaled artwork, reused until the artwork or size changes. Guarded by
+	// its own lock since strip and overlay renders may run concurrently.
+	thumbMu     sync.Mutex
+	thumbs      map[thumbKey]image.Image
+	backdrop    image.Image
+	backdropKey backdropKey
+
+	// Fallback art files by artKey, and the file found for each artist and
+	// album, listed and looked up on first use
+	fallbackMu    sync.Mutex
+	fallbackFiles map[string]string
+	fallbackPaths map[string]string
+
 	// Fonts
-	titleFace  font.Face
-	artistFace font.Face
+	boldFont    *opentype.Font
+	regularFont *opentype.Font
+	artistFace  font.Face
+	modeFace    font.Face
+	jumpFace    font.Face
+
+	// Scrobbles to last.fm, nil unless configured
+	scrobbler *scrobbler
+
+	// Extra state polls after media commands
+	booster module.Booster
 
 	// Cancel function for media stream
 	streamCancel context.CancelFunc
@@ -39,7 +224,10 @@ func New(dev device.Device) *Module {
 	return &Module{
 		BaseModule: module.NewBaseModule("nowplaying"),
 		device:     dev,
+		runner:     runner.Default,
 		liveState:  newLiveState(),
+
+		streamCommand: []string{"media-control", "stream", "--micros"},
 	}
 }
 
@@ -48,6 +236,12 @@ func (m *Module) ID() string {
 	return "nowplaying"
 }
 
+// Configured reports whether media-control is available to stream playback
+// state from. It's macOS-only, so the module is left out elsewhere.
+func (m *Module) Configured() bool {
+	return platform.MediaControlAvailable()
+}
+
 // Init initializes the module.
 func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Call base init
@@ -55,15 +249,39 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 		return err
 	}
 
+	// Load configuration
+	m.config = loadConfig()
+
 	// Initialize fonts
-	if err := m.initFonts(); err != nil {
-		return err
+	m.initFonts()
+
+	// Pause around sleep and when away if configured
+	m.subscribePower()
+	m.subscribeAway()
+
+	// Scrobble what the stream reports if configured
+	if m.config.Lastfm.APIKey != "" {
+		m.scrobbler = newScrobbler(m.config.Lastfm)
+		module.Supervise(ctx, "nowplaying scrobbler", m.scrobbler.run)
+		log.Println("NowPlaying: scrobbling to last.fm")
+	}
+
+	// Poll the state a few times after each media command
+	m.booster = module.Booster{
+		Fetch:    m.reconcilePosition,
+		Polls:    m.config.BoostPolls,
+		Interval: m.config.BoostInterval,
 	}
 
 	// Start media stream in background
 	streamCtx, cancel := context.WithCancel(ctx)
 	m.streamCancel = cancel
-	go m.startMediaStream(streamCtx)
+	module.Supervise(streamCtx, "nowplaying stream", m.startMediaStream)
+
+	// Correct the position if the stream goes quiet
+	if m.config.ReconcileInterval > 0 {
+		module.Supervise(streamCtx, "nowplaying reconcile", m.pollPosition)
+	}
 
 	log.Println("NowPlaying module initialized")
 	return nil
@@ -77,8 +295,221 @@ func (m *Module) Stop() error {
 	return m.BaseModule.Stop()
 }
 
+// loadConfig loads configuration from environment variables. An invalid
+// value is logged and its default used, so a typo can't disable the module.
+func loadConfig() Config {
+	var config Config
+
+	config.ArtRadius = intEnv("NOWPLAYING_ART_RADIUS", 0)
+	config.ArtBorder = intEnv("NOWPLAYING_ART_BORDER", 0)
+	config.ArtMaxSize = intEnv("NOWPLAYING_ART_MAX_SIZE", 400)
+
+	placeholder, err := loadArtPlaceholder(os.Getenv("NOWPLAYING_ART_PLACEHOLDER"))
+	if err != nil {
+		log.Printf("Invalid NOWPLAYING_ART_PLACEHOLDER, using the default: %v", err)
+		placeholder = notePlaceholder()
+	}
+	config.ArtPlaceholder = placeholder
+
+	if v := os.Getenv("NOWPLAYING_ART_FALLBACK_DIR"); v != "" {
+		if info, err := os.Stat(v); err != nil || !info.IsDir() {
+			log.Printf("Invalid NOWPLAYING_ART_FALLBACK_DIR %q is not a directory, ignoring it", v)
+		} else {
+			config.ArtFallbackDir = v
+		}
+	}
+
+	config.ArtBackground = boolEnv("NOWPLAYING_ART_BACKGROUND", false)
+	config.IdleAnimation = choiceEnv("NOWPLAYING_IDLE_ANIMATION", idleOff, idleGradient, idleBars)
+	config.Debug = boolEnv("NOWPLAYING_DEBUG", false)
+	config.LongTouch = choiceEnv("NOWPLAYING_LONG_TOUCH", "info", "app")
+	config.InfoAction = choiceEnv("NOWPLAYING_INFO_ACTION", "overlay", "app", "copy")
+
+	config.App = os.Getenv("NOWPLAYING_APP")
+	if config.App == "" {
+		config.App = "Music"
+	}
+
+	config.CopyFormat = os.Getenv("NOWPLAYING_COPY_FORMAT")
+	if config.CopyFormat == "" {
+		config.CopyFormat = "{artist} – {title}"
+	}
+
+	config.ProgressPlaying = colorEnv("NOWPLAYING_PROGRESS_PLAYING", colorLimeGreen)
+	config.ProgressPaused = colorEnv("NOWPLAYING_PROGRESS_PAUSED", colorOrange)
+	config.ProgressBackground = colorEnv("NOWPLAYING_PROGRESS_BG", colorProgressBg)
+
+	config.SeekStep = 5 * time.Second
+	if v := os.Getenv("NOWPLAYING_SEEK"); v != "" {
+		if pct, ok := strings.CutSuffix(v, "%"); ok {
+			percent, err := strconv.ParseFloat(pct, 64)
+			if err != nil || percent <= 0 || percent > 100 {
+				log.Printf("Invalid NOWPLAYING_SEEK %q, using default %v", v, config.SeekStep)
+			} else {
+				config.SeekPercent = percent
+			}
+		} else {
+			step, err := time.ParseDuration(v)
+			if err != nil || step <= 0 {
+				log.Printf("Invalid NOWPLAYING_SEEK %q (want e.g. \"5s\" or \"2%%\"), using default %v", v, config.SeekStep)
+			} else {
+				config.SeekStep = step
+			}
+		}
+	}
+
+	seekAccel, err := module.ParseAcceleration(os.Getenv("NOWPLAYING_SEEK_ACCEL"))
+	if err != nil {
+		log.Printf("Invalid NOWPLAYING_SEEK_ACCEL, leaving it off: %v", err)
+	}
+	config.SeekAccel = seekAccel
+
+	config.Keys = defaultKeys
+	if v := os.Getenv("NOWPLAYING_KEYS"); v != "" {
+		keys, err := parseKeyBindings(v)
+		if err != nil {
+			log.Printf("Invalid NOWPLAYING_KEYS %q, using the default keys: %v", v, err)
+		} else {
+			config.Keys = keys
+		}
+	}
+
+	if v := os.Getenv("NOWPLAYING_JUMPS"); v != "" {
+		jumps, err := parseJumps(v)
+		if err != nil {
+			log.Printf("Invalid NOWPLAYING_JUMPS %q (want e.g. \"-15s,+30s\"), adding no jump keys", v)
+		}
+		config.Keys = append(config.Keys, jumps...)
+	}
+
+	config.ProgressFromArt = boolEnv("NOWPLAYING_PROGRESS_FROM_ART", false)
+	config.PauseOnSleep = boolEnv("NOWPLAYING_PAUSE_ON_SLEEP", false)
+	config.ResumeOnWake = boolEnv("NOWPLAYING_RESUME_ON_WAKE", false)
+	config.ReconcileInterval = durationEnv("NOWPLAYING_RECONCILE", 30*time.Second)
+	config.PauseWhenAway = boolEnv("NOWPLAYING_PAUSE_WHEN_AWAY", false)
+	config.BoostPolls = intEnv("NOWPLAYING_BOOST_POLLS", 3)
+
+	config.BoostInterval = durationEnv("NOWPLAYING_BOOST_INTERVAL", 250*time.Millisecond)
+	if config.BoostInterval == 0 {
+		log.Printf("Invalid NOWPLAYING_BOOST_INTERVAL 0, using default %v", 250*time.Millisecond)
+		config.BoostInterval = 250 * time.Millisecond
+	}
+
+	config.Lastfm = LastfmCredentials{
+		APIKey:     os.Getenv("LASTFM_API_KEY"),
+		APISecret:  os.Getenv("LASTFM_API_SECRET"),
+		SessionKey: os.Getenv("LASTFM_SESSION_KEY"),
+		Username:   os.Getenv("LASTFM_USERNAME"),
+		Password:   os.Getenv("LASTFM_PASSWORD"),
+	}
+	if config.Lastfm.APIKey != "" {
+		switch {
+		case config.Lastfm.APISecret == "":
+			log.Println("LASTFM_API_SECRET must be set with LASTFM_API_KEY, not scrobbling")
+			config.Lastfm = LastfmCredentials{}
+		case config.Lastfm.SessionKey == "" && (config.Lastfm.Username == "" || config.Lastfm.Password == ""):
+			log.Println("LASTFM_SESSION_KEY, or LASTFM_USERNAME and LASTFM_PASSWORD, must be set with LASTFM_API_KEY, not scrobbling")
+			config.Lastfm = LastfmCredentials{}
+		}
+	}
+
+	return config
+}
+
+// parseJumps parses NOWPLAYING_JUMPS, a comma-separated list of signed
+// durations, into jump key bindings.
+func parseJumps(v string) ([]KeyBinding, error) {
+	var jumps []KeyBinding
+	for _, part := range strings.Split(v, ",") {
+		jump, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || jump == 0 {
+			return nil, fmt.Errorf("invalid jump %q", part)
+		}
+		jumps = append(jumps, KeyBinding{Jump: jump})
+	}
+	return jumps, nil
+}
+
+// intEnv parses a non-negative integer from an environment variable,
+// returning def if the variable is unset or invalid.
+func intEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("Invalid %s %q, using default %v", name, v, def)
+		return def
+	}
+	return n
+}
+
+// boolEnv parses a boolean from an environment variable, returning def if
+// the variable is unset or invalid.
+func boolEnv(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default %v", name, v, def)
+		return def
+	}
+	return b
+}
+
+// durationEnv parses a non-negative duration from an environment variable,
+// returning def if the variable is unset or invalid.
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("Invalid %s %q, using default %v", name, v, def)
+		return def
+	}
+	return d
+}
+
+// choiceEnv reads one of a fixed set of values from an environment
+// variable, returning def if the variable is unset or isn't def or one of
+// others.
+func choiceEnv(name, def string, others ...string) string {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	if v == def || slices.Contains(others, v) {
+		return v
+	}
+	log.Printf("Invalid %s %q (want %q or one of %q), using default %q", name, v, def, others, def)
+	return def
+}
+
+// colorEnv parses a hex color from an environment variable, returning def
+// if the variable is unset or invalid.
+func colorEnv(name string, def color.Color) color.Color {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	c, err := render.ParseHexColor(v)
+	if err != nil {
+		log.Printf("Invalid %s %q, using default: %v", name, v, err)
+		return def
+	}
+	return c
+}
+
 // RenderKeys returns images for the module's keys.
 func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	m.drawMu.Lock()
+	defer m.drawMu.Unlock()
+
 	keyRect, _ := m.device.GetKeyImageRectangle()
 	size := keyRect.Dx()
 
@@ -87,7 +518,7 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 	// Get current state
 	np := m.liveState.get()
 
-	// Key 5: Play/Pause icon (changes based on state)
+	// Track play state for the play/pause key
 	m.mu.Lock()
 	if np.Playing != m.lastPlaying {
 		m.lastPlaying = np.Playing
@@ -95,20 +526,24 @@ func (m *Module) RenderKeys() map[module.KeyID]image.Image {
 	playing := m.lastPlaying
 	m.mu.Unlock()
 
-	if playing {
-		keys[module.Key5] = renderSVGIcon(iconPauseSVG, size, colorOrange)
-	} else {
-		keys[module.Key5] = renderSVGIcon(iconPlaySVG, size, colorLimeGreen)
+	res := m.Resources()
+	for i, binding := range m.config.Keys {
+		if i >= len(res.Keys) {
+			break
+		}
+		if img := m.renderKeyBinding(binding, size, &np, playing); img != nil {
+			keys[res.Keys[i]] = img
+		}
 	}
 
-	// Key 6: Info icon (static)
-	keys[module.Key6] = renderSVGIcon(iconInfoSVG, size, colorDeepSkyBlue)
-
 	return keys
 }
 
 // RenderStrip returns the touch strip image.
 func (m *Module) RenderStrip() image.Image {
+	m.drawMu.Lock()
+	defer m.drawMu.Unlock()
+
 	if !m.device.GetTouchStripSupported() {
 		return nil
 	}
@@ -118,21 +553,57 @@ func (m *Module) RenderStrip() image.Image {
 		return nil
 	}
 
+	// The art comes from the same snapshot as the metadata, so a track
+	// change mid-render can't pair one track's title with another's art
 	np := m.liveState.get()
+	artwork, accent := m.artworkFor(&np)
 
-	// Update artwork cache if changed
-	m.mu.Lock()
-	if np.ArtworkData != "" && np.ArtworkData != m.artworkHash {
-		if img := decodeArtwork(np.ArtworkData); img != nil {
-			m.cachedArtwork = img
-			m.artworkHash = np.ArtworkData
-			log.Printf("Track: %s - %s", np.Artist, np.Title)
+	m.mu.RLock()
+	copied := time.Now().Before(m.copiedUntil)
+	m.mu.RUnlock()
+
+	if m.config.IdleAnimation != idleOff && nothingPlaying(&np) && !copied {
+		return m.renderIdleStrip(rect, m.Resources().StripRect, m.nextIdleFrame())
+	}
+	return m.renderStrip(rect, m.Resources().StripRect, &np, artwork, accent, copied)
+}
+
+// artworkFor returns the decoded artwork for np and its accent color, or nil
+// if np has no artwork or it doesn't decode. Tracks without artwork get
+// their fallback art file, if there is one. The last artwork decoded is
+// cached by its data, or the fallback file's path, so it's only decoded
+// again once it changes; a failed decode is cached too, as nil, rather than
+// leaving the previous track's art in place.
+func (m *Module) artworkFor(np *NowPlaying) (image.Image, color.Color) {
+	key := np.ArtworkData
+	fallback := ""
+	if key == "" {
+		fallback = m.fallbackArtPath(np)
+		if fallback == "" {
+			return nil, nil
 		}
+		// File keys can't clash with artwork data, which is base64
+		key = "file:" + fallback
 	}
-	artwork := m.cachedArtwork
-	m.mu.Unlock()
 
-	return m.renderStrip(rect, &np, artwork)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if key != m.artworkHash {
+		m.artworkHash = key
+		if fallback != "" {
+			m.cachedArtwork = loadArtworkFile(fallback, m.config.ArtMaxSize)
+		} else {
+			m.cachedArtwork = decodeArtwork(np.ArtworkData, m.config.ArtMaxSize)
+		}
+		m.artworkAccent = nil
+		if m.cachedArtwork != nil && m.config.ProgressFromArt {
+			if accent, ok := render.AccentColor(m.cachedArtwork); ok {
+				m.artworkAccent = accent
+			}
+		}
+		log.Printf("Track: %s - %s", np.Artist, np.Title)
+	}
+	return m.cachedArtwork, m.artworkAccent
 }
 
 // HandleKey processes key events.
@@ -142,55 +613,108 @@ func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
 		return nil
 	}
 
-	switch id {
-	case module.Key5:
-		log.Println("Key: Toggle play/pause")
-		go exec.Command("media-control", "toggle-play-pause").Run()
-	case module.Key6:
-		np := m.liveState.get()
-		log.Printf("Info: %s - %s (%s)", np.Artist, np.Title, np.Album)
+	res := m.Resources()
+	for i, binding := range m.config.Keys {
+		if i < len(res.Keys) && id == res.Keys[i] {
+			m.runKeyBinding(binding)
+			break
+		}
 	}
 
 	return nil
 }
 
+// HandleAction runs an external action: "play-pause", "next", "previous",
+// "stop", "info" (show the info overlay), "copy" (copy the track), "shuffle"
+// (toggle shuffle), "repeat" (cycle repeat off, all, one), or "like" or
+// "dislike" (rate the track, in apps that support it).
+func (m *Module) HandleAction(action string) error {
+	switch action {
+	case "play-pause":
+		m.mediaCommand("toggle-play-pause")
+	case "next":
+		m.mediaCommand("next-track")
+	case "previous":
+		m.mediaCommand("previous-track")
+	case "stop":
+		m.mediaCommand("stop")
+	case "info":
+		m.showOverlay(overlayInfo)
+	case "copy":
+		go m.copyTrack()
+	case "shuffle":
+		go m.toggleShuffle()
+	case "repeat":
+		go m.toggleRepeat()
+	case "like":
+		m.mediaCommand("send", mediaRemoteLikeTrack)
+	case "dislike":
+		m.mediaCommand("send", mediaRemoteDislikeTrack)
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+// mediaCommand runs "media-control" with args in the background, then polls
+// the state a few times so the display follows without waiting for the
+// stream.
+func (m *Module) mediaCommand(args ...string) {
+	go func() {
+		if err := m.runner.Run(m.Context(), "media-control", args...); err != nil {
+			return
+		}
+		m.booster.Boost(m.Context())
+	}()
+}
+
+// seek moves playback to pos, in micros, noting the seek so the updates
+// that follow aren't mistaken for jitter.
+func (m *Module) seek(pos int64) {
+	m.liveState.markSeek()
+
+	// media-control seek takes seconds
+	m.mediaCommand("seek", formatSeekPosition(pos))
+}
+
 // HandleDial processes dial events.
 func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 	switch id {
 	case module.Dial1:
 		switch event.Type {
 		case module.DialRotate:
-			// Seek 5 seconds per tick
-			seekAmount := int64(event.Delta) * 5 * 1000000 // 5 seconds in micros
-			log.Printf("Dial: Seeking %+d seconds", event.Delta*5)
-
 			np := m.liveState.get()
-			currentPos := getLiveElapsedMicros(&np)
-
-			newPos := currentPos + seekAmount
-			if newPos < 0 {
-				newPos = 0
+			newPos, ok := m.config.seekTarget(getLiveElapsedMicros(&np), np.DurationMicros, m.config.SeekAccel.Apply(event.Delta))
+			if !ok {
+				log.Println("Dial: Can't seek by percentage, track duration unknown")
+				return nil
 			}
-			if newPos > np.DurationMicros {
-				newPos = np.DurationMicros
-			}
-
-			// media-control seek takes seconds
-			go exec.Command("media-control", "seek", formatSeekPosition(newPos)).Run()
+			log.Printf("Dial: Seeking to %s", formatDurationMicros(newPos))
+			m.seek(newPos)
 
 		case module.DialPress:
 			log.Println("Dial: Toggle play/pause")
-			go exec.Command("media-control", "toggle-play-pause").Run()
+			m.mediaCommand("toggle-play-pause")
 		}
 
 	case module.Dial2:
-		if event.Type == module.DialRotate {
+		switch event.Type {
+		case module.DialRotate:
 			if event.Delta < 0 {
 				log.Println("Dial: Previous track")
-				go exec.Command("media-control", "previous-track").Run()
+				m.mediaCommand("previous-track")
 			} else {
 				log.Println("Dial: Next track")
-				go exec.Command("media-control", "next-track").Run()
+				m.mediaCommand("next-track")
+			}
+
+		case module.DialRelease:
+			// Holding the track dial is a hidden shortcut for the debug
+			// overlay; a regular press copies the track
+			if m.config.Debug && event.Duration >= debugHold {
+				m.showOverlay(overlayDebug)
+			} else {
+				go m.copyTrack()
 			}
 		}
 	}
@@ -198,8 +722,224 @@ func (m *Module) HandleDial(id module.DialID, event module.DialEvent) error {
 	return nil
 }
 
-// HandleStripTouch processes touch strip events.
+// HandleStripTouch processes touch strip events. A short tap seeks to the
+// tapped position along the progress bar; a long press runs the configured
+// long touch action.
 func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
-	// Not implemented yet - could add seek by touch
+	// Ignore touches on other modules' part of the strip
+	region := m.Resources().StripRect
+	if !event.Point.In(region) {
+		return nil
+	}
+
+	switch event.Type {
+	case module.TouchTap:
+		np := m.liveState.get()
+		if np.DurationMicros <= 0 {
+			return nil
+		}
+
+		newPos := int64(seekFraction(region, event.Point.X) * float64(np.DurationMicros))
+		log.Printf("Touch: Seeking to %s", formatDurationMicros(newPos))
+		m.seek(newPos)
+
+	case module.TouchLongTap:
+		if m.config.LongTouch == "app" {
+			log.Printf("Touch: Opening %s", m.config.App)
+			go m.openApp()
+		} else {
+			m.showOverlay(overlayInfo)
+		}
+	}
+
+	return nil
+}
+
+// seekTarget returns the position, in micros, delta Dial1 ticks away from pos
+// in a track durationMicros long. Percentage mode needs a known duration and
+// reports false without one; fixed mode only clamps to the end when the
+// duration is known.
+func (c Config) seekTarget(pos, durationMicros int64, delta int) (int64, bool) {
+	var step int64
+	if c.SeekPercent > 0 {
+		if durationMicros <= 0 {
+			return 0, false
+		}
+		step = int64(float64(durationMicros) * c.SeekPercent / 100)
+	} else {
+		step = c.SeekStep.Microseconds()
+	}
+
+	return clampPosition(pos+int64(delta)*step, durationMicros), true
+}
+
+// jump seeks by a relative amount from the live position.
+func (m *Module) jump(jump time.Duration) {
+	np := m.liveState.get()
+	newPos := jumpTarget(getLiveElapsedMicros(&np), np.DurationMicros, jump)
+	log.Printf("Key: Jumping %s to %s", formatJump(jump), formatDurationMicros(newPos))
+	m.seek(newPos)
+}
+
+// jumpTarget returns the position, in micros, a jump key moves to from pos
+// in a track durationMicros long.
+func jumpTarget(pos, durationMicros int64, jump time.Duration) int64 {
+	return clampPosition(pos+jump.Microseconds(), durationMicros)
+}
+
+// clampPosition keeps pos within the track, only clamping to the end when
+// the duration is known.
+func clampPosition(pos, durationMicros int64) int64 {
+	if pos < 0 {
+		return 0
+	}
+	if durationMicros > 0 && pos > durationMicros {
+		return durationMicros
+	}
+	return pos
+}
+
+// seekFraction maps a tap x coordinate onto the progress bar, returning the
+// fraction of the track to seek to.
+func seekFraction(region image.Rectangle, x int) float64 {
+	left, right := progressBarSpan(region)
+	if right <= left {
+		return 0
+	}
+
+	f := float64(x-left) / float64(right-left)
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// openApp launches or focuses the configured media app.
+func (m *Module) openApp() {
+	name, args := platform.OpenApp(m.config.App)
+	if err := m.runner.Run(m.Context(), name, args...); err != nil {
+		log.Printf("Failed to open %s: %v", m.config.App, err)
+	}
+}
+
+// copyTrack copies the current track, formatted with CopyFormat, to the
+// clipboard and flags the strip to confirm it.
+func (m *Module) copyTrack() {
+	np := m.liveState.get()
+	if np.Title == "" {
+		return
+	}
+
+	text := formatTrack(m.config.CopyFormat, &np)
+	name, args := platform.Copy()
+	if err := m.runner.RunInput(m.Context(), []byte(text), name, args...); err != nil {
+		log.Printf("Failed to copy track to clipboard: %v", err)
+		return
+	}
+	log.Printf("Copied to clipboard: %s", text)
+
+	m.mu.Lock()
+	m.copiedUntil = time.Now().Add(copiedFeedbackDuration)
+	m.mu.Unlock()
+}
+
+// formatTrack fills in the {artist}, {title} and {album} placeholders.
+func formatTrack(format string, np *NowPlaying) string {
+	return strings.NewReplacer(
+		"{artist}", np.Artist,
+		"{title}", np.Title,
+		"{album}", np.Album,
+	).Replace(format)
+}
+
+// IsOverlayActive returns true if the info or debug overlay is visible.
+func (m *Module) IsOverlayActive() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.overlay != overlayNone && time.Now().Before(m.overlayExpiry)
+}
+
+// RenderOverlayKeys returns images for all 8 keys while an overlay is up.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	m.drawMu.Lock()
+	defer m.drawMu.Unlock()
+
+	keyRect, _ := m.device.GetKeyImageRectangle()
+	hasStrip := m.device.GetTouchStripSupported()
+
+	m.mu.RLock()
+	overlay := m.overlay
+	m.mu.RUnlock()
+
+	if overlay == overlayDebug {
+		raw := m.liveState.getRaw()
+		return m.renderOverlayKeys(keyRect.Dx(), hasStrip, func(img *image.RGBA, area image.Rectangle) {
+			drawDebugText(img, raw, area)
+		})
+	}
+
+	np := m.liveState.get()
+	return m.renderOverlayKeys(keyRect.Dx(), hasStrip, func(img *image.RGBA, area image.Rectangle) {
+		m.drawInfoText(img, &np, area)
+	})
+}
+
+// RenderOverlayStrip returns the touch strip image for the active overlay.
+func (m *Module) RenderOverlayStrip() image.Image {
+	m.drawMu.Lock()
+	defer m.drawMu.Unlock()
+
+	if !m.device.GetTouchStripSupported() {
+		return nil
+	}
+
+	rect, err := m.device.GetTouchStripImageRectangle()
+	if err != nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	overlay := m.overlay
+	m.mu.RUnlock()
+
+	if overlay == overlayDebug {
+		return renderDebugStrip(rect, m.liveState.getRaw())
+	}
+
+	np := m.liveState.get()
+	artwork, _ := m.artworkFor(&np)
+	return m.renderInfoStrip(rect, &np, artwork)
+}
+
+// HandleOverlayKey dismisses the overlay on any key press.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if !event.Pressed {
+		return nil
+	}
+	m.dismissOverlay()
 	return nil
 }
+
+// HandleOverlayStripTouch dismisses the overlay on any touch.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	m.dismissOverlay()
+	return nil
+}
+
+// showOverlay brings up the given overlay.
+func (m *Module) showOverlay(kind overlayKind) {
+	m.mu.Lock()
+	m.overlay = kind
+	m.overlayExpiry = time.Now().Add(overlayDuration)
+	m.mu.Unlock()
+}
+
+// dismissOverlay hides the current overlay.
+func (m *Module) dismissOverlay() {
+	m.mu.Lock()
+	m.overlay = overlayNone
+	m.mu.Unlock()
+}
@@ -0,0 +1,322 @@
+package nowplaying
+
+import (
+	"errors"
+	"image"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+func TestLoadConfigInvalidValuesUseDefaults(t *testing.T) {
+	t.Setenv("NOWPLAYING_ART_RADIUS", "-3")
+	t.Setenv("NOWPLAYING_ART_MAX_SIZE", "big")
+	t.Setenv("NOWPLAYING_JUMPS", "soon")
+	t.Setenv("NOWPLAYING_PAUSE_ON_SLEEP", "maybe")
+	t.Setenv("NOWPLAYING_IDLE_ANIMATION", "sparkles")
+	t.Setenv("NOWPLAYING_BOOST_INTERVAL", "0s")
+
+	config := loadConfig()
+	if config.ArtRadius != 0 {
+		t.Errorf("ArtRadius = %d, want default 0", config.ArtRadius)
+	}
+	if config.ArtMaxSize != 400 {
+		t.Errorf("ArtMaxSize = %d, want default 400", config.ArtMaxSize)
+	}
+	if !slices.Equal(config.Keys, defaultKeys) {
+		t.Errorf("Keys = %v, want the defaults without jump keys", config.Keys)
+	}
+	if config.PauseOnSleep {
+		t.Error("PauseOnSleep = true, want default false")
+	}
+	if config.IdleAnimation != idleOff {
+		t.Errorf("IdleAnimation = %q, want default %q", config.IdleAnimation, idleOff)
+	}
+	if config.BoostInterval <= 0 {
+		t.Errorf("BoostInterval = %v, want the positive default", config.BoostInterval)
+	}
+}
+
+func TestLoadConfigValidValues(t *testing.T) {
+	t.Setenv("NOWPLAYING_ART_MAX_SIZE", "0")
+	t.Setenv("NOWPLAYING_PAUSE_ON_SLEEP", "true")
+	t.Setenv("NOWPLAYING_IDLE_ANIMATION", idleBars)
+
+	config := loadConfig()
+	if config.ArtMaxSize != 0 {
+		t.Errorf("ArtMaxSize = %d, want 0", config.ArtMaxSize)
+	}
+	if !config.PauseOnSleep {
+		t.Error("PauseOnSleep = false, want true")
+	}
+	if config.IdleAnimation != idleBars {
+		t.Errorf("IdleAnimation = %q, want %q", config.IdleAnimation, idleBars)
+	}
+}
+
+func TestJumpTarget(t *testing.T) {
+	const s = int64(time.Second / time.Microsecond)
+	for _, tt := range []struct {
+		pos, duration int64
+		jump          time.Duration
+		want          int64
+	}{
+		{60 * s, 300 * s, 30 * time.Second, 90 * s},
+		{10 * s, 300 * s, -15 * time.Second, 0},
+		{290 * s, 300 * s, 30 * time.Second, 300 * s},
+		{290 * s, 0, 30 * time.Second, 320 * s}, // duration unknown
+	} {
+		if got := jumpTarget(tt.pos, tt.duration, tt.jump); got != tt.want {
+			t.Errorf("jumpTarget(%ds, %ds, %v) = %ds, want %ds", tt.pos/s, tt.duration/s, tt.jump, got/s, tt.want/s)
+		}
+	}
+}
+
+func TestSeekTarget(t *testing.T) {
+	const s = int64(time.Second / time.Microsecond)
+	tests := []struct {
+		name          string
+		config        Config
+		pos, duration int64
+		delta         int
+		want          int64
+		wantOK        bool
+	}{
+		{"fixed forward", Config{SeekStep: 5 * time.Second}, 60 * s, 300 * s, 2, 70 * s, true},
+		{"fixed back", Config{SeekStep: 5 * time.Second}, 60 * s, 300 * s, -3, 45 * s, true},
+		{"fixed unknown duration", Config{SeekStep: 5 * time.Second}, 600 * s, 0, 4, 620 * s, true},
+		{"percent forward", Config{SeekPercent: 2}, 60 * s, 300 * s, 3, 78 * s, true},
+		{"percent back", Config{SeekPercent: 2}, 60 * s, 300 * s, -1, 54 * s, true},
+		{"percent of an hour", Config{SeekPercent: 2}, 0, 3600 * s, 1, 72 * s, true},
+		{"percent fraction", Config{SeekPercent: 0.5}, 10 * s, 200 * s, 1, 11 * s, true},
+		{"percent clamped to end", Config{SeekPercent: 10}, 290 * s, 300 * s, 2, 300 * s, true},
+		{"percent clamped to start", Config{SeekPercent: 10}, 20 * s, 300 * s, -2, 0, true},
+		{"percent unknown duration", Config{SeekPercent: 2}, 60 * s, 0, 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.config.seekTarget(tt.pos, tt.duration, tt.delta)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("seekTarget(%ds, %ds, %d) = %ds, %v; want %ds, %v", tt.pos/s, tt.duration/s, tt.delta, got/s, ok, tt.want/s, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSeek(t *testing.T) {
+	tests := []struct {
+		env         string
+		wantStep    time.Duration
+		wantPercent float64
+	}{
+		{"", 5 * time.Second, 0},
+		{"10s", 10 * time.Second, 0},
+		{"2%", 5 * time.Second, 2},
+		{"0%", 5 * time.Second, 0},
+		{"150%", 5 * time.Second, 0},
+		{"soon", 5 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		t.Setenv("NOWPLAYING_SEEK", tt.env)
+		config := loadConfig()
+		if config.SeekStep != tt.wantStep || config.SeekPercent != tt.wantPercent {
+			t.Errorf("NOWPLAYING_SEEK=%q gives step %v and %v%%, want %v and %v%%", tt.env, config.SeekStep, config.SeekPercent, tt.wantStep, tt.wantPercent)
+		}
+	}
+}
+
+func TestDialSeeksByPercentage(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+	m.config.SeekPercent = 2
+	m.config.SeekAccel = nil
+
+	m.SetNowPlaying(NowPlaying{Title: "T", ElapsedTimeMicros: 60_000_000, DurationMicros: 300_000_000})
+	if err := m.HandleDial(module.Dial1, module.DialEvent{Type: module.DialRotate, Delta: 3}); err != nil {
+		t.Fatal(err)
+	}
+	waitForCommand(t, fake, "media-control seek 78.0")
+
+	// Without a duration there's nothing to take a percentage of
+	m.SetNowPlaying(NowPlaying{Title: "Live", ElapsedTimeMicros: 60_000_000})
+	if err := m.HandleDial(module.Dial1, module.DialEvent{Type: module.DialRotate, Delta: 3}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := fake.Commands(); len(got) != 1 {
+		t.Errorf("ran %q, want no seek without a duration", got)
+	}
+}
+
+func TestJumpSeeksFromLivePosition(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+
+	m.SetNowPlaying(NowPlaying{Title: "T", ElapsedTimeMicros: 60_000_000, DurationMicros: 300_000_000})
+	m.jump(-15 * time.Second)
+	waitForCommand(t, fake, "media-control seek 45.0")
+
+	// Updates landing just behind the old position are then shown as is
+	m.liveState.RLock()
+	seeked := m.liveState.seeked
+	m.liveState.RUnlock()
+	if time.Since(seeked) >= seekSettle {
+		t.Error("seek not recorded, so the rewind would be smoothed over")
+	}
+}
+
+// waitForCommand waits for fake to run want, since commands run in the
+// background.
+func waitForCommand(t *testing.T, fake *runner.Fake, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !slices.Contains(fake.Commands(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("ran %q, want %q", fake.Commands(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestShortTouchSeeks(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+	m.SetNowPlaying(NowPlaying{Title: "T", ElapsedTimeMicros: 60_000_000, DurationMicros: 300_000_000})
+
+	// Outside the module's part of the strip, nothing happens
+	if err := m.HandleStripTouch(module.TouchStripEvent{Type: module.TouchTap, Point: image.Pt(600, 50)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The middle of the progress bar, 108 to 390 on a 400px region
+	if err := m.HandleStripTouch(module.TouchStripEvent{Type: module.TouchTap, Point: image.Pt(249, 50)}); err != nil {
+		t.Fatal(err)
+	}
+	waitForCommand(t, fake, "media-control seek 150.0")
+	if m.IsOverlayActive() {
+		t.Error("short touch showed the info overlay")
+	}
+	if got := fake.Commands(); len(got) != 1 {
+		t.Errorf("ran %q, want only the seek", got)
+	}
+}
+
+func TestLongTouch(t *testing.T) {
+	long := module.TouchStripEvent{Type: module.TouchLongTap, Point: image.Pt(200, 50)}
+
+	t.Run("info", func(t *testing.T) {
+		m := newStripModule(t)
+		fake := &runner.Fake{}
+		m.runner = fake
+		m.SetNowPlaying(NowPlaying{Title: "T", DurationMicros: 300_000_000})
+
+		if err := m.HandleStripTouch(long); err != nil {
+			t.Fatal(err)
+		}
+		if !m.IsOverlayActive() {
+			t.Error("long touch didn't show the info overlay")
+		}
+		time.Sleep(20 * time.Millisecond)
+		if got := fake.Commands(); len(got) != 0 {
+			t.Errorf("long touch ran %q, want no seek", got)
+		}
+	})
+
+	t.Run("app", func(t *testing.T) {
+		m := newStripModule(t)
+		fake := &runner.Fake{}
+		m.runner = fake
+		m.config.LongTouch = "app"
+		m.config.App = "Music"
+
+		if err := m.HandleStripTouch(long); err != nil {
+			t.Fatal(err)
+		}
+		name, args := platform.OpenApp("Music")
+		waitForCommand(t, fake, strings.Join(append([]string{name}, args...), " "))
+		if m.IsOverlayActive() {
+			t.Error("long touch set to open the app showed the info overlay")
+		}
+	})
+}
+
+// copyCommand returns the command line the clipboard copy runs.
+func copyCommand() string {
+	name, args := platform.Copy()
+	return strings.Join(append([]string{name}, args...), " ")
+}
+
+func TestDial2CopiesTrack(t *testing.T) {
+	m := newStripModule(t)
+	fake := &runner.Fake{}
+	m.runner = fake
+	m.SetNowPlaying(NowPlaying{Title: "Hey", Artist: "Someone", Album: "Songs"})
+
+	if err := m.HandleDial(module.Dial2, module.DialEvent{Type: module.DialRelease, Duration: 100 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	waitForCommand(t, fake, copyCommand())
+	if got, want := fake.Input(copyCommand()), "Someone – Hey"; got != want {
+		t.Errorf("copied %q, want %q", got, want)
+	}
+
+	// The strip confirms the copy once it's done
+	deadline := time.Now().Add(time.Second)
+	for {
+		m.mu.RLock()
+		copied := time.Now().Before(m.copiedUntil)
+		m.mu.RUnlock()
+		if copied {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("copy not confirmed on the strip")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCopyTrack(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		np     NowPlaying
+		want   string
+	}{
+		{"custom format", "{title} from {album} by {artist}", NowPlaying{Title: "Hey", Artist: "Someone", Album: "Songs"}, "Hey from Songs by Someone"},
+		{"nothing playing", "{artist} – {title}", NowPlaying{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newStripModule(t)
+			fake := &runner.Fake{}
+			m.runner = fake
+			m.config.CopyFormat = tt.format
+			m.SetNowPlaying(tt.np)
+
+			m.copyTrack()
+			if got := fake.Input(copyCommand()); got != tt.want {
+				t.Errorf("copied %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyTrackFailureNotConfirmed(t *testing.T) {
+	m := newStripModule(t)
+	m.runner = &runner.Fake{Err: errors.New("exit status 1")}
+	m.SetNowPlaying(NowPlaying{Title: "Hey", Artist: "Someone"})
+
+	m.copyTrack()
+	if !m.copiedUntil.IsZero() {
+		t.Error("failed copy confirmed on the strip")
+	}
+}
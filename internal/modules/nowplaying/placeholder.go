@@ -0,0 +1,48 @@
+package nowplaying
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+)
+
+// placeholderSize is the resolution the music note placeholder is drawn at
+// before being scaled like album art.
+const placeholderSize = 200
+
+// loadArtPlaceholder returns the image shown in place of album art when a
+// track has none: "note" (the default) draws a music note, "none" shows
+// nothing, and anything else is read as the path of an image file.
+func loadArtPlaceholder(v string) (image.Image, error) {
+	switch v {
+	case "", "note":
+		return notePlaceholder(), nil
+	case "none":
+		return nil, nil
+	}
+
+	f, err := os.Open(v)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", v, err)
+	}
+	return img, nil
+}
+
+// notePlaceholder draws a dim music note centered on a key-colored square.
+func notePlaceholder() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, placeholderSize, placeholderSize))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	iconSize := placeholderSize / 2
+	offset := (placeholderSize - iconSize) / 2
+	icon := renderSVGIcon(iconMusicSVG, iconSize, colorTime)
+	draw.Draw(img, image.Rect(offset, offset, offset+iconSize, offset+iconSize), icon, image.Point{}, draw.Over)
+	return img
+}
@@ -0,0 +1,58 @@
+package nowplaying
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArtPlaceholderFillsArtRegion(t *testing.T) {
+	m := newStripModule(t)
+	rect := image.Rect(0, 0, 400, 100)
+	artCenter := func(np NowPlaying) color.Color {
+		return m.renderStrip(rect, rect, &np, nil, nil, false).At(50, 50)
+	}
+
+	if got := artCenter(NowPlaying{Title: "T"}); got == colorBackground {
+		t.Error("art region empty for a track without art, want the placeholder")
+	}
+
+	// Art that fails to decode isn't replaced
+	if got := artCenter(NowPlaying{Title: "T", ArtworkData: "not an image"}); got != colorBackground {
+		t.Errorf("art region = %v for undecodable art, want the background", got)
+	}
+
+	m.config.ArtPlaceholder = nil
+	if got := artCenter(NowPlaying{Title: "T"}); got != colorBackground {
+		t.Errorf("art region = %v with no placeholder, want the background", got)
+	}
+}
+
+func TestLoadArtPlaceholder(t *testing.T) {
+	if img, err := loadArtPlaceholder("none"); img != nil || err != nil {
+		t.Errorf("loadArtPlaceholder(none) = %v, %v; want nothing", img, err)
+	}
+	if img, err := loadArtPlaceholder(""); img == nil || err != nil {
+		t.Errorf("loadArtPlaceholder(\"\") = %v, %v; want the note", img, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "art.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if img, err := loadArtPlaceholder(path); err != nil || img.Bounds().Dx() != 8 {
+		t.Errorf("loadArtPlaceholder(%s) = %v, %v; want the 8px image", path, img, err)
+	}
+
+	if _, err := loadArtPlaceholder(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("loadArtPlaceholder of a missing file succeeded")
+	}
+}
@@ -0,0 +1,70 @@
+package nowplaying
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/bus"
+	"github.com/phinze/belowdeck/internal/platform"
+)
+
+// powerCommandTimeout bounds the pause and play commands sent around sleep.
+// They run detached from the module's context because a wake is followed
+// right away by the device reconnecting, which stops the module.
+const powerCommandTimeout = 5 * time.Second
+
+// subscribePower pauses playback when the system sleeps and, if configured,
+// resumes it on wake. Does nothing unless PauseOnSleep is set.
+func (m *Module) subscribePower() {
+	b := m.Bus()
+	if b == nil || !m.config.PauseOnSleep {
+		return
+	}
+	b.SubscribeContext(m.Context(), bus.TopicPower, func(e bus.Event) {
+		switch e.Data {
+		case platform.Sleep:
+			go m.pauseForSleep()
+		case platform.Wake:
+			go m.resumeAfterWake()
+		}
+	})
+}
+
+// pauseForSleep pauses playback if anything is playing, remembering that
+// it did so, so resumeAfterWake knows whether to start it again.
+func (m *Module) pauseForSleep() {
+	if !m.liveState.get().Playing {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), powerCommandTimeout)
+	defer cancel()
+
+	log.Println("Pausing media for sleep")
+	if err := m.runner.Run(ctx, "media-control", "pause"); err != nil {
+		log.Printf("Failed to pause media for sleep: %v", err)
+		return
+	}
+	m.mu.Lock()
+	m.pausedForSleep = true
+	m.mu.Unlock()
+}
+
+// resumeAfterWake starts playback again if ResumeOnWake is set and it was
+// paused by pauseForSleep.
+func (m *Module) resumeAfterWake() {
+	m.mu.Lock()
+	paused := m.pausedForSleep
+	m.pausedForSleep = false
+	m.mu.Unlock()
+	if !paused || !m.config.ResumeOnWake {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), powerCommandTimeout)
+	defer cancel()
+
+	log.Println("Resuming media after wake")
+	if err := m.runner.Run(ctx, "media-control", "play"); err != nil {
+		log.Printf("Failed to resume media after wake: %v", err)
+	}
+}
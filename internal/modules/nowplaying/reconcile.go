@@ -0,0 +1,78 @@
+package nowplaying
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// pollPosition reconciles the playback position with "media-control get"
+// whenever the stream has been quiet for a whole ReconcileInterval. Between
+// stream events the position is extrapolated from the last one, which
+// drifts if the stream stalls while the process stays alive.
+func (m *Module) pollPosition(ctx context.Context) {
+	ticker := time.NewTicker(m.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if m.IsSnoozed() || time.Since(m.liveState.lastUpdate()) < m.config.ReconcileInterval {
+				continue
+			}
+			m.reconcilePosition(ctx)
+		}
+	}
+}
+
+// reconcilePosition fetches the current state once and corrects the
+// position from it.
+func (m *Module) reconcilePosition(ctx context.Context) {
+	out, err := m.runner.Output(ctx, "media-control", "get", "--micros")
+	if err != nil {
+		log.Printf("Failed to poll media position: %v", err)
+		return
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(out, &payload); err != nil || payload == nil {
+		return
+	}
+
+	m.liveState.Lock()
+	defer m.liveState.Unlock()
+	if next, ok := correctPosition(m.liveState.NowPlaying, payload, time.Now().UnixMicro()); ok {
+		m.liveState.NowPlaying = next
+	}
+}
+
+// correctPosition returns cur with its duration, elapsed time and playing
+// state taken from a polled payload, as of nowMicros. It reports false,
+// leaving cur alone, if the payload describes a different track: track
+// changes are left to the stream, which also carries the artwork.
+func correctPosition(cur NowPlaying, payload map[string]interface{}, nowMicros int64) (NowPlaying, bool) {
+	var polled NowPlaying
+	mergePayloadMap(&polled, payload)
+	if polled.Title != cur.Title || polled.Artist != cur.Artist || polled.Album != cur.Album {
+		return cur, false
+	}
+
+	next := cur
+	if _, ok := payload["durationMicros"]; ok {
+		next.DurationMicros = polled.DurationMicros
+	}
+	if _, ok := payload["playing"]; ok {
+		next.Playing = polled.Playing
+	}
+	if _, ok := payload["elapsedTimeMicros"]; ok {
+		next.ElapsedTimeMicros = polled.ElapsedTimeMicros
+		next.TimestampEpochMicros = polled.TimestampEpochMicros
+		if next.TimestampEpochMicros == 0 || next.TimestampEpochMicros > nowMicros+maxClockSkewMicros {
+			next.TimestampEpochMicros = nowMicros
+		}
+	}
+	return next, true
+}
@@ -0,0 +1,53 @@
+package nowplaying
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+func TestReconcilePositionCorrectsDrift(t *testing.T) {
+	const s = int64(time.Second / time.Microsecond)
+	m := newStripModule(t)
+	now := time.Now().UnixMicro()
+	fake := &runner.Fake{Outputs: map[string]string{}}
+	m.runner = fake
+	poll := func(title string) {
+		fake.Outputs["media-control get --micros"] = fmt.Sprintf(
+			`{"title": %q, "artist": "A", "playing": false, "durationMicros": %d, "elapsedTimeMicros": %d, "timestampEpochMicros": %d}`,
+			title, 240*s, 90*s, now)
+		m.reconcilePosition(context.Background())
+	}
+
+	// The stream last reported 10s in, a minute ago, and has since stalled
+	m.SetNowPlaying(NowPlaying{Title: "T", Artist: "A", Playing: true, DurationMicros: 180 * s, ElapsedTimeMicros: 10 * s, TimestampEpochMicros: now - 60*s})
+
+	// A poll for another track is left to the stream
+	poll("Other")
+	if got := m.liveState.get(); got.ElapsedTimeMicros != 10*s || !got.Playing {
+		t.Errorf("poll for another track changed the state to %+v", got)
+	}
+
+	poll("T")
+	got := m.liveState.get()
+	if got.ElapsedTimeMicros != 90*s || got.TimestampEpochMicros != now || got.DurationMicros != 240*s || got.Playing {
+		t.Errorf("state after the poll = %+v, want 90s of 240s elapsed as of now, paused", got)
+	}
+	if got.Title != "T" {
+		t.Errorf("title = %q, want it kept", got.Title)
+	}
+}
+
+func TestCorrectPositionFutureTimestamp(t *testing.T) {
+	const s = int64(time.Second / time.Microsecond)
+	cur := NowPlaying{Title: "T"}
+	payload := map[string]interface{}{"title": "T", "elapsedTimeMicros": float64(30 * s), "timestampEpochMicros": float64(1000 * s)}
+
+	next, ok := correctPosition(cur, payload, 100*s)
+	if !ok || next.TimestampEpochMicros != 100*s || next.ElapsedTimeMicros != 30*s {
+		t.Errorf("correctPosition = %+v, %v; want 30s elapsed as of now", next, ok)
+	}
+}
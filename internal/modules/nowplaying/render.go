@@ -11,7 +11,10 @@ import (
 	_ "image/png"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
 	"github.com/srwiley/oksvg"
 	"github.com/srwiley/rasterx"
 	"golang.org/x/image/draw"
@@ -35,6 +38,30 @@ var iconPauseSVG string
 //go:embed icons/info.svg
 var iconInfoSVG string
 
+//go:embed icons/music.svg
+var iconMusicSVG string
+
+//go:embed icons/skip-back.svg
+var iconSkipBackSVG string
+
+//go:embed icons/skip-forward.svg
+var iconSkipForwardSVG string
+
+//go:embed icons/stop.svg
+var iconStopSVG string
+
+//go:embed icons/shuffle.svg
+var iconShuffleSVG string
+
+//go:embed icons/repeat.svg
+var iconRepeatSVG string
+
+//go:embed icons/thumbs-up.svg
+var iconThumbsUpSVG string
+
+//go:embed icons/thumbs-down.svg
+var iconThumbsDownSVG string
+
 // Common colors
 var (
 	colorLimeGreen   = color.RGBA{50, 205, 50, 255}
@@ -45,76 +72,73 @@ var (
 	colorProgressBg  = color.RGBA{60, 60, 60, 255}
 	colorArtist      = color.RGBA{180, 180, 180, 255}
 	colorTime        = color.RGBA{120, 120, 120, 255}
+	colorArtBorder   = color.NRGBA{255, 255, 255, 48}
 )
 
 // initFonts initializes the font faces for rendering.
-func (m *Module) initFonts() error {
-	// Parse bold font for title
-	ttBold, err := opentype.Parse(fontBold)
-	if err != nil {
-		return fmt.Errorf("failed to parse bold font: %w", err)
-	}
-
-	m.titleFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    24,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create title face: %w", err)
-	}
-
-	// Parse regular font for artist
-	ttRegular, err := opentype.Parse(fontRegular)
-	if err != nil {
-		return fmt.Errorf("failed to parse regular font: %w", err)
-	}
-
-	m.artistFace, err = opentype.NewFace(ttRegular, &opentype.FaceOptions{
-		Size:    18,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create artist face: %w", err)
-	}
-
-	return nil
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("nowplaying bold", render.BoldFont, fontBold)
+	m.boldFont = ttBold
+	m.modeFace = render.NewFace(ttBold, 12)
+	m.jumpFace = render.NewFace(ttBold, 30)
+
+	ttRegular := render.ParseFont("nowplaying regular", render.RegularFont, fontRegular)
+	m.regularFont = ttRegular
+	m.artistFace = render.NewFace(ttRegular, 18)
 }
 
-// renderStrip renders the touch strip with album art, text, and progress bar.
-func (m *Module) renderStrip(rect image.Rectangle, np *NowPlaying, artwork image.Image) image.Image {
+// renderStrip renders the touch strip with album art, text, and progress bar
+// into region, the module's allocated part of the full strip rect. When
+// copied is set, a clipboard confirmation replaces the track text. A non-nil
+// accent fills the progress bar while playing instead of the configured color.
+func (m *Module) renderStrip(rect, region image.Rectangle, np *NowPlaying, artwork image.Image, accent color.Color, copied bool) image.Image {
 	img := image.NewRGBA(rect)
-	fullW := rect.Dx()
 	h := rect.Dy()
+	x0 := region.Min.X
+	right := region.Max.X
+
+	// Background - dark, only within our region so the rest stays
+	// transparent, or the blurred art if configured
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	if m.config.ArtBackground && artwork != nil {
+		backdrop := m.artworkBackdrop(artwork, region.Size())
+		draw.Draw(img, region, backdrop, image.Point{}, draw.Src)
+	}
 
-	// Only use left half of the strip
-	w := fullW / 2
-
-	// Background - dark (full strip to clear any previous content)
-	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
-
-	// Layout for left half: [Art full height] [gap] [Text + progress]
+	// Layout for our region: [Art full height] [gap] [Text + progress]
 	artSize := h // Full height bleed
-	textX := artSize + 8
+	textX := x0 + artSize + 8
 	progressH := 5
 	progressMargin := 8
 
-	// Draw album art thumbnail on left, full bleed
+	// Draw album art thumbnail on left, full bleed, or the placeholder for
+	// tracks without art
+	if artwork == nil && np.ArtworkData == "" {
+		artwork = m.config.ArtPlaceholder
+	}
 	if artwork != nil {
-		artRect := image.Rect(0, 0, artSize, artSize)
-		thumb := scaleImageSquare(artwork, artSize)
+		artRect := image.Rect(x0, 0, x0+artSize, artSize)
+		thumb := m.artworkThumb(artwork, artSize)
 		draw.Draw(img, artRect, thumb, image.Point{}, draw.Over)
 	}
 
-	// Draw title (bold)
-	if np.Title != "" {
-		m.drawText(img, np.Title, textX, 30, m.titleFace, color.White, w-textX-10)
-	}
+	// Draw time (elapsed / total) above progress bar, right-aligned; the
+	// track text is centered in the space above it
+	timeY := h - progressMargin - progressH - 6
+	textTop, textBottom := 6, timeY-m.artistFace.Metrics().Ascent.Ceil()-2
 
-	// Draw artist (regular, smaller, gray)
-	if np.Artist != "" {
-		m.drawText(img, np.Artist, textX, 54, m.artistFace, colorArtist, w-textX-10)
+	if copied {
+		m.drawText(img, "Copied to clipboard", textX, 42, m.artistFace, colorLimeGreen, right-textX-10)
+	} else {
+		// Draw title (bold, sized to fit) and artist (regular, smaller, gray)
+		layout := m.layoutStripText(np, right-textX-10, textTop, textBottom)
+		for _, line := range layout.lines {
+			col := color.Color(color.White)
+			if line.artist {
+				col = colorArtist
+			}
+			m.drawText(img, line.text, textX, line.y, line.face, col, right-textX-10)
+		}
 	}
 
 	// Calculate live elapsed time
@@ -131,29 +155,350 @@ func (m *Module) renderStrip(rect image.Rectangle, np *NowPlaying, artwork image
 	}
 
 	// Progress bar background
-	progressRect := image.Rect(textX, h-progressMargin-progressH, w-10, h-progressMargin)
-	draw.Draw(img, progressRect, &image.Uniform{colorProgressBg}, image.Point{}, draw.Src)
+	progressLeft, progressRight := progressBarSpan(region)
+	progressRect := image.Rect(progressLeft, h-progressMargin-progressH, progressRight, h-progressMargin)
+	draw.Draw(img, progressRect, &image.Uniform{m.config.ProgressBackground}, image.Point{}, draw.Src)
 
 	// Progress bar fill
-	progressColor := colorLimeGreen
+	progressColor := m.config.ProgressPlaying
 	if !np.Playing {
-		progressColor = colorOrange
+		progressColor = m.config.ProgressPaused
+	} else if accent != nil {
+		progressColor = accent
 	}
 	progressW := int(float64(progressRect.Dx()) * progress)
-	progressFill := image.Rect(textX, h-progressMargin-progressH, textX+progressW, h-progressMargin)
+	progressFill := image.Rect(progressLeft, h-progressMargin-progressH, progressLeft+progressW, h-progressMargin)
 	draw.Draw(img, progressFill, &image.Uniform{progressColor}, image.Point{}, draw.Src)
 
-	// Draw time (elapsed / total) above progress bar, right-aligned
+	modesRight := right - 10
 	if durationMicros > 0 {
 		elapsed := formatDurationMicros(elapsedMicros)
 		total := formatDurationMicros(durationMicros)
 		timeStr := fmt.Sprintf("%s / %s", elapsed, total)
-		m.drawTextRightAligned(img, timeStr, w-10, h-progressMargin-progressH-6, m.artistFace, colorTime)
+		m.drawTextRightAligned(img, timeStr, right-10, timeY, m.artistFace, colorTime)
+		modesRight -= font.MeasureString(m.artistFace, timeStr).Ceil() + 12
+	}
+
+	// Shuffle and repeat indicators to the left of the time
+	if labels := modeLabels(np); len(labels) > 0 {
+		m.drawTextRightAligned(img, strings.Join(labels, "  "), modesRight, timeY, m.modeFace, colorDeepSkyBlue)
+	}
+
+	return img
+}
+
+// progressBarSpan returns the horizontal extent of the progress bar within
+// the module's strip region. Touch seeking maps taps onto the same span.
+func progressBarSpan(region image.Rectangle) (left, right int) {
+	return region.Min.X + region.Dy() + 8, region.Max.X - 10
+}
+
+// stripTitleSizes are the title font sizes tried on the strip, largest
+// first. A title is drawn at the largest size that fits on one line; one
+// that doesn't fit even at the smallest is wrapped onto a second line when
+// there's no artist, and truncated otherwise.
+var stripTitleSizes = []float64{36, 32, 28, 24}
+
+// stripWrapSizes are the title font sizes tried, largest first, for a title
+// wrapped onto two lines.
+var stripWrapSizes = []float64{24, 22, 20}
+
+// stripTextLine is a line of track text on the strip, with its baseline.
+type stripTextLine struct {
+	text   string
+	face   font.Face
+	artist bool
+	y      int
+}
+
+// stripTextLayout is the sized and positioned track text on the strip.
+type stripTextLayout struct {
+	key   string
+	lines []stripTextLine
+}
+
+// layoutStripText returns the strip text layout for np within width and the
+// band from top to bottom, reusing the cached layout until the metadata or
+// area changes.
+func (m *Module) layoutStripText(np *NowPlaying, width, top, bottom int) *stripTextLayout {
+	key := fmt.Sprintf("%s\x00%s\x00%d\x00%d-%d", np.Title, np.Artist, width, top, bottom)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stripTextCache != nil && m.stripTextCache.key == key {
+		return m.stripTextCache
+	}
+
+	layout := fitStripText(m.boldFont, m.artistFace, np.Title, np.Artist, width, top, bottom)
+	layout.key = key
+	m.stripTextCache = layout
+	return layout
+}
+
+// fitStripText sizes the title per stripTitleSizes and centers the title
+// and artist lines vertically between top and bottom. The artist is always
+// drawn with artistFace.
+func fitStripText(bold *opentype.Font, artistFace font.Face, title, artist string, width, top, bottom int) *stripTextLayout {
+	height := bottom - top
+	artistHeight := 0
+	if artist != "" {
+		artistHeight = render.LineHeight(artistFace)
+	}
+
+	var titleFace font.Face
+	var titleLines []string
+	if title != "" {
+		for _, size := range stripTitleSizes {
+			face := render.NewFace(bold, size)
+			if font.MeasureString(face, title).Ceil() <= width && render.LineHeight(face)+artistHeight <= height {
+				titleFace, titleLines = face, []string{title}
+				break
+			}
+		}
+	}
+	if title != "" && titleFace == nil && artist == "" {
+		// Without an artist there's room for a second line. Whatever
+		// doesn't fit on it at the smallest size is truncated when drawn.
+		for _, size := range stripWrapSizes {
+			face := render.NewFace(bold, size)
+			wrapped := render.WrapText(face, title, width)
+			if len(wrapped) == 0 || 2*render.LineHeight(face) > height {
+				continue
+			}
+			titleFace = face
+			if len(wrapped) <= 2 {
+				titleLines = wrapped
+				break
+			}
+			titleLines = []string{wrapped[0], strings.Join(wrapped[1:], " ")}
+		}
+	}
+	if title != "" && titleFace == nil {
+		titleFace = render.NewFace(bold, stripTitleSizes[len(stripTitleSizes)-1])
+		titleLines = []string{title}
+	}
+
+	blockHeight := artistHeight
+	if titleFace != nil {
+		blockHeight += len(titleLines) * render.LineHeight(titleFace)
+	}
+	y := top
+	if blockHeight < height {
+		y += (height - blockHeight) / 2
+	}
+
+	layout := &stripTextLayout{}
+	add := func(text string, face font.Face, isArtist bool) {
+		layout.lines = append(layout.lines, stripTextLine{
+			text:   text,
+			face:   face,
+			artist: isArtist,
+			y:      y + face.Metrics().Ascent.Ceil(),
+		})
+		y += render.LineHeight(face)
+	}
+	for _, line := range titleLines {
+		add(line, titleFace, false)
+	}
+	if artist != "" {
+		add(artist, artistFace, true)
+	}
+	return layout
+}
+
+// infoTitleSizes are the title font sizes tried for the info overlay, largest
+// first. The artist/album line is drawn at three quarters of the title size.
+var infoTitleSizes = []float64{32, 28, 24, 20, 18, 16, 14, 12}
+
+// infoLayout is the wrapped, auto-sized track metadata for the info overlay.
+type infoLayout struct {
+	key        string
+	titleFace  font.Face
+	detailFace font.Face
+	title      []string
+	detail     []string
+}
+
+// height returns the total height in pixels of the laid out text block.
+func (l *infoLayout) height() int {
+	return len(l.title)*render.LineHeight(l.titleFace) + len(l.detail)*render.LineHeight(l.detailFace)
+}
+
+// renderInfoStrip renders the full-width info overlay: artwork on the left and
+// the complete title, artist and album wrapped into the remaining space.
+func (m *Module) renderInfoStrip(rect image.Rectangle, np *NowPlaying, artwork image.Image) image.Image {
+	img := image.NewRGBA(rect)
+	w := rect.Dx()
+	h := rect.Dy()
+
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	textX := 12
+	if artwork != nil {
+		thumb := m.artworkThumb(artwork, h)
+		draw.Draw(img, image.Rect(0, 0, h, h), thumb, image.Point{}, draw.Over)
+		textX = h + 12
+	}
+
+	m.drawInfoText(img, np, image.Rect(textX, 6, w-12, h-6))
+
+	return img
+}
+
+// renderOverlayKeys renders the keys for an overlay. Key8 is the back
+// button; on devices without a touch strip, drawContent fills the top row of
+// keys instead.
+func (m *Module) renderOverlayKeys(size int, hasStrip bool, drawContent func(img *image.RGBA, area image.Rectangle)) map[module.KeyID]image.Image {
+	keys := make(map[module.KeyID]image.Image)
+
+	allKeys := []module.KeyID{
+		module.Key1, module.Key2, module.Key3, module.Key4,
+		module.Key5, module.Key6, module.Key7,
+	}
+	for _, keyID := range allKeys {
+		blank := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(blank, blank.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+		keys[keyID] = blank
+	}
+	keys[module.Key8] = m.renderBackKey(size)
+
+	if hasStrip {
+		return keys
 	}
 
+	// Lay the content out once across the whole row, then slice it per key
+	row := []module.KeyID{module.Key1, module.Key2, module.Key3, module.Key4}
+	canvas := image.NewRGBA(image.Rect(0, 0, size*len(row), size))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+	drawContent(canvas, canvas.Bounds().Inset(6))
+
+	for i, keyID := range row {
+		key := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(key, key.Bounds(), canvas, image.Pt(i*size, 0), draw.Src)
+		keys[keyID] = key
+	}
+
+	return keys
+}
+
+// renderBackKey renders the back button for dismissing an overlay.
+func (m *Module) renderBackKey(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorKeyBg}, image.Point{}, draw.Src)
+
+	// Draw "Back" label centered
+	width := font.MeasureString(m.artistFace, "Back").Ceil()
+	m.drawText(img, "Back", (size-width)/2, size/2+6, m.artistFace, colorTime, size)
+
 	return img
 }
 
+// drawInfoText draws the wrapped track metadata vertically centered in area.
+func (m *Module) drawInfoText(img *image.RGBA, np *NowPlaying, area image.Rectangle) {
+	layout := m.layoutInfo(np, area.Dx(), area.Dy())
+	if layout == nil {
+		return
+	}
+
+	y := area.Min.Y
+	if h := layout.height(); h < area.Dy() {
+		y += (area.Dy() - h) / 2
+	}
+
+	drawLines := func(lines []string, face font.Face, col color.Color) {
+		metrics := face.Metrics()
+		for _, line := range lines {
+			// Drop lines that would spill out of the area at the minimum size
+			if y+metrics.Height.Ceil() > area.Max.Y {
+				return
+			}
+			m.drawText(img, line, area.Min.X, y+metrics.Ascent.Ceil(), face, col, area.Dx())
+			y += metrics.Height.Ceil()
+		}
+	}
+	drawLines(layout.title, layout.titleFace, color.White)
+	drawLines(layout.detail, layout.detailFace, colorArtist)
+}
+
+// layoutInfo returns the info overlay layout for np within width x height,
+// reusing the cached layout until the metadata or area changes.
+func (m *Module) layoutInfo(np *NowPlaying, width, height int) *infoLayout {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%dx%d", np.Title, np.Artist, np.Album, width, height)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.infoCache != nil && m.infoCache.key == key {
+		return m.infoCache
+	}
+
+	layout := fitInfoText(m.boldFont, m.regularFont, np.Title, infoDetail(np), width, height)
+	if layout != nil {
+		layout.key = key
+	}
+	m.infoCache = layout
+	return layout
+}
+
+// fitInfoText wraps title and detail at the largest size in infoTitleSizes
+// whose lines fit within width x height. If nothing fits, the smallest size
+// is returned and the caller drops lines that overflow.
+func fitInfoText(bold, regular *opentype.Font, title, detail string, width, height int) *infoLayout {
+	var layout *infoLayout
+	for _, size := range infoTitleSizes {
+		titleFace := render.NewFace(bold, size)
+		detailFace := render.NewFace(regular, size*0.75)
+
+		layout = &infoLayout{
+			titleFace:  titleFace,
+			detailFace: detailFace,
+			title:      render.WrapText(titleFace, title, width),
+			detail:     render.WrapText(detailFace, detail, width),
+		}
+		if layout.height() <= height {
+			return layout
+		}
+	}
+	return layout
+}
+
+// infoDetail returns the secondary info line: artist and album.
+func infoDetail(np *NowPlaying) string {
+	var parts []string
+	if np.Artist != "" {
+		parts = append(parts, np.Artist)
+	}
+	if np.Album != "" {
+		parts = append(parts, np.Album)
+	}
+	return strings.Join(parts, " — ")
+}
+
+// iconKeyCanvas returns a key canvas with an SVG icon filling it.
+func iconKeyCanvas(size int, svgContent string, iconColor color.Color) *render.Canvas {
+	c := render.NewKeyCanvas(size)
+	c.DrawImage(c.Bounds(), renderSVGIcon(svgContent, c.Px(size), iconColor))
+	return c
+}
+
+// drawModeBadges draws the shuffle and repeat indicators as badges along the
+// bottom of a key.
+func (m *Module) drawModeBadges(c *render.Canvas, np *NowPlaying) {
+	labels := modeLabels(np)
+	if len(labels) == 0 {
+		return
+	}
+
+	size := c.Bounds().Dx()
+	badgeH := render.BadgeSize(m.modeFace, labels[0]).Y
+	y := c.Bounds().Max.Y - badgeH/2 - 4
+	for i, label := range labels {
+		center := image.Pt(size*(2*i+1)/(2*len(labels)), y)
+		render.DrawBadge(c, label, center, m.modeFace, colorKeyBg, colorDeepSkyBlue)
+	}
+}
+
 // renderSVGIcon renders an SVG string to an image with the given size and color.
 func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
 	// Replace currentColor with the actual color
@@ -241,6 +586,40 @@ func truncateText(text string, face font.Face, maxWidth int) string {
 	return ellipsis
 }
 
+// thumbKey identifies a scaled artwork thumbnail.
+type thumbKey struct {
+	artwork image.Image
+	size    int
+}
+
+// artworkThumb scales artwork to a square of the given size and applies the
+// configured corner rounding and border. Thumbnails are cached, so the
+// expensive scale runs once per track and size rather than every frame.
+func (m *Module) artworkThumb(artwork image.Image, size int) image.Image {
+	m.thumbMu.Lock()
+	defer m.thumbMu.Unlock()
+
+	key := thumbKey{artwork: artwork, size: size}
+	if thumb, ok := m.thumbs[key]; ok {
+		return thumb
+	}
+
+	// Drop thumbnails of previous tracks' artwork
+	for k := range m.thumbs {
+		if k.artwork != artwork {
+			delete(m.thumbs, k)
+		}
+	}
+	if m.thumbs == nil {
+		m.thumbs = make(map[thumbKey]image.Image)
+	}
+
+	thumb := render.RoundCorners(scaleImageSquare(artwork, size), m.config.ArtRadius)
+	render.StrokeRoundedRect(thumb, thumb.Bounds(), m.config.ArtRadius, m.config.ArtBorder, colorArtBorder)
+	m.thumbs[key] = thumb
+	return thumb
+}
+
 // scaleImageSquare scales and crops an image to a square of the given size.
 func scaleImageSquare(src image.Image, size int) image.Image {
 	srcBounds := src.Bounds()
@@ -261,8 +640,9 @@ func scaleImageSquare(src image.Image, size int) image.Image {
 	return dst
 }
 
-// decodeArtwork decodes base64 artwork data to an image.
-func decodeArtwork(artworkBase64 string) image.Image {
+// decodeArtwork decodes base64 artwork data to an image, scaled down so
+// neither side exceeds maxSize. A maxSize of zero keeps the full size.
+func decodeArtwork(artworkBase64 string, maxSize int) image.Image {
 	imgData, err := base64.StdEncoding.DecodeString(artworkBase64)
 	if err != nil {
 		return nil
@@ -271,7 +651,49 @@ func decodeArtwork(artworkBase64 string) image.Image {
 	if err != nil {
 		return nil
 	}
-	return img
+	return limitImageSize(img, maxSize)
+}
+
+// limitImageSize scales img down, keeping its aspect ratio, so neither side
+// exceeds maxSize. Smaller images, and any image when maxSize is zero, are
+// returned as is.
+func limitImageSize(img image.Image, maxSize int) image.Image {
+	b := img.Bounds()
+	if maxSize <= 0 || (b.Dx() <= maxSize && b.Dy() <= maxSize) {
+		return img
+	}
+
+	w, h := maxSize, maxSize
+	if b.Dx() > b.Dy() {
+		h = max(1, b.Dy()*maxSize/b.Dx())
+	} else {
+		w = max(1, b.Dx()*maxSize/b.Dy())
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// renderJumpKey renders a jump key labeled with its amount, e.g. "+30"
+// over "sec".
+func (m *Module) renderJumpKey(size int, jump time.Duration) image.Image {
+	c := render.NewKeyCanvas(size)
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	label, unit := formatJump(jump), "sec"
+	if jump%time.Minute == 0 {
+		label, unit = fmt.Sprintf("%+d", int(jump/time.Minute)), "min"
+	}
+
+	c.DrawStringCentered(label, size/2, size/2+8, m.jumpFace, color.White)
+	c.DrawStringCentered(unit, size/2, size/2+28, m.modeFace, colorTime)
+	return c.Image()
+}
+
+// formatJump formats a jump in whole seconds with its sign, e.g. "+30".
+func formatJump(jump time.Duration) string {
+	return fmt.Sprintf("%+d", int(jump/time.Second))
 }
 
 // formatDurationMicros formats microseconds as m:ss.
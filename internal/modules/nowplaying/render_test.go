@@ -0,0 +1,171 @@
+package nowplaying
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+)
+
+func TestArtworkThumbCachedPerTrackAndSize(t *testing.T) {
+	m := newStripModule(t)
+	art := image.NewRGBA(image.Rect(0, 0, 300, 200))
+
+	thumb := m.artworkThumb(art, 64)
+	if got := thumb.Bounds().Size(); got != image.Pt(64, 64) {
+		t.Fatalf("thumb size = %v, want 64x64", got)
+	}
+	if again := m.artworkThumb(art, 64); again != thumb {
+		t.Error("same artwork and size scaled again, want the cached thumb")
+	}
+	if other := m.artworkThumb(art, 32); other == thumb {
+		t.Error("a new size reused the thumb for another size")
+	}
+
+	// A new track's artwork drops the old track's thumbs
+	next := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	m.artworkThumb(next, 64)
+	if len(m.thumbs) != 1 {
+		t.Errorf("cached %d thumbs after the artwork changed, want 1", len(m.thumbs))
+	}
+}
+
+func TestDecodeArtworkLimitsSize(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 800, 400))); err != nil {
+		t.Fatal(err)
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	for _, tt := range []struct {
+		max  int
+		want image.Point
+	}{
+		{200, image.Pt(200, 100)},
+		{1000, image.Pt(800, 400)},
+		{0, image.Pt(800, 400)},
+	} {
+		img := decodeArtwork(data, tt.max)
+		if img == nil {
+			t.Fatalf("decodeArtwork(max %d) = nil", tt.max)
+		}
+		if got := img.Bounds().Size(); got != tt.want {
+			t.Errorf("decodeArtwork(max %d) size = %v, want %v", tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestArtworkThumbRoundedCorners(t *testing.T) {
+	m := newStripModule(t)
+	m.config.ArtRadius = 10
+	red := color.RGBA{200, 0, 0, 255}
+	art := image.NewRGBA(image.Rect(0, 0, 300, 300))
+	draw.Draw(art, art.Bounds(), &image.Uniform{red}, image.Point{}, draw.Src)
+
+	thumb := m.artworkThumb(art, 100)
+	for _, p := range []image.Point{{0, 0}, {99, 0}, {0, 99}, {99, 99}} {
+		if _, _, _, a := thumb.At(p.X, p.Y).RGBA(); a != 0 {
+			t.Errorf("thumb corner %v alpha = %d, want transparent", p, a)
+		}
+	}
+	if got := color.RGBAModel.Convert(thumb.At(50, 50)); got != red {
+		t.Errorf("thumb center = %v, want the art", got)
+	}
+}
+
+func TestProgressColors(t *testing.T) {
+	t.Setenv("NOWPLAYING_PROGRESS_PLAYING", "#112233")
+	t.Setenv("NOWPLAYING_PROGRESS_PAUSED", "#778899")
+	t.Setenv("NOWPLAYING_PROGRESS_BG", "#445566")
+	playing := color.RGBA{0x11, 0x22, 0x33, 255}
+	paused := color.RGBA{0x77, 0x88, 0x99, 255}
+	track := color.RGBA{0x44, 0x55, 0x66, 255}
+	vivid := color.RGBA{200, 40, 40, 255}
+
+	tests := []struct {
+		name     string
+		fromArt  bool
+		playing  bool
+		art      color.RGBA
+		wantFill color.RGBA
+	}{
+		{"playing", false, true, vivid, playing},
+		{"paused", false, false, vivid, paused},
+		{"from art", true, true, vivid, vivid},
+		{"from grayscale art", true, true, color.RGBA{128, 128, 128, 255}, playing},
+		{"paused with art", true, false, vivid, paused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newStripModule(t)
+			m.config.ProgressFromArt = tt.fromArt
+			m.SetNowPlaying(NowPlaying{
+				Title:                "T",
+				Playing:              tt.playing,
+				ElapsedTimeMicros:    150_000_000,
+				DurationMicros:       300_000_000,
+				TimestampEpochMicros: time.Now().UnixMicro(),
+				ArtworkData:          solidArtwork(t, tt.art),
+			})
+			img := m.RenderStrip().(*image.RGBA)
+
+			// The bar runs from 108 to 390 on the 400px region, half full
+			if got := img.RGBAAt(120, 89); got != tt.wantFill {
+				t.Errorf("filled bar = %v, want %v", got, tt.wantFill)
+			}
+			if got := img.RGBAAt(380, 89); got != track {
+				t.Errorf("unfilled bar = %v, want the configured %v", got, track)
+			}
+		})
+	}
+}
+
+func TestFullWidthStrip(t *testing.T) {
+	np := NowPlaying{
+		Title:          "A Long Enough Title To Run Past The Middle Of The Strip",
+		Artist:         "Someone",
+		DurationMicros: 300_000_000,
+	}
+	half := newStripModule(t)
+	half.SetNowPlaying(np)
+	full := newStripModule(t)
+	full.BaseModule.Init(context.Background(), module.Resources{StripRect: image.Rect(0, 0, 800, 100)})
+	full.SetNowPlaying(np)
+
+	halfImg := half.RenderStrip().(*image.RGBA)
+	fullImg := full.RenderStrip().(*image.RGBA)
+
+	// The progress bar runs to the end of the allocation
+	track := full.config.ProgressBackground
+	if got := fullImg.RGBAAt(780, 89); got != color.RGBAModel.Convert(track) {
+		t.Errorf("full-width bar at x=780 = %v, want the track color", got)
+	}
+	if got := halfImg.RGBAAt(780, 89); got == color.RGBAModel.Convert(track) {
+		t.Error("half-width bar reaches x=780")
+	}
+
+	// Text spreads into the right half, which a half allocation leaves empty
+	inked := func(img *image.RGBA) bool {
+		for y := 0; y < 80; y++ {
+			for x := 420; x < 780; x++ {
+				if img.RGBAAt(x, y) != img.RGBAAt(799, 0) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if !inked(fullImg) {
+		t.Error("full-width strip draws nothing in its right half")
+	}
+	if inked(halfImg) {
+		t.Error("half-width strip draws into the other module's half")
+	}
+}
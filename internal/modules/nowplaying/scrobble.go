@@ -0,0 +1,195 @@
+package nowplaying
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/httpclient"
+)
+
+const (
+	// scrobbleMinDuration is how long a track must be to be scrobbled.
+	scrobbleMinDuration = 30 * time.Second
+
+	// scrobbleMaxPlayed is how long a track must play to be scrobbled,
+	// when that's less than half its duration.
+	scrobbleMaxPlayed = 4 * time.Minute
+
+	// scrobbleQueueSize is how many submissions can wait for the worker
+	// before more are dropped.
+	scrobbleQueueSize = 16
+)
+
+// scrobbleEligible reports whether a track of the given duration that played
+// for played counts as listened to: last.fm's rule is a track over 30
+// seconds played for half its duration or 4 minutes, whichever comes first.
+// A track of unknown (zero) duration needs the full 4 minutes.
+func scrobbleEligible(duration, played time.Duration) bool {
+	if duration <= 0 {
+		return played >= scrobbleMaxPlayed
+	}
+	if duration <= scrobbleMinDuration {
+		return false
+	}
+	return played >= min(duration/2, scrobbleMaxPlayed)
+}
+
+// scrobbleSubmission is a request for the scrobbler's worker: a now playing
+// update, or a scrobble if startedAt is set.
+type scrobbleSubmission struct {
+	track     scrobbleTrack
+	startedAt time.Time
+}
+
+// scrobbler follows the media stream, telling last.fm what's playing and
+// scrobbling each track that played long enough once the next one starts.
+// Only time spent playing counts, so pausing or seeking doesn't.
+type scrobbler struct {
+	client *lastfmClient
+	queue  chan scrobbleSubmission
+
+	// The current track: when it started, how long it has played before
+	// playingSince, and when it last started playing (zero while paused)
+	mu           sync.Mutex
+	track        scrobbleTrack
+	hasTrack     bool
+	startedAt    time.Time
+	played       time.Duration
+	playingSince time.Time
+	announced    bool
+}
+
+// newScrobbler creates a scrobbler submitting with creds.
+func newScrobbler(creds LastfmCredentials) *scrobbler {
+	return &scrobbler{
+		client: &lastfmClient{
+			baseURL:    lastfmAPIURL,
+			creds:      creds,
+			httpClient: httpclient.NewRetrying(),
+		},
+		queue: make(chan scrobbleSubmission, scrobbleQueueSize),
+	}
+}
+
+// trackOf returns the scrobbleable track in np, reporting false when
+// nothing identifiable is playing.
+func trackOf(np NowPlaying) (scrobbleTrack, bool) {
+	if np.Artist == "" || np.Artist == "?" || np.Title == "" || np.Title == "?" {
+		return scrobbleTrack{}, false
+	}
+	return scrobbleTrack{
+		Artist:   np.Artist,
+		Title:    np.Title,
+		Album:    np.Album,
+		Duration: time.Duration(np.DurationMicros) * time.Microsecond,
+	}, true
+}
+
+// observe records the playback state as of now, from a stream update.
+// Submissions are queued for the worker, so observe never blocks on the
+// network.
+func (s *scrobbler) observe(np NowPlaying, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	track, ok := trackOf(np)
+	if !ok || !s.hasTrack || track.Artist != s.track.Artist || track.Title != s.track.Title {
+		s.finishTrack(now)
+		if !ok {
+			return
+		}
+		s.track, s.hasTrack = track, true
+		s.startedAt, s.played, s.announced = now, 0, false
+	} else if track.Duration > 0 {
+		// Duration can arrive after the title
+		s.track.Duration = track.Duration
+	}
+
+	switch {
+	case np.Playing && s.playingSince.IsZero():
+		s.playingSince = now
+		if !s.announced {
+			s.announced = true
+			s.submit(scrobbleSubmission{track: s.track})
+		}
+	case !np.Playing && !s.playingSince.IsZero():
+		s.played += now.Sub(s.playingSince)
+		s.playingSince = time.Time{}
+	}
+}
+
+// finishTrack scrobbles the current track if it played long enough, and
+// forgets it. It's called with mu held.
+func (s *scrobbler) finishTrack(now time.Time) {
+	if !s.hasTrack {
+		return
+	}
+	played := s.played
+	if !s.playingSince.IsZero() {
+		played += now.Sub(s.playingSince)
+	}
+	if scrobbleEligible(s.track.Duration, played) {
+		s.submit(scrobbleSubmission{track: s.track, startedAt: s.startedAt})
+	}
+	s.hasTrack, s.playingSince = false, time.Time{}
+}
+
+// submit queues a submission, dropping it if the queue is full.
+func (s *scrobbler) submit(sub scrobbleSubmission) {
+	select {
+	case s.queue <- sub:
+	default:
+		log.Printf("last.fm: queue full, dropping %s - %s", sub.track.Artist, sub.track.Title)
+	}
+}
+
+// run sends queued submissions to last.fm until ctx is done. If the
+// credentials are rejected, it logs once and drops submissions from then
+// on.
+func (s *scrobbler) run(ctx context.Context) {
+	disabled := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sub := <-s.queue:
+			if disabled {
+				continue
+			}
+			err := s.send(ctx, sub)
+			var apiErr *lastfmError
+			if errors.As(err, &apiErr) && lastfmAuthFailed(apiErr.Code) {
+				log.Printf("last.fm: %v; scrobbling disabled, check the LASTFM_ credentials", err)
+				disabled = true
+			} else if err != nil {
+				log.Printf("last.fm: %v", err)
+			}
+		}
+	}
+}
+
+// send makes one submission.
+func (s *scrobbler) send(ctx context.Context, sub scrobbleSubmission) error {
+	if sub.startedAt.IsZero() {
+		return s.client.updateNowPlaying(ctx, sub.track)
+	}
+	if err := s.client.scrobble(ctx, sub.track, sub.startedAt); err != nil {
+		return err
+	}
+	log.Printf("last.fm: scrobbled %s - %s", sub.track.Artist, sub.track.Title)
+	return nil
+}
+
+// lastfmAuthFailed reports whether a last.fm error code means the
+// credentials are wrong, so retrying won't help: authentication failed (4),
+// invalid session key (9), invalid API key (10) or invalid signature (13).
+func lastfmAuthFailed(code int) bool {
+	switch code {
+	case 4, 9, 10, 13:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package nowplaying
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+func TestScrobbleEligible(t *testing.T) {
+	tests := []struct {
+		name             string
+		duration, played time.Duration
+		want             bool
+	}{
+		{"half played", 3 * time.Minute, 90 * time.Second, true},
+		{"just under half", 3 * time.Minute, 89 * time.Second, false},
+		{"four minutes of a long track", 20 * time.Minute, 4 * time.Minute, true},
+		{"under four minutes of a long track", 20 * time.Minute, 4*time.Minute - time.Second, false},
+		{"too short", 30 * time.Second, 30 * time.Second, false},
+		{"unknown duration", 0, 4 * time.Minute, true},
+		{"unknown duration, short play", 0, 3 * time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrobbleEligible(tt.duration, tt.played); got != tt.want {
+				t.Errorf("scrobbleEligible(%v, %v) = %v, want %v", tt.duration, tt.played, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignLastfm(t *testing.T) {
+	params := url.Values{
+		"track":   {"Song"},
+		"method":  {"track.scrobble"},
+		"api_key": {"key"},
+		"artist":  {"Band"},
+		"format":  {"json"},
+	}
+	sum := md5.Sum([]byte("api_keykeyartistBandmethodtrack.scrobbletrackSongsecret"))
+	if got, want := signLastfm(params, "secret"), hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("signLastfm() = %s, want %s", got, want)
+	}
+}
+
+// queued returns the submissions waiting in s's queue.
+func queued(s *scrobbler) []scrobbleSubmission {
+	var subs []scrobbleSubmission
+	for {
+		select {
+		case sub := <-s.queue:
+			subs = append(subs, sub)
+		default:
+			return subs
+		}
+	}
+}
+
+func TestScrobblerCountsOnlyPlayingTime(t *testing.T) {
+	s := newScrobbler(LastfmCredentials{})
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	song := NowPlaying{Artist: "Band", Title: "Song", DurationMicros: 200_000_000, Playing: true}
+
+	s.observe(song, start)
+	if subs := queued(s); len(subs) != 1 || !subs[0].startedAt.IsZero() {
+		t.Fatalf("got %+v when the track started, want a now playing update", subs)
+	}
+
+	// 60s playing, an hour paused, then 60s more: 120s of a 200s track
+	paused := song
+	paused.Playing = false
+	s.observe(paused, start.Add(time.Minute))
+	s.observe(song, start.Add(time.Hour))
+	s.observe(NowPlaying{Artist: "Band", Title: "Next", Playing: true}, start.Add(time.Hour+time.Minute))
+
+	subs := queued(s)
+	if len(subs) != 2 {
+		t.Fatalf("got %d submissions after the next track, want a scrobble and an update", len(subs))
+	}
+	if subs[0].track.Title != "Song" || !subs[0].startedAt.Equal(start) {
+		t.Errorf("scrobbled %+v, want Song started at %v", subs[0], start)
+	}
+
+	// 10s of the next, unknown-length track isn't enough
+	s.observe(NowPlaying{}, start.Add(time.Hour+time.Minute+10*time.Second))
+	if subs := queued(s); len(subs) != 0 {
+		t.Errorf("got %+v after skipping a track, want nothing", subs)
+	}
+}
+
+func TestLastfmClientScrobble(t *testing.T) {
+	srv := stubserver.New(t)
+	var mu sync.Mutex
+	var forms []url.Values
+	srv.Handle("POST /", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		forms = append(forms, r.PostForm)
+		mu.Unlock()
+		if r.PostForm.Get("method") == "auth.getMobileSession" {
+			stubserver.WriteJSON(w, map[string]any{"session": map[string]string{"key": "sk1"}})
+			return
+		}
+		stubserver.WriteJSON(w, map[string]any{})
+	})
+
+	c := &lastfmClient{
+		baseURL:    srv.URL + "/",
+		creds:      LastfmCredentials{APIKey: "key", APISecret: "secret", Username: "me", Password: "pw"},
+		httpClient: http.DefaultClient,
+	}
+	startedAt := time.Unix(1_790_000_000, 0)
+	if err := c.scrobble(context.Background(), scrobbleTrack{Artist: "Band", Title: "Song"}, startedAt); err != nil {
+		t.Fatalf("scrobble: %v", err)
+	}
+
+	if len(forms) != 2 {
+		t.Fatalf("made %d calls, want a session fetch and a scrobble", len(forms))
+	}
+	form := forms[1]
+	for name, want := range map[string]string{"method": "track.scrobble", "sk": "sk1", "timestamp": "1790000000", "artist": "Band"} {
+		if got := form.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	signed := url.Values{}
+	for name, v := range form {
+		if name != "api_sig" {
+			signed[name] = v
+		}
+	}
+	if got, want := form.Get("api_sig"), signLastfm(signed, "secret"); got != want {
+		t.Errorf("api_sig = %s, want %s", got, want)
+	}
+}
+
+func TestLastfmClientError(t *testing.T) {
+	srv := stubserver.New(t)
+	srv.Handle("POST /", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		stubserver.WriteJSON(w, map[string]any{"error": 9, "message": "Invalid session key"})
+	})
+
+	c := &lastfmClient{baseURL: srv.URL + "/", creds: LastfmCredentials{SessionKey: "old"}, httpClient: http.DefaultClient}
+	err := c.updateNowPlaying(context.Background(), scrobbleTrack{Artist: "Band", Title: "Song"})
+	var apiErr *lastfmError
+	if !errors.As(err, &apiErr) || apiErr.Code != 9 || !lastfmAuthFailed(apiErr.Code) {
+		t.Errorf("updateNowPlaying() error = %v, want a credentials error", err)
+	}
+}
@@ -0,0 +1,266 @@
+// Package quicknote provides a Stream Deck module for jotting down a quick
+// note or todo, appended with a timestamp to a file.
+package quicknote
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/platform"
+	"github.com/phinze/belowdeck/internal/runner"
+	"golang.org/x/image/font"
+)
+
+const (
+	// defaultTimeFormat is how entries are timestamped by default.
+	defaultTimeFormat = "2006-01-02 15:04"
+
+	// savedDuration is how long the key confirms a saved note.
+	savedDuration = 2 * time.Second
+)
+
+// Config holds the quick note module configuration.
+type Config struct {
+	// File is where notes are appended, one "- <timestamp> <note>" line
+	// each, so a Markdown file reads as a list.
+	File string
+
+	// PromptCommand is an optional shell command whose output is the note.
+	// When unset, a system dialog asks for it.
+	PromptCommand string
+
+	// TimeFormat is the Go time layout entries are timestamped with.
+	TimeFormat string
+}
+
+// Module implements the quick note module.
+type Module struct {
+	module.BaseModule
+
+	device  device.Device
+	config  Config
+	runner  runner.Runner
+	enabled bool
+
+	// openNotes opens the notes file for appending; now stamps entries
+	openNotes func(path string) (io.WriteCloser, error)
+	now       func() time.Time
+
+	// State: whether the prompt is up, and when the saved confirmation
+	// stops showing
+	mu         sync.RWMutex
+	prompting  bool
+	savedUntil time.Time
+
+	// Fonts
+	labelFace font.Face
+
+	// Resources
+	resources module.Resources
+}
+
+// New creates a new quick note module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("quicknote"),
+		device:     dev,
+		runner:     runner.Default,
+		openNotes:  openAppend,
+		now:        time.Now,
+	}
+}
+
+// openAppend opens path for appending, creating it if needed.
+func openAppend(path string) (io.WriteCloser, error) {
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "quicknote"
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	m.resources = res
+
+	// Load config from environment (optional - module disabled if not configured)
+	config, err := loadConfig()
+	if err != nil {
+		log.Printf("Quick note module disabled: %v", err)
+		m.enabled = false
+		return nil
+	}
+	m.config = config
+	m.enabled = true
+
+	// Initialize fonts
+	m.initFonts()
+
+	log.Printf("Quick note module initialized (%s)", m.config.File)
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+func loadConfig() (Config, error) {
+	file := os.Getenv("QUICKNOTE_FILE")
+	if file == "" {
+		return Config{}, fmt.Errorf("QUICKNOTE_FILE environment variable not set")
+	}
+
+	config := Config{
+		File:          file,
+		PromptCommand: os.Getenv("QUICKNOTE_PROMPT_COMMAND"),
+		TimeFormat:    defaultTimeFormat,
+	}
+	if v := os.Getenv("QUICKNOTE_TIME_FORMAT"); v != "" {
+		config.TimeFormat = v
+	}
+	return config, nil
+}
+
+// startPrompt marks the prompt as up, reporting false if it already was.
+func (m *Module) startPrompt() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.prompting {
+		return false
+	}
+	m.prompting = true
+	return true
+}
+
+// capture asks for a note and appends it to the notes file, flashing key
+// with the result. Cancelling the prompt, or entering nothing, saves
+// nothing.
+func (m *Module) capture(key module.KeyID) {
+	defer func() {
+		m.mu.Lock()
+		m.prompting = false
+		m.mu.Unlock()
+	}()
+
+	note, err := m.prompt(m.Context())
+	if err != nil {
+		log.Printf("Quick note cancelled: %v", err)
+		return
+	}
+	if note == "" {
+		return
+	}
+
+	err = m.appendNote(note)
+	if err != nil {
+		log.Printf("Failed to save quick note: %v", err)
+	} else {
+		m.mu.Lock()
+		m.savedUntil = time.Now().Add(savedDuration)
+		m.mu.Unlock()
+	}
+	if key != 0 {
+		m.FlashResult(key, err)
+	}
+}
+
+// prompt asks for a note with PromptCommand or the system dialog, returning
+// it on a single line.
+func (m *Module) prompt(ctx context.Context) (string, error) {
+	var out []byte
+	var err error
+	if m.config.PromptCommand != "" {
+		out, err = runner.ShellOutput(ctx, m.runner, m.config.PromptCommand)
+	} else {
+		name, args := platform.Prompt("Quick note")
+		out, err = m.runner.Output(ctx, name, args...)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(string(out)), " "), nil
+}
+
+// appendNote appends a timestamped entry for note to the notes file.
+func (m *Module) appendNote(note string) error {
+	w, err := m.openNotes(m.config.File)
+	if err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf("- %s %s\n", m.now().Format(m.config.TimeFormat), note)
+	if _, err := io.WriteString(w, entry); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	log.Printf("Saved quick note to %s", m.config.File)
+	return nil
+}
+
+// keyState returns whether the prompt is up and whether a note was just
+// saved.
+func (m *Module) keyState() (prompting, saved bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.prompting, time.Now().Before(m.savedUntil)
+}
+
+// RenderKeys returns images for the module's keys.
+func (m *Module) RenderKeys() map[module.KeyID]image.Image {
+	if !m.enabled || len(m.resources.Keys) == 0 {
+		return nil
+	}
+
+	prompting, saved := m.keyState()
+	return map[module.KeyID]image.Image{
+		m.resources.Keys[0]: m.renderNoteButton(prompting, saved),
+	}
+}
+
+// HandleAction runs an external action: "capture" asks for a note.
+func (m *Module) HandleAction(action string) error {
+	if action != "capture" {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	if !m.enabled {
+		return fmt.Errorf("not configured")
+	}
+	if m.startPrompt() {
+		go m.capture(0)
+	}
+	return nil
+}
+
+// HandleKey processes key events.
+func (m *Module) HandleKey(id module.KeyID, event module.KeyEvent) error {
+	if !m.enabled || !event.Pressed {
+		return nil
+	}
+
+	// The prompt waits on the user, so it runs in the background
+	if len(m.resources.Keys) > 0 && id == m.resources.Keys[0] && m.startPrompt() {
+		go m.capture(id)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package quicknote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/runner"
+)
+
+// notesBuffer is a notes file kept in memory.
+type notesBuffer struct {
+	bytes.Buffer
+	opened int
+}
+
+func (b *notesBuffer) Close() error { return nil }
+
+// newTestModule returns an enabled module whose prompt command prints note,
+// writing to the returned buffer at a fixed time.
+func newTestModule(t *testing.T, note string) (*Module, *notesBuffer, *runner.Fake) {
+	t.Helper()
+	notes := &notesBuffer{}
+	fake := &runner.Fake{Outputs: map[string]string{"sh -c ask": note}}
+
+	m := New(device.NewFake())
+	if err := m.BaseModule.Init(context.Background(), module.Resources{Keys: []module.KeyID{1}}); err != nil {
+		t.Fatal(err)
+	}
+	m.config = Config{File: "notes.md", PromptCommand: "ask", TimeFormat: defaultTimeFormat}
+	m.enabled = true
+	m.runner = fake
+	m.now = func() time.Time { return time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC) }
+	m.openNotes = func(path string) (io.WriteCloser, error) {
+		if path != "notes.md" {
+			t.Errorf("opened %q, want the configured file", path)
+		}
+		notes.opened++
+		return notes, nil
+	}
+	return m, notes, fake
+}
+
+func TestCaptureAppendsTimestampedNote(t *testing.T) {
+	m, notes, fake := newTestModule(t, "buy milk\nand eggs\n")
+
+	m.capture(0)
+	m.capture(0)
+
+	want := "- 2026-10-16 09:05 buy milk and eggs\n- 2026-10-16 09:05 buy milk and eggs\n"
+	if got := notes.String(); got != want {
+		t.Errorf("notes = %q, want %q", got, want)
+	}
+	if got := len(fake.Commands()); got != 2 {
+		t.Errorf("ran %d prompts, want 2", got)
+	}
+	if _, saved := m.keyState(); !saved {
+		t.Error("key not confirming a saved note")
+	}
+}
+
+func TestCaptureSavesNothing(t *testing.T) {
+	tests := []struct {
+		name string
+		note string
+		err  error
+	}{
+		{"empty", "  \n", nil},
+		{"cancelled", "", errors.New("exit status 1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, notes, fake := newTestModule(t, tt.note)
+			fake.Err = tt.err
+
+			m.capture(0)
+			if notes.opened != 0 {
+				t.Errorf("opened the notes file %d times, want 0", notes.opened)
+			}
+			if _, saved := m.keyState(); saved {
+				t.Error("key confirming a note that wasn't saved")
+			}
+		})
+	}
+}
+
+func TestHandleKeyPromptsOnce(t *testing.T) {
+	m, _, fake := newTestModule(t, "note")
+
+	// A press while the prompt is up doesn't open another
+	m.startPrompt()
+	if err := m.HandleKey(1, module.KeyEvent{Pressed: true}); err != nil {
+		t.Fatal(err)
+	}
+	if prompting, _ := m.keyState(); !prompting {
+		t.Fatal("prompt not up")
+	}
+	if got := fake.Commands(); len(got) != 0 {
+		t.Errorf("press while prompting ran %v, want nothing", got)
+	}
+}
@@ -0,0 +1,90 @@
+package quicknote
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strings"
+
+	"github.com/phinze/belowdeck/internal/render"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+//go:embed icons/notebook-pen.svg
+var iconNotebookSVG string
+
+// Common colors
+var (
+	colorKeyBg   = color.RGBA{40, 40, 40, 255}
+	colorWhite   = color.RGBA{255, 255, 255, 255}
+	colorYellow  = color.RGBA{255, 204, 0, 255}
+	colorGreen   = color.RGBA{40, 180, 70, 255}
+	colorDimGray = color.RGBA{120, 120, 120, 255}
+)
+
+const keySize = 72
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("quicknote bold", render.BoldFont, fontBold)
+	m.labelFace = render.NewFace(ttBold, 11)
+}
+
+// renderNoteButton renders the note key: dimmed while the prompt is up, and
+// confirming a note that was just saved.
+func (m *Module) renderNoteButton(prompting, saved bool) image.Image {
+	c := render.NewKeyCanvas(keySize)
+
+	// Background
+	c.Fill(c.Bounds(), colorKeyBg)
+
+	iconColor := colorYellow
+	labelText, labelColor := "Note", color.Color(colorWhite)
+	switch {
+	case prompting:
+		iconColor = colorDimGray
+		labelText = "Writing..."
+	case saved:
+		iconColor = colorGreen
+		labelText, labelColor = "Saved", colorGreen
+	}
+
+	// Draw icon in upper portion
+	iconImg := renderSVGIcon(iconNotebookSVG, c.Px(40), iconColor)
+	iconX := (keySize - 40) / 2
+	iconY := 8
+	c.DrawImage(image.Rect(iconX, iconY, iconX+40, iconY+40), iconImg)
+
+	// Draw label at bottom
+	c.DrawStringCentered(labelText, keySize/2, 62, m.labelFace, labelColor)
+
+	return c.Image()
+}
+
+// renderSVGIcon renders an SVG string to an image with the given size and color.
+func renderSVGIcon(svgContent string, size int, iconColor color.Color) image.Image {
+	r, g, b, _ := iconColor.RGBA()
+	hexColor := fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	svgContent = strings.ReplaceAll(svgContent, "currentColor", hexColor)
+
+	icon, err := oksvg.ReadIconStream(strings.NewReader(svgContent))
+	if err != nil {
+		log.Printf("Failed to parse SVG: %v", err)
+		return image.NewRGBA(image.Rect(0, 0, size, size))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.SetTarget(0, 0, float64(size), float64(size))
+
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	raster := rasterx.NewDasher(size, size, scanner)
+	icon.Draw(raster, 1.0)
+
+	return img
+}
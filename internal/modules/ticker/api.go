@@ -0,0 +1,124 @@
+package ticker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// chartURL is Yahoo Finance's chart endpoint. It needs no API key and covers
+// stocks, funds and crypto pairs such as "BTC-USD".
+const chartURL = "https://query1.finance.yahoo.com/v8/finance/chart/"
+
+// Quote is the latest price of one symbol.
+type Quote struct {
+	Symbol    string
+	Name      string
+	Currency  string
+	Price     float64
+	PrevClose float64
+	DayHigh   float64
+	DayLow    float64
+	Time      time.Time // when the price was quoted
+}
+
+// Change returns the change since the previous close.
+func (q Quote) Change() float64 {
+	return q.Price - q.PrevClose
+}
+
+// ChangePercent returns the change since the previous close as a
+// percentage, or 0 if the previous close is unknown.
+func (q Quote) ChangePercent() float64 {
+	if q.PrevClose == 0 {
+		return 0
+	}
+	return q.Change() / q.PrevClose * 100
+}
+
+// chartResponse is the part of the chart API response that's used.
+type chartResponse struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				Symbol               string  `json:"symbol"`
+				Currency             string  `json:"currency"`
+				ShortName            string  `json:"shortName"`
+				LongName             string  `json:"longName"`
+				RegularMarketPrice   float64 `json:"regularMarketPrice"`
+				ChartPreviousClose   float64 `json:"chartPreviousClose"`
+				PreviousClose        float64 `json:"previousClose"`
+				RegularMarketDayHigh float64 `json:"regularMarketDayHigh"`
+				RegularMarketDayLow  float64 `json:"regularMarketDayLow"`
+				RegularMarketTime    int64   `json:"regularMarketTime"`
+			} `json:"meta"`
+		} `json:"result"`
+		Error *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// fetchQuote fetches the latest quote for symbol from the chart API at
+// baseURL.
+func fetchQuote(ctx context.Context, client *http.Client, baseURL, symbol string) (Quote, error) {
+	params := url.Values{}
+	params.Set("range", "1d")
+	params.Set("interval", "1d")
+	reqURL := baseURL + url.PathEscape(symbol) + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return Quote{}, fmt.Errorf("create request: %w", err)
+	}
+	// The API turns away requests without a browser-like user agent
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; belowdeck)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Quote{}, fmt.Errorf("fetch %s: %w", symbol, err)
+	}
+	defer resp.Body.Close()
+
+	var data chartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return Quote{}, fmt.Errorf("API error for %s: %s", symbol, resp.Status)
+		}
+		return Quote{}, fmt.Errorf("decode response: %w", err)
+	}
+	if e := data.Chart.Error; e != nil {
+		return Quote{}, fmt.Errorf("API error for %s: %s", symbol, e.Description)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Quote{}, fmt.Errorf("API error for %s: %s", symbol, resp.Status)
+	}
+	if len(data.Chart.Result) == 0 {
+		return Quote{}, fmt.Errorf("no quote for %s", symbol)
+	}
+
+	meta := data.Chart.Result[0].Meta
+	q := Quote{
+		Symbol:    symbol,
+		Name:      meta.ShortName,
+		Currency:  meta.Currency,
+		Price:     meta.RegularMarketPrice,
+		PrevClose: meta.ChartPreviousClose,
+		DayHigh:   meta.RegularMarketDayHigh,
+		DayLow:    meta.RegularMarketDayLow,
+	}
+	if meta.LongName != "" {
+		q.Name = meta.LongName
+	}
+	if q.PrevClose == 0 {
+		q.PrevClose = meta.PreviousClose
+	}
+	if meta.RegularMarketTime > 0 {
+		q.Time = time.Unix(meta.RegularMarketTime, 0)
+	}
+	return q, nil
+}
@@ -0,0 +1,337 @@
+// Package ticker provides a Stream Deck module showing a scrolling stock and
+// crypto price ticker on the touch strip.
+package ticker
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/httpclient"
+	"github.com/phinze/belowdeck/internal/module"
+	"golang.org/x/image/font"
+)
+
+const (
+	// defaultInterval is how often quotes are fetched by default.
+	defaultInterval = 5 * time.Minute
+
+	// defaultSpeed is the default scroll speed in pixels per second.
+	defaultSpeed = 40
+
+	// overlayDuration is how long a symbol's details stay up after a tap.
+	overlayDuration = 10 * time.Second
+)
+
+// Config holds the ticker module configuration.
+type Config struct {
+	// Symbols are shown in order, e.g. "AAPL" or "BTC-USD".
+	Symbols []string
+
+	// Interval is how often quotes are fetched.
+	Interval time.Duration
+
+	// Speed is how fast the ticker scrolls, in pixels per second. Zero
+	// stops it scrolling.
+	Speed float64
+}
+
+// Module implements the ticker module.
+type Module struct {
+	module.BaseModule
+
+	device     device.Device
+	config     Config
+	httpClient *http.Client
+	baseURL    string
+
+	// State: the last quote fetched for each symbol, and which symbols'
+	// latest fetch failed so their last-known quote is shown dimmed
+	mu     sync.RWMutex
+	quotes map[string]Quote
+	failed map[string]bool
+
+	// started is when scrolling began; the scroll offset is derived from
+	// the time since
+	started time.Time
+
+	// Overlay state: the symbol whose details are up, until overlayExpiry
+	detail        string
+	overlayExpiry time.Time
+
+	// Fonts
+	symbolFace font.Face
+	priceFace  font.Face
+	labelFace  font.Face
+	valueFace  font.Face
+	titleFace  font.Face
+
+	// Cancel function for polling
+	pollCancel context.CancelFunc
+}
+
+// New creates a new ticker module.
+func New(dev device.Device) *Module {
+	return &Module{
+		BaseModule: module.NewBaseModule("ticker"),
+		device:     dev,
+		httpClient: httpclient.New(),
+		baseURL:    chartURL,
+		quotes:     make(map[string]Quote),
+		failed:     make(map[string]bool),
+	}
+}
+
+// ID returns the module identifier.
+func (m *Module) ID() string {
+	return "ticker"
+}
+
+// Configured reports whether any symbols are set.
+func (m *Module) Configured() bool {
+	_, err := loadConfig()
+	return err == nil
+}
+
+// Init initializes the module.
+func (m *Module) Init(ctx context.Context, res module.Resources) error {
+	if err := m.BaseModule.Init(ctx, res); err != nil {
+		return err
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	m.config = config
+
+	// The ticker only shows on the strip; without a region there's
+	// nothing to fetch for
+	if !res.HasStrip() {
+		log.Println("Ticker module has no strip region, not polling")
+		return nil
+	}
+
+	m.initFonts()
+	m.started = time.Now()
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	m.pollCancel = cancel
+	module.Supervise(pollCtx, "ticker poll", m.pollQuotes)
+
+	log.Printf("Ticker module initialized (%s)", strings.Join(m.config.Symbols, ", "))
+	return nil
+}
+
+// Stop shuts down the module.
+func (m *Module) Stop() error {
+	if m.pollCancel != nil {
+		m.pollCancel()
+	}
+	return m.BaseModule.Stop()
+}
+
+// loadConfig loads configuration from environment variables.
+func loadConfig() (Config, error) {
+	v := os.Getenv("TICKER_SYMBOLS")
+	if v == "" {
+		return Config{}, fmt.Errorf("TICKER_SYMBOLS environment variable not set")
+	}
+
+	config := Config{Interval: defaultInterval, Speed: defaultSpeed}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			config.Symbols = append(config.Symbols, s)
+		}
+	}
+	if len(config.Symbols) == 0 {
+		return Config{}, fmt.Errorf("TICKER_SYMBOLS has no symbols")
+	}
+
+	if v := os.Getenv("TICKER_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("invalid TICKER_INTERVAL: %q", v)
+		}
+		config.Interval = interval
+	}
+
+	if v := os.Getenv("TICKER_SPEED"); v != "" {
+		speed, err := strconv.ParseFloat(v, 64)
+		if err != nil || speed < 0 {
+			return Config{}, fmt.Errorf("invalid TICKER_SPEED: %q", v)
+		}
+		config.Speed = speed
+	}
+
+	return config, nil
+}
+
+// pollQuotes fetches quotes periodically.
+func (m *Module) pollQuotes(ctx context.Context) {
+	// Fetch immediately on start
+	m.fetchQuotes(ctx)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Skip fetches while snoozed
+			if m.IsSnoozed() {
+				continue
+			}
+			m.fetchQuotes(ctx)
+		}
+	}
+}
+
+// fetchQuotes fetches the quote for every symbol. A symbol whose fetch
+// fails keeps its last-known quote, marked as failed.
+func (m *Module) fetchQuotes(ctx context.Context) {
+	for _, symbol := range m.config.Symbols {
+		q, err := fetchQuote(ctx, m.httpClient, m.baseURL, symbol)
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.mu.Lock()
+		if err != nil {
+			if !m.failed[symbol] {
+				log.Printf("Ticker fetch error: %v", err)
+			}
+			m.failed[symbol] = true
+		} else {
+			m.quotes[symbol] = q
+			delete(m.failed, symbol)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// items returns what to show for each symbol, in order, leaving out
+// symbols that have never been fetched.
+func (m *Module) items() []item {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var items []item
+	for _, symbol := range m.config.Symbols {
+		if q, ok := m.quotes[symbol]; ok {
+			items = append(items, item{Quote: q, Stale: m.failed[symbol]})
+		}
+	}
+	return items
+}
+
+// fetchFailed reports whether every symbol's latest fetch failed.
+func (m *Module) fetchFailed() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.failed) == len(m.config.Symbols)
+}
+
+// RenderStrip returns the touch strip image.
+func (m *Module) RenderStrip() image.Image {
+	rect, err := m.device.GetTouchStripImageRectangle()
+	if err != nil {
+		return nil
+	}
+	return m.renderStrip(rect, m.Resources().StripRect, m.items(), m.fetchFailed(), time.Since(m.started))
+}
+
+// HandleStripTouch opens the details overlay for the tapped symbol.
+func (m *Module) HandleStripTouch(event module.TouchStripEvent) error {
+	region := m.Resources().StripRect
+	if event.Type != module.TouchTap || !event.Point.In(region) {
+		return nil
+	}
+
+	items := m.items()
+	i := itemAt(m.spans(items), region, event.Point.X, m.scrollOffset(items, region, time.Since(m.started)))
+	if i < 0 {
+		return nil
+	}
+
+	symbol := items[i].Quote.Symbol
+	log.Printf("Ticker: showing %s", symbol)
+	m.mu.Lock()
+	m.detail = symbol
+	m.overlayExpiry = time.Now().Add(overlayDuration)
+	m.mu.Unlock()
+	return nil
+}
+
+// detailItem returns the symbol whose details overlay is up.
+func (m *Module) detailItem() (item, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.detail == "" || time.Now().After(m.overlayExpiry) {
+		return item{}, false
+	}
+	q, ok := m.quotes[m.detail]
+	return item{Quote: q, Stale: m.failed[m.detail]}, ok
+}
+
+// closeOverlay dismisses the details overlay.
+func (m *Module) closeOverlay() {
+	m.mu.Lock()
+	m.detail = ""
+	m.mu.Unlock()
+}
+
+// IsOverlayActive returns true while a symbol's details are up.
+func (m *Module) IsOverlayActive() bool {
+	_, ok := m.detailItem()
+	return ok
+}
+
+// RenderOverlayKeys returns the details of the symbol on the keys.
+func (m *Module) RenderOverlayKeys() map[module.KeyID]image.Image {
+	it, ok := m.detailItem()
+	if !ok {
+		return nil
+	}
+	return m.renderDetailKeys(it)
+}
+
+// RenderOverlayStrip returns the strip for the details overlay.
+func (m *Module) RenderOverlayStrip() image.Image {
+	it, ok := m.detailItem()
+	if !ok {
+		return nil
+	}
+	rect, err := m.device.GetTouchStripImageRectangle()
+	if err != nil {
+		return nil
+	}
+	return m.renderDetailStrip(rect, it)
+}
+
+// HandleOverlayKey dismisses the overlay on any key press.
+func (m *Module) HandleOverlayKey(id module.KeyID, event module.KeyEvent) error {
+	if event.Pressed {
+		m.closeOverlay()
+	}
+	return nil
+}
+
+// HandleOverlayStripTouch dismisses the overlay on a tap.
+func (m *Module) HandleOverlayStripTouch(event module.TouchStripEvent) error {
+	if event.Type == module.TouchTap || event.Type == module.TouchLongTap {
+		m.closeOverlay()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package ticker
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+func TestFetchQuotesKeepsLastKnownOnFailure(t *testing.T) {
+	srv := stubserver.New(t)
+	var down atomic.Bool
+	srv.Handle("GET /chart/{symbol}", func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		stubserver.WriteJSON(w, map[string]any{"chart": map[string]any{"result": []any{
+			map[string]any{"meta": map[string]any{
+				"currency":           "USD",
+				"shortName":          r.PathValue("symbol") + " Inc.",
+				"regularMarketPrice": 101.5,
+				"chartPreviousClose": 100.0,
+			}},
+		}}})
+	})
+
+	m := New(device.NewFake())
+	m.config = Config{Symbols: []string{"AAPL", "MSFT"}}
+	m.httpClient = srv.Client()
+	m.baseURL = srv.URL + "/chart/"
+
+	m.fetchQuotes(context.Background())
+	items := m.items()
+	if len(items) != 2 || items[0].Quote.Symbol != "AAPL" || items[0].Quote.Price != 101.5 || items[0].Stale {
+		t.Fatalf("items = %+v, want fresh AAPL and MSFT quotes", items)
+	}
+	if m.fetchFailed() {
+		t.Error("fetchFailed after a successful fetch")
+	}
+
+	down.Store(true)
+	m.fetchQuotes(context.Background())
+	items = m.items()
+	if len(items) != 2 || items[1].Quote.Price != 101.5 || !items[1].Stale {
+		t.Errorf("items = %+v, want the last-known quotes marked stale", items)
+	}
+	if !m.fetchFailed() {
+		t.Error("fetchFailed false after every fetch failed")
+	}
+}
+
+func TestFetchQuoteAPIError(t *testing.T) {
+	srv := stubserver.New(t)
+	srv.JSON("GET /chart/{symbol}", map[string]any{"chart": map[string]any{
+		"error": map[string]string{"code": "Not Found", "description": "No data found, symbol may be delisted"},
+	}})
+
+	_, err := fetchQuote(context.Background(), srv.Client(), srv.URL+"/chart/", "NOPE")
+	if err == nil || !strings.Contains(err.Error(), "delisted") {
+		t.Errorf("err = %v, want the API's description", err)
+	}
+}
@@ -0,0 +1,386 @@
+package ticker
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/module"
+	"github.com/phinze/belowdeck/internal/render"
+	"golang.org/x/image/font"
+)
+
+//go:embed fonts/PublicSans-Bold.ttf
+var fontBold []byte
+
+//go:embed fonts/PublicSans-Regular.ttf
+var fontRegular []byte
+
+// Colors
+var (
+	colorUp         = color.RGBA{46, 204, 113, 255}
+	colorDown       = color.RGBA{231, 76, 60, 255}
+	colorFlat       = color.RGBA{160, 160, 160, 255}
+	colorBackground = color.RGBA{25, 25, 25, 255}
+	colorKeyBg      = color.RGBA{40, 40, 40, 255}
+	colorWhite      = color.RGBA{255, 255, 255, 255}
+	colorGray       = color.RGBA{160, 160, 160, 255}
+)
+
+const (
+	keySize = 72
+
+	// stripPadding is the space at the left of the region before the
+	// first symbol when the ticker isn't scrolling.
+	stripPadding = 12
+
+	// itemGap is the space between symbols, and between the last symbol
+	// and the first as the ticker wraps around.
+	itemGap = 36
+
+	// wordGap is the space between a symbol, its price and its change.
+	wordGap = 8
+)
+
+// currencySymbols are the prefixes shown for common quote currencies. Other
+// currencies show no prefix.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+}
+
+// item is a symbol as shown on the ticker. Stale items show their
+// last-known quote dimmed because the latest fetch failed.
+type item struct {
+	Quote Quote
+	Stale bool
+}
+
+// span is the horizontal extent of an item, relative to the start of the
+// ticker's content.
+type span struct {
+	start, end int
+}
+
+// initFonts initializes the font faces for rendering.
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("ticker bold", render.BoldFont, fontBold)
+	m.symbolFace = render.NewFace(ttBold, 20)
+	m.valueFace = render.NewFace(ttBold, 14)
+	m.titleFace = render.NewFace(ttBold, 28)
+
+	ttRegular := render.ParseFont("ticker regular", render.RegularFont, fontRegular)
+	m.priceFace = render.NewFace(ttRegular, 20)
+	m.labelFace = render.NewFace(ttRegular, 12)
+}
+
+// formatPrice formats a price for display, with the currency's symbol if it
+// has one and thousands separated. Large prices drop their cents and
+// fractional ones keep four decimals, so prices stay readable at a glance.
+func formatPrice(price float64, currency string) string {
+	abs := math.Abs(price)
+	decimals := 2
+	switch {
+	case abs >= 10000:
+		decimals = 0
+	case abs < 1:
+		decimals = 4
+	}
+
+	s := fmt.Sprintf("%.*f", decimals, abs)
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	s = groupThousands(whole)
+	if hasFrac {
+		s += "." + frac
+	}
+
+	s = currencySymbols[currency] + s
+	if price < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// groupThousands inserts commas between groups of three digits.
+func groupThousands(digits string) string {
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatChange formats a percentage change with its sign, e.g. "+1.25%".
+// Changes that round to zero show no sign.
+func formatChange(pct float64) string {
+	if roundChange(pct) == 0 {
+		return "0.00%"
+	}
+	return fmt.Sprintf("%+.2f%%", pct)
+}
+
+// formatChangeAmount formats the change in price with its sign, e.g.
+// "+$1.25".
+func formatChangeAmount(q Quote) string {
+	change := q.Change()
+	switch {
+	case roundChange(q.ChangePercent()) == 0:
+		return formatPrice(math.Abs(change), q.Currency)
+	case change > 0:
+		return "+" + formatPrice(change, q.Currency)
+	default:
+		return formatPrice(change, q.Currency)
+	}
+}
+
+// roundChange rounds a percentage change to the two decimals shown.
+func roundChange(pct float64) float64 {
+	return math.Round(pct*100) / 100
+}
+
+// changeColor returns the color for a quote's change: green if it's up,
+// red if it's down, gray if it's flat to the precision shown.
+func changeColor(q Quote) color.RGBA {
+	switch pct := roundChange(q.ChangePercent()); {
+	case pct > 0:
+		return colorUp
+	case pct < 0:
+		return colorDown
+	default:
+		return colorFlat
+	}
+}
+
+// dim returns col at half brightness, for last-known values.
+func dim(col color.RGBA) color.RGBA {
+	return color.RGBA{col.R / 2, col.G / 2, col.B / 2, col.A}
+}
+
+// itemColors returns the text and change colors for an item.
+func itemColors(it item) (text, change color.RGBA) {
+	text, change = colorWhite, changeColor(it.Quote)
+	if it.Stale {
+		return dim(text), dim(change)
+	}
+	return text, change
+}
+
+// itemWords returns the symbol, price and change shown for an item.
+func itemWords(q Quote) (symbol, price, change string) {
+	return q.Symbol, formatPrice(q.Price, q.Currency), formatChange(q.ChangePercent())
+}
+
+// spans lays the items out in a row and returns each one's extent.
+func (m *Module) spans(items []item) []span {
+	spans := make([]span, len(items))
+	x := 0
+	for i, it := range items {
+		symbol, price, change := itemWords(it.Quote)
+		w := font.MeasureString(m.symbolFace, symbol).Ceil() + wordGap +
+			font.MeasureString(m.priceFace, price).Ceil() + wordGap +
+			font.MeasureString(m.symbolFace, change).Ceil()
+		spans[i] = span{start: x, end: x + w}
+		x += w + itemGap
+	}
+	return spans
+}
+
+// period returns the width of one pass of the ticker: every item plus the
+// gap before the first comes round again.
+func period(spans []span) int {
+	if len(spans) == 0 {
+		return 0
+	}
+	return spans[len(spans)-1].end + itemGap
+}
+
+// marqueeOffset returns how far a ticker scrolling at speed pixels per
+// second has moved after elapsed, wrapped to [0, period).
+func marqueeOffset(elapsed time.Duration, speed float64, period int) int {
+	if period <= 0 || speed <= 0 || elapsed <= 0 {
+		return 0
+	}
+	return int(math.Mod(elapsed.Seconds()*speed, float64(period)))
+}
+
+// scrolls reports whether items laid out as spans need to scroll to fit in
+// region.
+func scrolls(spans []span, region image.Rectangle, speed float64) bool {
+	if len(spans) == 0 || speed <= 0 {
+		return false
+	}
+	return spans[len(spans)-1].end > region.Dx()-2*stripPadding
+}
+
+// scrollOffset returns the current scroll offset for items in region, zero
+// when they fit without scrolling.
+func (m *Module) scrollOffset(items []item, region image.Rectangle, elapsed time.Duration) int {
+	spans := m.spans(items)
+	if !scrolls(spans, region, m.config.Speed) {
+		return 0
+	}
+	return marqueeOffset(elapsed, m.config.Speed, period(spans))
+}
+
+// itemAt returns the index of the item under strip x-coordinate x when the
+// ticker is scrolled by offset, or -1 if x falls between items.
+func itemAt(spans []span, region image.Rectangle, x, offset int) int {
+	pos := x - region.Min.X - stripPadding + offset
+	if p := period(spans); offset > 0 && p > 0 {
+		pos = ((pos % p) + p) % p
+	}
+	for i, s := range spans {
+		if pos >= s.start && pos < s.end {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderStrip renders the ticker into region, the module's allocated part
+// of the full strip rect, scrolled to where it is after elapsed.
+func (m *Module) renderStrip(rect, region image.Rectangle, items []item, failed bool, elapsed time.Duration) image.Image {
+	img := image.NewRGBA(rect)
+	c := render.CanvasOf(img)
+	c.Fill(region, colorBackground)
+
+	y := region.Min.Y + region.Dy()/2 + 7
+	if len(items) == 0 {
+		msg := "Loading..."
+		if failed {
+			msg = "Quotes unavailable"
+		}
+		c.DrawString(msg, region.Min.X+10, y, m.priceFace, colorGray)
+		return img
+	}
+
+	spans := m.spans(items)
+	start := region.Min.X + stripPadding
+	step := 0
+	if scrolls(spans, region, m.config.Speed) {
+		start -= marqueeOffset(elapsed, m.config.Speed, period(spans))
+		step = period(spans)
+	}
+
+	// Draw passes until the region is covered; pixels outside it are
+	// clipped by the coordinator
+	for x := start; x < region.Max.X; x += step {
+		for i, it := range items {
+			m.drawItem(c, it, x+spans[i].start, y)
+		}
+		if step == 0 {
+			break
+		}
+	}
+	return img
+}
+
+// drawItem draws an item with its baseline starting at (x, y).
+func (m *Module) drawItem(c *render.Canvas, it item, x, y int) {
+	symbol, price, change := itemWords(it.Quote)
+	text, changeCol := itemColors(it)
+
+	c.DrawString(symbol, x, y, m.symbolFace, text)
+	x += c.MeasureString(m.symbolFace, symbol) + wordGap
+	c.DrawString(price, x, y, m.priceFace, text)
+	x += c.MeasureString(m.priceFace, price) + wordGap
+	c.DrawString(change, x, y, m.symbolFace, changeCol)
+}
+
+// renderDetailKeys renders a symbol's details across the keys, with Key8
+// closing the overlay.
+func (m *Module) renderDetailKeys(it item) map[module.KeyID]image.Image {
+	q := it.Quote
+	text, changeCol := itemColors(it)
+
+	keys := map[module.KeyID]image.Image{
+		module.Key1: m.renderSymbolKey(q.Symbol, text),
+		module.Key2: m.renderValueKey("Price", formatPrice(q.Price, q.Currency), text),
+		module.Key3: m.renderValueKey("Change", formatChangeAmount(q), changeCol),
+		module.Key4: m.renderValueKey("Percent", formatChange(q.ChangePercent()), changeCol),
+		module.Key5: m.renderValueKey("Day low", formatPrice(q.DayLow, q.Currency), text),
+		module.Key6: m.renderValueKey("Day high", formatPrice(q.DayHigh, q.Currency), text),
+		module.Key7: m.renderValueKey("Prev close", formatPrice(q.PrevClose, q.Currency), text),
+	}
+
+	c := render.NewKeyCanvas(keySize)
+	c.Fill(c.Bounds(), colorKeyBg)
+	c.DrawStringCentered("Back", keySize/2, keySize/2+4, m.valueFace, colorGray)
+	keys[module.Key8] = c.Image()
+
+	return keys
+}
+
+// renderSymbolKey renders a key showing just the symbol.
+func (m *Module) renderSymbolKey(symbol string, col color.Color) image.Image {
+	c := render.NewKeyCanvas(keySize)
+	c.Fill(c.Bounds(), colorKeyBg)
+	c.DrawStringCentered(fitText(m.valueFace, symbol, keySize-8), keySize/2, keySize/2+5, m.valueFace, col)
+	return c.Image()
+}
+
+// renderValueKey renders a key with a small label above a value.
+func (m *Module) renderValueKey(label, value string, col color.Color) image.Image {
+	c := render.NewKeyCanvas(keySize)
+	c.Fill(c.Bounds(), colorKeyBg)
+	c.DrawStringCentered(label, keySize/2, 24, m.labelFace, colorGray)
+	c.DrawStringCentered(fitText(m.valueFace, value, keySize-8), keySize/2, 48, m.valueFace, col)
+	return c.Image()
+}
+
+// renderDetailStrip renders a symbol's details across the full strip.
+func (m *Module) renderDetailStrip(rect image.Rectangle, it item) image.Image {
+	img := image.NewRGBA(rect)
+	c := render.CanvasOf(img)
+	c.Fill(rect, colorBackground)
+
+	q := it.Quote
+	text, changeCol := itemColors(it)
+
+	// Symbol and name on the left
+	c.DrawString(q.Symbol, 20, 44, m.titleFace, text)
+	if q.Name != "" {
+		c.DrawString(fitText(m.labelFace, q.Name, 300), 20, 72, m.labelFace, colorGray)
+	}
+
+	// Price and change in the middle
+	c.DrawString(formatPrice(q.Price, q.Currency), 340, 44, m.titleFace, text)
+	change := fmt.Sprintf("%s (%s)", formatChangeAmount(q), formatChange(q.ChangePercent()))
+	c.DrawString(change, 340, 74, m.priceFace, changeCol)
+
+	// When it was quoted on the right
+	status := "Latest"
+	if !q.Time.IsZero() {
+		status = "As of " + q.Time.Format("15:04")
+	}
+	if it.Stale {
+		status += " (stale)"
+	}
+	c.DrawString(status, 620, 44, m.labelFace, colorGray)
+	c.DrawString("Tap to close", 620, 72, m.labelFace, dim(colorGray))
+
+	return img
+}
+
+// fitText shortens text with an ellipsis to fit within maxWidth.
+func fitText(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		if s := string(runes[:n]) + "..."; font.MeasureString(face, s).Ceil() <= maxWidth {
+			return s
+		}
+	}
+	return "..."
+}
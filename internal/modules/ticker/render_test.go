@@ -0,0 +1,118 @@
+package ticker
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestFormatPrice(t *testing.T) {
+	for _, tt := range []struct {
+		price    float64
+		currency string
+		want     string
+	}{
+		{189.5, "USD", "$189.50"},
+		{1234.567, "EUR", "€1,234.57"},
+		{67890.12, "USD", "$67,890"},
+		{1234567, "JPY", "¥1,234,567"},
+		{0.123456, "USD", "$0.1235"},
+		{-2.5, "GBP", "-£2.50"},
+		{42, "CHF", "42.00"},
+	} {
+		if got := formatPrice(tt.price, tt.currency); got != tt.want {
+			t.Errorf("formatPrice(%v, %q) = %q, want %q", tt.price, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFormatChange(t *testing.T) {
+	for _, tt := range []struct {
+		pct  float64
+		want string
+	}{
+		{1.254, "+1.25%"},
+		{-0.5, "-0.50%"},
+		{0.001, "0.00%"},
+		{-0.004, "0.00%"},
+	} {
+		if got := formatChange(tt.pct); got != tt.want {
+			t.Errorf("formatChange(%v) = %q, want %q", tt.pct, got, tt.want)
+		}
+	}
+
+	q := Quote{Currency: "USD", Price: 101.25, PrevClose: 100}
+	if got := formatChangeAmount(q); got != "+$1.25" {
+		t.Errorf("formatChangeAmount = %q, want %q", got, "+$1.25")
+	}
+}
+
+func TestChangeColor(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		price float64
+		want  color.RGBA
+	}{
+		{"up", 101, colorUp},
+		{"down", 99, colorDown},
+		{"flat to the precision shown", 100.001, colorFlat},
+	} {
+		if got := changeColor(Quote{Price: tt.price, PrevClose: 100}); got != tt.want {
+			t.Errorf("%s: changeColor = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := changeColor(Quote{Price: 100}); got != colorFlat {
+		t.Errorf("changeColor without a previous close = %v, want flat", got)
+	}
+}
+
+func TestStaleItemsDimmed(t *testing.T) {
+	q := Quote{Price: 101, PrevClose: 100}
+	text, change := itemColors(item{Quote: q, Stale: true})
+	if text != dim(colorWhite) || change != dim(colorUp) {
+		t.Errorf("stale colors = %v, %v; want %v, %v", text, change, dim(colorWhite), dim(colorUp))
+	}
+	if text, _ := itemColors(item{Quote: q}); text != colorWhite {
+		t.Errorf("fresh text color = %v, want white", text)
+	}
+}
+
+func TestMarqueeOffset(t *testing.T) {
+	for _, tt := range []struct {
+		elapsed time.Duration
+		speed   float64
+		period  int
+		want    int
+	}{
+		{0, 40, 500, 0},
+		{time.Second, 40, 500, 40},
+		{2500 * time.Millisecond, 40, 500, 100},
+		{13 * time.Second, 40, 500, 20}, // wrapped
+		{time.Second, 0, 500, 0},
+		{time.Second, 40, 0, 0},
+	} {
+		if got := marqueeOffset(tt.elapsed, tt.speed, tt.period); got != tt.want {
+			t.Errorf("marqueeOffset(%v, %v, %d) = %d, want %d", tt.elapsed, tt.speed, tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestItemAt(t *testing.T) {
+	spans := []span{{0, 100}, {136, 200}}
+	region := image.Rect(400, 0, 800, 100)
+	for _, tt := range []struct {
+		x, offset int
+		want      int
+	}{
+		{400 + stripPadding + 50, 0, 0},
+		{400 + stripPadding + 110, 0, -1}, // in the gap
+		{400 + stripPadding + 150, 0, 1},
+		{400 + stripPadding + 10, 136, 1},
+		{400 + stripPadding + 10, 230, 0}, // wrapped round to the first
+	} {
+		if got := itemAt(spans, region, tt.x, tt.offset); got != tt.want {
+			t.Errorf("itemAt(x=%d, offset=%d) = %d, want %d", tt.x, tt.offset, got, tt.want)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"time"
 )
 
 // OneCallResponse represents the OpenWeatherMap One Call 3.0 API response.
@@ -69,10 +68,11 @@ type PrecipForecast struct {
 	Description string // Human-readable description
 }
 
-// fetchOneCall fetches weather data from the One Call 3.0 API.
-func fetchOneCall(ctx context.Context, apiKey string, lat, lon float64) (CurrentWeather, DailyForecast, PrecipForecast, error) {
-	baseURL := "https://api.openweathermap.org/data/3.0/onecall"
+// oneCallURL is the OpenWeatherMap One Call 3.0 endpoint.
+const oneCallURL = "https://api.openweathermap.org/data/3.0/onecall"
 
+// fetchOneCall fetches weather data from the One Call 3.0 API at baseURL.
+func fetchOneCall(ctx context.Context, client *http.Client, baseURL, apiKey string, lat, lon float64) (CurrentWeather, DailyForecast, PrecipForecast, error) {
 	params := url.Values{}
 	params.Set("lat", fmt.Sprintf("%.6f", lat))
 	params.Set("lon", fmt.Sprintf("%.6f", lon))
@@ -87,7 +87,6 @@ func fetchOneCall(ctx context.Context, apiKey string, lat, lon float64) (Current
 		return CurrentWeather{}, DailyForecast{}, PrecipForecast{}, fmt.Errorf("create request: %w", err)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return CurrentWeather{}, DailyForecast{}, PrecipForecast{}, fmt.Errorf("fetch weather: %w", err)
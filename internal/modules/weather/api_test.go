@@ -0,0 +1,55 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/phinze/belowdeck/internal/stubserver"
+)
+
+func TestFetchOneCall(t *testing.T) {
+	srv := stubserver.New(t)
+	srv.JSON("GET /onecall", map[string]any{
+		"current": map[string]any{
+			"temp":       71.6,
+			"feels_like": 70.2,
+			"humidity":   40,
+			"weather":    []map[string]any{{"main": "Clouds", "description": "broken clouds", "icon": "04d"}},
+		},
+		"daily": []map[string]any{{
+			"temp":    map[string]any{"min": 58.1, "max": 75.3},
+			"weather": []map[string]any{{"main": "Rain", "icon": "10d"}},
+		}},
+	})
+
+	current, daily, _, err := fetchOneCall(context.Background(), http.DefaultClient, srv.URL+"/onecall", "key", 41.88, -87.63)
+	if err != nil {
+		t.Fatalf("fetchOneCall: %v", err)
+	}
+	if current.Temp != 71.6 || current.Condition != "Clouds" || current.Icon != "04d" {
+		t.Errorf("current = %+v, want 71.6° Clouds 04d", current)
+	}
+	if daily.TempMax != 75.3 || daily.TempMin != 58.1 || daily.Condition != "Rain" {
+		t.Errorf("daily = %+v, want 58.1-75.3° Rain", daily)
+	}
+
+	query := srv.Requests()[0].URL.Query()
+	if got := query.Get("appid"); got != "key" {
+		t.Errorf("appid = %q, want %q", got, "key")
+	}
+	if got := query.Get("lat"); got != "41.880000" {
+		t.Errorf("lat = %q, want %q", got, "41.880000")
+	}
+}
+
+func TestFetchOneCallAPIError(t *testing.T) {
+	srv := stubserver.New(t)
+	srv.Handle("GET /onecall", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Invalid API key"}`, http.StatusUnauthorized)
+	})
+
+	if _, _, _, err := fetchOneCall(context.Background(), http.DefaultClient, srv.URL+"/onecall", "bad", 0, 0); err == nil {
+		t.Error("fetchOneCall succeeded on 401, want an error")
+	}
+}
@@ -6,16 +6,22 @@ import (
 	"fmt"
 	"image"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/phinze/belowdeck/internal/device"
+	"github.com/phinze/belowdeck/internal/httpclient"
 	"github.com/phinze/belowdeck/internal/module"
 	"golang.org/x/image/font"
 )
 
+// stripInterval is how often the strip is re-rendered. Weather is fetched
+// far less often, so the strip needn't redraw every render cycle.
+const stripInterval = 30 * time.Second
+
 // Config holds the weather module configuration.
 type Config struct {
 	APIKey string
@@ -27,8 +33,10 @@ type Config struct {
 type Module struct {
 	module.BaseModule
 
-	device device.Device
-	config Config
+	device     device.Device
+	config     Config
+	httpClient *http.Client
+	baseURL    string
 
 	// State
 	state *weatherState
@@ -61,6 +69,12 @@ func (s *weatherState) get() (CurrentWeather, DailyForecast, PrecipForecast) {
 	return s.Current, s.Daily, s.Precip
 }
 
+func (s *weatherState) lastFetch() time.Time {
+	s.RLock()
+	defer s.RUnlock()
+	return s.LastFetch
+}
+
 func (s *weatherState) update(current CurrentWeather, daily DailyForecast, precip PrecipForecast) {
 	s.Lock()
 	defer s.Unlock()
@@ -75,6 +89,8 @@ func New(dev device.Device) *Module {
 	return &Module{
 		BaseModule: module.NewBaseModule("weather"),
 		device:     dev,
+		httpClient: httpclient.New(),
+		baseURL:    oneCallURL,
 		state:      newWeatherState(),
 	}
 }
@@ -84,6 +100,12 @@ func (m *Module) ID() string {
 	return "weather"
 }
 
+// Configured reports whether the API key and location are set.
+func (m *Module) Configured() bool {
+	_, err := loadConfig()
+	return err == nil
+}
+
 // Init initializes the module.
 func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	// Call base init
@@ -98,15 +120,20 @@ func (m *Module) Init(ctx context.Context, res module.Resources) error {
 	}
 	m.config = config
 
-	// Initialize fonts
-	if err := m.initFonts(); err != nil {
-		return err
+	// Weather only shows on the strip; without a region (e.g. with the
+	// strip off) there's nothing to fetch for
+	if !res.HasStrip() {
+		log.Println("Weather module has no strip region, not polling")
+		return nil
 	}
 
+	// Initialize fonts
+	m.initFonts()
+
 	// Start polling in background
 	pollCtx, cancel := context.WithCancel(ctx)
 	m.pollCancel = cancel
-	go m.pollWeather(pollCtx)
+	module.Supervise(pollCtx, "weather poll", m.pollWeather)
 
 	log.Printf("Weather module initialized (lat=%.4f, lon=%.4f)", m.config.Lat, m.config.Lon)
 	return nil
@@ -163,6 +190,10 @@ func (m *Module) pollWeather(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			// Skip fetches while snoozed
+			if m.IsSnoozed() {
+				continue
+			}
 			m.fetchWeather(ctx)
 		}
 	}
@@ -170,7 +201,7 @@ func (m *Module) pollWeather(ctx context.Context) {
 
 // fetchWeather fetches current weather from the API.
 func (m *Module) fetchWeather(ctx context.Context) {
-	current, daily, precip, err := fetchOneCall(ctx, m.config.APIKey, m.config.Lat, m.config.Lon)
+	current, daily, precip, err := fetchOneCall(ctx, m.httpClient, m.baseURL, m.config.APIKey, m.config.Lat, m.config.Lon)
 	if err != nil {
 		log.Printf("Weather fetch error: %v", err)
 		return
@@ -203,7 +234,18 @@ func (m *Module) RenderStrip() image.Image {
 	}
 
 	current, daily, precip := m.state.get()
-	return m.renderStrip(rect, current, daily, precip)
+	return m.renderStrip(rect, m.Resources().StripRect, current, daily, precip)
+}
+
+// StripInterval returns how often the strip needs re-rendering.
+func (m *Module) StripInterval() time.Duration {
+	return stripInterval
+}
+
+// StripUpdated returns when the weather was last fetched, so the strip
+// shows new data without waiting out the strip interval.
+func (m *Module) StripUpdated() time.Time {
+	return m.state.lastFetch()
 }
 
 // HandleKey processes key events.
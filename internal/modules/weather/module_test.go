@@ -0,0 +1,23 @@
+package weather
+
+import "testing"
+
+func TestConfigured(t *testing.T) {
+	tests := []struct {
+		key, lat, lon string
+		want          bool
+	}{
+		{"k", "45.5", "-122.6", true},
+		{"", "45.5", "-122.6", false},
+		{"k", "", "-122.6", false},
+		{"k", "north", "-122.6", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("OPENWEATHERMAP_API_KEY", tt.key)
+		t.Setenv("WEATHER_LAT", tt.lat)
+		t.Setenv("WEATHER_LON", tt.lon)
+		if got := New(nil).Configured(); got != tt.want {
+			t.Errorf("Configured() with key %q at %q, %q = %v, want %v", tt.key, tt.lat, tt.lon, got, tt.want)
+		}
+	}
+}
@@ -8,11 +8,11 @@ import (
 	"log"
 	"strings"
 
+	"github.com/phinze/belowdeck/internal/render"
 	"github.com/srwiley/oksvg"
 	"github.com/srwiley/rasterx"
 	"golang.org/x/image/draw"
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -66,71 +66,47 @@ var (
 )
 
 // initFonts initializes the font faces for rendering.
-func (m *Module) initFonts() error {
-	ttBold, err := opentype.Parse(fontBold)
-	if err != nil {
-		return fmt.Errorf("parse bold font: %w", err)
-	}
-
-	// Large temp for strip
-	m.tempSmallFace, err = opentype.NewFace(ttBold, &opentype.FaceOptions{
-		Size:    32,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("create temp face: %w", err)
-	}
-
-	ttRegular, err := opentype.Parse(fontRegular)
-	if err != nil {
-		return fmt.Errorf("parse regular font: %w", err)
-	}
-
-	m.conditionFace, err = opentype.NewFace(ttRegular, &opentype.FaceOptions{
-		Size:    16,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		return fmt.Errorf("create condition face: %w", err)
-	}
+func (m *Module) initFonts() {
+	ttBold := render.ParseFont("weather bold", render.BoldFont, fontBold)
+	m.tempSmallFace = render.NewFace(ttBold, 32)
 
-	return nil
+	ttRegular := render.ParseFont("weather regular", render.RegularFont, fontRegular)
+	m.conditionFace = render.NewFace(ttRegular, 16)
 }
 
-// renderStrip renders the weather strip segment.
-func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily DailyForecast, precip PrecipForecast) image.Image {
-	// Create full-size image but only fill our region (400-800)
+// renderStrip renders the weather strip segment into region, the module's
+// allocated part of the full strip rect.
+func (m *Module) renderStrip(rect, region image.Rectangle, current CurrentWeather, daily DailyForecast, precip PrecipForecast) image.Image {
+	// Create full-size image but only fill our region
 	img := image.NewRGBA(rect)
 	h := rect.Dy()
+	x0 := region.Min.X
 
-	// Only fill our region with background (don't touch 0-400)
-	myRegion := image.Rect(400, 0, 800, h)
-	draw.Draw(img, myRegion, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+	// Only fill our region with background (leave the rest transparent)
+	draw.Draw(img, region, &image.Uniform{colorBackground}, image.Point{}, draw.Src)
 
 	// If no data yet, show placeholder
 	if current.Temp == 0 {
-		m.drawText(img, "Loading...", 410, h/2+6, m.conditionFace, colorGray)
+		m.drawText(img, "Loading...", x0+10, h/2+6, m.conditionFace, colorGray)
 		return img
 	}
 
-	// Layout (400-800, 400px wide):
-	// Icon: 400-480 (centered 70px icon with padding)
-	// Left text: 490-610 (temp, feels like, condition)
-	// Right text: 620-790 (high/low, precip)
+	// Layout (offsets from the region's left edge, 400px wide):
+	// Icon: 0-80 (centered 70px icon with padding)
+	// Left text: 90-210 (temp, feels like, condition)
+	// Right text: 220-390 (high/low, precip)
 
 	// ICON (left side)
 	iconSVG, iconColor := getWeatherIcon(current.Icon)
 	iconSize := 70
 	iconImg := renderSVGIcon(iconSVG, iconSize, iconColor)
-	iconX := 405
+	iconX := x0 + 5
 	iconY := (h - iconSize) / 2
 	iconRect := image.Rect(iconX, iconY, iconX+iconSize, iconY+iconSize)
 	draw.Draw(img, iconRect, iconImg, image.Point{}, draw.Over)
 
 	// LEFT TEXT SECTION
-	leftX := 490
+	leftX := x0 + 90
 
 	// Current temperature (large)
 	tempStr := fmt.Sprintf("%.0f°", current.Temp)
@@ -151,7 +127,7 @@ func (m *Module) renderStrip(rect image.Rectangle, current CurrentWeather, daily
 	m.drawText(img, condition, leftX, 82, m.conditionFace, colorGray)
 
 	// RIGHT TEXT SECTION
-	rightX := 620
+	rightX := x0 + 220
 
 	// High/Low
 	if daily.TempMax != 0 || daily.TempMin != 0 {
@@ -249,4 +225,3 @@ func (m *Module) drawText(img *image.RGBA, text string, x, y int, face font.Face
 	}
 	d.DrawString(text)
 }
-
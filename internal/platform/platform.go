@@ -0,0 +1,76 @@
+// Package platform isolates the commands and system services that differ
+// between macOS and other systems, so the daemon builds everywhere and
+// modules don't hard-code macOS tools. macOS is the primary platform;
+// elsewhere, features without an equivalent are reported as unavailable.
+package platform
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// PowerEvent is a system sleep or wake, as delivered by PowerEvents.
+type PowerEvent string
+
+const (
+	Sleep PowerEvent = "sleep"
+	Wake  PowerEvent = "wake"
+)
+
+// sendLatest sends ev on events, replacing any event still waiting to be
+// received, so a reader only ever sees the latest state rather than a stale
+// sleep from before a wake.
+func sendLatest(events chan PowerEvent, ev PowerEvent) {
+	for {
+		select {
+		case events <- ev:
+			return
+		default:
+		}
+		select {
+		case <-events:
+		default:
+		}
+	}
+}
+
+// MediaControlAvailable reports whether media-control, which the now
+// playing module streams playback state from, is installed. It's only
+// available on macOS.
+func MediaControlAvailable() bool {
+	if !mediaControlSupported {
+		return false
+	}
+	_, err := exec.LookPath("media-control")
+	return err == nil
+}
+
+// SwitchAudioSourceAvailable reports whether SwitchAudioSource, which the
+// audio output module reads and switches the output device with, is
+// installed. It's a macOS tool (brew install switchaudio-osx).
+func SwitchAudioSourceAvailable() bool {
+	_, err := exec.LookPath("SwitchAudioSource")
+	return err == nil
+}
+
+// CheckDependencies logs the external tools modules need but can't find.
+// Their modules are left out and the rest run, unless
+// BELOWDECK_REQUIRE_DEPS is set, in which case a missing tool is returned
+// as an error so the daemon can refuse to start.
+func CheckDependencies() error {
+	missing := missingDependencies()
+	if len(missing) == 0 {
+		return nil
+	}
+	if require, _ := strconv.ParseBool(os.Getenv("BELOWDECK_REQUIRE_DEPS")); require {
+		return errors.New(strings.Join(missing, "; "))
+	}
+	for _, m := range missing {
+		log.Println(m)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+//go:build darwin
+
+package platform
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/prashantgupta24/mac-sleep-notifier/notifier"
+)
+
+// mediaControlSupported is whether media-control can exist on this system.
+const mediaControlSupported = true
+
+// OpenURL returns the command that opens url in the default browser.
+func OpenURL(url string) (name string, args []string) {
+	return "open", []string{url}
+}
+
+// OpenApp returns the command that launches or focuses the named
+// application, e.g. "Spotify".
+func OpenApp(app string) (name string, args []string) {
+	return "open", []string{"-a", app}
+}
+
+// Copy returns the command that copies its standard input to the clipboard.
+func Copy() (name string, args []string) {
+	return "pbcopy", nil
+}
+
+// Prompt returns the command that asks for a line of text in a dialog
+// showing message, printing what was entered. It fails if the dialog is
+// cancelled.
+func Prompt(message string) (name string, args []string) {
+	script := fmt.Sprintf("text returned of (display dialog %s default answer \"\" with title \"belowdeck\")", appleScriptString(message))
+	return "osascript", []string{"-e", script}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// missingDependencies describes the external tools modules need that
+// can't be found, with how to install them.
+func missingDependencies() []string {
+	var missing []string
+	if !MediaControlAvailable() {
+		missing = append(missing, "media-control not found, skipping the now playing module. Install with: brew tap ungive/media-control && brew install media-control")
+	}
+	return missing
+}
+
+// PowerEvents returns a channel that receives an event each time the system
+// goes to sleep or wakes up. Only the latest event is kept: one that hasn't
+// been received yet is replaced by the next.
+func PowerEvents() <-chan PowerEvent {
+	events := make(chan PowerEvent, 1)
+	sleepCh := notifier.GetInstance().Start()
+	go func() {
+		for activity := range sleepCh {
+			var ev PowerEvent
+			switch activity.Type {
+			case notifier.Sleep:
+				log.Println("System sleep detected")
+				ev = Sleep
+			case notifier.Awake:
+				log.Println("System wake detected")
+				ev = Wake
+			default:
+				continue
+			}
+			sendLatest(events, ev)
+		}
+	}()
+	return events
+}
@@ -0,0 +1,50 @@
+//go:build !darwin
+
+package platform
+
+import "os"
+
+// mediaControlSupported is whether media-control can exist on this system.
+const mediaControlSupported = false
+
+// OpenURL returns the command that opens url in the default browser.
+func OpenURL(url string) (name string, args []string) {
+	return "xdg-open", []string{url}
+}
+
+// OpenApp returns the command that launches the named application. There's
+// no portable way to focus a running app, so the name is run as a command,
+// e.g. "spotify".
+func OpenApp(app string) (name string, args []string) {
+	return app, nil
+}
+
+// Copy returns the command that copies its standard input to the clipboard:
+// wl-copy under Wayland, xclip otherwise.
+func Copy() (name string, args []string) {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		return "wl-copy", nil
+	}
+	return "xclip", []string{"-selection", "clipboard"}
+}
+
+// Prompt returns the command that asks for a line of text in a dialog
+// showing message, printing what was entered. It uses zenity, and fails if
+// the dialog is cancelled.
+func Prompt(message string) (name string, args []string) {
+	return "zenity", []string{"--entry", "--title=belowdeck", "--text=" + message}
+}
+
+// missingDependencies describes the external tools modules need that
+// can't be found. Nothing is checked here; modules without their tools
+// report themselves unconfigured.
+func missingDependencies() []string {
+	return nil
+}
+
+// PowerEvents returns a channel that receives an event each time the system
+// goes to sleep or wakes up. Detection is macOS-only, so it never fires
+// here.
+func PowerEvents() <-chan PowerEvent {
+	return make(chan PowerEvent)
+}
@@ -0,0 +1,33 @@
+//go:build !darwin
+
+package platform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOtherPlatformCommands(t *testing.T) {
+	if name, args := OpenURL("https://example.com"); name != "xdg-open" || !slices.Equal(args, []string{"https://example.com"}) {
+		t.Errorf("OpenURL = %s %q, want xdg-open with the URL", name, args)
+	}
+
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	if name, _ := Copy(); name != "wl-copy" {
+		t.Errorf("Copy under Wayland = %s, want wl-copy", name)
+	}
+	t.Setenv("WAYLAND_DISPLAY", "")
+	if name, args := Copy(); name != "xclip" || !slices.Equal(args, []string{"-selection", "clipboard"}) {
+		t.Errorf("Copy under X = %s %q, want xclip to the clipboard", name, args)
+	}
+}
+
+func TestOtherPlatformHasNoMediaControl(t *testing.T) {
+	if MediaControlAvailable() {
+		t.Error("media-control reported available off macOS")
+	}
+	t.Setenv("BELOWDECK_REQUIRE_DEPS", "true")
+	if err := CheckDependencies(); err != nil {
+		t.Errorf("CheckDependencies = %v, want nothing required off macOS", err)
+	}
+}
@@ -0,0 +1,18 @@
+package platform
+
+import "testing"
+
+func TestSendLatestReplacesStaleEvent(t *testing.T) {
+	events := make(chan PowerEvent, 1)
+	sendLatest(events, Sleep)
+	sendLatest(events, Wake)
+
+	if got := <-events; got != Wake {
+		t.Errorf("received %q, want the latest event %q", got, Wake)
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("received stale %q after the latest event", ev)
+	default:
+	}
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Blur returns a blurred copy of src, with its bounds at the origin. Each
+// pass is a box blur of the given radius in pixels, horizontally then
+// vertically; three passes come close to a Gaussian blur. Edges are
+// extended, so the borders don't darken. A radius or pass count of zero
+// returns an unblurred copy.
+func Blur(src image.Image, radius, passes int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+
+	if radius <= 0 || b.Empty() {
+		return dst
+	}
+
+	w, h := b.Dx(), b.Dy()
+	tmp := make([]uint8, len(dst.Pix))
+	for range passes {
+		boxBlur(dst.Pix, tmp, w, h, 4, dst.Stride, radius)
+		boxBlur(tmp, dst.Pix, h, w, dst.Stride, 4, radius)
+	}
+	return dst
+}
+
+// boxBlur averages each pixel of src with radius pixels either side of it
+// along one axis, writing to dst. The blurred axis has n pixels, step bytes
+// apart, and there are lines of them, lineStep bytes apart. Running sums
+// make the cost independent of the radius.
+func boxBlur(src, dst []uint8, n, lines, step, lineStep, radius int) {
+	size := 2*radius + 1
+	for line := range lines {
+		base := line * lineStep
+		at := func(i int) int {
+			return base + min(max(i, 0), n-1)*step
+		}
+
+		// Start with the window around the first pixel
+		var sum [4]int
+		for i := -radius; i <= radius; i++ {
+			p := at(i)
+			for c := range 4 {
+				sum[c] += int(src[p+c])
+			}
+		}
+
+		for i := range n {
+			p := base + i*step
+			for c := range 4 {
+				dst[p+c] = uint8((sum[c] + size/2) / size)
+			}
+
+			// Slide the window along by one pixel
+			in, out := at(i+radius+1), at(i-radius)
+			for c := range 4 {
+				sum[c] += int(src[in+c]) - int(src[out+c])
+			}
+		}
+	}
+}
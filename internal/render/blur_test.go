@@ -0,0 +1,84 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// checkerboard returns a w×h image of alternating black and white pixels,
+// with its bounds offset from the origin.
+func checkerboard(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(10, 20, 10+w, 20+h))
+	for y := range h {
+		for x := range w {
+			if (x+y)%2 == 0 {
+				img.Set(10+x, 20+y, color.White)
+			} else {
+				img.Set(10+x, 20+y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+// maxNeighbourDiff returns the largest difference in red between
+// horizontally or vertically adjacent pixels of img.
+func maxNeighbourDiff(img *image.RGBA) int {
+	b := img.Bounds()
+	red := func(x, y int) int { return int(img.RGBAAt(x, y).R) }
+	diff := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if x+1 < b.Max.X {
+				diff = max(diff, abs(red(x, y)-red(x+1, y)))
+			}
+			if y+1 < b.Max.Y {
+				diff = max(diff, abs(red(x, y)-red(x, y+1)))
+			}
+		}
+	}
+	return diff
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestBlurSmoothsCheckerboard(t *testing.T) {
+	src := checkerboard(16, 12)
+	if got := maxNeighbourDiff(src); got != 255 {
+		t.Fatalf("checkerboard neighbour diff = %d, want 255", got)
+	}
+
+	blurred := Blur(src, 1, 3)
+	if got, want := blurred.Bounds(), image.Rect(0, 0, 16, 12); got != want {
+		t.Errorf("blurred bounds = %v, want %v", got, want)
+	}
+	if got := maxNeighbourDiff(blurred); got > 16 {
+		t.Errorf("blurred neighbour diff = %d, want at most 16", got)
+	}
+
+	// Extended edges keep the average brightness, even in the corners
+	if r := blurred.RGBAAt(0, 0).R; r < 100 || r > 155 {
+		t.Errorf("corner red = %d, want about half", r)
+	}
+	if a := blurred.RGBAAt(0, 0).A; a != 255 {
+		t.Errorf("corner alpha = %d, want opaque", a)
+	}
+
+	if src.RGBAAt(10, 20) != (color.RGBA{255, 255, 255, 255}) {
+		t.Error("Blur changed its source")
+	}
+}
+
+func TestBlurZeroRadiusCopies(t *testing.T) {
+	src := checkerboard(4, 4)
+	got := Blur(src, 0, 3)
+	if got == src || maxNeighbourDiff(got) != 255 {
+		t.Error("zero radius blur didn't return an unblurred copy")
+	}
+}
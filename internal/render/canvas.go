@@ -0,0 +1,201 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// maxKeyScale bounds BELOWDECK_KEY_SCALE; beyond 4x the extra detail is lost
+// in the downscale and only costs render time.
+const maxKeyScale = 4
+
+var keyScale = sync.OnceValue(func() int {
+	v := os.Getenv("BELOWDECK_KEY_SCALE")
+	if v == "" {
+		return 1
+	}
+	scale, err := strconv.Atoi(v)
+	if err != nil || scale < 1 || scale > maxKeyScale {
+		log.Printf("Invalid BELOWDECK_KEY_SCALE %q (want 1 to %d), using 1", v, maxKeyScale)
+		return 1
+	}
+	return scale
+})
+
+// KeyScale returns how many times the device's resolution key images are
+// drawn at before being downscaled, from BELOWDECK_KEY_SCALE. The default of
+// 1 draws at device resolution; 2 gives smoother text and edges for four
+// times the drawing work.
+func KeyScale() int {
+	return keyScale()
+}
+
+// Canvas is an image drawn in logical pixels but backed by one Scale times
+// larger, so text and shapes are rasterized at the higher resolution.
+// Image downscales it back to its logical size.
+type Canvas struct {
+	// Img is the backing image, Scale times the logical size. Drawing on it
+	// directly uses backing pixels; Px converts.
+	Img   *image.RGBA
+	Scale int
+
+	size image.Point
+}
+
+// NewCanvas returns a transparent canvas of the given logical size drawn at
+// scale times that resolution.
+func NewCanvas(width, height, scale int) *Canvas {
+	if scale < 1 {
+		scale = 1
+	}
+	return &Canvas{
+		Img:   image.NewRGBA(image.Rect(0, 0, width*scale, height*scale)),
+		Scale: scale,
+		size:  image.Pt(width, height),
+	}
+}
+
+// NewKeyCanvas returns a canvas for a size×size key image at KeyScale.
+func NewKeyCanvas(size int) *Canvas {
+	return NewCanvas(size, size, KeyScale())
+}
+
+// CanvasOf returns a canvas that draws directly on img, for using
+// Canvas-based helpers on an image that isn't supersampled.
+func CanvasOf(img *image.RGBA) *Canvas {
+	return &Canvas{Img: img, Scale: 1, size: img.Bounds().Size()}
+}
+
+// Bounds returns the canvas's logical bounds.
+func (c *Canvas) Bounds() image.Rectangle {
+	return image.Rectangle{Max: c.size}
+}
+
+// Px converts a logical length to backing pixels.
+func (c *Canvas) Px(v int) int {
+	return v * c.Scale
+}
+
+// rect converts a logical rectangle to backing pixels.
+func (c *Canvas) rect(r image.Rectangle) image.Rectangle {
+	return image.Rect(c.Px(r.Min.X), c.Px(r.Min.Y), c.Px(r.Max.X), c.Px(r.Max.Y))
+}
+
+// Fill replaces r with col.
+func (c *Canvas) Fill(r image.Rectangle, col color.Color) {
+	draw.Draw(c.Img, c.rect(r), image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+// FillRoundedRect fills r with col, its corners rounded to radius.
+func (c *Canvas) FillRoundedRect(r image.Rectangle, radius int, col color.Color) {
+	FillRoundedRect(c.Img, c.rect(r), c.Px(radius), col)
+}
+
+// DrawImage draws src over r, scaling it to fit. Sources already at the
+// backing size, such as icons rendered at Px of their size, are copied
+// without resampling.
+func (c *Canvas) DrawImage(r image.Rectangle, src image.Image) {
+	dst := c.rect(r)
+	if src.Bounds().Size() == dst.Size() {
+		draw.Draw(c.Img, dst, src, src.Bounds().Min, draw.Over)
+		return
+	}
+	draw.CatmullRom.Scale(c.Img, dst, src, src.Bounds(), draw.Over, nil)
+}
+
+// MeasureString returns the logical width of text drawn with face.
+func (c *Canvas) MeasureString(face font.Face, text string) int {
+	scaled, ok := scaledFace(face, c.Scale)
+	if !ok {
+		return font.MeasureString(face, text).Ceil()
+	}
+	return (font.MeasureString(scaled, text).Ceil() + c.Scale - 1) / c.Scale
+}
+
+// DrawString draws text with its baseline starting at logical (x, y). face
+// is drawn at the canvas's scale; faces that can't be scaled, such as
+// FallbackFace, are drawn at their own size and enlarged.
+func (c *Canvas) DrawString(text string, x, y int, face font.Face, col color.Color) {
+	if scaled, ok := scaledFace(face, c.Scale); ok {
+		drawString(c.Img, text, c.Px(x), c.Px(y), scaled, col)
+		return
+	}
+
+	// Draw at logical size, then scale up the pixels
+	m := face.Metrics()
+	width := font.MeasureString(face, text).Ceil()
+	ascent, descent := m.Ascent.Ceil(), m.Descent.Ceil()
+	small := image.NewRGBA(image.Rect(0, 0, width, ascent+descent))
+	drawString(small, text, 0, ascent, face, col)
+	c.DrawImage(image.Rect(x, y-ascent, x+width, y+descent), small)
+}
+
+// DrawStringCentered draws text horizontally centered on logical centerX
+// with its baseline at y.
+func (c *Canvas) DrawStringCentered(text string, centerX, y int, face font.Face, col color.Color) {
+	c.DrawString(text, centerX-c.MeasureString(face, text)/2, y, face, col)
+}
+
+// Image returns the canvas at its logical size, downscaled with CatmullRom
+// when it's supersampled.
+func (c *Canvas) Image() *image.RGBA {
+	if c.Scale == 1 {
+		return c.Img
+	}
+	out := image.NewRGBA(c.Bounds())
+	draw.CatmullRom.Scale(out, out.Bounds(), c.Img, c.Img.Bounds(), draw.Src, nil)
+	return out
+}
+
+// scalableFace is a face from NewFace that remembers its font and size, so
+// it can be recreated at a multiple of its size for a supersampled Canvas.
+type scalableFace struct {
+	font.Face
+
+	font *opentype.Font
+	size float64
+
+	mu     sync.Mutex
+	scaled map[int]font.Face
+}
+
+// ScaleFace returns face at scale times its size, or face itself if scale
+// is 1 or face didn't come from NewFace.
+func ScaleFace(face font.Face, scale int) font.Face {
+	if scaled, ok := scaledFace(face, scale); ok {
+		return scaled
+	}
+	return face
+}
+
+// scaledFace returns face at scale times its size, reporting false if face
+// can't be scaled. Scaled faces are cached on face.
+func scaledFace(face font.Face, scale int) (font.Face, bool) {
+	if scale == 1 {
+		return face, true
+	}
+	sf, ok := face.(*scalableFace)
+	if !ok {
+		return nil, false
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if f, ok := sf.scaled[scale]; ok {
+		return f, true
+	}
+	if sf.scaled == nil {
+		sf.scaled = make(map[int]font.Face)
+	}
+	f := buildFace(sf.font, sf.size*float64(scale))
+	sf.scaled[scale] = f
+	return f, true
+}
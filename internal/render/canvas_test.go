@@ -0,0 +1,56 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestCanvasDrawsAtScaleAndDownscales(t *testing.T) {
+	c := NewCanvas(72, 72, 2)
+	if got := c.Img.Bounds().Size(); got != image.Pt(144, 144) {
+		t.Fatalf("backing size = %v, want 144x144", got)
+	}
+
+	red := color.RGBA{255, 0, 0, 255}
+	c.Fill(image.Rect(0, 0, 36, 72), red)
+	if got := c.Img.RGBAAt(71, 100); got != red {
+		t.Errorf("backing pixel inside the fill = %v, want %v", got, red)
+	}
+
+	out := c.Image()
+	if got := out.Bounds().Size(); got != image.Pt(72, 72) {
+		t.Fatalf("image size = %v, want 72x72", got)
+	}
+	if got := out.RGBAAt(10, 10); got != red {
+		t.Errorf("downscaled pixel inside the fill = %v, want %v", got, red)
+	}
+	if got := out.RGBAAt(60, 10); got.A != 0 {
+		t.Errorf("downscaled pixel outside the fill = %v, want transparent", got)
+	}
+}
+
+func TestMeasureStringSameAtAnyScale(t *testing.T) {
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	face := NewFace(f, 14)
+
+	want := NewCanvas(72, 72, 1).MeasureString(face, "Office")
+	for _, scale := range []int{2, 3} {
+		got := NewCanvas(72, 72, scale).MeasureString(face, "Office")
+		if got < want-1 || got > want+1 {
+			t.Errorf("logical width at %dx = %d, want about %d", scale, got, want)
+		}
+	}
+	if ScaleFace(face, 2) == face {
+		t.Error("ScaleFace returned the face unscaled")
+	}
+	if ScaleFace(FallbackFace, 2) != FallbackFace {
+		t.Error("ScaleFace scaled FallbackFace")
+	}
+}
@@ -0,0 +1,127 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseHexColor parses a CSS-style hex color: "#rgb", "#rrggbb" or
+// "#rrggbbaa", with or without the leading "#".
+func ParseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+const (
+	// accentHueBuckets is how finely hues are grouped when looking for an
+	// image's accent color.
+	accentHueBuckets = 12
+
+	// accentMinSaturation and accentMinValue exclude grays and near-blacks,
+	// which make poor accents.
+	accentMinSaturation = 0.3
+	accentMinValue      = 0.3
+
+	// accentSamples is roughly how many pixels are sampled per axis.
+	accentSamples = 64
+)
+
+// AccentColor picks a vivid color representative of img: the average of the
+// most prominent hue among its saturated pixels, weighted by how vivid each
+// pixel is. It reports false for images that are essentially grayscale.
+func AccentColor(img image.Image) (color.RGBA, bool) {
+	type bucket struct {
+		weight  float64
+		r, g, b float64
+	}
+	var buckets [accentHueBuckets]bucket
+
+	b := img.Bounds()
+	stepX := max(b.Dx()/accentSamples, 1)
+	stepY := max(b.Dy()/accentSamples, 1)
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+			h, s, v := hsv(c)
+			if s < accentMinSaturation || v < accentMinValue {
+				continue
+			}
+
+			w := s * v
+			i := int(h*accentHueBuckets) % accentHueBuckets
+			buckets[i].weight += w
+			buckets[i].r += w * float64(c.R)
+			buckets[i].g += w * float64(c.G)
+			buckets[i].b += w * float64(c.B)
+		}
+	}
+
+	best := -1
+	for i := range buckets {
+		if buckets[i].weight > 0 && (best < 0 || buckets[i].weight > buckets[best].weight) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return color.RGBA{}, false
+	}
+
+	bk := buckets[best]
+	return color.RGBA{
+		R: uint8(math.Round(bk.r / bk.weight)),
+		G: uint8(math.Round(bk.g / bk.weight)),
+		B: uint8(math.Round(bk.b / bk.weight)),
+		A: 255,
+	}, true
+}
+
+// hsv converts c to hue, saturation and value, each in [0, 1).
+func hsv(c color.NRGBA) (h, s, v float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi := math.Max(r, math.Max(g, b))
+	lo := math.Min(r, math.Min(g, b))
+	v = hi
+	if hi == 0 {
+		return 0, 0, 0
+	}
+	d := hi - lo
+	s = d / hi
+	if d == 0 {
+		return 0, s, v
+	}
+
+	switch hi {
+	case r:
+		h = (g - b) / d
+	case g:
+		h = 2 + (b-r)/d
+	default:
+		h = 4 + (r-g)/d
+	}
+	h /= 6
+	if h < 0 {
+		h++
+	}
+	return h, s, v
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want color.RGBA
+	}{
+		{"#32cd32", color.RGBA{0x32, 0xcd, 0x32, 0xff}},
+		{"FFA500", color.RGBA{0xff, 0xa5, 0x00, 0xff}},
+		{"#abc", color.RGBA{0xaa, 0xbb, 0xcc, 0xff}},
+		{" #11223380 ", color.RGBA{0x11, 0x22, 0x33, 0x80}},
+	} {
+		if got, err := ParseHexColor(tt.s); err != nil || got != tt.want {
+			t.Errorf("ParseHexColor(%q) = %v, %v; want %v", tt.s, got, err, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "#12", "#12345", "#ggg", "red"} {
+		if _, err := ParseHexColor(s); err == nil {
+			t.Errorf("ParseHexColor(%q) accepted", s)
+		}
+	}
+}
+
+func TestAccentColor(t *testing.T) {
+	// Mostly blue art with a red corner and a gray band picks the blue
+	blue := color.RGBA{30, 60, 220, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(img, img.Bounds(), image.NewUniform(blue), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, 30, 30), image.NewUniform(color.RGBA{220, 20, 20, 255}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 60, 100, 80), image.NewUniform(color.RGBA{128, 128, 128, 255}), image.Point{}, draw.Src)
+
+	if got, ok := AccentColor(img); !ok || got != blue {
+		t.Errorf("AccentColor = %v, %v; want %v", got, ok, blue)
+	}
+
+	gray := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(gray, gray.Bounds(), image.NewUniform(color.RGBA{90, 90, 100, 255}), image.Point{}, draw.Src)
+	if got, ok := AccentColor(gray); ok {
+		t.Errorf("AccentColor of grayscale art = %v, want none", got)
+	}
+}
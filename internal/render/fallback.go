@@ -0,0 +1,83 @@
+package render
+
+import (
+	"image"
+	"os"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// EmojiFont returns the font at BELOWDECK_FONT_EMOJI, used for emoji and
+// other runes the regular fonts don't have, or nil if it's unset or can't
+// be loaded. Only outline glyphs are drawn, in the text's color, so use an
+// outline emoji font such as Noto Emoji rather than a bitmap color font.
+func EmojiFont() *opentype.Font {
+	path := os.Getenv("BELOWDECK_FONT_EMOJI")
+	if path == "" {
+		return nil
+	}
+	return loadFontFile(path)
+}
+
+// WithFallback returns a face that draws each rune with the first of
+// primary and fallbacks that has a glyph for it, so runes missing from
+// primary don't render as empty boxes. Metrics come from primary. Runes no
+// face has are drawn by primary.
+func WithFallback(primary font.Face, fallbacks ...font.Face) font.Face {
+	if len(fallbacks) == 0 {
+		return primary
+	}
+	return &fallbackFace{faces: append([]font.Face{primary}, fallbacks...)}
+}
+
+// fallbackFace is a font.Face made of several faces, tried in order.
+type fallbackFace struct {
+	faces []font.Face
+}
+
+// faceFor returns the face that draws r.
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	for _, face := range f.faces {
+		if _, ok := face.GlyphAdvance(r); ok {
+			return face
+		}
+	}
+	return f.faces[0]
+}
+
+func (f *fallbackFace) Close() error {
+	var err error
+	for _, face := range f.faces {
+		if cerr := face.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+// Kern only applies between runes drawn by the same face.
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := f.faceFor(r0)
+	if face != f.faceFor(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
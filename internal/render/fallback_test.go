@@ -0,0 +1,52 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestWithFallbackDrawsMissingRunes(t *testing.T) {
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback, err := newFace(f, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := basicfont.Face7x13
+	face := WithFallback(primary, fallback)
+
+	// The bitmap face has ASCII, so that's drawn from it
+	if got, _ := face.GlyphAdvance('A'); got != fixed.I(primary.Advance) {
+		t.Errorf("advance of A = %v, want the primary face's %v", got, primary.Advance)
+	}
+
+	// It has no arrows, so those come from the fallback
+	if _, ok := primary.GlyphAdvance('→'); ok {
+		t.Fatal("bitmap face has an arrow; pick another rune")
+	}
+	want, _ := fallback.GlyphAdvance('→')
+	if got, ok := face.GlyphAdvance('→'); !ok || got != want {
+		t.Errorf("advance of → = %v, %v; want the fallback's %v", got, ok, want)
+	}
+}
+
+func TestNewFaceUsesEmojiFont(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "emoji.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BELOWDECK_FONT_EMOJI", path)
+
+	face := NewFace(nil, 20)
+	if _, ok := face.GlyphAdvance('→'); !ok {
+		t.Error("face with an emoji font configured can't draw a rune only that font has")
+	}
+}
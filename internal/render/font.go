@@ -0,0 +1,129 @@
+package render
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+)
+
+var (
+	fontCacheMu sync.Mutex
+	fontCache   = map[string]*opentype.Font{}
+)
+
+// BoldFont returns the font used for titles, labels and numbers: the TTF/OTF
+// at BELOWDECK_FONT_BOLD if set and loadable, otherwise embedded.
+func BoldFont(embedded []byte) (*opentype.Font, error) {
+	return LoadFont(os.Getenv("BELOWDECK_FONT_BOLD"), embedded)
+}
+
+// RegularFont returns the font used for secondary text: the TTF/OTF at
+// BELOWDECK_FONT_REGULAR if set and loadable, otherwise embedded.
+func RegularFont(embedded []byte) (*opentype.Font, error) {
+	return LoadFont(os.Getenv("BELOWDECK_FONT_REGULAR"), embedded)
+}
+
+// LoadFont parses the font file at path, falling back to parsing embedded
+// when path is empty or the file can't be read or parsed. Fonts loaded from
+// disk are cached, so a bad path is only reported once.
+func LoadFont(path string, embedded []byte) (*opentype.Font, error) {
+	if path != "" {
+		if f := loadFontFile(path); f != nil {
+			return f, nil
+		}
+	}
+	return opentype.Parse(embedded)
+}
+
+// loadFontFile returns the cached font for path, loading it on first use. It
+// returns nil if the font can't be loaded.
+func loadFontFile(path string) *opentype.Font {
+	fontCacheMu.Lock()
+	defer fontCacheMu.Unlock()
+
+	if f, ok := fontCache[path]; ok {
+		return f
+	}
+
+	f, err := parseFontFile(path)
+	if err != nil {
+		log.Printf("Font: %v, using built-in font", err)
+	}
+	fontCache[path] = f
+	return f
+}
+
+// parseFontFile reads and parses a TTF/OTF file.
+func parseFontFile(path string) (*opentype.Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// FallbackFace is the built-in bitmap face text falls back to when a font
+// can't be loaded. It's always readable, if not pretty.
+var FallbackFace font.Face = basicfont.Face7x13
+
+// NewFace returns a face for f at size points, or FallbackFace if f is nil
+// or the face can't be created. Runes f lacks, such as emoji, are drawn
+// from EmojiFont when one is configured. Faces for f can be scaled up for
+// supersampled drawing with ScaleFace.
+func NewFace(f *opentype.Font, size float64) font.Face {
+	face := buildFace(f, size)
+	if f == nil || face == FallbackFace {
+		return face
+	}
+	return &scalableFace{Face: face, font: f, size: size}
+}
+
+// buildFace creates the face NewFace returns, before it's made scalable.
+func buildFace(f *opentype.Font, size float64) font.Face {
+	face := FallbackFace
+	if f != nil {
+		var err error
+		face, err = newFace(f, size)
+		if err != nil {
+			log.Printf("Font: create %vpt face: %v, using fallback font", size, err)
+			face = FallbackFace
+		}
+	}
+
+	if emoji := EmojiFont(); emoji != nil {
+		if emojiFace, err := newFace(emoji, size); err == nil {
+			return WithFallback(face, emojiFace)
+		}
+	}
+	return face
+}
+
+// newFace creates a face for f at size points.
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// ParseFont returns the font from load, logging and returning nil if it
+// fails so that NewFace falls back to FallbackFace. name describes the font
+// in the log, e.g. "weather bold".
+func ParseFont(name string, load func(embedded []byte) (*opentype.Font, error), embedded []byte) *opentype.Font {
+	f, err := load(embedded)
+	if err != nil {
+		log.Printf("Font: parse %s font: %v, using fallback font", name, err)
+		return nil
+	}
+	return f
+}
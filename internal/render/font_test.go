@@ -0,0 +1,84 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+)
+
+func TestUnparsableFontFallsBack(t *testing.T) {
+	t.Setenv("BELOWDECK_FONT_BOLD", "")
+	f := ParseFont("test bold", BoldFont, []byte("not a font"))
+	if f != nil {
+		t.Fatal("ParseFont returned a font for junk data")
+	}
+	if face := NewFace(f, 12); face != FallbackFace {
+		t.Errorf("NewFace(nil) = %T, want FallbackFace", face)
+	}
+}
+
+func TestLoadFontFallsBackToEmbedded(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.ttf")
+	f, err := LoadFont(missing, goregular.TTF)
+	if err != nil || f == nil {
+		t.Fatalf("LoadFont with a missing file = %v, %v; want the embedded font", f, err)
+	}
+	if face := NewFace(f, 12); face == FallbackFace {
+		t.Error("NewFace for a parsed font returned FallbackFace")
+	}
+}
+
+// familyName returns f's font family.
+func familyName(t *testing.T, f *opentype.Font) string {
+	t.Helper()
+	name, err := f.Name(nil, sfnt.NameIDFamily)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestConfiguredFontUsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mono.ttf")
+	if err := os.WriteFile(path, gomono.TTF, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BELOWDECK_FONT_BOLD", path)
+
+	f := ParseFont("test bold", BoldFont, goregular.TTF)
+	if got := familyName(t, f); got != "Go Mono" {
+		t.Fatalf("font family = %q, want the configured Go Mono", got)
+	}
+
+	// Text is set in the configured font: monospaced, unlike the embedded one
+	face := NewFace(f, 12)
+	if narrow, wide := font.MeasureString(face, "iii"), font.MeasureString(face, "MMM"); narrow != wide {
+		t.Errorf("\"iii\" is %v wide and \"MMM\" %v, want the monospaced font's equal widths", narrow, wide)
+	}
+
+	if again := ParseFont("test bold", BoldFont, goregular.TTF); again != f {
+		t.Error("configured font loaded again, want it cached")
+	}
+}
+
+func TestBadFontFileFallsBackToEmbedded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.ttf")
+	if err := os.WriteFile(path, []byte("not a font"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BELOWDECK_FONT_REGULAR", path)
+
+	f := ParseFont("test regular", RegularFont, goregular.TTF)
+	if f == nil {
+		t.Fatal("bad font file left no font, want the embedded one")
+	}
+	if got := familyName(t, f); got != "Go" {
+		t.Errorf("font family = %q, want the embedded Go", got)
+	}
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// RoundCorners returns a copy of src with its corners masked to the given
+// radius in pixels. Pixels outside the rounded rectangle become transparent
+// and the curve is anti-aliased. A radius of zero returns a square copy.
+func RoundCorners(src image.Image, radius int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, src, b.Min, draw.Src)
+
+	if radius <= 0 {
+		return dst
+	}
+
+	r := float64(radius)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cov := roundedRectCoverage(b, r, x, y)
+			if cov >= 1 {
+				continue
+			}
+			// RGBA is premultiplied, so every channel scales with coverage
+			i := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8(float64(dst.Pix[i+c]) * cov)
+			}
+		}
+	}
+
+	return dst
+}
+
+// StrokeRoundedRect draws a border of the given width just inside rect,
+// following the same rounded corners as RoundCorners with radius.
+func StrokeRoundedRect(img *image.RGBA, rect image.Rectangle, radius, width int, col color.Color) {
+	if width <= 0 {
+		return
+	}
+
+	inner := rect.Inset(width)
+	outerR := float64(radius)
+	innerR := math.Max(outerR-float64(width), 0)
+	src := image.NewUniform(col)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			cov := roundedRectCoverage(rect, outerR, x, y) - roundedRectCoverage(inner, innerR, x, y)
+			if cov <= 0 {
+				continue
+			}
+			mask := image.NewUniform(color.Alpha{uint8(cov * 255)})
+			draw.DrawMask(img, image.Rect(x, y, x+1, y+1), src, image.Point{}, mask, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// FillRoundedRect fills rect with col, its corners rounded to radius and
+// anti-aliased.
+func FillRoundedRect(img *image.RGBA, rect image.Rectangle, radius int, col color.Color) {
+	r := float64(radius)
+	src := image.NewUniform(col)
+	area := rect.Intersect(img.Bounds())
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			cov := roundedRectCoverage(rect, r, x, y)
+			if cov <= 0 {
+				continue
+			}
+			mask := image.NewUniform(color.Alpha{uint8(cov * 255)})
+			draw.DrawMask(img, image.Rect(x, y, x+1, y+1), src, image.Point{}, mask, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// roundedRectCoverage returns how much of pixel (x, y) lies inside rect with
+// corners rounded to radius, from 0 (outside) to 1 (fully inside).
+func roundedRectCoverage(rect image.Rectangle, radius float64, x, y int) float64 {
+	if !image.Pt(x, y).In(rect) {
+		return 0
+	}
+	if radius <= 0 {
+		return 1
+	}
+
+	// Distance from the pixel center to the nearest point of the rectangle
+	// shrunk by radius; zero everywhere except the corner regions
+	px, py := float64(x)+0.5, float64(y)+0.5
+	cx := math.Max(float64(rect.Min.X)+radius, math.Min(px, float64(rect.Max.X)-radius))
+	cy := math.Max(float64(rect.Min.Y)+radius, math.Min(py, float64(rect.Max.Y)-radius))
+	d := math.Hypot(px-cx, py-cy)
+	if d == 0 {
+		return 1
+	}
+
+	return math.Max(0, math.Min(1, radius-d+0.5))
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// solid returns a size x size opaque image of col.
+func solid(size int, col color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{col}, image.Point{}, draw.Src)
+	return img
+}
+
+func TestRoundCornersTransparentAtRadius(t *testing.T) {
+	red := color.RGBA{200, 0, 0, 255}
+	const size, radius = 40, 8
+	img := RoundCorners(solid(size, red), radius)
+
+	// Each corner pixel, and one in from it along the diagonal, lies
+	// outside the curve
+	for _, p := range []image.Point{
+		{0, 0}, {1, 1}, {size - 1, 0}, {size - 2, 1},
+		{0, size - 1}, {1, size - 2}, {size - 1, size - 1}, {size - 2, size - 2},
+	} {
+		if a := img.RGBAAt(p.X, p.Y).A; a != 0 {
+			t.Errorf("pixel %v alpha = %d, want transparent", p, a)
+		}
+	}
+
+	// Edges between the corners and the middle stay opaque
+	for _, p := range []image.Point{{radius, 0}, {0, radius}, {size / 2, size - 1}, {size / 2, size / 2}} {
+		if got := img.RGBAAt(p.X, p.Y); got != red {
+			t.Errorf("pixel %v = %v, want %v", p, got, red)
+		}
+	}
+
+	// The curve is anti-aliased, so partly covered pixels are premultiplied
+	edge := img.RGBAAt(1, 3)
+	if edge.A == 0 || edge.A == 255 || edge.R > edge.A {
+		t.Errorf("pixel on the curve = %v, want partly transparent and premultiplied", edge)
+	}
+}
+
+func TestRoundCornersZeroRadiusIsSquare(t *testing.T) {
+	red := color.RGBA{200, 0, 0, 255}
+	img := RoundCorners(solid(20, red), 0)
+	if got := img.RGBAAt(0, 0); got != red {
+		t.Errorf("corner = %v, want %v", got, red)
+	}
+}
+
+func TestStrokeRoundedRectFollowsCorners(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	StrokeRoundedRect(img, img.Bounds(), 8, 2, white)
+
+	if got := img.RGBAAt(20, 0); got != white {
+		t.Errorf("top edge = %v, want the border", got)
+	}
+	if a := img.RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("corner alpha = %d, want none outside the curve", a)
+	}
+	if a := img.RGBAAt(20, 20).A; a != 0 {
+		t.Errorf("middle alpha = %d, want none inside the border", a)
+	}
+}
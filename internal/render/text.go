@@ -0,0 +1,69 @@
+// Package render provides drawing helpers shared across modules.
+package render
+
+import (
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// WrapText splits text into lines that each fit within maxWidth pixels when
+// drawn with face. Words are never reordered; a single word wider than
+// maxWidth is broken at the last rune that fits.
+func WrapText(face font.Face, text string, maxWidth int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	var current string
+
+	for _, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			current = candidate
+			continue
+		}
+
+		if current != "" {
+			lines = append(lines, current)
+			current = ""
+		}
+
+		// Break words that don't fit on a line of their own
+		for font.MeasureString(face, word).Ceil() > maxWidth {
+			head, tail := splitToWidth(face, word, maxWidth)
+			lines = append(lines, head)
+			word = tail
+		}
+		current = word
+	}
+
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// LineHeight returns the distance in pixels between consecutive baselines
+// for face.
+func LineHeight(face font.Face) int {
+	return face.Metrics().Height.Ceil()
+}
+
+// splitToWidth splits word into the longest prefix that fits within maxWidth
+// and the remainder. At least one rune is always taken so callers make
+// progress even when maxWidth is smaller than a single glyph.
+func splitToWidth(face font.Face, word string, maxWidth int) (string, string) {
+	runes := []rune(word)
+	n := 1
+	for n < len(runes) && font.MeasureString(face, string(runes[:n+1])).Ceil() <= maxWidth {
+		n++
+	}
+	return string(runes[:n]), string(runes[n:])
+}
@@ -0,0 +1,98 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// StatRowHeight is the vertical space taken by one stat row, so rows can
+	// be stacked at fixed offsets.
+	StatRowHeight = 14
+
+	statDotSize   = 6
+	statLabelGap  = 10
+	statBaseline  = 8
+	badgePaddingX = 6
+	badgePaddingY = 3
+
+	loadingDotSize = 6
+	loadingDotGap  = 10
+)
+
+// StatRowStyle holds the faces and colors shared by a set of stat rows.
+type StatRowStyle struct {
+	LabelFace  font.Face
+	CountFace  font.Face
+	LabelColor color.Color
+	CountColor color.Color
+}
+
+// DrawStatRow draws a stat row across area: a square dot in dotColor at the
+// left edge, label next to it, and count right-aligned at the right edge.
+// Only area's horizontal extent and top edge are used; rows are
+// StatRowHeight tall.
+func DrawStatRow(c *Canvas, area image.Rectangle, label string, count int, dotColor color.Color, style StatRowStyle) {
+	dot := image.Rect(area.Min.X, area.Min.Y+2, area.Min.X+statDotSize, area.Min.Y+2+statDotSize)
+	c.Fill(dot, dotColor)
+
+	y := area.Min.Y + statBaseline
+	c.DrawString(label, area.Min.X+statLabelGap, y, style.LabelFace, style.LabelColor)
+
+	countStr := fmt.Sprintf("%d", count)
+	width := c.MeasureString(style.CountFace, countStr)
+	c.DrawString(countStr, area.Max.X-width, y, style.CountFace, style.CountColor)
+}
+
+// BadgeSize returns the size of the pill DrawBadge draws for text.
+func BadgeSize(face font.Face, text string) image.Point {
+	w := font.MeasureString(face, text).Ceil() + 2*badgePaddingX
+	h := LineHeight(face) + 2*badgePaddingY
+	if w < h {
+		// Short text still gets a circle rather than a squashed pill
+		w = h
+	}
+	return image.Pt(w, h)
+}
+
+// DrawBadge draws text in a rounded pill centered on center and returns the
+// pill's bounds.
+func DrawBadge(c *Canvas, text string, center image.Point, face font.Face, textColor, bgColor color.Color) image.Rectangle {
+	size := BadgeSize(face, text)
+	min := center.Sub(size.Div(2))
+	rect := image.Rectangle{Min: min, Max: min.Add(size)}
+
+	c.FillRoundedRect(rect, size.Y/2, bgColor)
+
+	m := face.Metrics()
+	// Center the glyph box (ascent + descent) vertically in the pill
+	y := rect.Min.Y + (size.Y-(m.Ascent+m.Descent).Ceil())/2 + m.Ascent.Ceil()
+	c.DrawStringCentered(text, center.X, y, face, textColor)
+
+	return rect
+}
+
+// DrawLoadingDots draws three small dots centered on center, the key
+// placeholder shown in place of values that haven't been fetched yet.
+func DrawLoadingDots(c *Canvas, center image.Point, col color.Color) {
+	for i := -1; i <= 1; i++ {
+		x := center.X + i*loadingDotGap
+		dot := image.Rect(x-loadingDotSize/2, center.Y-loadingDotSize/2, x+loadingDotSize/2, center.Y+loadingDotSize/2)
+		c.FillRoundedRect(dot, loadingDotSize/2, col)
+	}
+}
+
+// drawString draws text with its baseline starting at (x, y).
+func drawString(img *image.RGBA, text string, x, y int, face font.Face, col color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+	}
+	d.DrawString(text)
+}
@@ -0,0 +1,111 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// testFace returns a Go Regular face at size points.
+func testFace(t *testing.T, size float64) font.Face {
+	t.Helper()
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewFace(f, size)
+}
+
+// inkBounds returns the bounds of img's pixels for which match is true.
+func inkBounds(img *image.RGBA, match func(color.RGBA) bool) image.Rectangle {
+	var bounds image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if match(img.RGBAAt(x, y)) {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
+
+func TestDrawStatRowLayout(t *testing.T) {
+	c := NewCanvas(72, 72, 1)
+	area := image.Rect(8, 20, 64, 20+StatRowHeight)
+	dot := color.RGBA{255, 0, 0, 255}
+	DrawStatRow(c, area, "Wait", 42, dot, StatRowStyle{
+		LabelFace:  testFace(t, 10),
+		CountFace:  testFace(t, 10),
+		LabelColor: color.RGBA{0, 255, 0, 255},
+		CountColor: color.RGBA{0, 0, 255, 255},
+	})
+
+	// Text is drawn over transparency, so antialiased pixels keep only
+	// their own channel
+	dotInk := inkBounds(c.Img, func(p color.RGBA) bool { return p == dot })
+	label := inkBounds(c.Img, func(p color.RGBA) bool { return p.G > 0 })
+	count := inkBounds(c.Img, func(p color.RGBA) bool { return p.B > 0 })
+
+	if want := image.Rect(8, 22, 8+statDotSize, 22+statDotSize); dotInk != want {
+		t.Errorf("dot at %v, want %v", dotInk, want)
+	}
+	if label.Empty() || label.Min.X < area.Min.X+statLabelGap || label.Max.X >= count.Min.X {
+		t.Errorf("label at %v, want it after the dot and before the count at %v", label, count)
+	}
+	if count.Empty() || count.Max.X > area.Max.X || count.Max.X < area.Max.X-2 {
+		t.Errorf("count at %v, want it right-aligned to %d", count, area.Max.X)
+	}
+	for name, r := range map[string]image.Rectangle{"dot": dotInk, "label": label, "count": count} {
+		if !r.In(area) {
+			t.Errorf("%s at %v, outside the row %v", name, r, area)
+		}
+	}
+}
+
+func TestBadgeSize(t *testing.T) {
+	face := testFace(t, 12)
+	h := LineHeight(face) + 2*badgePaddingY
+
+	// Short text gets a circle
+	if got := BadgeSize(face, "3"); got != image.Pt(h, h) {
+		t.Errorf("BadgeSize(\"3\") = %v, want a %dpx circle", got, h)
+	}
+
+	// Longer text gets a pill padded around it
+	text := "Living Room"
+	want := image.Pt(font.MeasureString(face, text).Ceil()+2*badgePaddingX, h)
+	if got := BadgeSize(face, text); got != want {
+		t.Errorf("BadgeSize(%q) = %v, want %v", text, got, want)
+	}
+}
+
+func TestDrawBadge(t *testing.T) {
+	c := NewCanvas(72, 72, 1)
+	face := testFace(t, 12)
+	bg := color.RGBA{0, 0, 255, 255}
+	center := image.Pt(36, 30)
+
+	rect := DrawBadge(c, "88%", center, face, color.RGBA{255, 255, 255, 255}, bg)
+
+	if got, want := rect.Size(), BadgeSize(face, "88%"); got != want {
+		t.Errorf("badge size = %v, want %v", got, want)
+	}
+	if mid := rect.Min.Add(rect.Size().Div(2)); !mid.In(image.Rect(center.X-1, center.Y-1, center.X+2, center.Y+2)) {
+		t.Errorf("badge centered on %v, want %v", mid, center)
+	}
+	if got := c.Img.RGBAAt(rect.Min.X, rect.Min.Y); got.A != 0 {
+		t.Errorf("badge corner = %v, want it rounded off", got)
+	}
+	if got := c.Img.RGBAAt(rect.Min.X+2, rect.Min.Y+rect.Dy()/2); got != bg {
+		t.Errorf("badge edge = %v, want the background %v", got, bg)
+	}
+	text := inkBounds(c.Img, func(p color.RGBA) bool { return p.R > 0 })
+	if text.Empty() || !text.In(rect) {
+		t.Errorf("text at %v, want it inside the badge %v", text, rect)
+	}
+}
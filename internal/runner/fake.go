@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Fake is a Runner that records the commands it's given instead of running
+// them, for tests.
+type Fake struct {
+	// Outputs maps a command line, as "name arg...", to what Output
+	// returns for it.
+	Outputs map[string]string
+
+	// Err, if set, is returned for every command.
+	Err error
+
+	mu       sync.Mutex
+	commands []string
+	inputs   map[string]string
+}
+
+// Run records the command.
+func (f *Fake) Run(ctx context.Context, name string, args ...string) error {
+	f.record(name, args)
+	return f.Err
+}
+
+// Output records the command and returns its entry in Outputs.
+func (f *Fake) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	line := f.record(name, args)
+	return []byte(f.Outputs[line]), f.Err
+}
+
+// RunInput records the command and its input.
+func (f *Fake) RunInput(ctx context.Context, input []byte, name string, args ...string) error {
+	line := f.record(name, args)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.inputs == nil {
+		f.inputs = make(map[string]string)
+	}
+	f.inputs[line] = string(input)
+	return f.Err
+}
+
+// Commands returns the command lines run so far, oldest first, as
+// "name arg...".
+func (f *Fake) Commands() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+// Input returns the input last given to a command line, as "name arg...",
+// by RunInput.
+func (f *Fake) Input(line string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.inputs[line]
+}
+
+func (f *Fake) record(name string, args []string) string {
+	line := strings.Join(append([]string{name}, args...), " ")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.commands = append(f.commands, line)
+	return line
+}
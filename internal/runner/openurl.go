@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/platform"
+)
+
+// DefaultOpenWindow is the throttle window for opening URLs unless
+// configured otherwise.
+const DefaultOpenWindow = 2 * time.Second
+
+// openBurst is how many URLs may be opened within a throttle window.
+const openBurst = 3
+
+// ErrThrottled is returned by OpenURL when it drops a URL rather than
+// open it.
+var ErrThrottled = errors.New("URL opened too recently")
+
+// OpenThrottle bounds how often URLs are opened in the browser, so mashed
+// keys or a misread chord can't flood it with tabs: the same URL isn't
+// opened twice within Window, and no more than a few URLs are opened
+// within it in all. A zero Window turns the throttle off.
+type OpenThrottle struct {
+	Window time.Duration
+
+	mu     sync.Mutex
+	opened []openedURL
+}
+
+// openedURL is a URL the throttle let through, and when.
+type openedURL struct {
+	url string
+	at  time.Time
+}
+
+// Allow reports whether url may be opened now, recording it if so.
+func (t *OpenThrottle) Allow(url string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.Window <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	recent := t.opened[:0]
+	for _, o := range t.opened {
+		if now.Sub(o.at) < t.Window {
+			recent = append(recent, o)
+		}
+	}
+	t.opened = recent
+
+	if len(t.opened) >= openBurst {
+		return false
+	}
+	for _, o := range t.opened {
+		if o.url == url {
+			return false
+		}
+	}
+	t.opened = append(t.opened, openedURL{url: url, at: now})
+	return true
+}
+
+// URLThrottle is the throttle shared by everything opening URLs through
+// OpenURL, so the bound holds across modules.
+var URLThrottle = &OpenThrottle{Window: DefaultOpenWindow}
+
+// OpenURL opens url in the default browser with r, unless URLThrottle
+// drops it, in which case it returns ErrThrottled.
+func OpenURL(ctx context.Context, r Runner, url string) error {
+	if !URLThrottle.Allow(url) {
+		return ErrThrottled
+	}
+	name, args := platform.OpenURL(url)
+	return r.Run(ctx, name, args...)
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phinze/belowdeck/internal/platform"
+)
+
+// useThrottle swaps URLThrottle for one with the given window for the
+// length of the test.
+func useThrottle(t *testing.T, window time.Duration) {
+	t.Helper()
+	saved := URLThrottle
+	URLThrottle = &OpenThrottle{Window: window}
+	t.Cleanup(func() { URLThrottle = saved })
+}
+
+// openCommand returns the command line OpenURL runs for url.
+func openCommand(url string) string {
+	name, args := platform.OpenURL(url)
+	return strings.Join(append([]string{name}, args...), " ")
+}
+
+func TestOpenURLRepeatedOpensOnce(t *testing.T) {
+	useThrottle(t, time.Minute)
+	fake := &Fake{}
+
+	const url = "https://example.com/pr/1"
+	for i := range 10 {
+		err := OpenURL(context.Background(), fake, url)
+		if i == 0 && err != nil {
+			t.Fatalf("first open: %v", err)
+		}
+		if i > 0 && !errors.Is(err, ErrThrottled) {
+			t.Fatalf("open %d = %v, want ErrThrottled", i+1, err)
+		}
+	}
+
+	got := fake.Commands()
+	if len(got) != 1 || got[0] != openCommand(url) {
+		t.Errorf("ran %q, want just %q", got, openCommand(url))
+	}
+}
+
+func TestOpenThrottleBurst(t *testing.T) {
+	throttle := &OpenThrottle{Window: time.Minute}
+
+	urls := []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"}
+	var allowed []string
+	for _, url := range urls {
+		if throttle.Allow(url) {
+			allowed = append(allowed, url)
+		}
+	}
+	if len(allowed) != openBurst {
+		t.Errorf("allowed %q, want the first %d", allowed, openBurst)
+	}
+}
+
+func TestOpenThrottleWindowPasses(t *testing.T) {
+	throttle := &OpenThrottle{Window: 20 * time.Millisecond}
+
+	const url = "https://example.com"
+	if !throttle.Allow(url) {
+		t.Fatal("first open dropped")
+	}
+	if throttle.Allow(url) {
+		t.Fatal("repeat open within the window allowed")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !throttle.Allow(url) {
+		t.Error("open after the window dropped")
+	}
+}
+
+func TestOpenThrottleDisabled(t *testing.T) {
+	throttle := &OpenThrottle{}
+	for i := range 5 {
+		if !throttle.Allow("https://example.com") {
+			t.Fatalf("open %d dropped with the throttle off", i+1)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package runner abstracts running external commands so modules don't call
+// os/exec directly.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+)
+
+// Runner runs external commands.
+type Runner interface {
+	// Run runs the command and waits for it to finish.
+	Run(ctx context.Context, name string, args ...string) error
+
+	// Output runs the command and returns its standard output.
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+
+	// RunInput runs the command with input on its standard input.
+	RunInput(ctx context.Context, input []byte, name string, args ...string) error
+}
+
+// Exec is a Runner backed by os/exec.
+type Exec struct{}
+
+// Run runs the command and waits for it to finish.
+func (Exec) Run(ctx context.Context, name string, args ...string) error {
+	return exec.CommandContext(ctx, name, args...).Run()
+}
+
+// Output runs the command and returns its standard output.
+func (Exec) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// RunInput runs the command with input on its standard input.
+func (Exec) RunInput(ctx context.Context, input []byte, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	return cmd.Run()
+}
+
+// Default is the Runner used by modules unless one is injected.
+var Default Runner = Exec{}
+
+// Shell runs a shell command line through sh -c.
+func Shell(ctx context.Context, r Runner, command string) error {
+	return r.Run(ctx, "sh", "-c", command)
+}
+
+// ShellOutput runs a shell command line through sh -c and returns its standard output.
+func ShellOutput(ctx context.Context, r Runner, command string) ([]byte, error) {
+	return r.Output(ctx, "sh", "-c", command)
+}
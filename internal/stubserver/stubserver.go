@@ -0,0 +1,87 @@
+// Package stubserver provides a local HTTP server with canned responses, so
+// the modules' API clients can be tested without the network.
+package stubserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Server is a test server routing requests to handlers registered by
+// pattern, as with http.ServeMux; unmatched requests get 404 Not Found. It
+// records the requests it receives and how many it has handled at once.
+type Server struct {
+	*httptest.Server
+
+	mux *http.ServeMux
+
+	mu          sync.Mutex
+	requests    []*http.Request
+	inFlight    int
+	maxInFlight int
+}
+
+// New starts a server that's closed when the test ends. Point a client at
+// its URL field.
+func New(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{mux: http.NewServeMux()}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle registers h for requests matching pattern, e.g.
+// "GET /api/states/{id}".
+func (s *Server) Handle(pattern string, h http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, h)
+}
+
+// JSON registers a handler answering requests matching pattern with body
+// encoded as JSON.
+func (s *Server) JSON(pattern string, body any) {
+	s.Handle(pattern, func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, body)
+	})
+}
+
+// WriteJSON writes body to w as a JSON response, for handlers that answer
+// conditionally.
+func WriteJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// Requests returns the requests received so far, oldest first. Their
+// bodies have been consumed.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// MaxInFlight returns the most requests the server has handled at once.
+func (s *Server) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Clone(r.Context()))
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	s.mux.ServeHTTP(w, r)
+}